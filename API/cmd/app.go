@@ -5,12 +5,15 @@ import (
 	"log"
 
 	"API/config"
+	"API/mailer"
 	"API/routes"
 	"API/services"
 	"API/storage/cache"
 	"API/storage/database"
+	"API/utils"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -18,7 +21,7 @@ import (
 // Start 是应用程序的入口点，包含所有初始化逻辑和服务器启动
 func Start() {
 	// 初始化应用
-	_, stop := context.WithCancel(context.Background())
+	ctx, stop := context.WithCancel(context.Background())
 	defer stop()
 
 	// 初始化各组件
@@ -41,11 +44,58 @@ func Start() {
 
 	// 初始化服务层
 	cacheService := cache.NewRedisCacheService(redisClient)
-	userService := services.NewUserService(db, cacheService)
+	permissionService := services.NewPermissionService(db, cacheService)
+	// 启动时补授了默认权限的角色需清除权限缓存，其他运行中的实例随即读取到新权限
+	permissionService.InvalidateRoles(ctx, database.ReseededRoles...)
+	tokenService := services.NewTokenService(cacheService, initTokenIssuer())
+	loginGuard := services.NewLoginGuard(cacheService, services.LoadLoginGuardConfig())
+	mfaService := initMFAService(db, cacheService, permissionService, tokenService)
+	passwordPolicy := initPasswordPolicy()
+	userService := services.NewUserService(db, cacheService, tokenService, loginGuard, mfaService, passwordPolicy)
+	mail := initMailer()
+	accountService := services.NewAccountService(db, cacheService, mail, tokenService, loginGuard, passwordPolicy, services.LoadAccountConfig())
 	jobService := services.NewJobService(db)
+	resumeService := services.NewResumeService(db, cacheService)
+	requisitionService := services.NewRequisitionService(db, mail, services.LoadRequisitionConfig())
+	scheduleService := services.NewScheduleService(db, services.LoadScheduleConfig())
+
+	// 后台任务
+	startPeriodicTask(ctx, "关闭过期职位", viper.GetDuration("jobs.expiry_sweep_interval"), func(ctx context.Context) error {
+		closed, err := jobService.CloseExpiredJobs(ctx)
+		if closed > 0 {
+			log.Printf("已关闭 %d 个过期职位", closed)
+		}
+		return err
+	})
+
+	offerService := services.NewOfferService(db, tokenService)
+	startPeriodicTask(ctx, "标记过期录用通知", viper.GetDuration("offers.expiry_sweep_interval"), func(ctx context.Context) error {
+		expired, err := offerService.ExpireOffers(ctx)
+		if expired > 0 {
+			log.Printf("已标记 %d 个过期录用通知", expired)
+		}
+		return err
+	})
+
+	retention := viper.GetDuration("resumes.version_retention")
+	startPeriodicTask(ctx, "清理淘汰候选人简历版本", viper.GetDuration("resumes.retention_sweep_interval"), func(ctx context.Context) error {
+		purged, err := resumeService.PurgeRejectedVersions(ctx, retention)
+		if purged > 0 {
+			log.Printf("已清理 %d 个历史简历版本", purged)
+		}
+		return err
+	})
+
+	startDailyTask(ctx, "标记未下班打卡记录", viper.GetString("attendance.missing_clock_out_check_at"), func(ctx context.Context) error {
+		flagged, err := scheduleService.FlagMissingClockOuts(ctx)
+		if flagged > 0 {
+			log.Printf("已标记 %d 条未下班打卡记录", flagged)
+		}
+		return err
+	})
 
 	// 创建增强版路由
-	router := routes.SetupRouter(userService, jobService)
+	router := routes.SetupRouter(userService, accountService, mfaService, jobService, resumeService, requisitionService, tokenService, permissionService, scheduleService)
 
 	// 启动服务器
 	log.Println("🚀 启动服务器...")
@@ -83,6 +133,47 @@ func initRedis() *redis.Client {
 	return redisClient
 }
 
+// initTokenIssuer 根据已加载的配置初始化令牌签发器
+func initTokenIssuer() *utils.TokenIssuer {
+	log.Println("🚀 开始初始化令牌签发器...")
+	cfg, err := utils.LoadJWTConfig()
+	if err != nil {
+		log.Fatalf("❌ 令牌配置加载失败: %v", err)
+	}
+	issuer, err := utils.NewTokenIssuer(cfg)
+	if err != nil {
+		log.Fatalf("❌ 令牌签发器初始化失败: %v", err)
+	}
+	return issuer
+}
+
+// initMFAService 初始化两步验证服务
+func initMFAService(db *gorm.DB, cacheService cache.Provider, permissionService *services.PermissionService, tokenService *services.TokenService) *services.MFAService {
+	mfaService, err := services.NewMFAService(db, cacheService, permissionService, tokenService, services.LoadMFAConfig())
+	if err != nil {
+		log.Fatalf("❌ 两步验证初始化失败: %v", err)
+	}
+	return mfaService
+}
+
+// initPasswordPolicy 初始化密码策略
+func initPasswordPolicy() *services.PasswordPolicy {
+	policy, err := services.NewPasswordPolicy(services.LoadPasswordPolicyConfig())
+	if err != nil {
+		log.Fatalf("❌ 密码策略初始化失败: %v", err)
+	}
+	return policy
+}
+
+// initMailer 初始化邮件发送器
+func initMailer() mailer.Mailer {
+	m, err := mailer.New(mailer.LoadMailConfig())
+	if err != nil {
+		log.Fatalf("❌ 邮件发送器初始化失败: %v", err)
+	}
+	return m
+}
+
 // initLogger 初始化日志
 func initLogger() *zap.Logger {
 	logger, _ := zap.NewProduction()
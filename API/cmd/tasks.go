@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// startPeriodicTask 启动按固定间隔执行的后台任务，启动时先执行一次；interval 不大于0时不启动
+func startPeriodicTask(ctx context.Context, name string, interval time.Duration, task func(context.Context) error) {
+	if interval <= 0 {
+		log.Printf("⏸️ 后台任务「%s」未启用", name)
+		return
+	}
+
+	go func() {
+		runTask(ctx, name, task)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runTask(ctx, name, task)
+			}
+		}
+	}()
+	log.Printf("🚀 后台任务「%s」已启动，间隔 %s", name, interval)
+}
+
+// startDailyTask 启动每天在指定本地时间（HH:MM）执行的后台任务；at 为空时不启动
+func startDailyTask(ctx context.Context, name, at string, task func(context.Context) error) {
+	if at == "" {
+		log.Printf("⏸️ 后台任务「%s」未启用", name)
+		return
+	}
+	clock, err := time.Parse("15:04", at)
+	if err != nil {
+		log.Printf("❌ 后台任务「%s」执行时间无效: %s", name, at)
+		return
+	}
+
+	go func() {
+		for {
+			now := time.Now()
+			next := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+			if !next.After(now) {
+				next = next.AddDate(0, 0, 1)
+			}
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				runTask(ctx, name, task)
+			}
+		}
+	}()
+	log.Printf("🚀 后台任务「%s」已启动，每天 %s 执行", name, at)
+}
+
+// runTask 执行一次后台任务，记录错误并从异常中恢复
+func runTask(ctx context.Context, name string, task func(context.Context) error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("❌ 后台任务「%s」异常: %v", name, r)
+		}
+	}()
+	if err := task(ctx); err != nil {
+		log.Printf("⚠️ 后台任务「%s」执行失败: %v", name, err)
+	}
+}
@@ -9,6 +9,12 @@ import (
 	"github.com/spf13/viper"
 )
 
+// DefaultInsecureSecret jwt.secret 的占位默认值，令牌签发器拒绝使用该值启动
+const DefaultInsecureSecret = "default-insecure-secret"
+
+// DefaultInsecureMFAKey mfa.secret_key 的占位默认值（公开的开发密钥），两步验证服务拒绝使用该值启动
+const DefaultInsecureMFAKey = "ZGV2LW9ubHktbWZhLXNlY3JldC1rZXktMzItYnl0ZXM="
+
 func InitConfig() error {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
@@ -24,8 +30,34 @@ func InitConfig() error {
 	// 设置智能默认值
 	viper.SetDefault("server.port", "8080")
 	viper.SetDefault("server.mode", "debug")
-	viper.SetDefault("jwt.secret", "default-insecure-secret")
-	viper.SetDefault("jwt.expiration", 720*time.Hour) // 30天
+	viper.SetDefault("jwt.secret", DefaultInsecureSecret)
+	viper.SetDefault("jwt.algorithm", "HS256")
+	viper.SetDefault("jwt.issuer", "hrms")
+	viper.SetDefault("jwt.expiration", 15*time.Minute)        // 访问令牌
+	viper.SetDefault("jwt.refresh_expiration", 720*time.Hour) // 刷新令牌，30天
+	viper.SetDefault("mail.driver", "log")
+	viper.SetDefault("mail.link_base_url", "http://localhost:8080")
+	viper.SetDefault("mfa.clock_skew", 1) // 允许前后各一个时间步（30秒）
+	viper.SetDefault("mfa.secret_key", DefaultInsecureMFAKey)
+	viper.SetDefault("jobs.expiry_sweep_interval", 10*time.Minute)
+	viper.SetDefault("offers.expiry_sweep_interval", 10*time.Minute)
+	viper.SetDefault("attendance.default_schedule.start_time", "09:00")
+	viper.SetDefault("attendance.default_schedule.end_time", "18:00")
+	viper.SetDefault("attendance.default_schedule.late_grace", 30) // 分钟
+	viper.SetDefault("attendance.default_schedule.early_leave_grace", 0)
+	viper.SetDefault("attendance.default_schedule.work_days", "1,2,3,4,5")
+	viper.SetDefault("attendance.clock_out_window", 6*time.Hour) // 班次结束后仍可下班打卡的时长
+	viper.SetDefault("attendance.missing_clock_out_check_at", "02:00")
+	viper.SetDefault("holidays.workday_keywords", []string{"补班", "上班"})
+	viper.SetDefault("overtime.multipliers.weekday", 1.5)
+	viper.SetDefault("overtime.multipliers.weekend", 2.0)
+	viper.SetDefault("overtime.multipliers.holiday", 3.0)
+	viper.SetDefault("overtime.min_minutes", 30)
+	viper.SetDefault("overtime.standard_daily_hours", 8)
+	viper.SetDefault("resumes.version_retention", 180*24*time.Hour) // 淘汰候选人历史简历版本保留180天
+	viper.SetDefault("resumes.retention_sweep_interval", 24*time.Hour)
+	viper.SetDefault("talent.allow_free_form_tags", true)
+	viper.SetDefault("requisitions.approval_chain", []string{"department_head", "hr"})
 
 	// 环境变量支持
 	viper.AutomaticEnv()
@@ -47,6 +79,7 @@ func InitConfig() error {
 	log.Printf("  连接MySQL地址为: %s:%d", viper.GetString("database.mysql.host"), viper.GetInt("database.mysql.port"))
 	log.Printf("  连接Redis地址为: %s:%d", viper.GetString("database.redis.host"), viper.GetInt("database.redis.port"))
 	log.Printf("  Server Port: %s", viper.GetString("server.port"))
+	log.Printf("  JWT Algorithm: %s", viper.GetString("jwt.algorithm"))
 	log.Printf("  JWT Secret: %s", maskSecret(viper.GetString("jwt.secret")))
 
 	return nil
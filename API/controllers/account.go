@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"net/http"
+
+	"API/services"
+	"API/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountController 密码重置与邮箱验证控制器
+type AccountController struct {
+	BaseController
+	accountService *services.AccountService
+}
+
+// NewAccountController 初始化账户控制器
+func NewAccountController(as *services.AccountService) *AccountController {
+	return &AccountController{accountService: as}
+}
+
+// ForgotPassword 申请重置密码
+// @Summary 申请重置密码
+// @Description 向邮箱发送密码重置链接，无论邮箱是否注册均返回成功
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body struct{Email string `json:"email" binding:"required,email"`} true "注册邮箱"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "无效的请求参数"
+// @Router /api/v1/auth/password/forgot [post]
+func (ctl *AccountController) ForgotPassword(c *gin.Context) {
+	var request struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	if err := ctl.accountService.RequestPasswordReset(c.Request.Context(), request.Email); err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "申请重置密码失败")
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "若该邮箱已注册，重置链接将发送至邮箱"})
+}
+
+// ResetPassword 重置密码
+// @Summary 重置密码
+// @Description 使用邮件中的一次性令牌设置新密码，成功后所有已登录会话失效
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body struct{Token string `json:"token" binding:"required"` NewPassword string `json:"new_password" binding:"required"`} true "重置信息"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "链接无效或已过期"
+// @Router /api/v1/auth/password/reset [post]
+func (ctl *AccountController) ResetPassword(c *gin.Context) {
+	var request struct {
+		Token       string `json:"token" binding:"required"`
+		NewPassword string `json:"new_password" binding:"required"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	if err := ctl.accountService.ResetPassword(c.Request.Context(), request.Token, request.NewPassword); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "密码已重置，请重新登录"})
+}
+
+// VerifyEmail 验证邮箱
+// @Summary 验证邮箱
+// @Description 使用邮件中的一次性令牌确认邮箱
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body struct{Token string `json:"token" binding:"required"`} true "验证令牌"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "链接无效或已过期"
+// @Router /api/v1/auth/email/verify [post]
+func (ctl *AccountController) VerifyEmail(c *gin.Context) {
+	var request struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	if err := ctl.accountService.VerifyEmail(c.Request.Context(), request.Token); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "邮箱验证成功"})
+}
+
+// ResendEmailVerification 重新发送邮箱验证邮件
+// @Summary 重新发送验证邮件
+// @Description 向当前用户的邮箱重新发送验证链接
+// @Tags 认证
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "邮箱已验证或未设置邮箱"
+// @Failure 401 {object} utils.Response "未授权"
+// @Router /api/v1/auth/email/resend [post]
+func (ctl *AccountController) ResendEmailVerification(c *gin.Context) {
+	userID, _ := ctl.GetAuthUser(c)
+	if err := ctl.accountService.SendEmailVerification(c.Request.Context(), userID); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "验证邮件已发送"})
+}
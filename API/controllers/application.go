@@ -1,9 +1,6 @@
 package controllers
 
 import (
-	"net/http"
-	"strconv"
-
 	"API/services"
 	"API/utils"
 
@@ -11,6 +8,7 @@ import (
 )
 
 type ApplicationController struct {
+	BaseController
 	applicationService *services.ApplicationService
 }
 
@@ -18,34 +16,165 @@ func NewApplicationController(as *services.ApplicationService) *ApplicationContr
 	return &ApplicationController{applicationService: as}
 }
 
-// UpdateApplicationStatus 更新申请状态
-// @Summary 更新申请状态
-// @Description 更新指定申请的状态
+// MoveApplicationStage 推进申请的招聘阶段
+// @Summary 推进申请的招聘阶段
+// @Description 将申请流转到同一职位流程中的目标阶段：进行中的阶段只能前进或退回一步，任一阶段可淘汰，仅最后一个进行中的阶段可录用
 // @Tags 申请管理
+// @Security Bearer
 // @Accept json
 // @Produce json
 // @Param id path int true "申请ID"
-// @Param request body struct{Status string `json:"status" binding:"required"`} true "状态信息"
-// @Success 200 {object} utils.Response{data=string} "状态更新成功"
-// @Failure 400 {object} utils.Response "无效的申请ID或请求参数"
-// @Failure 500 {object} utils.Response "服务器内部错误"
-// @Router /api/v1/applications/{id}/status [put]
-func (ctl *ApplicationController) UpdateApplicationStatus(c *gin.Context) {
-	applicationID, err := strconv.Atoi(c.Param("id"))
+// @Param request body struct{StageID uint `json:"stage_id" binding:"required"` Reason string `json:"reason"`} true "目标阶段"
+// @Success 200 {object} utils.Response{data=models.Application}
+// @Failure 400 {object} utils.Response "不允许的阶段流转"
+// @Failure 404 {object} utils.Response "申请记录不存在"
+// @Router /api/v1/applications/{id}/stage [post]
+func (ctl *ApplicationController) MoveApplicationStage(c *gin.Context) {
+	applicationID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var request struct {
+		StageID uint   `json:"stage_id" binding:"required"`
+		Reason  string `json:"reason" binding:"max=500"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+
+	operatorID, _ := ctl.GetAuthUser(c)
+	application, err := ctl.applicationService.MoveStage(c.Request.Context(), applicationID, request.StageID, operatorID, request.Reason)
 	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "无效的申请ID")
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, application)
+}
+
+// GetApplicationHistory 获取申请的阶段流转记录
+// @Summary 获取申请的阶段流转记录
+// @Description 按时间顺序返回申请在各阶段间的流转、操作人和原因
+// @Tags 申请管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "申请ID"
+// @Success 200 {object} utils.Response{data=[]models.ApplicationStageHistory}
+// @Failure 404 {object} utils.Response "申请记录不存在"
+// @Router /api/v1/applications/{id}/history [get]
+func (ctl *ApplicationController) GetApplicationHistory(c *gin.Context) {
+	applicationID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	history, err := ctl.applicationService.GetStageHistory(c.Request.Context(), applicationID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, history)
+}
+
+// GetApplicationResume 获取申请投递时的简历
+// @Summary 获取申请投递时的简历
+// @Description 返回候选人投递或最后一次更换简历时固定的简历版本，之后对简历的修改不影响该内容；启用版本管理前的申请返回简历当前内容，版本号为0
+// @Tags 申请管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "申请ID"
+// @Success 200 {object} utils.Response{data=services.ResumeVersionDetail}
+// @Failure 404 {object} utils.Response "申请记录不存在或未附带简历"
+// @Router /api/v1/applications/{id}/resume [get]
+func (ctl *ApplicationController) GetApplicationResume(c *gin.Context) {
+	applicationID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	resume, err := ctl.applicationService.GetApplicationResume(c.Request.Context(), applicationID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, resume)
+}
+
+// GetMyApplications 获取我的申请
+// @Summary 获取我的申请
+// @Description 返回当前用户投递的全部申请，含职位信息和当前所处阶段
+// @Tags 申请管理
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.Application}
+// @Router /api/v1/applications/my [get]
+func (ctl *ApplicationController) GetMyApplications(c *gin.Context) {
+	userID, _ := ctl.GetAuthUser(c)
+	applications, err := ctl.applicationService.ListMyApplications(c.Request.Context(), userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, applications)
+}
+
+// UpdateMyApplication 更换申请附带的简历或求职信
+// @Summary 更换申请附带的简历或求职信
+// @Description 申请进行中时可更换附带的简历和求职信，未指定简历时附带本人当前简历，未指定版本时使用简历的最新版本
+// @Tags 申请管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "申请ID"
+// @Param request body services.ApplyInput true "简历和求职信"
+// @Success 200 {object} utils.Response{data=models.Application}
+// @Failure 400 {object} utils.Response "申请已结束或简历无效"
+// @Failure 404 {object} utils.Response "申请记录不存在"
+// @Router /api/v1/applications/{id} [put]
+func (ctl *ApplicationController) UpdateMyApplication(c *gin.Context) {
+	applicationID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input services.ApplyInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	application, err := ctl.applicationService.UpdateMyApplication(c.Request.Context(), userID, applicationID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, application)
+}
+
+// WithdrawApplication 撤回申请
+// @Summary 撤回申请
+// @Description 撤回本人进行中或已录用但尚未接受录用通知的申请，待进行的面试取消，尚未答复的录用通知作废
+// @Tags 申请管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "申请ID"
+// @Param request body struct{Reason string `json:"reason"`} false "撤回原因"
+// @Success 200 {object} utils.Response{data=models.Application}
+// @Failure 400 {object} utils.Response "申请已结束或已接受录用通知"
+// @Failure 404 {object} utils.Response "申请记录不存在"
+// @Router /api/v1/applications/{id}/withdraw [post]
+func (ctl *ApplicationController) WithdrawApplication(c *gin.Context) {
+	applicationID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
 		return
 	}
 	var request struct {
-		Status string `json:"status" binding:"required"`
+		Reason string `json:"reason" binding:"max=500"`
 	}
-	if err := c.ShouldBindJSON(&request); err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "无效的请求参数")
+	if c.Request.ContentLength > 0 && !ctl.BindJSON(c, &request) {
 		return
 	}
-	if err := ctl.applicationService.UpdateApplicationStatus(c.Request.Context(), uint(applicationID), request.Status); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+	userID, _ := ctl.GetAuthUser(c)
+	application, err := ctl.applicationService.WithdrawApplication(c.Request.Context(), userID, applicationID, request.Reason)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
 		return
 	}
-	utils.RespondSuccess(c, gin.H{"message": "状态更新成功"})
+	utils.RespondSuccess(c, application)
 }
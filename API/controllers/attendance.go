@@ -21,56 +21,59 @@ func NewAttendanceController(s *services.AttendanceService) *AttendanceControlle
 
 // ClockIn 上班打卡
 // @Summary 上班打卡
-// @Description 记录员工上班打卡时间
+// @Description 记录员工上班打卡时间，按当日适用的班次判断是否迟到；夜班在次日下班前打卡计入前一日的班次
 // @Tags 考勤管理
 // @Security Bearer
 // @Produce json
-// @Success 200 {object} utils.Response{data=map[string]string{clock_time=string,message=string}} "打卡成功"
+// @Success 200 {object} utils.Response{data=map[string]interface{}{clock_time=string,message=string,status=string,late_minutes=int}} "打卡成功"
 // @Failure 401 {object} utils.Response "未授权的请求"
 // @Failure 500 {object} utils.Response "打卡失败"
 // @Router /api/v1/attendance/clock-in [post]
 func (ctl *AttendanceController) ClockIn(c *gin.Context) {
 	userID, _ := ctl.GetAuthUser(c)
 
-	if err := ctl.service.ClockIn(c.Request.Context(), userID); err != nil {
+	attendance, err := ctl.service.ClockIn(c.Request.Context(), userID)
+	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "打卡失败: "+err.Error())
 		return
 	}
 
 	utils.RespondSuccess(c, gin.H{
-		"clock_time": time.Now().Format(time.RFC3339),
-		"message":    "打卡成功",
+		"clock_time":   attendance.ClockIn.Format(time.RFC3339),
+		"message":      "打卡成功",
+		"status":       attendance.Status,
+		"late_minutes": attendance.LateMinutes,
 	})
 }
 
 // ClockOut 下班打卡
 // @Summary 下班打卡
-// @Description 记录员工下班打卡时间
+// @Description 记录员工下班打卡时间，按上班打卡时适用的班次判断是否早退；超过班次结束后的最晚打卡时间或已被标记为未下班打卡的记录需提交补卡申请
 // @Tags 考勤管理
 // @Security Bearer
 // @Produce json
-// @Success 200 {object} utils.Response "打卡成功"
+// @Success 200 {object} utils.Response{data=models.Attendance} "打卡成功"
 // @Failure 401 {object} utils.Response "未授权的请求"
 // @Failure 500 {object} utils.Response "打卡失败"
 // @Router /api/v1/attendance/clock-out [post]
 func (ctl *AttendanceController) ClockOut(c *gin.Context) {
-	userID, _ := c.Get("userID")
-	err := ctl.service.ClockOut(c.Request.Context(), userID.(uint))
+	userID, _ := ctl.GetAuthUser(c)
+	attendance, err := ctl.service.ClockOut(c.Request.Context(), userID)
 	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "打卡失败")
+		utils.RespondError(c, http.StatusInternalServerError, "打卡失败: "+err.Error())
 		return
 	}
-	utils.RespondSuccess(c, nil)
+	utils.RespondSuccess(c, attendance)
 }
 
 // GetMonthly 获取月度考勤
 // @Summary 获取月度考勤
-// @Description 获取指定月份的考勤记录，管理员可查看所有记录
+// @Description 获取指定月份的考勤记录和已批准的请假，管理员可查看所有记录。查看本人考勤时附带出勤汇总，请假日期不计为缺勤
 // @Tags 考勤管理
 // @Security Bearer
 // @Produce json
 // @Param month query string true "月份格式YYYY-MM"
-// @Success 200 {object} utils.Response{data=[]models.Attendance} "考勤记录列表"
+// @Success 200 {object} utils.Response{data=services.MonthlyAttendance} "月度考勤"
 // @Failure 400 {object} utils.Response "日期格式错误"
 // @Failure 401 {object} utils.Response "未授权的请求"
 // @Failure 500 {object} utils.Response "获取记录失败"
@@ -92,13 +95,13 @@ func (ctl *AttendanceController) GetMonthly(c *gin.Context) {
 		}
 	}
 
-	records, err := ctl.service.GetMonthlyAttendance(c.Request.Context(), userID, month, isAdmin)
+	monthly, err := ctl.service.GetMonthlyAttendance(c.Request.Context(), userID, month, isAdmin)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "获取记录失败: "+err.Error())
 		return
 	}
 
-	utils.RespondSuccess(c, records)
+	utils.RespondSuccess(c, monthly)
 }
 
 // GetAttendanceStats 获取考勤统计
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"API/services"
+	"API/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttendanceCorrectionController 补卡申请控制器
+type AttendanceCorrectionController struct {
+	BaseController
+	correctionService *services.AttendanceCorrectionService
+}
+
+func NewAttendanceCorrectionController(s *services.AttendanceCorrectionService) *AttendanceCorrectionController {
+	return &AttendanceCorrectionController{correctionService: s}
+}
+
+type correctionDecisionRequest struct {
+	Comment string `json:"comment" binding:"max=500"`
+}
+
+// SubmitCorrection 提交补卡申请
+// @Summary 提交补卡申请
+// @Description 类型为 missing_clock_in（漏打上班卡，须填写上班时间）、missing_clock_out（漏打下班卡，须填写下班时间）或 wrong_time（打卡时间有误，至少填写一项）。时间格式为 YYYY-MM-DD HH:MM，夜班的下班时间可为次日；同一日期只能有一个待审批的申请
+// @Tags 考勤管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body services.CorrectionInput true "补卡申请"
+// @Success 200 {object} utils.Response{data=models.AttendanceCorrection}
+// @Failure 400 {object} utils.Response "参数无效、与打卡记录不符或重复申请"
+// @Router /api/v1/attendance/corrections [post]
+func (ctl *AttendanceCorrectionController) SubmitCorrection(c *gin.Context) {
+	var input services.CorrectionInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	correction, err := ctl.correctionService.Submit(c.Request.Context(), userID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, correction)
+}
+
+// GetMyCorrections 获取我的补卡申请
+// @Summary 获取我的补卡申请
+// @Tags 考勤管理
+// @Security Bearer
+// @Produce json
+// @Param status query string false "状态：pending/approved/rejected/canceled"
+// @Success 200 {object} utils.Response{data=[]models.AttendanceCorrection}
+// @Router /api/v1/attendance/corrections/my [get]
+func (ctl *AttendanceCorrectionController) GetMyCorrections(c *gin.Context) {
+	userID, _ := ctl.GetAuthUser(c)
+	corrections, err := ctl.correctionService.ListMyCorrections(c.Request.Context(), userID, c.Query("status"))
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, corrections)
+}
+
+// CancelCorrection 撤销补卡申请
+// @Summary 撤销补卡申请
+// @Description 仅待审批的申请可撤销
+// @Tags 考勤管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "补卡申请ID"
+// @Success 200 {object} utils.Response{data=models.AttendanceCorrection}
+// @Failure 400 {object} utils.Response "申请已处理"
+// @Failure 404 {object} utils.Response "补卡申请不存在"
+// @Router /api/v1/attendance/corrections/{id}/cancel [post]
+func (ctl *AttendanceCorrectionController) CancelCorrection(c *gin.Context) {
+	correctionID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	correction, err := ctl.correctionService.Cancel(c.Request.Context(), correctionID, userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, correction)
+}
+
+// ListPendingCorrections 获取待我审批的补卡申请
+// @Summary 获取待我审批的补卡申请
+// @Description 人事可审批全部员工的补卡，部门负责人仅限本部门员工
+// @Tags 考勤管理
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.AttendanceCorrection}
+// @Router /api/v1/attendance/corrections/pending [get]
+func (ctl *AttendanceCorrectionController) ListPendingCorrections(c *gin.Context) {
+	userID, _ := ctl.GetAuthUser(c)
+	corrections, err := ctl.correctionService.ListPendingForApprover(c.Request.Context(), userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, corrections)
+}
+
+// ApproveCorrection 批准补卡申请
+// @Summary 批准补卡申请
+// @Description 审批人须为员工所在部门的负责人或人事，且不能审批自己的申请；批准后按申请改写打卡记录并重新计算迟到、早退和加班，原打卡时间和状态保留在申请中
+// @Tags 考勤管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "补卡申请ID"
+// @Param request body correctionDecisionRequest false "审批意见"
+// @Success 200 {object} utils.Response{data=models.AttendanceCorrection}
+// @Failure 400 {object} utils.Response "申请已处理或与当前打卡记录不符"
+// @Failure 403 {object} utils.Response "不是该员工的审批人"
+// @Failure 404 {object} utils.Response "补卡申请不存在"
+// @Router /api/v1/attendance/corrections/{id}/approve [post]
+func (ctl *AttendanceCorrectionController) ApproveCorrection(c *gin.Context) {
+	correctionID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var body correctionDecisionRequest
+	if c.Request.ContentLength != 0 && !ctl.BindJSON(c, &body) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	correction, err := ctl.correctionService.Approve(c.Request.Context(), correctionID, userID, body.Comment)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, correction)
+}
+
+// RejectCorrection 驳回补卡申请
+// @Summary 驳回补卡申请
+// @Description 驳回须填写审批意见
+// @Tags 考勤管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "补卡申请ID"
+// @Param request body correctionDecisionRequest true "审批意见"
+// @Success 200 {object} utils.Response{data=models.AttendanceCorrection}
+// @Failure 400 {object} utils.Response "未填写审批意见或申请已处理"
+// @Failure 403 {object} utils.Response "不是该员工的审批人"
+// @Failure 404 {object} utils.Response "补卡申请不存在"
+// @Router /api/v1/attendance/corrections/{id}/reject [post]
+func (ctl *AttendanceCorrectionController) RejectCorrection(c *gin.Context) {
+	correctionID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var body correctionDecisionRequest
+	if !ctl.BindJSON(c, &body) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	correction, err := ctl.correctionService.Reject(c.Request.Context(), correctionID, userID, body.Comment)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, correction)
+}
+
+// ListMissingClockOuts 获取未下班打卡的记录
+// @Summary 获取未下班打卡的记录
+// @Description 返回每晚定时任务标记为未下班打卡、且尚未通过补卡更正的记录
+// @Tags 考勤管理
+// @Security Bearer
+// @Produce json
+// @Param department query string false "部门"
+// @Success 200 {object} utils.Response{data=[]models.Attendance}
+// @Router /api/v1/attendance/missing-clock-outs [get]
+func (ctl *AttendanceCorrectionController) ListMissingClockOuts(c *gin.Context) {
+	records, err := ctl.correctionService.ListMissingClockOuts(c.Request.Context(), c.Query("department"))
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, records)
+}
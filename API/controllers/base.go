@@ -1,8 +1,12 @@
 package controllers
 
 import (
+	"errors"
+	"log"
+	"math"
 	"net/http"
 	"strconv"
+	"time"
 
 	"API/utils"
 	"github.com/gin-gonic/gin"
@@ -39,3 +43,66 @@ func (bc *BaseController) GetAuthUser(c *gin.Context) (userID uint, roles []stri
 	roles = c.GetStringSlice("roles")
 	return
 }
+
+// ParseIDParam 解析路径中的ID参数
+func (bc *BaseController) ParseIDParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		utils.RespondError(c, http.StatusBadRequest, "无效的ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ParseYearQuery 解析 year 查询参数，未传时为今年
+func (bc *BaseController) ParseYearQuery(c *gin.Context) (int, bool) {
+	value := c.Query("year")
+	if value == "" {
+		return time.Now().Year(), true
+	}
+	year, err := strconv.Atoi(value)
+	if err != nil || year < 2000 || year > 2100 {
+		utils.RespondError(c, http.StatusBadRequest, "无效的年度")
+		return 0, false
+	}
+	return year, true
+}
+
+// ParseMonthQuery 解析 month 查询参数（YYYY-MM），未传时为本月
+func (bc *BaseController) ParseMonthQuery(c *gin.Context) (string, bool) {
+	month := c.Query("month")
+	if month == "" {
+		return time.Now().Format("2006-01"), true
+	}
+	if _, err := time.Parse("2006-01", month); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "月份格式错误，请使用YYYY-MM格式")
+		return "", false
+	}
+	return month, true
+}
+
+// RespondServiceError 按错误类型返回对应的HTTP状态码，其余错误记录日志后只返回通用提示，
+// 避免把数据库、缓存等内部错误信息暴露给客户端
+func (bc *BaseController) RespondServiceError(c *gin.Context, err error) {
+	var validationErr *utils.ValidationError
+	var notFoundErr *utils.NotFoundError
+	var authErr *utils.AuthError
+	var attemptsErr *utils.TooManyAttemptsError
+	var forbiddenErr *utils.ForbiddenError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.RespondError(c, http.StatusBadRequest, validationErr.Message)
+	case errors.As(err, &notFoundErr):
+		utils.RespondError(c, http.StatusNotFound, notFoundErr.Message)
+	case errors.As(err, &authErr):
+		utils.RespondError(c, http.StatusUnauthorized, authErr.Message)
+	case errors.As(err, &forbiddenErr):
+		utils.RespondError(c, http.StatusForbidden, forbiddenErr.Message)
+	case errors.As(err, &attemptsErr):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(attemptsErr.RetryAfter.Seconds()))))
+		utils.RespondError(c, http.StatusTooManyRequests, attemptsErr.Message)
+	default:
+		log.Printf("%s %s 处理失败: %v", c.Request.Method, c.Request.URL.Path, err)
+		utils.RespondError(c, http.StatusInternalServerError, "服务器内部错误")
+	}
+}
@@ -0,0 +1,337 @@
+package controllers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"API/services"
+	"API/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCalendarFileSize iCalendar 文件大小上限
+const maxCalendarFileSize = 1 << 20
+
+// HolidayController 节假日日历控制器
+type HolidayController struct {
+	BaseController
+	holidayService *services.HolidayService
+	workdays       *services.WorkdayCalculator
+}
+
+func NewHolidayController(s *services.HolidayService, workdays *services.WorkdayCalculator) *HolidayController {
+	return &HolidayController{holidayService: s, workdays: workdays}
+}
+
+type userRegionRequest struct {
+	Region string `json:"region" binding:"max=50"`
+}
+
+// ListCalendars 获取节假日日历
+// @Summary 获取节假日日历
+// @Tags 节假日管理
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.HolidayCalendar}
+// @Router /api/v1/holiday-calendars [get]
+func (ctl *HolidayController) ListCalendars(c *gin.Context) {
+	calendars, err := ctl.holidayService.ListCalendars(c.Request.Context())
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, calendars)
+}
+
+// CreateCalendar 创建节假日日历
+// @Summary 创建节假日日历
+// @Description 每个地区一个日历，员工按工作地区匹配；未设置地区或没有对应日历的员工使用默认日历
+// @Tags 节假日管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body services.CalendarInput true "节假日日历"
+// @Success 200 {object} utils.Response{data=models.HolidayCalendar}
+// @Failure 400 {object} utils.Response "参数无效或名称、地区已存在"
+// @Router /api/v1/holiday-calendars [post]
+func (ctl *HolidayController) CreateCalendar(c *gin.Context) {
+	var input services.CalendarInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	calendar, err := ctl.holidayService.CreateCalendar(c.Request.Context(), input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, calendar)
+}
+
+// UpdateCalendar 修改节假日日历
+// @Summary 修改节假日日历
+// @Tags 节假日管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "日历ID"
+// @Param request body services.CalendarInput true "节假日日历"
+// @Success 200 {object} utils.Response{data=models.HolidayCalendar}
+// @Failure 400 {object} utils.Response "参数无效或名称、地区已存在"
+// @Failure 404 {object} utils.Response "日历不存在"
+// @Router /api/v1/holiday-calendars/{id} [put]
+func (ctl *HolidayController) UpdateCalendar(c *gin.Context) {
+	calendarID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input services.CalendarInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	calendar, err := ctl.holidayService.UpdateCalendar(c.Request.Context(), calendarID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, calendar)
+}
+
+// DeleteCalendar 删除节假日日历
+// @Summary 删除节假日日历
+// @Description 同时删除日历中的全部节假日和调休上班日
+// @Tags 节假日管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "日历ID"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 404 {object} utils.Response "日历不存在"
+// @Router /api/v1/holiday-calendars/{id} [delete]
+func (ctl *HolidayController) DeleteCalendar(c *gin.Context) {
+	calendarID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ctl.holidayService.DeleteCalendar(c.Request.Context(), calendarID); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "节假日日历已删除"})
+}
+
+// ListHolidays 获取日历中的节假日
+// @Summary 获取日历中的节假日
+// @Tags 节假日管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "日历ID"
+// @Param year query int false "年度，默认今年"
+// @Success 200 {object} utils.Response{data=[]models.Holiday}
+// @Failure 400 {object} utils.Response "年度无效"
+// @Failure 404 {object} utils.Response "日历不存在"
+// @Router /api/v1/holiday-calendars/{id}/holidays [get]
+func (ctl *HolidayController) ListHolidays(c *gin.Context) {
+	calendarID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	year, ok := ctl.ParseYearQuery(c)
+	if !ok {
+		return
+	}
+	holidays, err := ctl.holidayService.ListHolidays(c.Request.Context(), calendarID, year)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, holidays)
+}
+
+// SetHoliday 设置节假日或调休上班日
+// @Summary 设置节假日或调休上班日
+// @Description kind 为 holiday 表示放假，workday 表示调休上班（即使是周末也需上班）；该日期已有设置时覆盖
+// @Tags 节假日管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "日历ID"
+// @Param request body services.HolidayInput true "日期设置"
+// @Success 200 {object} utils.Response{data=models.Holiday}
+// @Failure 400 {object} utils.Response "参数无效"
+// @Failure 404 {object} utils.Response "日历不存在"
+// @Router /api/v1/holiday-calendars/{id}/holidays [post]
+func (ctl *HolidayController) SetHoliday(c *gin.Context) {
+	calendarID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input services.HolidayInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	holiday, err := ctl.holidayService.SetHoliday(c.Request.Context(), calendarID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, holiday)
+}
+
+// DeleteHoliday 删除节假日设置
+// @Summary 删除节假日设置
+// @Description 删除后该日期恢复按班次的工作日判断
+// @Tags 节假日管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "日历ID"
+// @Param holiday_id path int true "节假日ID"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 404 {object} utils.Response "节假日不存在"
+// @Router /api/v1/holiday-calendars/{id}/holidays/{holiday_id} [delete]
+func (ctl *HolidayController) DeleteHoliday(c *gin.Context) {
+	calendarID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	holidayID, ok := ctl.ParseIDParam(c, "holiday_id")
+	if !ok {
+		return
+	}
+	if err := ctl.holidayService.DeleteHoliday(c.Request.Context(), calendarID, holidayID); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "节假日设置已删除"})
+}
+
+// ImportHolidays 从 iCalendar 文件导入节假日
+// @Summary 导入节假日
+// @Description 导入 .ics 文件中的全天事件，多日事件按日期展开；标题包含调休上班关键字（默认“补班”“上班”）的事件导入为调休上班日，其余为放假。已有设置的日期会被覆盖
+// @Tags 节假日管理
+// @Security Bearer
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "日历ID"
+// @Param file formData file true "iCalendar 文件，不超过1MB"
+// @Success 200 {object} utils.Response{data=services.HolidayImportResult}
+// @Failure 400 {object} utils.Response "文件无效"
+// @Failure 404 {object} utils.Response "日历不存在"
+// @Router /api/v1/holiday-calendars/{id}/import [post]
+func (ctl *HolidayController) ImportHolidays(c *gin.Context) {
+	calendarID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "请上传 iCalendar 文件")
+		return
+	}
+	if header.Size > maxCalendarFileSize {
+		utils.RespondError(c, http.StatusBadRequest, fmt.Sprintf("iCalendar 文件不能超过%dMB", maxCalendarFileSize>>20))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "无法读取 iCalendar 文件")
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxCalendarFileSize))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "无法读取 iCalendar 文件")
+		return
+	}
+
+	result, err := ctl.holidayService.ImportICS(c.Request.Context(), calendarID, data)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, result)
+}
+
+// SetUserRegion 设置员工的工作地区
+// @Summary 设置员工的工作地区
+// @Description 工作地区用于匹配节假日日历，为空时使用默认日历
+// @Tags 节假日管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "员工ID"
+// @Param request body userRegionRequest true "工作地区"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 404 {object} utils.Response "员工不存在"
+// @Router /api/v1/users/{id}/region [put]
+func (ctl *HolidayController) SetUserRegion(c *gin.Context) {
+	userID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var request userRegionRequest
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	if err := ctl.holidayService.SetUserRegion(c.Request.Context(), userID, request.Region); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "工作地区已更新"})
+}
+
+// GetMyHolidays 获取我的节假日
+// @Summary 获取我的节假日
+// @Description 返回当前用户适用的节假日日历中某年度的放假和调休上班日期
+// @Tags 节假日管理
+// @Security Bearer
+// @Produce json
+// @Param year query int false "年度，默认今年"
+// @Success 200 {object} utils.Response{data=[]models.Holiday}
+// @Failure 400 {object} utils.Response "年度无效"
+// @Router /api/v1/holidays [get]
+func (ctl *HolidayController) GetMyHolidays(c *gin.Context) {
+	year, ok := ctl.ParseYearQuery(c)
+	if !ok {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	holidays, err := ctl.holidayService.MyHolidays(c.Request.Context(), userID, year)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, holidays)
+}
+
+// GetMyWorkdays 计算我的工作日
+// @Summary 计算我的工作日
+// @Description 按当前用户的班次和节假日日历计算期间内的工作日，期间不超过一年
+// @Tags 节假日管理
+// @Security Bearer
+// @Produce json
+// @Param start query string true "开始日期，YYYY-MM-DD"
+// @Param end query string true "结束日期（含），YYYY-MM-DD"
+// @Success 200 {object} utils.Response{data=services.WorkdaySummary}
+// @Failure 400 {object} utils.Response "日期格式错误或期间过长"
+// @Router /api/v1/workdays [get]
+func (ctl *HolidayController) GetMyWorkdays(c *gin.Context) {
+	start, err := time.ParseInLocation("2006-01-02", c.Query("start"), time.Local)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "开始日期格式错误，请使用YYYY-MM-DD格式")
+		return
+	}
+	end, err := time.ParseInLocation("2006-01-02", c.Query("end"), time.Local)
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "结束日期格式错误，请使用YYYY-MM-DD格式")
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	summary, err := ctl.workdays.Summarize(c.Request.Context(), userID, start, end)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, summary)
+}
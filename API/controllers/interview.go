@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"API/services"
+	"API/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InterviewController 面试控制器
+type InterviewController struct {
+	BaseController
+	interviewService *services.InterviewService
+}
+
+func NewInterviewController(is *services.InterviewService) *InterviewController {
+	return &InterviewController{interviewService: is}
+}
+
+// ScheduleInterview 为申请安排面试
+// @Summary 为申请安排面试
+// @Description 指定时间、地点和一名或多名面试官；面试官在该时段已有面试或已报名培训时返回冲突信息
+// @Tags 面试管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "申请ID"
+// @Param request body services.InterviewInput true "面试安排"
+// @Success 200 {object} utils.Response{data=models.Interview}
+// @Failure 400 {object} utils.Response "时间冲突或参数无效"
+// @Failure 404 {object} utils.Response "申请记录不存在"
+// @Router /api/v1/applications/{id}/interviews [post]
+func (ctl *InterviewController) ScheduleInterview(c *gin.Context) {
+	applicationID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input services.InterviewInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+
+	operatorID, _ := ctl.GetAuthUser(c)
+	interview, err := ctl.interviewService.ScheduleInterview(c.Request.Context(), applicationID, operatorID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, interview)
+}
+
+// ListApplicationInterviews 获取申请的面试与评分
+// @Summary 获取申请的面试与评分
+// @Description 返回申请的全部面试、面试官评分表，以及按推荐意见和考察项汇总的结果
+// @Tags 面试管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "申请ID"
+// @Success 200 {object} utils.Response{data=services.ApplicationInterviews}
+// @Failure 404 {object} utils.Response "申请记录不存在"
+// @Router /api/v1/applications/{id}/interviews [get]
+func (ctl *InterviewController) ListApplicationInterviews(c *gin.Context) {
+	applicationID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	result, err := ctl.interviewService.ListApplicationInterviews(c.Request.Context(), applicationID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, result)
+}
+
+// RescheduleInterview 调整面试
+// @Summary 调整面试
+// @Description 修改待进行且尚无评分的面试的时间、地点和面试官
+// @Tags 面试管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "面试ID"
+// @Param request body services.InterviewInput true "面试安排"
+// @Success 200 {object} utils.Response{data=models.Interview}
+// @Failure 400 {object} utils.Response "时间冲突或面试状态不允许"
+// @Failure 404 {object} utils.Response "面试不存在"
+// @Router /api/v1/interviews/{id} [put]
+func (ctl *InterviewController) RescheduleInterview(c *gin.Context) {
+	interviewID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input services.InterviewInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	interview, err := ctl.interviewService.RescheduleInterview(c.Request.Context(), interviewID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, interview)
+}
+
+// CancelInterview 取消面试
+// @Summary 取消面试
+// @Description 取消待进行的面试，已取消面试的评分不计入汇总
+// @Tags 面试管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "面试ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response "面试状态不允许取消"
+// @Failure 404 {object} utils.Response "面试不存在"
+// @Router /api/v1/interviews/{id}/cancel [post]
+func (ctl *InterviewController) CancelInterview(c *gin.Context) {
+	interviewID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ctl.interviewService.CancelInterview(c.Request.Context(), interviewID); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, nil)
+}
+
+// GetMyInterviews 获取我的待进行面试
+// @Summary 获取我的待进行面试
+// @Description 返回当前用户作为面试官尚未结束的面试
+// @Tags 面试管理
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.Interview}
+// @Router /api/v1/interviews/my [get]
+func (ctl *InterviewController) GetMyInterviews(c *gin.Context) {
+	userID, _ := ctl.GetAuthUser(c)
+	interviews, err := ctl.interviewService.ListMyInterviews(c.Request.Context(), userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, interviews)
+}
+
+// SubmitScorecard 提交面试评分表
+// @Summary 提交面试评分表
+// @Description 面试官在面试开始后提交或修改本人的评分表，包含推荐意见和各考察项1-5分的评分
+// @Tags 面试管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "面试ID"
+// @Param request body services.ScorecardInput true "评分表"
+// @Success 200 {object} utils.Response{data=models.Scorecard}
+// @Failure 400 {object} utils.Response "参数无效或面试状态不允许"
+// @Failure 403 {object} utils.Response "不是该面试的面试官"
+// @Failure 404 {object} utils.Response "面试不存在"
+// @Router /api/v1/interviews/{id}/scorecard [post]
+func (ctl *InterviewController) SubmitScorecard(c *gin.Context) {
+	interviewID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input services.ScorecardInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+
+	userID, _ := ctl.GetAuthUser(c)
+	scorecard, err := ctl.interviewService.SubmitScorecard(c.Request.Context(), interviewID, userID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, scorecard)
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"API/models"
 	"API/services"
@@ -12,6 +13,7 @@ import (
 )
 
 type JobController struct {
+	BaseController
 	jobService *services.JobService
 }
 
@@ -19,46 +21,78 @@ func NewJobController(js *services.JobService) *JobController {
 	return &JobController{jobService: js}
 }
 
+// jobRequest 创建或更新职位时提交的信息，更新时未提供的字段保持不变
+type jobRequest struct {
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	Requirements   string     `json:"requirements"`
+	SalaryMin      *float64   `json:"salary_min" binding:"omitempty,gte=0"`
+	SalaryMax      *float64   `json:"salary_max" binding:"omitempty,gte=0"`
+	SalaryCurrency string     `json:"salary_currency"` // ISO 4217 代码，新建时默认 CNY
+	SalaryPeriod   string     `json:"salary_period"`   // hour、day、month、year，新建时默认 month
+	ExpirationDate *time.Time `json:"expiration_date"`
+	Category       string     `json:"category" binding:"max=50"`
+	Location       string     `json:"location" binding:"max=100"`
+	Experience     uint       `json:"experience"`
+	Skills         []string   `json:"skills" binding:"omitempty,max=50,dive,max=50"` // 技能要求，更新时传空数组表示清空
+}
+
+func (r jobRequest) toModel() *models.Job {
+	job := &models.Job{
+		Title:          r.Title,
+		Description:    r.Description,
+		Requirements:   r.Requirements,
+		SalaryMin:      r.SalaryMin,
+		SalaryMax:      r.SalaryMax,
+		SalaryCurrency: r.SalaryCurrency,
+		SalaryPeriod:   r.SalaryPeriod,
+		ExpirationDate: r.ExpirationDate,
+		Category:       r.Category,
+		Location:       r.Location,
+		Experience:     r.Experience,
+	}
+	if r.Skills != nil {
+		job.Skills = make([]models.Skill, 0, len(r.Skills))
+		for _, name := range r.Skills {
+			job.Skills = append(job.Skills, models.Skill{Name: name})
+		}
+	}
+	return job
+}
+
 // CreateJob 创建新职位
 // @Summary 创建新职位
-// @Description 创建一个新的职位信息
+// @Description 创建草稿职位，分类、地点、工作年限和薪资用于公开职位搜索的筛选，技能要求用于候选人匹配；发起招聘需求并审批通过后方可发布
 // @Tags 职位管理
 // @Accept json
 // @Produce json
-// @Param job body struct{Title string `json:"title" binding:"required"` Description string `json:"description"`} true "职位信息"
-// @Success 200 {object} utils.Response
+// @Param job body jobRequest true "职位信息"
+// @Success 200 {object} utils.Response{data=models.Job}
 // @Failure 400 {object} utils.Response "无效的请求参数"
 // @Failure 500 {object} utils.Response "服务器内部错误"
 // @Router /api/v1/jobs [post]
 func (ctl *JobController) CreateJob(c *gin.Context) {
-	var job struct {
-		Title       string `json:"title" binding:"required"`
-		Description string `json:"description"`
-	}
-
-	if err := c.ShouldBindJSON(&job); err != nil {
+	var job jobRequest
+	if err := c.ShouldBindJSON(&job); err != nil || job.Title == "" {
 		utils.RespondError(c, http.StatusBadRequest, "无效的请求参数")
 		return
 	}
 
-	err := ctl.jobService.CreateJob(c.Request.Context(), &models.Job{
-		Title:       job.Title,
-		Description: job.Description,
-	})
-
-	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+	model := job.toModel()
+	if err := ctl.jobService.CreateJob(c.Request.Context(), model); err != nil {
+		ctl.RespondServiceError(c, err)
 		return
 	}
 
-	utils.RespondSuccess(c, nil)
+	utils.RespondSuccess(c, model)
 }
 
 // ListJobs 获取职位列表
 // @Summary 获取职位列表
-// @Description 获取所有开放的职位列表
+// @Description 按状态获取职位列表，默认返回开放的职位
 // @Tags 职位管理
 // @Produce json
+// @Param status query string false "职位状态" Enums(draft, pending_approval, open, closed) default(open)
 // @Param page query int false "页码" default(1)
 // @Param pageSize query int false "每页数量" default(10)
 // @Success 200 {object} utils.Response{data=[]models.Job,total=int}
@@ -68,7 +102,7 @@ func (ctl *JobController) ListJobs(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
 
-	jobs, total, err := ctl.jobService.GetOpenJobs(c.Request.Context(), page, pageSize)
+	jobs, total, err := ctl.jobService.GetJobs(c.Request.Context(), c.Query("status"), page, pageSize)
 	if err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, err.Error())
 		return
@@ -80,14 +114,70 @@ func (ctl *JobController) ListJobs(c *gin.Context) {
 	})
 }
 
+// SearchJobs 公开职位搜索
+// @Summary 公开职位搜索
+// @Description 无需登录。按关键词全文搜索开放中的职位，支持分类、地点、工作年限和薪资区间筛选，返回分类和地点的分面统计
+// @Tags 职位管理
+// @Produce json
+// @Param q query string false "关键词，匹配职位名称、描述和要求"
+// @Param category query string false "职位分类"
+// @Param location query string false "工作地点"
+// @Param experience query int false "求职者工作年限，仅返回要求不超过该年限的职位"
+// @Param salary_min query number false "期望最低月薪，按职位的薪资周期折算为月薪后比较"
+// @Param salary_max query number false "期望最高月薪"
+// @Param currency query string false "薪资币种，按薪资筛选时生效" default(CNY)
+// @Param sort query string false "排序方式" Enums(relevance, recent)
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Success 200 {object} utils.Response{data=services.JobSearchResult}
+// @Failure 400 {object} utils.Response "无效的查询参数"
+// @Router /api/v1/job-board [get]
+func (ctl *JobController) SearchJobs(c *gin.Context) {
+	var query services.JobSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "无效的查询参数: "+err.Error())
+		return
+	}
+	query.Page, query.Size = ctl.ParsePagination(c)
+
+	result, err := ctl.jobService.SearchJobs(c.Request.Context(), query)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, result)
+}
+
+// GetPublicJob 公开职位详情
+// @Summary 公开职位详情
+// @Description 无需登录，获取开放中的职位详情
+// @Tags 职位管理
+// @Produce json
+// @Param id path int true "职位ID"
+// @Success 200 {object} utils.Response{data=models.Job}
+// @Failure 404 {object} utils.Response "职位不存在或已关闭"
+// @Router /api/v1/job-board/{id} [get]
+func (ctl *JobController) GetPublicJob(c *gin.Context) {
+	jobID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	job, err := ctl.jobService.GetPublicJob(c.Request.Context(), jobID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, job)
+}
+
 // UpdateJob 更新职位信息
 // @Summary 更新职位信息
-// @Description 更新指定职位的信息
+// @Description 更新指定职位的信息，薪资与原有值合并后校验；职位状态不在此修改
 // @Tags 职位管理
 // @Accept json
 // @Produce json
 // @Param id path int true "职位ID"
-// @Param job body struct{Title string `json:"title"` Description string `json:"description"`} true "职位信息"
+// @Param job body jobRequest true "职位信息"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response "无效的请求参数"
 // @Failure 500 {object} utils.Response "服务器内部错误"
@@ -99,49 +189,104 @@ func (ctl *JobController) UpdateJob(c *gin.Context) {
 		return
 	}
 
-	var job struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
-	}
-
+	var job jobRequest
 	if err := c.ShouldBindJSON(&job); err != nil {
 		utils.RespondError(c, http.StatusBadRequest, "无效的请求参数")
 		return
 	}
 
-	err = ctl.jobService.UpdateJob(c.Request.Context(), uint(id), &models.Job{
-		Title:       job.Title,
-		Description: job.Description,
-	})
+	err = ctl.jobService.UpdateJob(c.Request.Context(), uint(id), job.toModel())
 
 	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		ctl.RespondServiceError(c, err)
 		return
 	}
 
 	utils.RespondSuccess(c, nil)
 }
 
+// ReopenJob 重新开放职位
+// @Summary 重新开放职位
+// @Description 重新开放已关闭或已过期的职位，必须设置晚于当前时间的新截止日期
+// @Tags 职位管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "职位ID"
+// @Param request body struct{ExpirationDate time.Time `json:"expiration_date" binding:"required"`} true "新的截止日期"
+// @Success 200 {object} utils.Response{data=models.Job}
+// @Failure 400 {object} utils.Response "截止日期无效或职位正在开放中"
+// @Failure 404 {object} utils.Response "职位不存在"
+// @Router /api/v1/jobs/{id}/reopen [post]
+func (ctl *JobController) ReopenJob(c *gin.Context) {
+	jobID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var request struct {
+		ExpirationDate time.Time `json:"expiration_date" binding:"required"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	job, err := ctl.jobService.ReopenJob(c.Request.Context(), jobID, request.ExpirationDate)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, job)
+}
+
+// PublishJob 发布职位
+// @Summary 发布职位
+// @Description 发布招聘需求已审批通过的草稿职位，发布后出现在公开职位列表中
+// @Tags 职位管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "职位ID"
+// @Success 200 {object} utils.Response{data=models.Job}
+// @Failure 400 {object} utils.Response "招聘需求未审批通过或职位已发布"
+// @Failure 404 {object} utils.Response "职位不存在"
+// @Router /api/v1/jobs/{id}/publish [post]
+func (ctl *JobController) PublishJob(c *gin.Context) {
+	jobID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	job, err := ctl.jobService.PublishJob(c.Request.Context(), jobID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, job)
+}
+
 // ApplyForJob 申请职位
 // @Summary 申请职位
-// @Description 用户申请指定职位
+// @Description 用户申请指定职位，可指定附带的简历和求职信，未指定简历时附带本人当前简历；申请固定投递时的简历版本，未指定版本时使用最新版本
 // @Tags 职位管理
 // @Security Bearer
+// @Accept json
 // @Produce json
 // @Param id path int true "职位ID"
+// @Param request body services.ApplyInput false "简历和求职信"
 // @Success 200 {object} utils.Response{message=string}
-// @Failure 400 {object} utils.Response "无效的职位ID"
+// @Failure 400 {object} utils.Response "职位已关闭、重复申请或简历无效"
+// @Failure 404 {object} utils.Response "职位不存在"
 // @Failure 500 {object} utils.Response "服务器内部错误"
 // @Router /api/v1/jobs/{id}/apply [post]
 func (ctl *JobController) ApplyForJob(c *gin.Context) {
-	userID, _ := c.Get("userID") // 从认证中间件获取用户ID
-	jobID, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "无效的职位ID")
+	userID, _ := ctl.GetAuthUser(c)
+	jobID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
 		return
 	}
-	if err := ctl.jobService.ApplyForJob(c.Request.Context(), userID.(uint), uint(jobID)); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+	var input services.ApplyInput
+	if c.Request.ContentLength > 0 && !ctl.BindJSON(c, &input) {
+		return
+	}
+	if err := ctl.jobService.ApplyForJob(c.Request.Context(), userID, jobID, input); err != nil {
+		ctl.RespondServiceError(c, err)
 		return
 	}
 	utils.RespondSuccess(c, gin.H{"message": "申请成功"})
@@ -0,0 +1,270 @@
+package controllers
+
+import (
+	"API/services"
+	"API/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LeaveController 请假管理控制器
+type LeaveController struct {
+	BaseController
+	leaveService *services.LeaveService
+}
+
+func NewLeaveController(s *services.LeaveService) *LeaveController {
+	return &LeaveController{leaveService: s}
+}
+
+type leaveDecisionRequest struct {
+	Comment string `json:"comment" binding:"max=500"`
+}
+
+// ListLeaveTypes 获取假期类型
+// @Summary 获取假期类型
+// @Tags 请假管理
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.LeaveType}
+// @Router /api/v1/leave/types [get]
+func (ctl *LeaveController) ListLeaveTypes(c *gin.Context) {
+	types, err := ctl.leaveService.ListTypes(c.Request.Context())
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, types)
+}
+
+// UpdateLeaveType 修改假期类型的额度规则
+// @Summary 修改假期类型
+// @Description 修改每月累计天数、额度上限和年度结转上限，之后的累计按新规则计算，已累计的额度不变
+// @Tags 请假管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "假期类型ID"
+// @Param request body services.LeaveTypeInput true "额度规则"
+// @Success 200 {object} utils.Response{data=models.LeaveType}
+// @Failure 400 {object} utils.Response "参数无效"
+// @Failure 404 {object} utils.Response "假期类型不存在"
+// @Router /api/v1/leave-types/{id} [put]
+func (ctl *LeaveController) UpdateLeaveType(c *gin.Context) {
+	typeID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input services.LeaveTypeInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	leaveType, err := ctl.leaveService.UpdateType(c.Request.Context(), typeID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, leaveType)
+}
+
+// GetMyBalances 获取我的假期额度
+// @Summary 获取我的假期额度
+// @Description 返回各类假期的上年结转、本年累计、已用、待审批和可用天数，额度按月累计至当前月份
+// @Tags 请假管理
+// @Security Bearer
+// @Produce json
+// @Param year query int false "年度，默认今年"
+// @Success 200 {object} utils.Response{data=[]services.LeaveBalanceSummary}
+// @Failure 400 {object} utils.Response "年度无效"
+// @Router /api/v1/leave/balances [get]
+func (ctl *LeaveController) GetMyBalances(c *gin.Context) {
+	year, ok := ctl.ParseYearQuery(c)
+	if !ok {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	balances, err := ctl.leaveService.GetBalances(c.Request.Context(), userID, year)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, balances)
+}
+
+// GetUserBalances 获取员工的假期额度
+// @Summary 获取员工的假期额度
+// @Tags 请假管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "员工ID"
+// @Param year query int false "年度，默认今年"
+// @Success 200 {object} utils.Response{data=[]services.LeaveBalanceSummary}
+// @Failure 400 {object} utils.Response "年度无效"
+// @Failure 404 {object} utils.Response "员工不存在"
+// @Router /api/v1/users/{id}/leave-balances [get]
+func (ctl *LeaveController) GetUserBalances(c *gin.Context) {
+	userID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	year, ok := ctl.ParseYearQuery(c)
+	if !ok {
+		return
+	}
+	balances, err := ctl.leaveService.GetBalances(c.Request.Context(), userID, year)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, balances)
+}
+
+// SubmitLeaveRequest 提交请假申请
+// @Summary 提交请假申请
+// @Description 按员工班次和节假日日历计算请假的工作日天数，不能与待审批或已批准的申请重叠，跨年度需分别申请；占用额度的假期需有足够的可用天数
+// @Tags 请假管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body services.LeaveRequestInput true "请假申请"
+// @Success 200 {object} utils.Response{data=models.LeaveRequest}
+// @Failure 400 {object} utils.Response "参数无效、时间重叠或余额不足"
+// @Router /api/v1/leave/requests [post]
+func (ctl *LeaveController) SubmitLeaveRequest(c *gin.Context) {
+	var input services.LeaveRequestInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	request, err := ctl.leaveService.Submit(c.Request.Context(), userID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, request)
+}
+
+// GetMyLeaveRequests 获取我的请假申请
+// @Summary 获取我的请假申请
+// @Tags 请假管理
+// @Security Bearer
+// @Produce json
+// @Param status query string false "状态：pending/approved/rejected/canceled"
+// @Success 200 {object} utils.Response{data=[]models.LeaveRequest}
+// @Router /api/v1/leave/requests/my [get]
+func (ctl *LeaveController) GetMyLeaveRequests(c *gin.Context) {
+	userID, _ := ctl.GetAuthUser(c)
+	requests, err := ctl.leaveService.ListMyRequests(c.Request.Context(), userID, c.Query("status"))
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, requests)
+}
+
+// CancelLeaveRequest 撤销请假申请
+// @Summary 撤销请假申请
+// @Description 待审批的申请可随时撤销；已批准的申请仅在假期开始前可撤销，并退回已扣减的额度
+// @Tags 请假管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "请假申请ID"
+// @Success 200 {object} utils.Response{data=models.LeaveRequest}
+// @Failure 400 {object} utils.Response "假期已开始或申请已结束"
+// @Failure 404 {object} utils.Response "请假申请不存在"
+// @Router /api/v1/leave/requests/{id}/cancel [post]
+func (ctl *LeaveController) CancelLeaveRequest(c *gin.Context) {
+	requestID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	request, err := ctl.leaveService.Cancel(c.Request.Context(), requestID, userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, request)
+}
+
+// ListPendingLeaveRequests 获取待我审批的请假申请
+// @Summary 获取待我审批的请假申请
+// @Description 人事可审批全部员工的请假，部门负责人仅限本部门员工
+// @Tags 请假管理
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.LeaveRequest}
+// @Router /api/v1/leave/requests/pending [get]
+func (ctl *LeaveController) ListPendingLeaveRequests(c *gin.Context) {
+	userID, _ := ctl.GetAuthUser(c)
+	requests, err := ctl.leaveService.ListPendingForApprover(c.Request.Context(), userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, requests)
+}
+
+// ApproveLeaveRequest 批准请假申请
+// @Summary 批准请假申请
+// @Description 审批人须为员工所在部门的负责人或人事，且不能审批自己的申请；批准后扣减假期额度
+// @Tags 请假管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "请假申请ID"
+// @Param request body leaveDecisionRequest false "审批意见"
+// @Success 200 {object} utils.Response{data=models.LeaveRequest}
+// @Failure 400 {object} utils.Response "申请已处理或余额不足"
+// @Failure 403 {object} utils.Response "不是该员工的审批人"
+// @Failure 404 {object} utils.Response "请假申请不存在"
+// @Router /api/v1/leave/requests/{id}/approve [post]
+func (ctl *LeaveController) ApproveLeaveRequest(c *gin.Context) {
+	requestID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var body leaveDecisionRequest
+	if c.Request.ContentLength != 0 && !ctl.BindJSON(c, &body) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	request, err := ctl.leaveService.Approve(c.Request.Context(), requestID, userID, body.Comment)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, request)
+}
+
+// RejectLeaveRequest 驳回请假申请
+// @Summary 驳回请假申请
+// @Description 驳回须填写审批意见
+// @Tags 请假管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "请假申请ID"
+// @Param request body leaveDecisionRequest true "审批意见"
+// @Success 200 {object} utils.Response{data=models.LeaveRequest}
+// @Failure 400 {object} utils.Response "未填写审批意见或申请已处理"
+// @Failure 403 {object} utils.Response "不是该员工的审批人"
+// @Failure 404 {object} utils.Response "请假申请不存在"
+// @Router /api/v1/leave/requests/{id}/reject [post]
+func (ctl *LeaveController) RejectLeaveRequest(c *gin.Context) {
+	requestID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var body leaveDecisionRequest
+	if !ctl.BindJSON(c, &body) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	request, err := ctl.leaveService.Reject(c.Request.Context(), requestID, userID, body.Comment)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, request)
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"strconv"
+
+	"API/services"
+	"API/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MatchingController 候选人与职位匹配控制器
+type MatchingController struct {
+	BaseController
+	matchingService *services.MatchingService
+}
+
+func NewMatchingController(s *services.MatchingService) *MatchingController {
+	return &MatchingController{matchingService: s}
+}
+
+// parseLimit 解析返回条数，默认20，最多100
+func parseLimit(c *gin.Context) int {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		return 20
+	}
+	return limit
+}
+
+// RankCandidates 按匹配度排列职位的候选人
+// @Summary 职位候选人匹配排名
+// @Description 按技能重合、工作年限、期望薪资和工作地点计算匹配度并排序，返回各项得分说明；职位未设置技能要求时从职位要求文本中识别
+// @Tags 职位匹配
+// @Security Bearer
+// @Produce json
+// @Param id path int true "职位ID"
+// @Param scope query string false "候选范围：applicants 仅申请人（按投递时固定的简历版本），all 填写了技能的有效用户" Enums(applicants, all) default(applicants)
+// @Param limit query int false "返回条数" default(20)
+// @Success 200 {object} utils.Response{data=[]services.CandidateMatch}
+// @Failure 404 {object} utils.Response "职位不存在"
+// @Router /api/v1/jobs/{id}/candidates [get]
+func (ctl *MatchingController) RankCandidates(c *gin.Context) {
+	jobID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	matches, err := ctl.matchingService.RankCandidates(c.Request.Context(), jobID, c.Query("scope"), parseLimit(c))
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, matches)
+}
+
+// RecommendJobs 推荐职位
+// @Summary 推荐职位
+// @Description 按当前用户简历与开放职位的匹配度推荐尚未申请的职位
+// @Tags 职位匹配
+// @Security Bearer
+// @Produce json
+// @Param limit query int false "返回条数" default(20)
+// @Success 200 {object} utils.Response{data=[]services.JobMatch}
+// @Failure 404 {object} utils.Response "尚未填写简历"
+// @Router /api/v1/jobs/recommended [get]
+func (ctl *MatchingController) RecommendJobs(c *gin.Context) {
+	userID, _ := ctl.GetAuthUser(c)
+	matches, err := ctl.matchingService.RecommendJobs(c.Request.Context(), userID, parseLimit(c))
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, matches)
+}
+
+// MatchJob 查看与职位的匹配度
+// @Summary 查看与职位的匹配度
+// @Description 计算当前用户简历与开放职位的匹配度及各项得分说明
+// @Tags 职位匹配
+// @Security Bearer
+// @Produce json
+// @Param id path int true "职位ID"
+// @Success 200 {object} utils.Response{data=services.MatchResult}
+// @Failure 404 {object} utils.Response "职位不存在或尚未填写简历"
+// @Router /api/v1/jobs/{id}/match [get]
+func (ctl *MatchingController) MatchJob(c *gin.Context) {
+	jobID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	result, err := ctl.matchingService.MatchJob(c.Request.Context(), userID, jobID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, result)
+}
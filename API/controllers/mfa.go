@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"API/services"
+	"API/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MFAController 两步验证控制器
+type MFAController struct {
+	BaseController
+	userService *services.UserService
+	mfaService  *services.MFAService
+}
+
+// NewMFAController 初始化两步验证控制器
+func NewMFAController(us *services.UserService, ms *services.MFAService) *MFAController {
+	return &MFAController{userService: us, mfaService: ms}
+}
+
+// VerifyLogin 登录第二步验证
+// @Summary 登录第二步验证
+// @Description 使用登录返回的 mfa_token 和验证器验证码（或恢复码）换取令牌；需先绑定的账户在此完成绑定并返回恢复码
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body struct{MFAToken string `json:"mfa_token" binding:"required"` Code string `json:"code" binding:"required"`} true "验证信息"
+// @Success 200 {object} utils.Response{data=services.LoginResult}
+// @Failure 400 {object} utils.Response "无效的请求参数"
+// @Failure 401 {object} utils.Response "验证码错误或登录验证已过期"
+// @Failure 429 {object} utils.Response "验证码错误次数过多"
+// @Router /api/v1/auth/mfa/verify [post]
+func (ctl *MFAController) VerifyLogin(c *gin.Context) {
+	var request struct {
+		MFAToken string `json:"mfa_token" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	result, err := ctl.userService.CompleteMFALogin(c.Request.Context(), request.MFAToken, request.Code)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, result)
+}
+
+// EnrollLogin 登录过程中绑定验证器
+// @Summary 登录过程中绑定验证器
+// @Description 角色策略要求两步验证但尚未绑定时，使用 mfa_token 获取 TOTP 密钥，绑定后调用第二步验证接口完成登录
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body struct{MFAToken string `json:"mfa_token" binding:"required"`} true "登录返回的 mfa_token"
+// @Success 200 {object} utils.Response{data=services.MFASetup}
+// @Failure 400 {object} utils.Response "该账户已绑定两步验证"
+// @Failure 401 {object} utils.Response "登录验证已过期"
+// @Router /api/v1/auth/mfa/enroll [post]
+func (ctl *MFAController) EnrollLogin(c *gin.Context) {
+	var request struct {
+		MFAToken string `json:"mfa_token" binding:"required"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	setup, err := ctl.mfaService.EnrollChallenge(c.Request.Context(), request.MFAToken)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, setup)
+}
+
+// Setup 生成两步验证密钥
+// @Summary 生成两步验证密钥
+// @Description 为当前用户生成 TOTP 密钥及 otpauth 地址，需调用启用接口确认后才生效
+// @Tags 认证
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=services.MFASetup}
+// @Failure 400 {object} utils.Response "已启用两步验证"
+// @Failure 401 {object} utils.Response "未授权"
+// @Router /api/v1/auth/mfa/setup [post]
+func (ctl *MFAController) Setup(c *gin.Context) {
+	userID, _ := ctl.GetAuthUser(c)
+	setup, err := ctl.mfaService.Setup(c.Request.Context(), userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, setup)
+}
+
+// Enable 启用两步验证
+// @Summary 启用两步验证
+// @Description 使用验证器中的验证码确认绑定，返回的恢复码仅显示一次
+// @Tags 认证
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body struct{Code string `json:"code" binding:"required"`} true "验证码"
+// @Success 200 {object} utils.Response{data=object}
+// @Failure 400 {object} utils.Response "请先生成两步验证密钥"
+// @Failure 401 {object} utils.Response "验证码错误"
+// @Router /api/v1/auth/mfa/enable [post]
+func (ctl *MFAController) Enable(c *gin.Context) {
+	var request struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	codes, err := ctl.mfaService.Enable(c.Request.Context(), userID, request.Code)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"recovery_codes": codes})
+}
+
+// Disable 停用两步验证
+// @Summary 停用两步验证
+// @Description 校验验证码或恢复码后停用两步验证，角色策略强制启用时不可停用
+// @Tags 认证
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body struct{Code string `json:"code" binding:"required"`} true "验证码或恢复码"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "未启用或不可停用"
+// @Failure 401 {object} utils.Response "验证码错误"
+// @Router /api/v1/auth/mfa/disable [post]
+func (ctl *MFAController) Disable(c *gin.Context) {
+	var request struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	if err := ctl.mfaService.Disable(c.Request.Context(), userID, request.Code); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "两步验证已停用"})
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+// @Summary 重新生成恢复码
+// @Description 校验验证码后生成新的恢复码，旧恢复码全部作废
+// @Tags 认证
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body struct{Code string `json:"code" binding:"required"`} true "验证码或恢复码"
+// @Success 200 {object} utils.Response{data=object}
+// @Failure 400 {object} utils.Response "未启用两步验证"
+// @Failure 401 {object} utils.Response "验证码错误"
+// @Router /api/v1/auth/mfa/recovery-codes [post]
+func (ctl *MFAController) RegenerateRecoveryCodes(c *gin.Context) {
+	var request struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	codes, err := ctl.mfaService.RegenerateRecoveryCodes(c.Request.Context(), userID, request.Code)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"recovery_codes": codes})
+}
+
+// ResetUserMFA 重置用户的两步验证
+// @Summary 重置用户的两步验证
+// @Description 清除指定用户的 TOTP 密钥和恢复码并吊销其令牌，用于设备丢失等情况
+// @Tags 用户管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "无效的ID"
+// @Failure 404 {object} utils.Response "用户不存在"
+// @Router /api/v1/users/{id}/mfa/reset [post]
+func (ctl *MFAController) ResetUserMFA(c *gin.Context) {
+	userID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ctl.mfaService.Reset(c.Request.Context(), userID); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "两步验证已重置"})
+}
@@ -149,7 +149,7 @@ func (ctl *NoticeController) UpdateNotice(c *gin.Context) {
 // @Failure 400 {object} utils.Response "无效的通知ID"
 // @Failure 401 {object} utils.Response "未授权的请求"
 // @Failure 500 {object} utils.Response "服务器内部错误"
-// @Router /api/v1/notices/{id}/read [post]
+// @Router /api/v1/notices/{id}/read [put]
 func (ctl *NoticeController) MarkNoticeAsRead(c *gin.Context) {
 	noticeID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -164,7 +164,7 @@ func (ctl *NoticeController) MarkNoticeAsRead(c *gin.Context) {
 		return
 	}
 	
-	if err := ctl.noticeService.MarkNoticeAsRead(c.Request.Context(), uint(userID.(float64)), uint(noticeID)); err != nil {
+	if err := ctl.noticeService.MarkNoticeAsRead(c.Request.Context(), userID.(uint), uint(noticeID)); err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "标记通知已读失败")
 		return
 	}
@@ -0,0 +1,302 @@
+package controllers
+
+import (
+	"API/models"
+	"API/services"
+	"API/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OfferController 录用通知控制器
+type OfferController struct {
+	BaseController
+	offerService *services.OfferService
+}
+
+func NewOfferController(s *services.OfferService) *OfferController {
+	return &OfferController{offerService: s}
+}
+
+type offerTemplateRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	Body string `json:"body" binding:"required"`
+}
+
+// CreateTemplate 创建录用通知模板
+// @Summary 创建录用通知模板
+// @Description 正文使用 Go text/template 语法，可用字段: CandidateName、Email、JobTitle、Position、Department、Salary、StartDate、ExpiresAt，可用函数: date、money
+// @Tags 录用通知
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body offerTemplateRequest true "模板"
+// @Success 200 {object} utils.Response{data=models.OfferTemplate}
+// @Failure 400 {object} utils.Response "模板无效或名称重复"
+// @Router /api/v1/offer-templates [post]
+func (ctl *OfferController) CreateTemplate(c *gin.Context) {
+	var request offerTemplateRequest
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	tpl := models.OfferTemplate{Name: request.Name, Body: request.Body, CreatedBy: userID}
+	if err := ctl.offerService.CreateTemplate(c.Request.Context(), &tpl); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, tpl)
+}
+
+// ListTemplates 获取录用通知模板列表
+// @Summary 获取录用通知模板列表
+// @Tags 录用通知
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.OfferTemplate}
+// @Router /api/v1/offer-templates [get]
+func (ctl *OfferController) ListTemplates(c *gin.Context) {
+	templates, err := ctl.offerService.ListTemplates(c.Request.Context())
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, templates)
+}
+
+// UpdateTemplate 更新录用通知模板
+// @Summary 更新录用通知模板
+// @Description 已生成的录用通知正文不受影响
+// @Tags 录用通知
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "模板ID"
+// @Param request body offerTemplateRequest true "模板"
+// @Success 200 {object} utils.Response{data=models.OfferTemplate}
+// @Failure 400 {object} utils.Response "模板无效或名称重复"
+// @Failure 404 {object} utils.Response "模板不存在"
+// @Router /api/v1/offer-templates/{id} [put]
+func (ctl *OfferController) UpdateTemplate(c *gin.Context) {
+	templateID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var request offerTemplateRequest
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	tpl, err := ctl.offerService.UpdateTemplate(c.Request.Context(), templateID, request.Name, request.Body)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, tpl)
+}
+
+// CreateOffer 起草录用通知
+// @Summary 起草录用通知
+// @Description 为已进入录用阶段的申请按模板生成录用通知草稿
+// @Tags 录用通知
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "申请ID"
+// @Param request body services.OfferInput true "录用条件"
+// @Success 200 {object} utils.Response{data=models.Offer}
+// @Failure 400 {object} utils.Response "申请尚未录用或已有进行中的录用通知"
+// @Failure 404 {object} utils.Response "申请或模板不存在"
+// @Router /api/v1/applications/{id}/offers [post]
+func (ctl *OfferController) CreateOffer(c *gin.Context) {
+	applicationID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input services.OfferInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	offer, err := ctl.offerService.CreateOffer(c.Request.Context(), applicationID, userID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, offer)
+}
+
+// ListApplicationOffers 获取申请的录用通知
+// @Summary 获取申请的录用通知
+// @Tags 录用通知
+// @Security Bearer
+// @Produce json
+// @Param id path int true "申请ID"
+// @Success 200 {object} utils.Response{data=[]models.Offer}
+// @Router /api/v1/applications/{id}/offers [get]
+func (ctl *OfferController) ListApplicationOffers(c *gin.Context) {
+	applicationID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	offers, err := ctl.offerService.ListApplicationOffers(c.Request.Context(), applicationID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, offers)
+}
+
+// UpdateOffer 修改录用通知
+// @Summary 修改录用通知
+// @Description 修改尚未发送的录用通知并重新生成正文，已审批的录用通知修改后需重新审批
+// @Tags 录用通知
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "录用通知ID"
+// @Param request body services.OfferInput true "录用条件"
+// @Success 200 {object} utils.Response{data=models.Offer}
+// @Failure 400 {object} utils.Response "录用通知已发送"
+// @Failure 404 {object} utils.Response "录用通知不存在"
+// @Router /api/v1/offers/{id} [put]
+func (ctl *OfferController) UpdateOffer(c *gin.Context) {
+	offerID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input services.OfferInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	offer, err := ctl.offerService.UpdateOffer(c.Request.Context(), offerID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, offer)
+}
+
+// ApproveOffer 审批录用通知
+// @Summary 审批录用通知
+// @Description 审批草稿录用通知，起草人不能审批自己起草的录用通知
+// @Tags 录用通知
+// @Security Bearer
+// @Produce json
+// @Param id path int true "录用通知ID"
+// @Success 200 {object} utils.Response{data=models.Offer}
+// @Failure 400 {object} utils.Response "录用通知不是草稿状态"
+// @Failure 403 {object} utils.Response "不能审批自己起草的录用通知"
+// @Failure 404 {object} utils.Response "录用通知不存在"
+// @Router /api/v1/offers/{id}/approve [post]
+func (ctl *OfferController) ApproveOffer(c *gin.Context) {
+	offerID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	offer, err := ctl.offerService.ApproveOffer(c.Request.Context(), offerID, userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, offer)
+}
+
+// SendOffer 发送录用通知
+// @Summary 发送录用通知
+// @Description 将已审批的录用通知发送给候选人，候选人需在截止时间前答复
+// @Tags 录用通知
+// @Security Bearer
+// @Produce json
+// @Param id path int true "录用通知ID"
+// @Success 200 {object} utils.Response{data=models.Offer}
+// @Failure 400 {object} utils.Response "录用通知尚未审批或已过截止时间"
+// @Failure 404 {object} utils.Response "录用通知不存在"
+// @Router /api/v1/offers/{id}/send [post]
+func (ctl *OfferController) SendOffer(c *gin.Context) {
+	offerID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	offer, err := ctl.offerService.SendOffer(c.Request.Context(), offerID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, offer)
+}
+
+// GetMyOffers 获取我的录用通知
+// @Summary 获取我的录用通知
+// @Description 返回已发送给当前用户的录用通知及其答复状态
+// @Tags 录用通知
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.Offer}
+// @Router /api/v1/offers/my [get]
+func (ctl *OfferController) GetMyOffers(c *gin.Context) {
+	userID, _ := ctl.GetAuthUser(c)
+	offers, err := ctl.offerService.ListMyOffers(c.Request.Context(), userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, offers)
+}
+
+// AcceptOffer 接受录用通知
+// @Summary 接受录用通知
+// @Description 接受后账户转为员工，入职日期、部门、职位和基本工资按录用通知设置；角色变更后需重新登录
+// @Tags 录用通知
+// @Security Bearer
+// @Produce json
+// @Param id path int true "录用通知ID"
+// @Success 200 {object} utils.Response{data=models.Offer}
+// @Failure 400 {object} utils.Response "录用通知已答复或已过期"
+// @Failure 404 {object} utils.Response "录用通知不存在"
+// @Router /api/v1/offers/{id}/accept [post]
+func (ctl *OfferController) AcceptOffer(c *gin.Context) {
+	offerID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	offer, err := ctl.offerService.AcceptOffer(c.Request.Context(), offerID, userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, offer)
+}
+
+// DeclineOffer 拒绝录用通知
+// @Summary 拒绝录用通知
+// @Tags 录用通知
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "录用通知ID"
+// @Param request body struct{Reason string `json:"reason"`} false "拒绝原因"
+// @Success 200 {object} utils.Response{data=models.Offer}
+// @Failure 400 {object} utils.Response "录用通知已答复或已过期"
+// @Failure 404 {object} utils.Response "录用通知不存在"
+// @Router /api/v1/offers/{id}/decline [post]
+func (ctl *OfferController) DeclineOffer(c *gin.Context) {
+	offerID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var request struct {
+		Reason string `json:"reason" binding:"max=500"`
+	}
+	if c.Request.ContentLength > 0 && !ctl.BindJSON(c, &request) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	offer, err := ctl.offerService.DeclineOffer(c.Request.Context(), offerID, userID, request.Reason)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, offer)
+}
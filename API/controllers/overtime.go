@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"API/services"
+	"API/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OvertimeController 加班管理控制器
+type OvertimeController struct {
+	BaseController
+	overtimeService *services.OvertimeService
+}
+
+func NewOvertimeController(s *services.OvertimeService) *OvertimeController {
+	return &OvertimeController{overtimeService: s}
+}
+
+type overtimeDecisionRequest struct {
+	Comment string `json:"comment" binding:"max=500"`
+}
+
+// SubmitOvertimeRequest 提交加班申请
+// @Summary 提交加班申请
+// @Description 今天及以后的日期为事前申请；过去的日期为事后补报，须有完整的打卡记录且时长不超过打卡记录中超出班次的时长。加班类型按该日为工作日、休息日或节假日自动确定
+// @Tags 加班管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body services.OvertimeInput true "加班申请"
+// @Success 200 {object} utils.Response{data=models.OvertimeRequest}
+// @Failure 400 {object} utils.Response "参数无效、重复申请或超过打卡时长"
+// @Router /api/v1/overtime/requests [post]
+func (ctl *OvertimeController) SubmitOvertimeRequest(c *gin.Context) {
+	var input services.OvertimeInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	request, err := ctl.overtimeService.Submit(c.Request.Context(), userID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, request)
+}
+
+// GetMyOvertimeRequests 获取我的加班申请
+// @Summary 获取我的加班申请
+// @Tags 加班管理
+// @Security Bearer
+// @Produce json
+// @Param status query string false "状态：pending/approved/rejected/canceled"
+// @Success 200 {object} utils.Response{data=[]models.OvertimeRequest}
+// @Router /api/v1/overtime/requests/my [get]
+func (ctl *OvertimeController) GetMyOvertimeRequests(c *gin.Context) {
+	userID, _ := ctl.GetAuthUser(c)
+	requests, err := ctl.overtimeService.ListMyRequests(c.Request.Context(), userID, c.Query("status"))
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, requests)
+}
+
+// CancelOvertimeRequest 撤销加班申请
+// @Summary 撤销加班申请
+// @Description 待审批的申请可随时撤销，已批准的申请仅在加班日期之前可撤销
+// @Tags 加班管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "加班申请ID"
+// @Success 200 {object} utils.Response{data=models.OvertimeRequest}
+// @Failure 400 {object} utils.Response "加班日期已到或申请已结束"
+// @Failure 404 {object} utils.Response "加班申请不存在"
+// @Router /api/v1/overtime/requests/{id}/cancel [post]
+func (ctl *OvertimeController) CancelOvertimeRequest(c *gin.Context) {
+	requestID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	request, err := ctl.overtimeService.Cancel(c.Request.Context(), requestID, userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, request)
+}
+
+// GetMyOvertimeSummary 获取我的月度加班统计
+// @Summary 获取我的月度加班统计
+// @Description 按加班类型汇总已批准的加班，计薪时长取批准时长与打卡记录中加班时长的较小值，并返回按倍数加权后的时长
+// @Tags 加班管理
+// @Security Bearer
+// @Produce json
+// @Param month query string false "月份，YYYY-MM，默认本月"
+// @Success 200 {object} utils.Response{data=services.OvertimeSummary}
+// @Failure 400 {object} utils.Response "月份格式错误"
+// @Router /api/v1/overtime/summary [get]
+func (ctl *OvertimeController) GetMyOvertimeSummary(c *gin.Context) {
+	month, ok := ctl.ParseMonthQuery(c)
+	if !ok {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	summary, err := ctl.overtimeService.MonthlySummary(c.Request.Context(), userID, month)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, summary)
+}
+
+// GetMonthlyOvertime 获取全体员工的月度加班统计
+// @Summary 获取全体员工的月度加班统计
+// @Description 供薪资核算使用，仅包含当月有加班记录的员工
+// @Tags 加班管理
+// @Security Bearer
+// @Produce json
+// @Param month query string false "月份，YYYY-MM，默认本月"
+// @Success 200 {object} utils.Response{data=[]services.OvertimeSummary}
+// @Failure 400 {object} utils.Response "月份格式错误"
+// @Router /api/v1/overtime/monthly [get]
+func (ctl *OvertimeController) GetMonthlyOvertime(c *gin.Context) {
+	month, ok := ctl.ParseMonthQuery(c)
+	if !ok {
+		return
+	}
+	totals, err := ctl.overtimeService.MonthlyTotals(c.Request.Context(), month)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, totals)
+}
+
+// ListPendingOvertimeRequests 获取待我审批的加班申请
+// @Summary 获取待我审批的加班申请
+// @Description 人事可审批全部员工的加班，部门负责人仅限本部门员工
+// @Tags 加班管理
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.OvertimeRequest}
+// @Router /api/v1/overtime/requests/pending [get]
+func (ctl *OvertimeController) ListPendingOvertimeRequests(c *gin.Context) {
+	userID, _ := ctl.GetAuthUser(c)
+	requests, err := ctl.overtimeService.ListPendingForApprover(c.Request.Context(), userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, requests)
+}
+
+// ApproveOvertimeRequest 批准加班申请
+// @Summary 批准加班申请
+// @Description 审批人须为员工所在部门的负责人或人事，且不能审批自己的申请
+// @Tags 加班管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "加班申请ID"
+// @Param request body overtimeDecisionRequest false "审批意见"
+// @Success 200 {object} utils.Response{data=models.OvertimeRequest}
+// @Failure 400 {object} utils.Response "申请已处理"
+// @Failure 403 {object} utils.Response "不是该员工的审批人"
+// @Failure 404 {object} utils.Response "加班申请不存在"
+// @Router /api/v1/overtime/requests/{id}/approve [post]
+func (ctl *OvertimeController) ApproveOvertimeRequest(c *gin.Context) {
+	requestID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var body overtimeDecisionRequest
+	if c.Request.ContentLength != 0 && !ctl.BindJSON(c, &body) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	request, err := ctl.overtimeService.Approve(c.Request.Context(), requestID, userID, body.Comment)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, request)
+}
+
+// RejectOvertimeRequest 驳回加班申请
+// @Summary 驳回加班申请
+// @Description 驳回须填写审批意见
+// @Tags 加班管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "加班申请ID"
+// @Param request body overtimeDecisionRequest true "审批意见"
+// @Success 200 {object} utils.Response{data=models.OvertimeRequest}
+// @Failure 400 {object} utils.Response "未填写审批意见或申请已处理"
+// @Failure 403 {object} utils.Response "不是该员工的审批人"
+// @Failure 404 {object} utils.Response "加班申请不存在"
+// @Router /api/v1/overtime/requests/{id}/reject [post]
+func (ctl *OvertimeController) RejectOvertimeRequest(c *gin.Context) {
+	requestID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var body overtimeDecisionRequest
+	if !ctl.BindJSON(c, &body) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	request, err := ctl.overtimeService.Reject(c.Request.Context(), requestID, userID, body.Comment)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, request)
+}
@@ -10,6 +10,7 @@ import (
 )
 
 type PermissionController struct {
+	BaseController
 	service *services.PermissionService
 }
 
@@ -57,3 +58,30 @@ func (ctl *PermissionController) GetPermissions(c *gin.Context) {
 	}
 	utils.RespondSuccess(c, permissions)
 }
+
+// GetUserPermissions 获取用户有效权限
+// @Summary 获取用户有效权限
+// @Description 获取指定用户当前拥有的角色及其对应的全部权限代码
+// @Tags 权限管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} utils.Response{data=map[string][]string{roles=[]string,permissions=[]string}}
+// @Failure 400 {object} utils.Response "无效的ID"
+// @Failure 500 {object} utils.Response "服务器内部错误"
+// @Router /api/v1/users/{id}/permissions [get]
+func (ctl *PermissionController) GetUserPermissions(c *gin.Context) {
+	userID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	roles, codes, err := ctl.service.GetUserPermissionCodes(c.Request.Context(), userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{
+		"roles":       roles,
+		"permissions": codes,
+	})
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"API/services"
+	"API/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PipelineController 招聘流程控制器
+type PipelineController struct {
+	BaseController
+	pipelineService *services.PipelineService
+}
+
+func NewPipelineController(ps *services.PipelineService) *PipelineController {
+	return &PipelineController{pipelineService: ps}
+}
+
+// GetPipeline 获取职位的招聘流程
+// @Summary 获取职位的招聘流程
+// @Description 按顺序返回职位的招聘阶段，未配置时使用默认流程
+// @Tags 招聘流程
+// @Security Bearer
+// @Produce json
+// @Param id path int true "职位ID"
+// @Success 200 {object} utils.Response{data=[]models.PipelineStage}
+// @Failure 404 {object} utils.Response "职位不存在"
+// @Router /api/v1/jobs/{id}/pipeline [get]
+func (ctl *PipelineController) GetPipeline(c *gin.Context) {
+	jobID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	stages, err := ctl.pipelineService.GetPipeline(c.Request.Context(), jobID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, stages)
+}
+
+// UpdatePipeline 配置职位的招聘流程
+// @Summary 配置职位的招聘流程
+// @Description 按提交顺序设置招聘阶段，需包含至少一个进行中阶段及录用、淘汰阶段各一个；仍有申请的阶段不能移除
+// @Tags 招聘流程
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "职位ID"
+// @Param request body struct{Stages []services.PipelineStageInput `json:"stages" binding:"required"`} true "阶段列表"
+// @Success 200 {object} utils.Response{data=[]models.PipelineStage}
+// @Failure 400 {object} utils.Response "无效的阶段配置"
+// @Failure 404 {object} utils.Response "职位不存在"
+// @Router /api/v1/jobs/{id}/pipeline [put]
+func (ctl *PipelineController) UpdatePipeline(c *gin.Context) {
+	jobID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var request struct {
+		Stages []services.PipelineStageInput `json:"stages" binding:"required,dive"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	stages, err := ctl.pipelineService.UpdatePipeline(c.Request.Context(), jobID, request.Stages)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, stages)
+}
+
+// ListJobApplications 按阶段列出职位的申请
+// @Summary 按阶段列出职位的申请
+// @Description 返回职位招聘流程的每个阶段及其中的申请，申请附带面试评分汇总
+// @Tags 招聘流程
+// @Security Bearer
+// @Produce json
+// @Param id path int true "职位ID"
+// @Success 200 {object} utils.Response{data=[]services.StageApplications}
+// @Failure 404 {object} utils.Response "职位不存在"
+// @Router /api/v1/jobs/{id}/applications [get]
+func (ctl *PipelineController) ListJobApplications(c *gin.Context) {
+	jobID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	groups, err := ctl.pipelineService.ListApplicationsByStage(c.Request.Context(), jobID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, groups)
+}
@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"API/services"
+	"API/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequisitionController 招聘需求控制器
+type RequisitionController struct {
+	BaseController
+	requisitionService *services.RequisitionService
+}
+
+func NewRequisitionController(s *services.RequisitionService) *RequisitionController {
+	return &RequisitionController{requisitionService: s}
+}
+
+type requisitionDecisionRequest struct {
+	Comment string `json:"comment" binding:"max=500"`
+}
+
+// SubmitRequisition 为草稿职位发起招聘需求
+// @Summary 发起招聘需求
+// @Description 为草稿职位提交招聘人数、预算和招聘理由，职位转为待审批，并邮件通知第一步审批人；用人部门为空时使用发起人所在部门
+// @Tags 招聘需求
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "职位ID"
+// @Param request body services.RequisitionInput true "招聘需求"
+// @Success 200 {object} utils.Response{data=models.Requisition}
+// @Failure 400 {object} utils.Response "职位不是草稿或参数无效"
+// @Failure 404 {object} utils.Response "职位不存在"
+// @Router /api/v1/jobs/{id}/requisition [post]
+func (ctl *RequisitionController) SubmitRequisition(c *gin.Context) {
+	jobID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input services.RequisitionInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	requisition, err := ctl.requisitionService.Submit(c.Request.Context(), jobID, userID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, requisition)
+}
+
+// ListRequisitions 获取招聘需求列表
+// @Summary 获取招聘需求列表
+// @Tags 招聘需求
+// @Security Bearer
+// @Produce json
+// @Param status query string false "审批状态" Enums(pending, approved, rejected)
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Success 200 {object} utils.Response{data=[]models.Requisition,total=int}
+// @Router /api/v1/requisitions [get]
+func (ctl *RequisitionController) ListRequisitions(c *gin.Context) {
+	page, size := ctl.ParsePagination(c)
+	requisitions, total, err := ctl.requisitionService.ListRequisitions(c.Request.Context(), c.Query("status"), page, size)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{
+		"data":  requisitions,
+		"total": total,
+	})
+}
+
+// ListPendingRequisitions 获取待我审批的招聘需求
+// @Summary 获取待我审批的招聘需求
+// @Description 返回当前审批步骤角色由当前用户持有的招聘需求，部门负责人仅能看到本部门的需求
+// @Tags 招聘需求
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.Requisition}
+// @Router /api/v1/requisitions/pending [get]
+func (ctl *RequisitionController) ListPendingRequisitions(c *gin.Context) {
+	userID, _ := ctl.GetAuthUser(c)
+	requisitions, err := ctl.requisitionService.ListPendingForApprover(c.Request.Context(), userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, requisitions)
+}
+
+// GetRequisition 获取招聘需求详情
+// @Summary 获取招聘需求详情
+// @Description 包含各审批步骤的审批人、意见和时间
+// @Tags 招聘需求
+// @Security Bearer
+// @Produce json
+// @Param id path int true "招聘需求ID"
+// @Success 200 {object} utils.Response{data=models.Requisition}
+// @Failure 404 {object} utils.Response "招聘需求不存在"
+// @Router /api/v1/requisitions/{id} [get]
+func (ctl *RequisitionController) GetRequisition(c *gin.Context) {
+	requisitionID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	requisition, err := ctl.requisitionService.GetRequisition(c.Request.Context(), requisitionID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, requisition)
+}
+
+// ApproveRequisition 审批通过招聘需求的当前步骤
+// @Summary 审批通过招聘需求
+// @Description 当前用户须持有当前步骤的审批角色，且不能是发起人；通过后通知下一步审批人，最后一步通过后通知发起人可发布职位
+// @Tags 招聘需求
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "招聘需求ID"
+// @Param request body requisitionDecisionRequest false "审批意见"
+// @Success 200 {object} utils.Response{data=models.Requisition}
+// @Failure 400 {object} utils.Response "招聘需求已完成审批"
+// @Failure 403 {object} utils.Response "不是当前步骤的审批人"
+// @Failure 404 {object} utils.Response "招聘需求不存在"
+// @Router /api/v1/requisitions/{id}/approve [post]
+func (ctl *RequisitionController) ApproveRequisition(c *gin.Context) {
+	requisitionID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var request requisitionDecisionRequest
+	if c.Request.ContentLength != 0 && !ctl.BindJSON(c, &request) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	requisition, err := ctl.requisitionService.Approve(c.Request.Context(), requisitionID, userID, request.Comment)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, requisition)
+}
+
+// RejectRequisition 驳回招聘需求
+// @Summary 驳回招聘需求
+// @Description 驳回须填写审批意见，职位退回草稿并通知发起人，可修改后重新发起
+// @Tags 招聘需求
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "招聘需求ID"
+// @Param request body requisitionDecisionRequest true "审批意见"
+// @Success 200 {object} utils.Response{data=models.Requisition}
+// @Failure 400 {object} utils.Response "未填写审批意见或招聘需求已完成审批"
+// @Failure 403 {object} utils.Response "不是当前步骤的审批人"
+// @Failure 404 {object} utils.Response "招聘需求不存在"
+// @Router /api/v1/requisitions/{id}/reject [post]
+func (ctl *RequisitionController) RejectRequisition(c *gin.Context) {
+	requisitionID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var request requisitionDecisionRequest
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	requisition, err := ctl.requisitionService.Reject(c.Request.Context(), requisitionID, userID, request.Comment)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, requisition)
+}
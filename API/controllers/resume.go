@@ -1,16 +1,23 @@
 package controllers
 
 import (
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 
-	"API/models"
 	"API/services"
 	"API/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
+// maxResumeFileSize 解析简历文件的大小上限
+const maxResumeFileSize = 5 << 20
+
 type ResumeController struct {
+	BaseController
 	resumeService *services.ResumeService
 }
 
@@ -20,38 +27,31 @@ func NewResumeController(rs *services.ResumeService) *ResumeController {
 
 // SubmitResume 提交简历信息
 // @Summary 提交简历信息
-// @Description 提交或更新用户的简历信息
+// @Description 提交或更新用户的简历信息，教育经历、工作经历和技能整体替换原有内容；日期格式为 YYYY-MM-DD、YYYY-MM 或 YYYY，结束日期为空表示至今
 // @Tags 简历管理
 // @Accept json
 // @Produce json
 // @Security Bearer
-// @Param resume body models.Resume true "简历信息"
-// @Success 200 {object} utils.Response{message=string}
+// @Param resume body services.ResumeInput true "简历信息"
+// @Success 200 {object} utils.Response{data=models.Resume}
 // @Failure 400 {object} utils.Response "无效的请求参数"
 // @Failure 401 {object} utils.Response "未授权的请求"
 // @Failure 500 {object} utils.Response "服务器内部错误"
 // @Router /api/v1/resumes [post]
 func (ctl *ResumeController) SubmitResume(c *gin.Context) {
-	var resume models.Resume
-	if err := c.ShouldBindJSON(&resume); err != nil {
-		utils.RespondError(c, http.StatusBadRequest, "无效的请求参数")
-		return
-	}
-
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "未授权的请求")
+	var input services.ResumeInput
+	if !ctl.BindJSON(c, &input) {
 		return
 	}
 
-	resume.UserID = userID.(uint)
-
-	if err := ctl.resumeService.SubmitResume(c.Request.Context(), resume.UserID, &resume); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+	userID, _ := ctl.GetAuthUser(c)
+	resume, err := ctl.resumeService.SubmitResume(c.Request.Context(), userID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
 		return
 	}
 
-	utils.RespondSuccess(c, gin.H{"message": "简历提交成功"})
+	utils.RespondSuccess(c, resume)
 }
 
 // GetResume 获取用户简历
@@ -62,20 +62,190 @@ func (ctl *ResumeController) SubmitResume(c *gin.Context) {
 // @Security Bearer
 // @Success 200 {object} utils.Response{data=models.Resume}
 // @Failure 401 {object} utils.Response "未授权的请求"
+// @Failure 404 {object} utils.Response "简历不存在"
 // @Failure 500 {object} utils.Response "服务器内部错误"
 // @Router /api/v1/resumes [get]
 func (ctl *ResumeController) GetResume(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.RespondError(c, http.StatusUnauthorized, "未授权的请求")
+	userID, _ := ctl.GetAuthUser(c)
+	resume, err := ctl.resumeService.GetResumeByUserID(c.Request.Context(), userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
 		return
 	}
 
-	resume, err := ctl.resumeService.GetResumeByUserID(c.Request.Context(), userID.(uint))
+	utils.RespondSuccess(c, resume)
+}
+
+// ParseResume 解析简历文件
+// @Summary 解析简历文件
+// @Description 从 txt、docx 或带文本层的 pdf 文件中提取个人简介、教育经历、工作经历和技能，仅返回解析结果，确认后通过提交简历接口保存
+// @Tags 简历管理
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param file formData file true "简历文件，不超过5MB"
+// @Success 200 {object} utils.Response{data=services.ResumeInput}
+// @Failure 400 {object} utils.Response "文件格式不支持或无法提取文本"
+// @Router /api/v1/resumes/parse [post]
+func (ctl *ResumeController) ParseResume(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "请上传简历文件")
+		return
+	}
+	if header.Size > maxResumeFileSize {
+		utils.RespondError(c, http.StatusBadRequest, fmt.Sprintf("简历文件不能超过%dMB", maxResumeFileSize>>20))
+		return
+	}
+	file, err := header.Open()
 	if err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, err.Error())
+		utils.RespondError(c, http.StatusBadRequest, "无法读取简历文件")
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxResumeFileSize))
+	if err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "无法读取简历文件")
+		return
+	}
+
+	parsed, err := ctl.resumeService.ParseResumeFile(header.Filename, data)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, parsed)
+}
+
+// ExportResume 以 JSON Resume 格式导出简历
+// @Summary 导出简历
+// @Description 按 JSON Resume 格式（https://jsonresume.org/schema）导出当前用户的简历
+// @Tags 简历管理
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} services.JSONResume
+// @Failure 404 {object} utils.Response "简历不存在"
+// @Router /api/v1/resumes/export [get]
+func (ctl *ResumeController) ExportResume(c *gin.Context) {
+	userID, _ := ctl.GetAuthUser(c)
+	export, err := ctl.resumeService.ExportJSONResume(c.Request.Context(), userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="resume.json"`)
+	c.JSON(http.StatusOK, export)
+}
+
+// ImportResume 导入 JSON Resume 格式的简历
+// @Summary 导入简历
+// @Description 导入 JSON Resume 格式的简历并替换当前简历的教育经历、工作经历和技能；basics 中仅导入 label、summary 和 location，技能的 keywords 同样作为技能导入
+// @Tags 简历管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param resume body services.JSONResume true "JSON Resume"
+// @Success 200 {object} utils.Response{data=models.Resume}
+// @Failure 400 {object} utils.Response "简历内容无效"
+// @Router /api/v1/resumes/import [post]
+func (ctl *ResumeController) ImportResume(c *gin.Context) {
+	var data services.JSONResume
+	if !ctl.BindJSON(c, &data) {
+		return
+	}
+	input := data.ResumeInput()
+	if err := binding.Validator.ValidateStruct(&input); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "简历内容无效: "+err.Error())
 		return
 	}
 
+	userID, _ := ctl.GetAuthUser(c)
+	resume, err := ctl.resumeService.ImportResume(c.Request.Context(), userID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
 	utils.RespondSuccess(c, resume)
 }
+
+// parseVersion 解析简历版本号，无效时返回400
+func parseVersion(c *gin.Context, value string) (int, bool) {
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		utils.RespondError(c, http.StatusBadRequest, "无效的简历版本号")
+		return 0, false
+	}
+	return version, true
+}
+
+// ListResumeVersions 获取简历版本列表
+// @Summary 获取简历版本列表
+// @Description 每次提交简历且内容有变化时生成一个不可变的版本，按版本号倒序返回
+// @Tags 简历管理
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} utils.Response{data=[]models.ResumeVersion}
+// @Router /api/v1/resumes/versions [get]
+func (ctl *ResumeController) ListResumeVersions(c *gin.Context) {
+	userID, _ := ctl.GetAuthUser(c)
+	versions, err := ctl.resumeService.ListVersions(c.Request.Context(), userID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, versions)
+}
+
+// GetResumeVersion 获取简历的指定版本
+// @Summary 获取简历的指定版本
+// @Description 返回指定版本的简历内容快照
+// @Tags 简历管理
+// @Produce json
+// @Security Bearer
+// @Param version path int true "版本号"
+// @Success 200 {object} utils.Response{data=services.ResumeVersionDetail}
+// @Failure 404 {object} utils.Response "简历版本不存在"
+// @Router /api/v1/resumes/versions/{version} [get]
+func (ctl *ResumeController) GetResumeVersion(c *gin.Context) {
+	version, ok := parseVersion(c, c.Param("version"))
+	if !ok {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	detail, err := ctl.resumeService.GetVersion(c.Request.Context(), userID, version)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, detail)
+}
+
+// DiffResumeVersions 比较简历的两个版本
+// @Summary 比较简历的两个版本
+// @Description 返回基本信息字段的变化、教育和工作经历的增删改（按学校或公司及开始日期对应）以及技能的增删
+// @Tags 简历管理
+// @Produce json
+// @Security Bearer
+// @Param from query int true "原版本号"
+// @Param to query int true "新版本号"
+// @Success 200 {object} utils.Response{data=services.ResumeDiff}
+// @Failure 400 {object} utils.Response "无效的简历版本号"
+// @Failure 404 {object} utils.Response "简历版本不存在"
+// @Router /api/v1/resumes/versions/diff [get]
+func (ctl *ResumeController) DiffResumeVersions(c *gin.Context) {
+	from, ok := parseVersion(c, c.Query("from"))
+	if !ok {
+		return
+	}
+	to, ok := parseVersion(c, c.Query("to"))
+	if !ok {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	diff, err := ctl.resumeService.DiffVersions(c.Request.Context(), userID, from, to)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, diff)
+}
@@ -11,6 +11,7 @@ import (
 
 // RoleController 角色控制器
 type RoleController struct {
+	BaseController
 	roleService *services.RoleService
 }
 
@@ -20,6 +21,15 @@ func NewRoleController(rs *services.RoleService) *RoleController {
 }
 
 // CreateRole 创建角色
+// @Summary 创建角色
+// @Tags 角色管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param role body models.Role true "角色信息"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "无效的请求参数"
+// @Router /api/v1/roles [post]
 func (ctl *RoleController) CreateRole(c *gin.Context) {
 	var role models.Role
 	if err := c.ShouldBindJSON(&role); err != nil {
@@ -34,6 +44,12 @@ func (ctl *RoleController) CreateRole(c *gin.Context) {
 }
 
 // GetRoles 获取角色列表
+// @Summary 获取角色列表
+// @Tags 角色管理
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.Role}
+// @Router /api/v1/roles [get]
 func (ctl *RoleController) GetRoles(c *gin.Context) {
 	roles, err := ctl.roleService.GetRoles(c.Request.Context())
 	if err != nil {
@@ -42,3 +58,200 @@ func (ctl *RoleController) GetRoles(c *gin.Context) {
 	}
 	utils.RespondSuccess(c, roles)
 }
+
+// GetRole 获取角色详情
+// @Summary 获取角色详情
+// @Description 获取指定角色及其拥有的权限
+// @Tags 角色管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "角色ID"
+// @Success 200 {object} utils.Response{data=models.Role}
+// @Failure 400 {object} utils.Response "无效的ID"
+// @Failure 404 {object} utils.Response "角色不存在"
+// @Router /api/v1/roles/{id} [get]
+func (ctl *RoleController) GetRole(c *gin.Context) {
+	id, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	role, err := ctl.roleService.GetRoleByID(c.Request.Context(), id)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, role)
+}
+
+// UpdateRole 更新角色
+// @Summary 更新角色
+// @Description 更新角色名称、描述和两步验证策略，只修改传入的字段，系统内置角色不可改名；改名后持有该角色的用户需重新登录
+// @Tags 角色管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "角色ID"
+// @Param request body services.RoleUpdate true "角色信息"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "无效的请求参数"
+// @Failure 404 {object} utils.Response "角色不存在"
+// @Router /api/v1/roles/{id} [put]
+func (ctl *RoleController) UpdateRole(c *gin.Context) {
+	id, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var request services.RoleUpdate
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	if err := ctl.roleService.UpdateRole(c.Request.Context(), id, request); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "角色更新成功"})
+}
+
+// DeleteRole 删除角色
+// @Summary 删除角色
+// @Description 删除角色并解除其权限与用户关联，系统内置角色(admin、employee、candidate)不可删除
+// @Tags 角色管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "角色ID"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "系统内置角色不可删除"
+// @Failure 404 {object} utils.Response "角色不存在"
+// @Router /api/v1/roles/{id} [delete]
+func (ctl *RoleController) DeleteRole(c *gin.Context) {
+	id, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ctl.roleService.DeleteRole(c.Request.Context(), id); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "角色删除成功"})
+}
+
+// GrantPermissions 为角色授予权限
+// @Summary 授予角色权限
+// @Description 为指定角色授予一个或多个权限
+// @Tags 角色管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "角色ID"
+// @Param request body struct{PermissionIDs []uint `json:"permission_ids" binding:"required"`} true "权限ID列表"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "无效的请求参数"
+// @Failure 404 {object} utils.Response "角色或权限不存在"
+// @Router /api/v1/roles/{id}/permissions [post]
+func (ctl *RoleController) GrantPermissions(c *gin.Context) {
+	id, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var request struct {
+		PermissionIDs []uint `json:"permission_ids" binding:"required,min=1"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	if err := ctl.roleService.GrantPermissions(c.Request.Context(), id, request.PermissionIDs); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "权限授予成功"})
+}
+
+// RevokePermissions 撤销角色权限
+// @Summary 撤销角色权限
+// @Description 撤销指定角色的一个或多个权限
+// @Tags 角色管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "角色ID"
+// @Param request body struct{PermissionIDs []uint `json:"permission_ids" binding:"required"`} true "权限ID列表"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "无效的请求参数"
+// @Failure 404 {object} utils.Response "角色或权限不存在"
+// @Router /api/v1/roles/{id}/permissions [delete]
+func (ctl *RoleController) RevokePermissions(c *gin.Context) {
+	id, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var request struct {
+		PermissionIDs []uint `json:"permission_ids" binding:"required,min=1"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	if err := ctl.roleService.RevokePermissions(c.Request.Context(), id, request.PermissionIDs); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "权限撤销成功"})
+}
+
+// AssignUserRoles 为用户分配角色
+// @Summary 分配用户角色
+// @Description 为指定用户分配一个或多个角色
+// @Tags 角色管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param request body struct{RoleIDs []uint `json:"role_ids" binding:"required"`} true "角色ID列表"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "无效的请求参数"
+// @Failure 404 {object} utils.Response "用户或角色不存在"
+// @Router /api/v1/users/{id}/roles [post]
+func (ctl *RoleController) AssignUserRoles(c *gin.Context) {
+	userID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var request struct {
+		RoleIDs []uint `json:"role_ids" binding:"required,min=1"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	if err := ctl.roleService.AssignRoles(c.Request.Context(), userID, request.RoleIDs); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "角色分配成功"})
+}
+
+// UnassignUserRole 移除用户角色
+// @Summary 移除用户角色
+// @Description 移除指定用户的某个角色
+// @Tags 角色管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param role_id path int true "角色ID"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "无效的ID"
+// @Failure 404 {object} utils.Response "用户或角色不存在"
+// @Router /api/v1/users/{id}/roles/{role_id} [delete]
+func (ctl *RoleController) UnassignUserRole(c *gin.Context) {
+	userID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	roleID, ok := ctl.ParseIDParam(c, "role_id")
+	if !ok {
+		return
+	}
+	if err := ctl.roleService.UnassignRole(c.Request.Context(), userID, roleID); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "角色移除成功"})
+}
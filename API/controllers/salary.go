@@ -21,7 +21,7 @@ func NewSalaryController(s *services.SalaryService) *SalaryController {
 
 // GenerateSalary 生成薪资记录
 // @Summary 生成薪资记录
-// @Description 为指定用户生成指定月份的薪资记录
+// @Description 为指定用户生成指定月份的薪资记录，基本工资按计薪天数占当月工作日（不含节假日）的比例折算，当月入职从入职日起计薪，无薪假不计薪；加班工资按已批准的加班时长和加班类型倍数计算
 // @Tags 薪资管理
 // @Accept json
 // @Produce json
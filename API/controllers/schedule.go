@@ -0,0 +1,210 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"API/services"
+	"API/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduleController 班次管理控制器
+type ScheduleController struct {
+	BaseController
+	scheduleService *services.ScheduleService
+}
+
+func NewScheduleController(s *services.ScheduleService) *ScheduleController {
+	return &ScheduleController{scheduleService: s}
+}
+
+// ListSchedules 获取班次列表
+// @Summary 获取班次列表
+// @Tags 班次管理
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.WorkSchedule}
+// @Router /api/v1/schedules [get]
+func (ctl *ScheduleController) ListSchedules(c *gin.Context) {
+	schedules, err := ctl.scheduleService.ListSchedules(c.Request.Context())
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, schedules)
+}
+
+// CreateSchedule 创建班次
+// @Summary 创建班次
+// @Description 固定班次按上下班时间判断迟到早退；夜班的下班时间在次日；弹性班次的起止时间为核心工作时间，并需满足每日最低工时
+// @Tags 班次管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body services.ScheduleInput true "班次"
+// @Success 200 {object} utils.Response{data=models.WorkSchedule}
+// @Failure 400 {object} utils.Response "参数无效或名称已存在"
+// @Router /api/v1/schedules [post]
+func (ctl *ScheduleController) CreateSchedule(c *gin.Context) {
+	var input services.ScheduleInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	schedule, err := ctl.scheduleService.CreateSchedule(c.Request.Context(), input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, schedule)
+}
+
+// UpdateSchedule 修改班次
+// @Summary 修改班次
+// @Description 修改后的班次用于之后的打卡，已有打卡记录的判定结果不变
+// @Tags 班次管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "班次ID"
+// @Param request body services.ScheduleInput true "班次"
+// @Success 200 {object} utils.Response{data=models.WorkSchedule}
+// @Failure 400 {object} utils.Response "参数无效或名称已存在"
+// @Failure 404 {object} utils.Response "班次不存在"
+// @Router /api/v1/schedules/{id} [put]
+func (ctl *ScheduleController) UpdateSchedule(c *gin.Context) {
+	scheduleID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input services.ScheduleInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	schedule, err := ctl.scheduleService.UpdateSchedule(c.Request.Context(), scheduleID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, schedule)
+}
+
+// DeleteSchedule 删除班次
+// @Summary 删除班次
+// @Description 仍有生效中或未来生效的分配时不能删除
+// @Tags 班次管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "班次ID"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "班次仍在使用"
+// @Failure 404 {object} utils.Response "班次不存在"
+// @Router /api/v1/schedules/{id} [delete]
+func (ctl *ScheduleController) DeleteSchedule(c *gin.Context) {
+	scheduleID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ctl.scheduleService.DeleteSchedule(c.Request.Context(), scheduleID); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "班次已删除"})
+}
+
+// ListAssignments 获取班次分配
+// @Summary 获取班次分配
+// @Tags 班次管理
+// @Security Bearer
+// @Produce json
+// @Param user_id query int false "员工ID"
+// @Param department query string false "部门"
+// @Success 200 {object} utils.Response{data=[]models.ScheduleAssignment}
+// @Router /api/v1/schedule-assignments [get]
+func (ctl *ScheduleController) ListAssignments(c *gin.Context) {
+	userID, _ := strconv.ParseUint(c.Query("user_id"), 10, 64)
+	assignments, err := ctl.scheduleService.ListAssignments(c.Request.Context(), uint(userID), c.Query("department"))
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, assignments)
+}
+
+// CreateAssignment 分配班次
+// @Summary 分配班次
+// @Description 将班次分配给员工或部门，个人分配优先于部门分配。同一对象的分配期间不能重叠，早于新分配开始的长期分配自动在新分配生效前一天结束
+// @Tags 班次管理
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body services.AssignmentInput true "班次分配"
+// @Success 200 {object} utils.Response{data=models.ScheduleAssignment}
+// @Failure 400 {object} utils.Response "参数无效或分配期间重叠"
+// @Failure 404 {object} utils.Response "班次或员工不存在"
+// @Router /api/v1/schedule-assignments [post]
+func (ctl *ScheduleController) CreateAssignment(c *gin.Context) {
+	var input services.AssignmentInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	operatorID, _ := ctl.GetAuthUser(c)
+	assignment, err := ctl.scheduleService.Assign(c.Request.Context(), operatorID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, assignment)
+}
+
+// DeleteAssignment 删除班次分配
+// @Summary 删除班次分配
+// @Tags 班次管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "班次分配ID"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 404 {object} utils.Response "班次分配不存在"
+// @Router /api/v1/schedule-assignments/{id} [delete]
+func (ctl *ScheduleController) DeleteAssignment(c *gin.Context) {
+	assignmentID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ctl.scheduleService.DeleteAssignment(c.Request.Context(), assignmentID); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "班次分配已删除"})
+}
+
+// GetMyShift 获取我的班次
+// @Summary 获取我的班次
+// @Description 返回当前用户指定日期适用的班次及当日的上下班时间
+// @Tags 考勤管理
+// @Security Bearer
+// @Produce json
+// @Param date query string false "日期，YYYY-MM-DD，默认今天"
+// @Success 200 {object} utils.Response{data=services.ShiftInfo}
+// @Failure 400 {object} utils.Response "日期格式错误"
+// @Router /api/v1/attendance/shift [get]
+func (ctl *ScheduleController) GetMyShift(c *gin.Context) {
+	date := time.Now()
+	if value := c.Query("date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			utils.RespondError(c, http.StatusBadRequest, "日期格式错误，请使用YYYY-MM-DD格式")
+			return
+		}
+		date = parsed
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	shift, err := ctl.scheduleService.ShiftFor(c.Request.Context(), userID, date)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, shift)
+}
@@ -0,0 +1,407 @@
+package controllers
+
+import (
+	"net/http"
+
+	"API/services"
+	"API/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TalentController 人才库控制器
+type TalentController struct {
+	BaseController
+	talentService *services.TalentService
+}
+
+func NewTalentController(s *services.TalentService) *TalentController {
+	return &TalentController{talentService: s}
+}
+
+// ListTalentPools 获取人才库列表
+// @Summary 获取人才库列表
+// @Description 返回全部人才库及各库的候选人数量
+// @Tags 人才库
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.TalentPool}
+// @Router /api/v1/talent-pools [get]
+func (ctl *TalentController) ListTalentPools(c *gin.Context) {
+	pools, err := ctl.talentService.ListPools(c.Request.Context())
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, pools)
+}
+
+// CreateTalentPool 创建人才库
+// @Summary 创建人才库
+// @Tags 人才库
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body services.TalentPoolInput true "人才库"
+// @Success 200 {object} utils.Response{data=models.TalentPool}
+// @Failure 400 {object} utils.Response "名称已存在"
+// @Router /api/v1/talent-pools [post]
+func (ctl *TalentController) CreateTalentPool(c *gin.Context) {
+	var input services.TalentPoolInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	pool, err := ctl.talentService.CreatePool(c.Request.Context(), userID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, pool)
+}
+
+// UpdateTalentPool 修改人才库
+// @Summary 修改人才库
+// @Tags 人才库
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "人才库ID"
+// @Param request body services.TalentPoolInput true "人才库"
+// @Success 200 {object} utils.Response{data=models.TalentPool}
+// @Failure 400 {object} utils.Response "名称已存在"
+// @Failure 404 {object} utils.Response "人才库不存在"
+// @Router /api/v1/talent-pools/{id} [put]
+func (ctl *TalentController) UpdateTalentPool(c *gin.Context) {
+	poolID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var input services.TalentPoolInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	pool, err := ctl.talentService.UpdatePool(c.Request.Context(), poolID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, pool)
+}
+
+// DeleteTalentPool 删除人才库
+// @Summary 删除人才库
+// @Description 删除人才库及其候选人名单，候选人的标签和备注保留
+// @Tags 人才库
+// @Security Bearer
+// @Produce json
+// @Param id path int true "人才库ID"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 404 {object} utils.Response "人才库不存在"
+// @Router /api/v1/talent-pools/{id} [delete]
+func (ctl *TalentController) DeleteTalentPool(c *gin.Context) {
+	poolID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ctl.talentService.DeletePool(c.Request.Context(), poolID); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "人才库已删除"})
+}
+
+// AddTalentPoolMembers 将候选人加入人才库
+// @Summary 将候选人加入人才库
+// @Description 已在库中的候选人忽略
+// @Tags 人才库
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "人才库ID"
+// @Param request body struct{UserIDs []uint `json:"user_ids" binding:"required"`} true "候选人ID"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 404 {object} utils.Response "人才库或候选人不存在"
+// @Router /api/v1/talent-pools/{id}/members [post]
+func (ctl *TalentController) AddTalentPoolMembers(c *gin.Context) {
+	poolID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var request struct {
+		UserIDs []uint `json:"user_ids" binding:"required,min=1,max=100"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	operatorID, _ := ctl.GetAuthUser(c)
+	if err := ctl.talentService.AddPoolMembers(c.Request.Context(), poolID, operatorID, request.UserIDs); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "候选人已加入人才库"})
+}
+
+// RemoveTalentPoolMember 将候选人移出人才库
+// @Summary 将候选人移出人才库
+// @Tags 人才库
+// @Security Bearer
+// @Produce json
+// @Param id path int true "人才库ID"
+// @Param user_id path int true "候选人ID"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 404 {object} utils.Response "候选人不在该人才库中"
+// @Router /api/v1/talent-pools/{id}/members/{user_id} [delete]
+func (ctl *TalentController) RemoveTalentPoolMember(c *gin.Context) {
+	poolID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := ctl.ParseIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+	if err := ctl.talentService.RemovePoolMember(c.Request.Context(), poolID, userID); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "候选人已移出人才库"})
+}
+
+// ListTalentTags 获取候选人标签列表
+// @Summary 获取候选人标签列表
+// @Description 受控标签在前，其后为打标签时自动创建的自由标签
+// @Tags 人才库
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.TalentTag}
+// @Router /api/v1/talent-tags [get]
+func (ctl *TalentController) ListTalentTags(c *gin.Context) {
+	tags, err := ctl.talentService.ListTags(c.Request.Context())
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, tags)
+}
+
+// CreateTalentTag 定义受控标签
+// @Summary 定义受控标签
+// @Description 同名的自由标签转为受控标签；关闭自由标签后只能使用受控标签
+// @Tags 人才库
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body services.TalentTagInput true "标签"
+// @Success 200 {object} utils.Response{data=models.TalentTag}
+// @Failure 400 {object} utils.Response "标签已存在"
+// @Router /api/v1/talent-tags [post]
+func (ctl *TalentController) CreateTalentTag(c *gin.Context) {
+	var input services.TalentTagInput
+	if !ctl.BindJSON(c, &input) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	tag, err := ctl.talentService.CreateTag(c.Request.Context(), userID, input)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, tag)
+}
+
+// DeleteTalentTag 删除标签
+// @Summary 删除标签
+// @Description 删除标签并从所有候选人上移除
+// @Tags 人才库
+// @Security Bearer
+// @Produce json
+// @Param id path int true "标签ID"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 404 {object} utils.Response "标签不存在"
+// @Router /api/v1/talent-tags/{id} [delete]
+func (ctl *TalentController) DeleteTalentTag(c *gin.Context) {
+	tagID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ctl.talentService.DeleteTag(c.Request.Context(), tagID); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "标签已删除"})
+}
+
+// SearchCandidates 搜索人才
+// @Summary 搜索人才
+// @Description 在投递过职位、提交过简历或加入人才库的候选人中按人才库、标签、技能、最近申请状态和最近活动时间搜索，按最近活动倒序，便于为新职位重新联系过往候选人
+// @Tags 人才库
+// @Security Bearer
+// @Produce json
+// @Param pool_id query int false "人才库ID"
+// @Param tag query []string false "标签名称，可重复，需全部匹配" collectionFormat(multi)
+// @Param skill query []string false "技能，可重复，需全部匹配" collectionFormat(multi)
+// @Param status query string false "最近一次申请的状态" Enums(pending, interviewed, hired, rejected, withdrawn)
+// @Param active_after query string false "最近活动不早于该日期，YYYY-MM-DD"
+// @Param active_before query string false "最近活动早于该日期，YYYY-MM-DD"
+// @Param exclude_job_id query int false "排除已申请该职位的候选人"
+// @Param q query string false "关键词，匹配用户名、邮箱或求职意向"
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Success 200 {object} utils.Response{data=[]services.CandidateSummary,total=int}
+// @Failure 400 {object} utils.Response "无效的查询参数"
+// @Router /api/v1/candidates [get]
+func (ctl *TalentController) SearchCandidates(c *gin.Context) {
+	var filter services.CandidateFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.RespondError(c, http.StatusBadRequest, "无效的查询参数: "+err.Error())
+		return
+	}
+	page, size := ctl.ParsePagination(c)
+
+	candidates, total, err := ctl.talentService.SearchCandidates(c.Request.Context(), filter, page, size)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{
+		"data":  candidates,
+		"total": total,
+	})
+}
+
+// TagCandidate 为候选人打标签
+// @Summary 为候选人打标签
+// @Description 标签按名称匹配，不存在时在允许自由标签的情况下自动创建；返回候选人的全部标签
+// @Tags 人才库
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "候选人ID"
+// @Param request body struct{Tags []string `json:"tags" binding:"required"`} true "标签名称"
+// @Success 200 {object} utils.Response{data=[]models.TalentTag}
+// @Failure 400 {object} utils.Response "标签不在受控标签中"
+// @Failure 404 {object} utils.Response "候选人不存在"
+// @Router /api/v1/candidates/{id}/tags [post]
+func (ctl *TalentController) TagCandidate(c *gin.Context) {
+	candidateID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var request struct {
+		Tags []string `json:"tags" binding:"required,min=1,max=20,dive,max=50"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	operatorID, _ := ctl.GetAuthUser(c)
+	tags, err := ctl.talentService.TagCandidate(c.Request.Context(), candidateID, operatorID, request.Tags)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, tags)
+}
+
+// UntagCandidate 移除候选人的标签
+// @Summary 移除候选人的标签
+// @Tags 人才库
+// @Security Bearer
+// @Produce json
+// @Param id path int true "候选人ID"
+// @Param tag_id path int true "标签ID"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 404 {object} utils.Response "候选人没有该标签"
+// @Router /api/v1/candidates/{id}/tags/{tag_id} [delete]
+func (ctl *TalentController) UntagCandidate(c *gin.Context) {
+	candidateID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	tagID, ok := ctl.ParseIDParam(c, "tag_id")
+	if !ok {
+		return
+	}
+	if err := ctl.talentService.UntagCandidate(c.Request.Context(), candidateID, tagID); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "标签已移除"})
+}
+
+// ListCandidateNotes 获取候选人备注
+// @Summary 获取候选人备注
+// @Description 按时间倒序返回备注及其作者
+// @Tags 人才库
+// @Security Bearer
+// @Produce json
+// @Param id path int true "候选人ID"
+// @Success 200 {object} utils.Response{data=[]models.CandidateNote}
+// @Router /api/v1/candidates/{id}/notes [get]
+func (ctl *TalentController) ListCandidateNotes(c *gin.Context) {
+	candidateID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	notes, err := ctl.talentService.ListNotes(c.Request.Context(), candidateID)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, notes)
+}
+
+// AddCandidateNote 添加候选人备注
+// @Summary 添加候选人备注
+// @Tags 人才库
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path int true "候选人ID"
+// @Param request body struct{Content string `json:"content" binding:"required"`} true "备注内容"
+// @Success 200 {object} utils.Response{data=models.CandidateNote}
+// @Failure 404 {object} utils.Response "候选人不存在"
+// @Router /api/v1/candidates/{id}/notes [post]
+func (ctl *TalentController) AddCandidateNote(c *gin.Context) {
+	candidateID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var request struct {
+		Content string `json:"content" binding:"required,max=5000"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	authorID, _ := ctl.GetAuthUser(c)
+	note, err := ctl.talentService.AddNote(c.Request.Context(), candidateID, authorID, request.Content)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, note)
+}
+
+// DeleteCandidateNote 删除候选人备注
+// @Summary 删除候选人备注
+// @Description 仅作者本人可删除
+// @Tags 人才库
+// @Security Bearer
+// @Produce json
+// @Param id path int true "备注ID"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 403 {object} utils.Response "不是备注作者"
+// @Failure 404 {object} utils.Response "备注不存在"
+// @Router /api/v1/candidate-notes/{id} [delete]
+func (ctl *TalentController) DeleteCandidateNote(c *gin.Context) {
+	noteID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	operatorID, _ := ctl.GetAuthUser(c)
+	if err := ctl.talentService.DeleteNote(c.Request.Context(), noteID, operatorID); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "备注已删除"})
+}
@@ -12,6 +12,7 @@ import (
 )
 
 type TrainingController struct {
+	BaseController
 	trainingService *services.TrainingService
 }
 
@@ -136,7 +137,7 @@ func (ctl *TrainingController) UpdateTrainingRecord(c *gin.Context) {
 
 // CancelTrainingRegistration 取消培训注册
 // @Summary 取消培训注册
-// @Description 取消已报名的培训课程
+// @Description 取消本人已报名的培训课程
 // @Tags 培训管理
 // @Security Bearer
 // @Produce json
@@ -151,7 +152,8 @@ func (ctl *TrainingController) CancelTrainingRegistration(c *gin.Context) {
 		utils.RespondError(c, http.StatusBadRequest, "无效的记录ID")
 		return
 	}
-	if err := ctl.trainingService.CancelTrainingRegistration(c.Request.Context(), uint(recordID)); err != nil {
+	userID, _ := ctl.GetAuthUser(c)
+	if err := ctl.trainingService.CancelTrainingRegistration(c.Request.Context(), userID, uint(recordID)); err != nil {
 		utils.RespondError(c, http.StatusInternalServerError, "取消培训注册失败")
 		return
 	}
@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"log"
 	"net/http"
 	"time"
 
@@ -14,14 +16,25 @@ import (
 
 type UserController struct {
 	BaseController
-	userService *services.UserService
+	userService    *services.UserService
+	accountService *services.AccountService
 }
 
-func NewUserController(us *services.UserService) *UserController {
-	return &UserController{userService: us}
+func NewUserController(us *services.UserService, as *services.AccountService) *UserController {
+	return &UserController{userService: us, accountService: as}
 }
 
 // Register 用户注册
+// @Summary 用户注册
+// @Description 注册候选人账户，手机号和邮箱至少填写一项，密码需满足密码策略；填写邮箱时发送验证邮件
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body struct{Username string `json:"username" binding:"required"` Password string `json:"password" binding:"required"` Phone string `json:"phone"` Email string `json:"email"`} true "注册信息"
+// @Success 200 {object} utils.Response{data=object}
+// @Failure 400 {object} utils.Response "无效的请求参数或密码不符合策略"
+// @Failure 500 {object} utils.Response "注册失败"
+// @Router /api/v1/auth/register [post]
 func (ctl *UserController) Register(c *gin.Context) {
 	var request struct {
 		Username string `json:"username" binding:"required"`
@@ -50,10 +63,22 @@ func (ctl *UserController) Register(c *gin.Context) {
 	}
 
 	if err := ctl.userService.RegisterUser(c.Request.Context(), newUser); err != nil {
+		var validationErr *utils.ValidationError
+		if errors.As(err, &validationErr) {
+			ctl.RespondServiceError(c, err)
+			return
+		}
 		utils.RespondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	// 验证邮件发送失败不影响注册，用户可稍后重新发送
+	if newUser.Email != "" {
+		if err := ctl.accountService.SendEmailVerification(c.Request.Context(), newUser.ID); err != nil {
+			log.Printf("发送验证邮件失败: %v", err)
+		}
+	}
+
 	utils.RespondSuccess(c, gin.H{
 		"user_id": newUser.ID,
 		"message": "注册成功，请完善简历信息",
@@ -61,6 +86,13 @@ func (ctl *UserController) Register(c *gin.Context) {
 }
 
 // GetProfile 获取用户信息
+// @Summary 获取当前用户信息
+// @Tags 用户
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} utils.Response{data=models.User}
+// @Failure 401 {object} utils.Response "未授权"
+// @Router /api/v1/users/profile [get]
 func (ctl *UserController) GetProfile(c *gin.Context) {
 	userID, exists := c.Get("userID") // 从认证中间件获取用户ID
 	if !exists {
@@ -76,6 +108,17 @@ func (ctl *UserController) GetProfile(c *gin.Context) {
 }
 
 // UpdateProfile 更新用户信息
+// @Summary 更新当前用户信息
+// @Description 仅可修改邮箱和手机号，修改邮箱后清除验证状态并向新邮箱发送验证邮件
+// @Tags 用户
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body object true "待更新字段（email、phone）"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "无效的请求参数、格式不正确或邮箱、手机号已被注册"
+// @Failure 401 {object} utils.Response "未授权"
+// @Router /api/v1/users/profile [put]
 func (ctl *UserController) UpdateProfile(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -87,14 +130,35 @@ func (ctl *UserController) UpdateProfile(c *gin.Context) {
 		utils.RespondError(c, http.StatusBadRequest, "无效的请求参数")
 		return
 	}
-	if err := ctl.userService.UpdateProfile(c.Request.Context(), userID.(uint), updates); err != nil {
-		utils.RespondError(c, http.StatusInternalServerError, "更新用户信息失败")
+	emailChanged, err := ctl.userService.UpdateProfile(c.Request.Context(), userID.(uint), updates)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+
+	// 新邮箱需重新验证，验证邮件发送失败时用户可稍后重新发送
+	if emailChanged {
+		if err := ctl.accountService.SendEmailVerification(c.Request.Context(), userID.(uint)); err != nil {
+			log.Printf("发送验证邮件失败: %v", err)
+		}
+		utils.RespondSuccess(c, gin.H{"message": "用户信息更新成功，请查收验证邮件完成新邮箱验证"})
 		return
 	}
 	utils.RespondSuccess(c, gin.H{"message": "用户信息更新成功"})
 }
 
 // Login 用户登录
+// @Summary 用户登录
+// @Description username 可填写用户名、邮箱或手机号；启用两步验证的账户返回 mfa_token，需调用 /auth/mfa/verify 完成登录
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body struct{Username string `json:"username" binding:"required"` Password string `json:"password" binding:"required"`} true "登录信息"
+// @Success 200 {object} utils.Response{data=services.LoginResult}
+// @Failure 400 {object} utils.Response "无效的请求参数"
+// @Failure 401 {object} utils.Response "用户名或密码错误"
+// @Failure 429 {object} utils.Response "登录失败次数过多"
+// @Router /api/v1/auth/login [post]
 func (ctl *UserController) Login(c *gin.Context) {
 	var credentials struct {
 		Username string `json:"username" binding:"required"`
@@ -108,11 +172,150 @@ func (ctl *UserController) Login(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	token, err := ctl.userService.Authenticate(ctx, credentials.Username, credentials.Password)
+	result, err := ctl.userService.Authenticate(ctx, credentials.Username, credentials.Password, c.ClientIP())
 	if err != nil {
-		utils.RespondError(c, http.StatusUnauthorized, "认证失败")
+		var attemptsErr *utils.TooManyAttemptsError
+		var authErr *utils.AuthError
+		if errors.As(err, &attemptsErr) || errors.As(err, &authErr) {
+			ctl.RespondServiceError(c, err)
+			return
+		}
+		utils.RespondError(c, http.StatusInternalServerError, "认证失败")
 		return
 	}
 
-	utils.RespondSuccess(c, gin.H{"token": token})
+	utils.RespondSuccess(c, result)
+}
+
+// ChangePassword 修改密码
+// @Summary 修改密码
+// @Description 校验当前密码后设置新密码，新密码需满足密码策略且不能与近期密码相同，成功后需重新登录
+// @Tags 认证
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body struct{CurrentPassword string `json:"current_password" binding:"required"` NewPassword string `json:"new_password" binding:"required"`} true "密码信息"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "当前密码错误或新密码不符合策略"
+// @Failure 401 {object} utils.Response "未授权"
+// @Router /api/v1/auth/password/change [post]
+func (ctl *UserController) ChangePassword(c *gin.Context) {
+	var request struct {
+		CurrentPassword string `json:"current_password" binding:"required"`
+		NewPassword     string `json:"new_password" binding:"required"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+	userID, _ := ctl.GetAuthUser(c)
+	if err := ctl.userService.ChangePassword(c.Request.Context(), userID, request.CurrentPassword, request.NewPassword); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "密码已修改，请重新登录"})
+}
+
+// RefreshToken 刷新令牌
+// @Summary 刷新令牌
+// @Description 使用刷新令牌换取新的访问令牌和刷新令牌，旧刷新令牌随即作废
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body struct{RefreshToken string `json:"refresh_token" binding:"required"`} true "刷新令牌"
+// @Success 200 {object} utils.Response{data=services.TokenPair}
+// @Failure 400 {object} utils.Response "无效的请求参数"
+// @Failure 401 {object} utils.Response "刷新令牌无效或已失效"
+// @Router /api/v1/auth/refresh [post]
+func (ctl *UserController) RefreshToken(c *gin.Context) {
+	var request struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if !ctl.BindJSON(c, &request) {
+		return
+	}
+
+	tokens, err := ctl.userService.RefreshToken(c.Request.Context(), request.RefreshToken)
+	if err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, tokens)
+}
+
+// Logout 退出登录
+// @Summary 退出登录
+// @Description 吊销当前访问令牌，若提供刷新令牌则一并吊销
+// @Tags 认证
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body struct{RefreshToken string `json:"refresh_token"`} false "刷新令牌"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "无效的刷新令牌"
+// @Failure 401 {object} utils.Response "未授权"
+// @Router /api/v1/auth/logout [post]
+func (ctl *UserController) Logout(c *gin.Context) {
+	claims, ok := c.Get("claims")
+	if !ok {
+		utils.RespondError(c, http.StatusUnauthorized, "未授权")
+		return
+	}
+
+	var request struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	// 请求体可选
+	_ = c.ShouldBindJSON(&request)
+
+	if err := ctl.userService.Logout(c.Request.Context(), claims.(*utils.Claims), request.RefreshToken); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "已退出登录"})
+}
+
+// RevokeUserTokens 吊销用户全部令牌
+// @Summary 吊销用户全部令牌
+// @Description 吊销指定用户此前签发的全部访问令牌和刷新令牌，用户需重新登录
+// @Tags 用户管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "无效的ID"
+// @Failure 404 {object} utils.Response "用户不存在"
+// @Router /api/v1/users/{id}/tokens/revoke [post]
+func (ctl *UserController) RevokeUserTokens(c *gin.Context) {
+	userID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ctl.userService.RevokeUserTokens(c.Request.Context(), userID); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "用户令牌已全部吊销"})
+}
+
+// UnlockAccount 解除账户登录锁定
+// @Summary 解除账户登录锁定
+// @Description 清除指定用户的登录失败记录并解除临时锁定
+// @Tags 用户管理
+// @Security Bearer
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} utils.Response{message=string}
+// @Failure 400 {object} utils.Response "无效的ID"
+// @Failure 404 {object} utils.Response "用户不存在"
+// @Router /api/v1/users/{id}/unlock [post]
+func (ctl *UserController) UnlockAccount(c *gin.Context) {
+	userID, ok := ctl.ParseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := ctl.userService.UnlockAccount(c.Request.Context(), userID); err != nil {
+		ctl.RespondServiceError(c, err)
+		return
+	}
+	utils.RespondSuccess(c, gin.H{"message": "账户已解锁"})
 }
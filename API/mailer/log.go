@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogMailer 将邮件写入文件或标准日志而不真正发送，用于本地开发和测试
+type LogMailer struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLogMailer 创建日志邮件发送器，path 为空时输出到标准日志
+func NewLogMailer(path string) *LogMailer {
+	return &LogMailer{path: path}
+}
+
+// Send 记录邮件内容
+func (m *LogMailer) Send(_ context.Context, msg Message) error {
+	entry := fmt.Sprintf("==== %s ====\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC3339), strings.Join(msg.To, ", "), msg.Subject, msg.Body)
+
+	if m.path == "" {
+		log.Printf("📧 邮件(未发送)\n%s", entry)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("打开邮件输出文件失败: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(entry); err != nil {
+		return fmt.Errorf("写入邮件输出文件失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// Message 待发送的邮件
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// MailConfig 邮件配置
+type MailConfig struct {
+	Driver       string // smtp | file | log
+	From         string
+	FilePath     string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPTLS      bool // 使用隐式TLS（通常为465端口），否则在服务器支持时使用STARTTLS
+}
+
+// LoadMailConfig 从配置文件加载邮件配置
+func LoadMailConfig() MailConfig {
+	return MailConfig{
+		Driver:       viper.GetString("mail.driver"),
+		From:         viper.GetString("mail.from"),
+		FilePath:     viper.GetString("mail.file_path"),
+		SMTPHost:     viper.GetString("mail.smtp.host"),
+		SMTPPort:     viper.GetInt("mail.smtp.port"),
+		SMTPUsername: viper.GetString("mail.smtp.username"),
+		SMTPPassword: viper.GetString("mail.smtp.password"),
+		SMTPTLS:      viper.GetBool("mail.smtp.tls"),
+	}
+}
+
+// New 根据配置创建邮件发送器，未配置时使用日志输出
+func New(config MailConfig) (Mailer, error) {
+	switch config.Driver {
+	case "smtp":
+		if config.SMTPHost == "" || config.SMTPPort == 0 {
+			return nil, fmt.Errorf("SMTP 配置不完整")
+		}
+		if config.From == "" {
+			return nil, fmt.Errorf("未配置发件人 mail.from")
+		}
+		return NewSMTPMailer(config), nil
+	case "file":
+		if config.FilePath == "" {
+			return nil, fmt.Errorf("未配置邮件输出文件 mail.file_path")
+		}
+		return NewLogMailer(config.FilePath), nil
+	case "", "log":
+		return NewLogMailer(""), nil
+	default:
+		return nil, fmt.Errorf("不支持的邮件驱动: %s", config.Driver)
+	}
+}
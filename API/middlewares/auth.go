@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"strings"
@@ -10,7 +11,13 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func JWT() gin.HandlerFunc {
+// TokenVerifier 解析访问令牌并查询其是否已在服务端被吊销
+type TokenVerifier interface {
+	ParseAccessToken(tokenString string) (*utils.Claims, error)
+	IsRevoked(ctx context.Context, claims *utils.Claims) (bool, error)
+}
+
+func JWT(verifier TokenVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		log.Printf("[JWT] 请求路径: %s %s | Authorization头: %s", c.Request.Method, c.Request.URL.Path, c.GetHeader("Authorization"))
 
@@ -23,7 +30,7 @@ func JWT() gin.HandlerFunc {
 		}
 
 		tokenString = strings.TrimPrefix(tokenString, "Bearer ")
-		claims, err := utils.ParseToken(tokenString)
+		claims, err := verifier.ParseAccessToken(tokenString)
 		if err != nil {
 			log.Printf("[JWT] 解析失败: %v | 原始令牌: %s", err, tokenString[:10]+"...")
 			utils.RespondError(c, http.StatusUnauthorized, "无效的令牌")
@@ -31,10 +38,25 @@ func JWT() gin.HandlerFunc {
 			return
 		}
 
+		revoked, err := verifier.IsRevoked(c.Request.Context(), claims)
+		if err != nil {
+			log.Printf("[JWT] 查询吊销状态失败: %v", err)
+			utils.RespondError(c, http.StatusInternalServerError, "令牌校验失败")
+			c.Abort()
+			return
+		}
+		if revoked {
+			log.Printf("[JWT] 令牌已吊销 | 用户ID: %d | jti: %s", claims.UserID, claims.ID)
+			utils.RespondError(c, http.StatusUnauthorized, "令牌已失效")
+			c.Abort()
+			return
+		}
+
 		log.Printf("[JWT] 解析成功 | 用户ID: %d | 角色: %v | 过期时间: %v", claims.UserID, claims.Roles, claims.ExpiresAt)
 
 		c.Set("userID", uint(claims.UserID))
 		c.Set("roles", claims.Roles)
+		c.Set("claims", claims)
 		// 添加类型断言确保后续使用安全
 		if claims.UserID == 0 {
 			log.Println("[JWT] 错误: 无效的用户标识")
@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"API/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionResolver 将角色解析为权限代码
+type PermissionResolver interface {
+	GetPermissionCodesByRoles(ctx context.Context, roles []string) ([]string, error)
+}
+
+// RequirePermission 校验当前用户的角色是否拥有指定权限代码，需在 JWT() 之后使用
+func RequirePermission(resolver PermissionResolver, code string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roles := c.GetStringSlice("roles")
+		if len(roles) == 0 {
+			utils.RespondError(c, http.StatusForbidden, "权限不足")
+			c.Abort()
+			return
+		}
+
+		codes, err := resolver.GetPermissionCodesByRoles(c.Request.Context(), roles)
+		if err != nil {
+			log.Printf("[Permission] 解析权限失败: %v | 角色: %v", err, roles)
+			utils.RespondError(c, http.StatusInternalServerError, "权限校验失败")
+			c.Abort()
+			return
+		}
+
+		for _, granted := range codes {
+			if granted == code {
+				c.Next()
+				return
+			}
+		}
+
+		utils.RespondError(c, http.StatusForbidden, "缺少权限: "+code)
+		c.Abort()
+	}
+}
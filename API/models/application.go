@@ -2,13 +2,23 @@ package models
 
 import "gorm.io/gorm"
 
-// Application 职位申请模型
+// Application 职位申请模型，Status 由所在招聘阶段推导，保留以兼容按状态筛选；
+// 候选人撤回的申请移入淘汰阶段，状态为 withdrawn
 type Application struct {
 	gorm.Model
-	UserID uint   `gorm:"uniqueIndex:uniq_user_job;not null;comment:用户ID"`
-	JobID  uint   `gorm:"uniqueIndex:uniq_user_job;not null;comment:职位ID"`
-	Status string `gorm:"type:ENUM('pending','interviewed','hired','rejected');default:'pending';comment:申请状态"`
+	UserID          uint   `gorm:"uniqueIndex:uniq_user_job;not null;comment:用户ID"`
+	JobID           uint   `gorm:"uniqueIndex:uniq_user_job;not null;comment:职位ID"`
+	Status          string `gorm:"type:ENUM('pending','interviewed','hired','rejected','withdrawn');default:'pending';comment:申请状态"`
+	StageID         *uint  `gorm:"index;comment:当前阶段ID"`
+	ResumeID        *uint  `gorm:"comment:投递时附带的简历ID"`
+	ResumeVersionID *uint  `gorm:"index;comment:投递时固定的简历版本ID"`
+	CoverLetter     string `gorm:"type:text;comment:求职信"`
 
-	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
-	Job  Job  `gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE;"`
+	User          User           `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+	Job           Job            `gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE;"`
+	Stage         *PipelineStage `gorm:"foreignKey:StageID"`
+	Resume        *Resume        `gorm:"foreignKey:ResumeID"`
+	ResumeVersion *ResumeVersion `gorm:"foreignKey:ResumeVersionID"`
+
+	Recommendation *InterviewRecommendation `gorm:"-"` // 面试评分汇总，查询时填充
 }
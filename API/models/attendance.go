@@ -12,11 +12,19 @@ type Attendance struct {
 	gorm.Model
 	UserID   uint       `gorm:"index:idx_user_date;not null;comment:用户ID"`
 	ClockIn  time.Time  `gorm:"not null;comment:打卡时间"`
-	ClockOut *time.Time `gorm:"comment:签退时间"`
+	ClockOut *time.Time `gorm:"index:idx_open_attendance,priority:1;comment:签退时间"`
 	Status   string     `gorm:"type:ENUM('normal','late','early_leave');default:'normal';comment:考勤状态"`
-	Date     time.Time  `gorm:"index:idx_user_date;type:date;comment:考勤日期"`
+	Date     time.Time  `gorm:"index:idx_user_date;index:idx_open_attendance,priority:3;type:date;comment:考勤日期"`
 	Duration float64    `gorm:"-;comment:出勤时长（小时）"`
 
+	ScheduleID        *uint `gorm:"comment:打卡时适用的班次ID"`
+	LateMinutes       int   `gorm:"not null;default:0;comment:迟到分钟数"`
+	EarlyLeaveMinutes int   `gorm:"not null;default:0;comment:早退分钟数"`
+
+	OvertimeMinutes int    `gorm:"not null;default:0;comment:超出班次的加班分钟数"`
+	OvertimeKind    string `gorm:"size:10;comment:加班类型：weekday/weekend/holiday"`
+	ClockOutMissing bool   `gorm:"not null;default:false;index;index:idx_open_attendance,priority:2;comment:班次结束后未下班打卡，由每日任务标记"`
+
 	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
 }
 
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 补卡类型
+const (
+	CorrectionMissingClockIn  = "missing_clock_in"  // 漏打上班卡
+	CorrectionMissingClockOut = "missing_clock_out" // 漏打下班卡
+	CorrectionWrongTime       = "wrong_time"        // 打卡时间有误
+)
+
+// 补卡申请状态
+const (
+	CorrectionPending  = "pending"
+	CorrectionApproved = "approved"
+	CorrectionRejected = "rejected"
+	CorrectionCanceled = "canceled"
+)
+
+// AttendanceCorrection 补卡申请，由员工所在部门的负责人或人事审批。
+// 审批通过时按申请的时间改写打卡记录，并在 Original* 字段保留改写前的值
+type AttendanceCorrection struct {
+	gorm.Model
+	UserID       uint       `gorm:"index;not null;comment:员工ID"`
+	Date         time.Time  `gorm:"type:date;index;not null;comment:考勤日期"`
+	AttendanceID *uint      `gorm:"index;comment:更正的打卡记录ID"`
+	Kind         string     `gorm:"type:ENUM('missing_clock_in','missing_clock_out','wrong_time');not null;comment:补卡类型"`
+	ClockIn      *time.Time `gorm:"comment:更正后的上班时间"`
+	ClockOut     *time.Time `gorm:"comment:更正后的下班时间"`
+	Reason       string     `gorm:"size:500;not null;comment:补卡原因"`
+	Status       string     `gorm:"type:ENUM('pending','approved','rejected','canceled');default:'pending';index;comment:审批状态"`
+	ApproverID   *uint      `gorm:"comment:审批人ID"`
+	Comment      string     `gorm:"size:500;comment:审批意见"`
+	DecidedAt    *time.Time `gorm:"comment:审批时间"`
+
+	OriginalClockIn  *time.Time `gorm:"comment:更正前的上班时间"`
+	OriginalClockOut *time.Time `gorm:"comment:更正前的下班时间"`
+	OriginalStatus   string     `gorm:"size:20;comment:更正前的考勤状态"`
+
+	User User `gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 节假日日历中的日期类型
+const (
+	HolidayOff     = "holiday" // 法定节假日或调休放假
+	HolidayWorkday = "workday" // 调休上班，即使是周末或班次的休息日也需上班
+)
+
+// HolidayCalendar 按地区维护的节假日日历，员工按所属地区匹配，
+// 未设置地区或没有对应日历的员工使用默认日历
+type HolidayCalendar struct {
+	gorm.Model
+	Name        string `gorm:"size:50;uniqueIndex;not null;comment:日历名称"`
+	Region      string `gorm:"size:50;uniqueIndex;not null;comment:适用地区"`
+	IsDefault   bool   `gorm:"not null;default:false;comment:是否为默认日历"`
+	Description string `gorm:"size:200;comment:日历说明"`
+}
+
+// Holiday 日历中的节假日或调休上班日
+type Holiday struct {
+	ID         uint      `gorm:"primarykey"`
+	CalendarID uint      `gorm:"uniqueIndex:uniq_calendar_date;not null;comment:日历ID"`
+	Date       time.Time `gorm:"type:date;uniqueIndex:uniq_calendar_date;not null;comment:日期"`
+	Name       string    `gorm:"size:100;not null;comment:节日名称"`
+	Kind       string    `gorm:"type:ENUM('holiday','workday');default:'holiday';not null;comment:放假或调休上班"`
+}
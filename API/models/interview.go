@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 面试状态
+const (
+	InterviewScheduled = "scheduled"
+	InterviewCompleted = "completed"
+	InterviewCanceled  = "canceled"
+)
+
+// 面试官推荐意见，分值用于汇总
+const (
+	RecommendStrongYes = "strong_yes"
+	RecommendYes       = "yes"
+	RecommendNo        = "no"
+	RecommendStrongNo  = "strong_no"
+)
+
+// RecommendationScores 推荐意见对应的分值
+var RecommendationScores = map[string]int{
+	RecommendStrongYes: 2,
+	RecommendYes:       1,
+	RecommendNo:        -1,
+	RecommendStrongNo:  -2,
+}
+
+// Interview 面试安排，关联一个职位申请和多名面试官
+type Interview struct {
+	gorm.Model
+	ApplicationID uint      `gorm:"index;not null;comment:申请ID"`
+	StageID       *uint     `gorm:"comment:安排时所在招聘阶段ID"`
+	StartTime     time.Time `gorm:"index;not null;comment:开始时间"`
+	EndTime       time.Time `gorm:"index;not null;comment:结束时间"`
+	Location      string    `gorm:"size:100;comment:面试地点或会议链接"`
+	Status        string    `gorm:"type:ENUM('scheduled','completed','canceled');default:'scheduled';index;comment:面试状态"`
+	Notes         string    `gorm:"type:text;comment:备注"`
+	CreatedBy     uint      `gorm:"comment:安排人ID"`
+
+	Application  Application    `gorm:"foreignKey:ApplicationID;constraint:OnDelete:CASCADE;"`
+	Stage        *PipelineStage `gorm:"foreignKey:StageID"`
+	Interviewers []User         `gorm:"many2many:interview_interviewers;"`
+	Scorecards   []Scorecard    `gorm:"foreignKey:InterviewID"`
+}
+
+// Scorecard 面试官对一场面试的评分表，每名面试官每场面试一份
+type Scorecard struct {
+	gorm.Model
+	InterviewID    uint   `gorm:"uniqueIndex:uniq_interview_interviewer;not null;comment:面试ID"`
+	InterviewerID  uint   `gorm:"uniqueIndex:uniq_interview_interviewer;not null;comment:面试官ID"`
+	Recommendation string `gorm:"type:ENUM('strong_yes','yes','no','strong_no');not null;comment:推荐意见"`
+	Summary        string `gorm:"type:text;comment:总体评价"`
+
+	Interviewer User              `gorm:"foreignKey:InterviewerID"`
+	Ratings     []ScorecardRating `gorm:"foreignKey:ScorecardID;constraint:OnDelete:CASCADE;"`
+}
+
+// ScorecardRating 评分表中单个考察项的评分
+type ScorecardRating struct {
+	gorm.Model
+	ScorecardID uint   `gorm:"index;not null;comment:评分表ID"`
+	Criterion   string `gorm:"size:50;not null;comment:考察项"`
+	Rating      uint8  `gorm:"not null;comment:评分(1-5)"`
+	Comment     string `gorm:"size:500;comment:评语"`
+}
+
+// InterviewRecommendation 申请所有面试评分的汇总，不落库
+type InterviewRecommendation struct {
+	Scorecards     int                // 已提交的评分表数量
+	Score          float64            // 推荐意见平均分，范围 -2 ~ 2
+	Recommendation string             // 汇总推荐意见，分歧持平时为 mixed
+	Criteria       map[string]float64 // 各考察项平均分
+}
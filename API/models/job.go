@@ -6,18 +6,46 @@ import (
 	"gorm.io/gorm"
 )
 
+// 薪资计算周期
+const (
+	SalaryPerHour  = "hour"
+	SalaryPerDay   = "day"
+	SalaryPerMonth = "month"
+	SalaryPerYear  = "year"
+)
+
+// MonthlySalaryFactors 各计算周期折算为月薪的系数，按每月21.75个工作日、每天8小时计
+var MonthlySalaryFactors = map[string]float64{
+	SalaryPerHour:  174,
+	SalaryPerDay:   21.75,
+	SalaryPerMonth: 1,
+	SalaryPerYear:  1.0 / 12,
+}
+
+// 职位状态：新建为草稿，提交招聘需求后待审批，审批通过后发布为开放
+const (
+	JobDraft           = "draft"
+	JobPendingApproval = "pending_approval"
+	JobOpen            = "open"
+	JobClosed          = "closed"
+)
+
 // Job 职位模型
 type Job struct {
 	gorm.Model
 	Title          string     `gorm:"size:100;not null;index;comment:职位名称"`
 	Description    string     `gorm:"type:text;not null;comment:职位描述"`
 	Requirements   string     `gorm:"type:text;not null;comment:职位要求"`
-	SalaryRange    string     `gorm:"size:50;comment:薪资范围"`
+	SalaryMin      *float64   `gorm:"type:decimal(12,2);index;comment:薪资下限"`
+	SalaryMax      *float64   `gorm:"type:decimal(12,2);index;comment:薪资上限"`
+	SalaryCurrency string     `gorm:"size:3;default:'CNY';comment:薪资币种(ISO 4217)"`
+	SalaryPeriod   string     `gorm:"type:ENUM('hour','day','month','year');default:'month';comment:薪资计算周期"`
 	ExpirationDate *time.Time `gorm:"comment:截止日期"`
-	Status         string     `gorm:"type:ENUM('open','closed');default:'open';index;comment:职位状态"`
+	Status         string     `gorm:"type:ENUM('draft','pending_approval','open','closed');default:'draft';index;comment:职位状态"`
 	Category       string     `gorm:"size:50;index;comment:职位分类"`
 	Location       string     `gorm:"size:100;index;comment:工作地点"`
 	Experience     uint       `gorm:"default:0;comment:所需工作年限"`
 
 	Applications []Application `gorm:"foreignKey:JobID"`
+	Skills       []Skill       `gorm:"many2many:job_skills;"` // 技能要求，用于候选人匹配
 }
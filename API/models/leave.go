@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 内置假期类型
+const (
+	LeaveAnnual   = "annual"
+	LeaveSick     = "sick"
+	LeavePersonal = "personal"
+	LeaveUnpaid   = "unpaid"
+)
+
+// 请假申请状态
+const (
+	LeavePending  = "pending"
+	LeaveApproved = "approved"
+	LeaveRejected = "rejected"
+	LeaveCanceled = "canceled"
+)
+
+// LeaveType 假期类型及其额度规则，额度单位均为天
+type LeaveType struct {
+	ID              uint    `gorm:"primarykey"`
+	Code            string  `gorm:"size:20;uniqueIndex;not null;comment:假期类型标识"`
+	Name            string  `gorm:"size:50;not null;comment:假期名称"`
+	Paid            bool    `gorm:"not null;comment:是否带薪"`
+	RequiresBalance bool    `gorm:"not null;comment:是否占用假期额度"`
+	MonthlyAccrual  float64 `gorm:"type:decimal(5,2);not null;default:0;comment:每月累计天数"`
+	MaxBalance      float64 `gorm:"type:decimal(6,2);not null;default:0;comment:额度上限，0表示不限"`
+	MaxCarryOver    float64 `gorm:"type:decimal(6,2);not null;default:0;comment:可结转至次年的天数上限"`
+}
+
+// DefaultLeaveTypes 启动时自动写入的内置假期类型，已存在的不会被覆盖
+var DefaultLeaveTypes = []LeaveType{
+	{Code: LeaveAnnual, Name: "年假", Paid: true, RequiresBalance: true, MonthlyAccrual: 1.25, MaxBalance: 20, MaxCarryOver: 5},
+	{Code: LeaveSick, Name: "病假", Paid: true, RequiresBalance: true, MonthlyAccrual: 1, MaxBalance: 12},
+	{Code: LeavePersonal, Name: "事假", Paid: true, RequiresBalance: true, MonthlyAccrual: 0.5, MaxBalance: 6},
+	{Code: LeaveUnpaid, Name: "无薪假", Paid: false, RequiresBalance: false},
+}
+
+// LeaveBalance 员工某一年度某类假期的额度，可用天数为结转、累计之和减去已用
+type LeaveBalance struct {
+	ID           uint      `gorm:"primarykey"`
+	UserID       uint      `gorm:"uniqueIndex:uniq_leave_balance;not null;comment:员工ID"`
+	LeaveTypeID  uint      `gorm:"uniqueIndex:uniq_leave_balance;not null;comment:假期类型ID"`
+	Year         int       `gorm:"uniqueIndex:uniq_leave_balance;not null;comment:年度"`
+	CarriedOver  float64   `gorm:"type:decimal(6,2);not null;default:0;comment:上年结转天数"`
+	Accrued      float64   `gorm:"type:decimal(6,2);not null;default:0;comment:本年累计天数"`
+	Used         float64   `gorm:"type:decimal(6,2);not null;default:0;comment:已用天数"`
+	AccruedMonth int       `gorm:"not null;default:0;comment:已累计到的月份"`
+	UpdatedAt    time.Time `gorm:"comment:更新时间"`
+
+	LeaveType LeaveType `gorm:"foreignKey:LeaveTypeID"`
+}
+
+// Available 可用天数
+func (b LeaveBalance) Available() float64 {
+	return b.CarriedOver + b.Accrued - b.Used
+}
+
+// LeaveRequest 请假申请，由员工所在部门的负责人或人事审批
+type LeaveRequest struct {
+	gorm.Model
+	UserID      uint       `gorm:"index;not null;comment:员工ID"`
+	LeaveTypeID uint       `gorm:"not null;comment:假期类型ID"`
+	StartDate   time.Time  `gorm:"type:date;index;not null;comment:开始日期"`
+	EndDate     time.Time  `gorm:"type:date;index;not null;comment:结束日期（含）"`
+	Days        float64    `gorm:"type:decimal(5,2);not null;comment:请假工作日天数"`
+	Reason      string     `gorm:"size:500;comment:请假原因"`
+	Status      string     `gorm:"type:ENUM('pending','approved','rejected','canceled');default:'pending';index;comment:审批状态"`
+	ApproverID  *uint      `gorm:"comment:审批人ID"`
+	Comment     string     `gorm:"size:500;comment:审批意见"`
+	DecidedAt   *time.Time `gorm:"comment:审批时间"`
+
+	User      User      `gorm:"foreignKey:UserID"`
+	LeaveType LeaveType `gorm:"foreignKey:LeaveTypeID"`
+}
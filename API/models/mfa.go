@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MFARecoveryCode 两步验证恢复码，仅保存哈希，每个恢复码只能使用一次
+type MFARecoveryCode struct {
+	gorm.Model
+	UserID   uint       `gorm:"index;not null;comment:用户ID"`
+	CodeHash string     `gorm:"size:64;uniqueIndex;not null;comment:恢复码哈希"`
+	UsedAt   *time.Time `gorm:"comment:使用时间"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Offer 状态：草稿经审批后发送给候选人，候选人接受、拒绝或逾期未回复；
+// 申请撤回或淘汰时尚未答复的录用通知作废
+const (
+	OfferDraft    = "draft"
+	OfferApproved = "approved"
+	OfferSent     = "sent"
+	OfferAccepted = "accepted"
+	OfferDeclined = "declined"
+	OfferExpired  = "expired"
+	OfferVoided   = "voided"
+)
+
+// OfferTemplate 录用通知模板，正文为 Go text/template
+type OfferTemplate struct {
+	gorm.Model
+	Name      string `gorm:"size:100;uniqueIndex;not null;comment:模板名称"`
+	Body      string `gorm:"type:text;not null;comment:模板正文"`
+	CreatedBy uint   `gorm:"comment:创建人ID"`
+}
+
+// Offer 发给已录用申请人的录用通知
+type Offer struct {
+	gorm.Model
+	ApplicationID uint       `gorm:"index;not null;comment:申请ID"`
+	TemplateID    uint       `gorm:"not null;comment:模板ID"`
+	Position      string     `gorm:"size:50;not null;comment:职位"`
+	Department    string     `gorm:"size:50;not null;comment:部门"`
+	SalaryBase    float64    `gorm:"type:decimal(12,2);not null;comment:基本工资"`
+	StartDate     time.Time  `gorm:"type:date;not null;comment:入职日期"`
+	ExpiresAt     time.Time  `gorm:"index;not null;comment:答复截止时间"`
+	Status        string     `gorm:"type:ENUM('draft','approved','sent','accepted','declined','expired','voided');default:'draft';index;comment:状态"`
+	Content       string     `gorm:"type:text;comment:按模板生成的正文"`
+	CreatedBy     uint       `gorm:"comment:创建人ID"`
+	ApprovedBy    *uint      `gorm:"comment:审批人ID"`
+	ApprovedAt    *time.Time `gorm:"comment:审批时间"`
+	SentAt        *time.Time `gorm:"comment:发送时间"`
+	RespondedAt   *time.Time `gorm:"comment:候选人答复时间"`
+	DeclineReason string     `gorm:"size:500;comment:拒绝原因"`
+
+	Application Application   `gorm:"foreignKey:ApplicationID;constraint:OnDelete:CASCADE;"`
+	Template    OfferTemplate `gorm:"foreignKey:TemplateID"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 加班类型，按加班日期是工作日、休息日还是法定节假日划分
+const (
+	OvertimeWeekday = "weekday"
+	OvertimeWeekend = "weekend"
+	OvertimeHoliday = "holiday"
+)
+
+// 加班申请状态
+const (
+	OvertimePending  = "pending"
+	OvertimeApproved = "approved"
+	OvertimeRejected = "rejected"
+	OvertimeCanceled = "canceled"
+)
+
+// OvertimeRequest 加班申请，可事前申请或按打卡记录事后补报，由员工所在部门的负责人或人事审批。
+// 计薪时长取批准时长与打卡记录中加班时长的较小值
+type OvertimeRequest struct {
+	gorm.Model
+	UserID       uint       `gorm:"index;not null;comment:员工ID"`
+	Date         time.Time  `gorm:"type:date;index;not null;comment:加班日期"`
+	Hours        float64    `gorm:"type:decimal(4,2);not null;comment:申请加班时长（小时）"`
+	Kind         string     `gorm:"type:ENUM('weekday','weekend','holiday');not null;comment:加班类型"`
+	PostHoc      bool       `gorm:"not null;default:false;comment:是否为事后补报"`
+	AttendanceID *uint      `gorm:"comment:事后补报对应的打卡记录ID"`
+	Reason       string     `gorm:"size:500;not null;comment:加班事由"`
+	Status       string     `gorm:"type:ENUM('pending','approved','rejected','canceled');default:'pending';index;comment:审批状态"`
+	ApproverID   *uint      `gorm:"comment:审批人ID"`
+	Comment      string     `gorm:"size:500;comment:审批意见"`
+	DecidedAt    *time.Time `gorm:"comment:审批时间"`
+
+	User User `gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "gorm.io/gorm"
+
+// PasswordHistory 历史密码哈希，用于禁止重复使用近期密码
+type PasswordHistory struct {
+	gorm.Model
+	UserID       uint   `gorm:"index;not null;comment:用户ID"`
+	PasswordHash string `gorm:"size:60;not null;comment:密码哈希"`
+}
@@ -10,3 +10,95 @@ type Permission struct {
 
 	Roles []Role `gorm:"many2many:role_permissions;"`
 }
+
+// 系统内置权限代码（格式: 资源:操作）
+const (
+	PermSalaryGenerate     = "salary:generate"
+	PermSalaryView         = "salary:view"
+	PermNoticeCreate       = "notice:create"
+	PermNoticeUpdate       = "notice:update"
+	PermNoticeDelete       = "notice:delete"
+	PermRoleCreate         = "role:create"
+	PermRoleView           = "role:view"
+	PermRoleUpdate         = "role:update"
+	PermRoleDelete         = "role:delete"
+	PermRoleGrant          = "role:grant"
+	PermUserAssignRole     = "user:assign_role"
+	PermUserPermissions    = "user:permissions"
+	PermUserRevokeTokens   = "user:revoke_tokens"
+	PermUserUnlock         = "user:unlock"
+	PermUserResetMFA       = "user:reset_mfa"
+	PermJobView            = "job:view"
+	PermJobCreate          = "job:create"
+	PermJobUpdate          = "job:update"
+	PermJobDelete          = "job:delete"
+	PermJobApply           = "job:apply"
+	PermPermissionView     = "permission:view"
+	PermPermissionCreate   = "permission:create"
+	PermAttendanceStats    = "attendance:stats"
+	PermTrainingCreate     = "training:create"
+	PermTrainingGrade      = "training:grade"
+	PermApplicationReview  = "application:review"
+	PermPipelineManage     = "pipeline:manage"
+	PermInterviewManage    = "interview:manage"
+	PermOfferManage        = "offer:manage"
+	PermOfferApprove       = "offer:approve"
+	PermRequisitionCreate  = "requisition:create"
+	PermRequisitionApprove = "requisition:approve"
+	PermJobPublish         = "job:publish"
+	PermTalentManage       = "talent:manage"
+	PermTalentTagManage    = "talent:tag_manage"
+	PermScheduleManage     = "schedule:manage"
+	PermLeaveApprove       = "leave:approve"
+	PermLeaveManage        = "leave:manage"
+	PermHolidayManage      = "holiday:manage"
+	PermOvertimeApprove    = "overtime:approve"
+	PermOvertimeView       = "overtime:view"
+	PermAttendanceCorrect  = "attendance:correct"
+)
+
+// DefaultPermissions 系统内置权限列表，启动时自动写入数据库
+var DefaultPermissions = []Permission{
+	{Code: PermSalaryGenerate, Description: "生成薪资记录"},
+	{Code: PermSalaryView, Description: "查看薪资记录"},
+	{Code: PermNoticeCreate, Description: "发布通知"},
+	{Code: PermNoticeUpdate, Description: "更新通知"},
+	{Code: PermNoticeDelete, Description: "删除通知"},
+	{Code: PermRoleCreate, Description: "创建角色"},
+	{Code: PermRoleView, Description: "查看角色"},
+	{Code: PermRoleUpdate, Description: "更新角色"},
+	{Code: PermRoleDelete, Description: "删除角色"},
+	{Code: PermRoleGrant, Description: "为角色授予或撤销权限"},
+	{Code: PermUserAssignRole, Description: "为用户分配或移除角色"},
+	{Code: PermUserPermissions, Description: "查看用户的有效权限"},
+	{Code: PermUserRevokeTokens, Description: "吊销用户的全部令牌"},
+	{Code: PermUserUnlock, Description: "解除账户登录锁定"},
+	{Code: PermUserResetMFA, Description: "重置用户的两步验证"},
+	{Code: PermJobView, Description: "查看职位"},
+	{Code: PermJobCreate, Description: "创建职位"},
+	{Code: PermJobUpdate, Description: "更新职位"},
+	{Code: PermJobDelete, Description: "删除职位"},
+	{Code: PermJobApply, Description: "申请职位"},
+	{Code: PermPermissionView, Description: "查看权限"},
+	{Code: PermPermissionCreate, Description: "创建权限"},
+	{Code: PermAttendanceStats, Description: "查看考勤统计"},
+	{Code: PermTrainingCreate, Description: "创建培训课程"},
+	{Code: PermTrainingGrade, Description: "更新培训记录的状态和成绩"},
+	{Code: PermApplicationReview, Description: "查看职位申请并推进招聘阶段"},
+	{Code: PermPipelineManage, Description: "配置职位的招聘流程阶段"},
+	{Code: PermInterviewManage, Description: "安排、改期和取消面试"},
+	{Code: PermOfferManage, Description: "管理录用通知模板，起草和发送录用通知"},
+	{Code: PermOfferApprove, Description: "审批录用通知"},
+	{Code: PermRequisitionCreate, Description: "为草稿职位发起招聘需求"},
+	{Code: PermRequisitionApprove, Description: "按所持角色审批招聘需求"},
+	{Code: PermJobPublish, Description: "发布审批通过的职位"},
+	{Code: PermTalentManage, Description: "管理人才库，搜索候选人并维护候选人标签和备注"},
+	{Code: PermTalentTagManage, Description: "定义和删除受控的候选人标签"},
+	{Code: PermScheduleManage, Description: "管理班次并为员工或部门分配班次"},
+	{Code: PermLeaveApprove, Description: "审批请假申请，部门负责人限本部门员工"},
+	{Code: PermLeaveManage, Description: "配置假期额度规则并查看员工的假期额度"},
+	{Code: PermHolidayManage, Description: "维护节假日日历和调休上班日，设置员工的工作地区"},
+	{Code: PermOvertimeApprove, Description: "审批加班申请，部门负责人限本部门员工"},
+	{Code: PermOvertimeView, Description: "查看全体员工的月度加班统计"},
+	{Code: PermAttendanceCorrect, Description: "审批补卡申请，部门负责人限本部门员工"},
+}
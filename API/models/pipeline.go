@@ -0,0 +1,43 @@
+package models
+
+import "gorm.io/gorm"
+
+// 招聘阶段类型：进行中的阶段按顺序推进，录用和淘汰为终止阶段
+const (
+	StageKindActive   = "active"
+	StageKindHired    = "hired"
+	StageKindRejected = "rejected"
+)
+
+// PipelineStage 职位招聘流程中的阶段，每个职位可单独配置
+type PipelineStage struct {
+	gorm.Model
+	JobID    uint   `gorm:"uniqueIndex:uniq_job_stage;not null;comment:职位ID"`
+	Code     string `gorm:"uniqueIndex:uniq_job_stage;size:50;not null;comment:阶段代码"`
+	Name     string `gorm:"size:50;not null;comment:阶段名称"`
+	Kind     string `gorm:"type:ENUM('active','hired','rejected');default:'active';comment:阶段类型"`
+	Position int    `gorm:"not null;default:0;comment:排序"`
+}
+
+// ApplicationStageHistory 申请在招聘阶段间的流转记录
+type ApplicationStageHistory struct {
+	gorm.Model
+	ApplicationID uint   `gorm:"index;not null;comment:申请ID"`
+	FromStageID   *uint  `gorm:"comment:原阶段ID"`
+	ToStageID     uint   `gorm:"not null;comment:目标阶段ID"`
+	MovedBy       uint   `gorm:"index;comment:操作人ID"`
+	Reason        string `gorm:"size:500;comment:流转原因"`
+
+	FromStage *PipelineStage `gorm:"foreignKey:FromStageID"`
+	ToStage   PipelineStage  `gorm:"foreignKey:ToStageID"`
+}
+
+// DefaultPipelineStages 职位未配置招聘流程时使用的默认阶段
+var DefaultPipelineStages = []PipelineStage{
+	{Code: "screening", Name: "简历筛选", Kind: StageKindActive},
+	{Code: "phone_screen", Name: "电话面试", Kind: StageKindActive},
+	{Code: "technical", Name: "技术面试", Kind: StageKindActive},
+	{Code: "offer", Name: "录用意向", Kind: StageKindActive},
+	{Code: "hired", Name: "已录用", Kind: StageKindHired},
+	{Code: "rejected", Name: "未通过", Kind: StageKindRejected},
+}
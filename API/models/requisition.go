@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 招聘需求状态
+const (
+	RequisitionPending  = "pending"
+	RequisitionApproved = "approved"
+	RequisitionRejected = "rejected"
+)
+
+// 审批步骤状态
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+	ApprovalSkipped  = "skipped"
+)
+
+// Requisition 招聘需求，由用人经理为草稿职位发起，逐级审批通过后职位方可发布
+type Requisition struct {
+	gorm.Model
+	JobID         uint    `gorm:"index;not null;comment:职位ID"`
+	RequestedBy   uint    `gorm:"index;not null;comment:发起人ID"`
+	Department    string  `gorm:"size:50;not null;index;comment:用人部门"`
+	Headcount     uint    `gorm:"not null;comment:招聘人数"`
+	Budget        float64 `gorm:"type:decimal(14,2);not null;comment:年度预算"`
+	Justification string  `gorm:"type:text;not null;comment:招聘理由"`
+	Status        string  `gorm:"type:ENUM('pending','approved','rejected');default:'pending';index;comment:审批状态"`
+	CurrentStep   int     `gorm:"not null;default:0;comment:当前审批步骤"`
+
+	Job       Job                   `gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE;"`
+	Requester User                  `gorm:"foreignKey:RequestedBy"`
+	Approvals []RequisitionApproval `gorm:"foreignKey:RequisitionID"`
+}
+
+// RequisitionApproval 招聘需求的单个审批步骤，由持有对应角色的用户审批
+type RequisitionApproval struct {
+	gorm.Model
+	RequisitionID uint       `gorm:"uniqueIndex:uniq_requisition_step;not null;comment:招聘需求ID"`
+	Step          int        `gorm:"uniqueIndex:uniq_requisition_step;not null;comment:步骤序号"`
+	ApproverRole  string     `gorm:"size:50;not null;comment:审批角色"`
+	Status        string     `gorm:"type:ENUM('pending','approved','rejected','skipped');default:'pending';comment:审批状态"`
+	ApproverID    *uint      `gorm:"comment:审批人ID"`
+	Comment       string     `gorm:"size:500;comment:审批意见"`
+	DecidedAt     *time.Time `gorm:"comment:审批时间"`
+}
@@ -5,11 +5,15 @@ import "gorm.io/gorm"
 type Resume struct {
 	gorm.Model
 	UserID         uint    `gorm:"uniqueIndex;not null;comment:用户ID"`
-	Education      string  `gorm:"type:text;comment:教育背景"`
-	WorkExperience string  `gorm:"type:text;comment:工作经历"`
-	Skills         string  `gorm:"type:text;comment:技能列表"`
-	ExpectedSalary float64 `gorm:"type:decimal(12,2);comment:期望薪资"`
+	Headline       string  `gorm:"size:100;comment:求职意向"`
+	Summary        string  `gorm:"type:text;comment:个人简介"`
+	ExpectedSalary float64 `gorm:"type:decimal(12,2);comment:期望月薪（人民币）"`
+	Location       string  `gorm:"size:100;index;comment:期望工作地点"`
 	FilePath       string  `gorm:"size:255;comment:简历文件路径"`
+	Version        int     `gorm:"not null;default:0;comment:当前版本号"`
 
 	User           User    `gorm:"foreignKey:UserID"`
+	Education      []ResumeEducation  `gorm:"foreignKey:ResumeID"`
+	Employment     []ResumeEmployment `gorm:"foreignKey:ResumeID"`
+	Skills         []Skill            `gorm:"many2many:resume_skills;"`
 }
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ResumeEducation 简历中的一段教育经历
+type ResumeEducation struct {
+	gorm.Model
+	ResumeID    uint       `gorm:"index;not null;comment:简历ID"`
+	Institution string     `gorm:"size:100;not null;comment:学校"`
+	Degree      string     `gorm:"size:50;comment:学历学位"`
+	Major       string     `gorm:"size:100;comment:专业"`
+	StartDate   *time.Time `gorm:"type:date;comment:开始日期"`
+	EndDate     *time.Time `gorm:"type:date;comment:结束日期，为空表示至今"`
+	SortOrder   int        `gorm:"not null;default:0;comment:排序"`
+}
+
+// ResumeEmployment 简历中的一段工作经历
+type ResumeEmployment struct {
+	gorm.Model
+	ResumeID    uint       `gorm:"index;not null;comment:简历ID"`
+	Company     string     `gorm:"size:100;not null;comment:公司"`
+	Title       string     `gorm:"size:100;comment:职位"`
+	StartDate   *time.Time `gorm:"type:date;comment:开始日期"`
+	EndDate     *time.Time `gorm:"type:date;comment:结束日期，为空表示至今"`
+	Description string     `gorm:"type:text;comment:工作内容"`
+	SortOrder   int        `gorm:"not null;default:0;comment:排序"`
+}
+
+// Skill 规范化的技能标签，Key 为统一小写并合并别名后的标识
+type Skill struct {
+	ID   uint   `gorm:"primarykey"`
+	Key  string `gorm:"size:50;uniqueIndex;not null;comment:规范化标识"`
+	Name string `gorm:"size:50;not null;comment:展示名称"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// ResumeVersion 简历的不可变版本，每次提交简历内容有变化时生成，投递申请时固定使用的版本
+type ResumeVersion struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ResumeID  uint      `gorm:"uniqueIndex:uniq_resume_version;not null;comment:简历ID" json:"resume_id"`
+	UserID    uint      `gorm:"index;not null;comment:用户ID" json:"user_id"`
+	Version   int       `gorm:"uniqueIndex:uniq_resume_version;not null;comment:版本号" json:"version"`
+	Content   string    `gorm:"type:json;not null;comment:简历内容快照" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,13 +1,68 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // Role 角色模型
 type Role struct {
 	gorm.Model
 	Name        string `gorm:"size:50;uniqueIndex;not null;comment:角色名称"`
 	Description string `gorm:"size:200;comment:角色描述"`
+	RequireMFA  bool   `gorm:"default:false;comment:是否强制两步验证"`
 
 	Permissions []Permission `gorm:"many2many:role_permissions;"`
 	Users       []User       `gorm:"many2many:user_roles;"`
 }
+
+// RoleDefaultGrant 已授予系统内置角色的默认权限。启动时只补授尚未记录的默认权限，
+// 升级新增的默认权限会授予已有角色，管理员撤销过的默认权限不会再次授予
+type RoleDefaultGrant struct {
+	RoleID       uint `gorm:"primaryKey;autoIncrement:false;comment:角色ID"`
+	PermissionID uint `gorm:"primaryKey;autoIncrement:false;comment:权限ID"`
+	CreatedAt    time.Time
+}
+
+// 系统内置角色
+const (
+	RoleAdmin          = "admin"
+	RoleEmployee       = "employee"
+	RoleCandidate      = "candidate"
+	RoleDepartmentHead = "department_head"
+	RoleHR             = "hr"
+)
+
+// SystemRoles 启动时自动创建的系统内置角色
+var SystemRoles = []string{RoleAdmin, RoleEmployee, RoleCandidate, RoleDepartmentHead, RoleHR}
+
+// IsSystemRole 判断是否为不可删除的系统内置角色
+func IsSystemRole(name string) bool {
+	for _, role := range SystemRoles {
+		if role == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultRolePermissions 系统内置角色的默认权限，admin 拥有全部内置权限
+var DefaultRolePermissions = map[string][]string{
+	RoleEmployee:       {PermJobView},
+	RoleCandidate:      {PermJobView, PermJobApply},
+	RoleDepartmentHead: {PermJobView, PermJobCreate, PermRequisitionCreate, PermRequisitionApprove, PermLeaveApprove, PermOvertimeApprove, PermAttendanceCorrect},
+	RoleHR:             {PermJobView, PermJobCreate, PermRequisitionCreate, PermRequisitionApprove, PermLeaveApprove, PermOvertimeApprove, PermAttendanceCorrect},
+}
+
+// IsAdminPermission 判断是否为管理类权限，内置普通角色默认拥有的权限之外均视为管理类权限
+func IsAdminPermission(code string) bool {
+	for _, codes := range DefaultRolePermissions {
+		for _, c := range codes {
+			if c == code {
+				return false
+			}
+		}
+	}
+	return true
+}
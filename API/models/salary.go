@@ -10,8 +10,12 @@ import (
 type Salary struct {
 	gorm.Model
 	UserID      uint      `gorm:"uniqueIndex:uniq_user_month;not null;comment:用户ID"`
-	Month       string    `gorm:"size:6;uniqueIndex:uniq_user_month;comment:薪资月份"`
-	Base        float64   `gorm:"type:decimal(12,2);not null;comment:基本工资"`
+	Month       string    `gorm:"size:7;uniqueIndex:uniq_user_month;comment:薪资月份"`
+	Base        float64   `gorm:"type:decimal(12,2);not null;comment:基本工资（按计薪天数折算）"`
+	WorkDays    int       `gorm:"not null;default:0;comment:当月应出勤工作日"`
+	PaidDays    float64   `gorm:"type:decimal(5,2);not null;default:0;comment:计薪天数"`
+	Overtime    float64   `gorm:"type:decimal(6,2);not null;default:0;comment:计薪加班时长（小时）"`
+	OvertimePay float64   `gorm:"type:decimal(12,2);not null;default:0;comment:加班工资"`
 	Bonus       float64   `gorm:"type:decimal(12,2);default:0.00;comment:奖金"`
 	Deductions  float64   `gorm:"type:decimal(12,2);default:0.00;comment:扣款"`
 	PaymentDate time.Time `gorm:"comment:发放日期"`
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 班次类型
+const (
+	ScheduleFixed    = "fixed"    // 固定班次，按上下班时间判断迟到早退
+	ScheduleFlexible = "flexible" // 弹性班次，按核心工作时间和每日最低工时判断
+	ScheduleNight    = "night"    // 夜班，下班时间在次日
+)
+
+// WorkSchedule 班次。固定班次和夜班的 StartTime/EndTime 为上下班时间，
+// 弹性班次为核心工作时间，时间格式均为 HH:MM
+type WorkSchedule struct {
+	gorm.Model
+	Name            string  `gorm:"size:50;uniqueIndex;not null;comment:班次名称"`
+	Kind            string  `gorm:"type:ENUM('fixed','flexible','night');default:'fixed';not null;comment:班次类型"`
+	StartTime       string  `gorm:"size:5;not null;comment:上班时间或核心时间开始"`
+	EndTime         string  `gorm:"size:5;not null;comment:下班时间或核心时间结束"`
+	RequiredHours   float64 `gorm:"type:decimal(4,2);not null;default:0;comment:弹性班次每日最低工时"`
+	LateGrace       int     `gorm:"not null;default:0;comment:迟到宽限（分钟）"`
+	EarlyLeaveGrace int     `gorm:"not null;default:0;comment:早退宽限（分钟）"`
+	WorkDays        string  `gorm:"size:20;not null;default:'1,2,3,4,5';comment:工作日，0为周日"`
+	Description     string  `gorm:"size:200;comment:班次说明"`
+}
+
+// ScheduleAssignment 班次分配，指定到部门或个人，个人分配优先于部门分配；
+// EffectiveTo 为空表示长期有效
+type ScheduleAssignment struct {
+	gorm.Model
+	ScheduleID    uint       `gorm:"index;not null;comment:班次ID"`
+	UserID        *uint      `gorm:"index;comment:员工ID"`
+	Department    string     `gorm:"size:50;index;comment:部门"`
+	EffectiveFrom time.Time  `gorm:"type:date;not null;comment:生效日期"`
+	EffectiveTo   *time.Time `gorm:"type:date;comment:失效日期（含）"`
+	CreatedBy     uint       `gorm:"not null;comment:创建人ID"`
+
+	Schedule WorkSchedule `gorm:"foreignKey:ScheduleID"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TalentPool 人才库，招聘人员维护的候选人名单，便于在新职位开放时重新联系
+type TalentPool struct {
+	gorm.Model
+	Name        string `gorm:"size:100;uniqueIndex;not null;comment:人才库名称"`
+	Description string `gorm:"size:500;comment:人才库说明"`
+	CreatedBy   uint   `gorm:"index;not null;comment:创建人ID"`
+
+	MemberCount int64 `gorm:"-"` // 候选人数量，查询时填充
+}
+
+// TalentPoolMember 人才库中的候选人
+type TalentPoolMember struct {
+	ID        uint      `gorm:"primarykey"`
+	PoolID    uint      `gorm:"uniqueIndex:uniq_pool_member;not null;comment:人才库ID"`
+	UserID    uint      `gorm:"uniqueIndex:uniq_pool_member;index;not null;comment:候选人ID"`
+	AddedBy   uint      `gorm:"not null;comment:添加人ID"`
+	CreatedAt time.Time `gorm:"comment:加入时间"`
+
+	Pool TalentPool `gorm:"foreignKey:PoolID;constraint:OnDelete:CASCADE;"`
+	User User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+}
+
+// TalentTag 候选人标签。受控标签由管理员预先定义，自由标签在打标签时自动创建
+type TalentTag struct {
+	ID          uint      `gorm:"primarykey"`
+	Name        string    `gorm:"size:50;uniqueIndex;not null;comment:标签名称"`
+	Description string    `gorm:"size:200;comment:标签说明"`
+	Controlled  bool      `gorm:"not null;default:false;comment:是否受控标签"`
+	CreatedBy   uint      `gorm:"not null;comment:创建人ID"`
+	CreatedAt   time.Time `gorm:"comment:创建时间"`
+}
+
+// CandidateTag 候选人与标签的关联
+type CandidateTag struct {
+	ID        uint      `gorm:"primarykey"`
+	UserID    uint      `gorm:"uniqueIndex:uniq_candidate_tag;not null;comment:候选人ID"`
+	TagID     uint      `gorm:"uniqueIndex:uniq_candidate_tag;index;not null;comment:标签ID"`
+	TaggedBy  uint      `gorm:"not null;comment:打标签人ID"`
+	CreatedAt time.Time `gorm:"comment:打标签时间"`
+
+	Tag TalentTag `gorm:"foreignKey:TagID;constraint:OnDelete:CASCADE;"`
+}
+
+// CandidateNote 招聘人员对候选人的备注
+type CandidateNote struct {
+	gorm.Model
+	UserID   uint   `gorm:"index;not null;comment:候选人ID"`
+	AuthorID uint   `gorm:"index;not null;comment:作者ID"`
+	Content  string `gorm:"type:text;not null;comment:备注内容"`
+
+	Author User `gorm:"foreignKey:AuthorID"`
+}
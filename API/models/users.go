@@ -8,16 +8,20 @@ import (
 
 type User struct {
 	gorm.Model
-	Username     string     `gorm:"size:50;uniqueIndex;not null;comment:用户名"`
-	Email        string     `gorm:"size:50;uniqueIndex;not null;comment:邮箱"`
-	Phone        string     `gorm:"size:20;uniqueIndex;not null;comment:手机号"`
-	PasswordHash string     `gorm:"size:60;not null;comment:密码哈希"`
-	Usertype     string     `gorm:"type:ENUM('admin','employee','candidate');default:'candidate';index;comment:用户类型"`
-	Department   string     `gorm:"size:50;index;comment:所属部门"`
-	Position     string     `gorm:"size:50;index;comment:职位"`
-	HireDate     *time.Time `gorm:"comment:入职日期"`
-	SalaryBase   float64    `gorm:"type:decimal(12,2);comment:基本工资"`
-	Active       bool       `gorm:"default:true;index;comment:账户状态"`
+	Username        string     `gorm:"size:50;uniqueIndex;not null;comment:用户名"`
+	Email           string     `gorm:"size:50;uniqueIndex;not null;comment:邮箱"`
+	EmailVerifiedAt *time.Time `gorm:"comment:邮箱验证时间"`
+	Phone           string     `gorm:"size:20;uniqueIndex;not null;comment:手机号"`
+	PasswordHash    string     `gorm:"size:60;not null;comment:密码哈希" json:"-"`
+	Usertype        string     `gorm:"type:ENUM('admin','employee','candidate');default:'candidate';index;comment:用户类型"`
+	Department      string     `gorm:"size:50;index;comment:所属部门"`
+	Position        string     `gorm:"size:50;index;comment:职位"`
+	Region          string     `gorm:"size:50;index;comment:工作地区，用于匹配节假日日历"`
+	HireDate        *time.Time `gorm:"comment:入职日期"`
+	SalaryBase      float64    `gorm:"type:decimal(12,2);comment:基本工资"`
+	Active          bool       `gorm:"default:true;index;comment:账户状态"`
+	MFAEnabled      bool       `gorm:"default:false;comment:是否启用两步验证"`
+	MFASecret       string     `gorm:"size:128;comment:TOTP密钥，AES-GCM加密" json:"-"`
 
 	Applications    []Application    `gorm:"foreignKey:UserID"`
 	Attendances     []Attendance     `gorm:"foreignKey:UserID"`
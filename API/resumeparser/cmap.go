@@ -0,0 +1,179 @@
+package resumeparser
+
+import (
+	"bytes"
+	"strings"
+)
+
+// toUnicodeMap 字体 ToUnicode CMap 中的字符编码到 Unicode 文本的映射
+type toUnicodeMap struct {
+	codeLengths []int // codespacerange 中出现的编码字节数，按从短到长排列
+	chars       map[string]string
+	ranges      []cmapRange
+}
+
+// cmapRange bfrange 中的一段连续编码
+type cmapRange struct {
+	low, high []byte
+	base      []rune   // 起始编码对应的文本，后续编码按最后一个字符递增
+	values    []string // 数组形式时逐个给出的文本
+}
+
+// parseToUnicode 解析 ToUnicode CMap 中的 codespacerange、bfchar 和 bfrange
+func parseToUnicode(data []byte) *toUnicodeMap {
+	m := &toUnicodeMap{chars: make(map[string]string)}
+	tokens := cmapTokens(data)
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i].text {
+		case "begincodespacerange":
+			for i++; i+1 < len(tokens) && tokens[i].text != "endcodespacerange"; i += 2 {
+				m.addCodeLength(len(tokens[i].hex))
+			}
+		case "beginbfchar":
+			for i++; i+1 < len(tokens) && tokens[i].text != "endbfchar"; i += 2 {
+				m.addCodeLength(len(tokens[i].hex))
+				m.chars[string(tokens[i].hex)] = decodeUTF16BE(tokens[i+1].hex)
+			}
+		case "beginbfrange":
+			for i++; i+2 < len(tokens) && tokens[i].text != "endbfrange"; {
+				r := cmapRange{low: tokens[i].hex, high: tokens[i+1].hex}
+				m.addCodeLength(len(r.low))
+				i += 2
+				if tokens[i].text == "[" {
+					for i++; i < len(tokens) && tokens[i].text != "]"; i++ {
+						r.values = append(r.values, decodeUTF16BE(tokens[i].hex))
+					}
+				} else {
+					r.base = []rune(decodeUTF16BE(tokens[i].hex))
+				}
+				i++
+				if len(r.low) == len(r.high) && len(r.low) > 0 {
+					m.ranges = append(m.ranges, r)
+				}
+			}
+		}
+	}
+	if len(m.codeLengths) == 0 {
+		m.codeLengths = []int{2}
+	}
+	return m
+}
+
+func (m *toUnicodeMap) addCodeLength(n int) {
+	if n <= 0 || n > 4 {
+		return
+	}
+	for i, length := range m.codeLengths {
+		if length == n {
+			return
+		}
+		if length > n {
+			m.codeLengths = append(m.codeLengths[:i], append([]int{n}, m.codeLengths[i:]...)...)
+			return
+		}
+	}
+	m.codeLengths = append(m.codeLengths, n)
+}
+
+// decode 按编码逐个转换字符串，返回文本、编码数和无法映射的编码数
+func (m *toUnicodeMap) decode(raw []byte) (string, int, int) {
+	var sb strings.Builder
+	codes, unmapped := 0, 0
+	for i := 0; i < len(raw); {
+		matched := false
+		for _, n := range m.codeLengths {
+			if i+n > len(raw) {
+				break
+			}
+			if text, ok := m.lookup(raw[i : i+n]); ok {
+				sb.WriteString(text)
+				i += n
+				matched = true
+				break
+			}
+		}
+		codes++
+		if !matched {
+			unmapped++
+			// 按最长的编码长度跳过无法映射的字符
+			i += m.codeLengths[len(m.codeLengths)-1]
+		}
+	}
+	return sb.String(), codes, unmapped
+}
+
+func (m *toUnicodeMap) lookup(code []byte) (string, bool) {
+	if text, ok := m.chars[string(code)]; ok {
+		return text, true
+	}
+	for _, r := range m.ranges {
+		if len(code) != len(r.low) || bytes.Compare(code, r.low) < 0 || bytes.Compare(code, r.high) > 0 {
+			continue
+		}
+		offset := cmapOffset(r.low, code)
+		if r.values != nil {
+			if offset < len(r.values) {
+				return r.values[offset], true
+			}
+			return "", false
+		}
+		if len(r.base) == 0 {
+			return "", false
+		}
+		text := append([]rune(nil), r.base...)
+		text[len(text)-1] += rune(offset)
+		return string(text), true
+	}
+	return "", false
+}
+
+// cmapOffset 计算编码相对于区间起点的偏移
+func cmapOffset(low, code []byte) int {
+	offset := 0
+	for i := range code {
+		offset = offset<<8 + int(code[i]) - int(low[i])
+	}
+	return offset
+}
+
+// cmapToken CMap 中的记号，十六进制字符串保存解码后的字节
+type cmapToken struct {
+	text string
+	hex  []byte
+}
+
+func cmapTokens(data []byte) []cmapToken {
+	var tokens []cmapToken
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch {
+		case c == '<' && i+1 < len(data) && data[i+1] != '<':
+			end := bytes.IndexByte(data[i:], '>')
+			if end < 0 {
+				return tokens
+			}
+			tokens = append(tokens, cmapToken{text: "<>", hex: pdfHexString(data[i+1 : i+end])})
+			i += end + 1
+		case c == '[' || c == ']':
+			tokens = append(tokens, cmapToken{text: string(c)})
+			i++
+		case c == '%':
+			for i < len(data) && data[i] != '\n' && data[i] != '\r' {
+				i++
+			}
+		case c == '(':
+			_, next := pdfLiteralString(data, i)
+			i = next
+		case isPDFRegular(c):
+			j := i + 1
+			for j < len(data) && isPDFRegular(data[j]) {
+				j++
+			}
+			tokens = append(tokens, cmapToken{text: string(data[i:j])})
+			i = j
+		default:
+			i++
+		}
+	}
+	return tokens
+}
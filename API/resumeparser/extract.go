@@ -0,0 +1,100 @@
+package resumeparser
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxExtractedSize 解压后内容的上限，防止压缩炸弹
+const maxExtractedSize = 20 << 20
+
+// ErrUnsupportedFormat 不支持的简历文件格式
+var ErrUnsupportedFormat = errors.New("仅支持 txt、docx、pdf 格式的简历")
+
+// ExtractText 按文件扩展名提取简历文件的纯文本
+func ExtractText(filename string, data []byte) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".txt", ".text", ".md":
+		return plainText(data)
+	case ".docx":
+		return docxText(data)
+	case ".pdf":
+		return pdfText(data)
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// plainText 读取UTF-8文本，去掉BOM
+func plainText(data []byte) (string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		return "", errors.New("文本文件必须使用UTF-8编码")
+	}
+	return string(data), nil
+}
+
+// docxText 读取 word/document.xml 中的正文，每个段落一行
+func docxText(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("无法读取docx文件: %w", err)
+	}
+	var document *zip.File
+	for _, f := range archive.File {
+		if f.Name == "word/document.xml" {
+			document = f
+			break
+		}
+	}
+	if document == nil {
+		return "", errors.New("docx文件缺少正文内容")
+	}
+	rc, err := document.Open()
+	if err != nil {
+		return "", fmt.Errorf("无法读取docx正文: %w", err)
+	}
+	defer rc.Close()
+
+	var sb strings.Builder
+	decoder := xml.NewDecoder(io.LimitReader(rc, maxExtractedSize))
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("docx正文格式错误: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				sb.WriteByte('\t')
+			case "br", "cr":
+				sb.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				sb.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				sb.Write(t)
+			}
+		}
+	}
+	return sb.String(), nil
+}
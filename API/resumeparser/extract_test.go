@@ -0,0 +1,115 @@
+package resumeparser
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// buildDocx 生成只包含 word/document.xml 的 docx 文件
+func buildDocx(t *testing.T, document string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("word/document.xml")
+	if err != nil {
+		t.Fatalf("创建docx失败: %v", err)
+	}
+	if _, err := f.Write([]byte(document)); err != nil {
+		t.Fatalf("写入docx失败: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("写入docx失败: %v", err)
+	}
+	return buf.Bytes()
+}
+
+const sampleDocument = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:body>
+<w:p><w:r><w:t>教育</w:t></w:r><w:r><w:t>背景</w:t></w:r></w:p>
+<w:p><w:r><w:t>2014.09 - 2018.06</w:t><w:tab/><w:t xml:space="preserve">北京大学 本科</w:t></w:r></w:p>
+<w:p><w:r><w:t>第一行</w:t><w:br/><w:t>第二行</w:t></w:r></w:p>
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:instrText>PAGE</w:instrText></w:r></w:p>
+</w:body>
+</w:document>`
+
+func TestExtractText(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		data     []byte
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "UTF-8 文本去掉BOM",
+			filename: "resume.TXT",
+			data:     []byte("\xef\xbb\xbf张三\n技能"),
+			want:     "张三\n技能",
+		},
+		{
+			name:     "非UTF-8文本",
+			filename: "resume.txt",
+			data:     []byte{0xd5, 0xc5, 0xc8, 0xfd},
+			wantErr:  "文本文件必须使用UTF-8编码",
+		},
+		{
+			name:     "docx 段落、制表符与换行",
+			filename: "resume.docx",
+			data:     buildDocx(t, sampleDocument),
+			want:     "教育背景\n2014.09 - 2018.06\t北京大学 本科\n第一行\n第二行\n\n",
+		},
+		{
+			name:     "docx 缺少正文",
+			filename: "resume.docx",
+			data: func() []byte {
+				var buf bytes.Buffer
+				w := zip.NewWriter(&buf)
+				w.Create("word/styles.xml")
+				w.Close()
+				return buf.Bytes()
+			}(),
+			wantErr: "docx文件缺少正文内容",
+		},
+		{
+			name:     "不支持的格式",
+			filename: "resume.doc",
+			data:     []byte("x"),
+			wantErr:  ErrUnsupportedFormat.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractText(tt.filename, tt.data)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("错误为 %v，期望 %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExtractText: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExtractText = %q, 期望 %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ExtractText("resume.docx", []byte("not a zip")); err == nil || errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("损坏的docx应返回读取错误，得到 %v", err)
+	}
+}
+
+func TestExtractAndParseDocx(t *testing.T) {
+	text, err := ExtractText("resume.docx", buildDocx(t, sampleDocument))
+	if err != nil {
+		t.Fatalf("ExtractText: %v", err)
+	}
+	education := Parse(text).Education
+	if len(education) != 1 || education[0].Institution != "北京大学" || education[0].Degree != "本科" {
+		t.Errorf("docx 简历的教育背景解析为 %+v", education)
+	}
+}
@@ -0,0 +1,345 @@
+// Package resumeparser 从简历文本中提取教育背景、工作经历和技能
+package resumeparser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Education 一段教育经历，日期为 YYYY-MM 或 YYYY，结束日期为空表示至今
+type Education struct {
+	Institution string `json:"institution"`
+	Degree      string `json:"degree"`
+	Major       string `json:"major"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+}
+
+// Employment 一段工作经历，日期格式同 Education
+type Employment struct {
+	Company     string `json:"company"`
+	Title       string `json:"title"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+	Description string `json:"description"`
+}
+
+// Result 解析结果
+type Result struct {
+	Summary    string       `json:"summary"`
+	Education  []Education  `json:"education"`
+	Employment []Employment `json:"employment"`
+	Skills     []string     `json:"skills"`
+}
+
+type section int
+
+const (
+	sectionNone section = iota
+	sectionSummary
+	sectionEducation
+	sectionEmployment
+	sectionSkills
+	sectionOther
+)
+
+// sectionHeadings 各部分常见标题，匹配时忽略大小写和标点
+var sectionHeadings = map[section][]string{
+	sectionSummary:    {"个人简介", "自我评价", "个人总结", "summary", "profile", "about me", "objective"},
+	sectionEducation:  {"教育背景", "教育经历", "学历", "education", "academic background"},
+	sectionEmployment: {"工作经历", "工作经验", "职业经历", "实习经历", "experience", "work experience", "employment", "employment history", "professional experience"},
+	sectionSkills:     {"专业技能", "技能", "技能特长", "技术栈", "skills", "technical skills", "core competencies"},
+	sectionOther:      {"项目经历", "项目经验", "证书", "获奖情况", "语言能力", "兴趣爱好", "projects", "certifications", "awards", "languages", "interests", "references"},
+}
+
+// dateRangePattern 匹配 "2018.09 - 2022.06"、"2019年3月至今"、"Jan 2020 – Present" 等时间段
+var dateRangePattern = regexp.MustCompile(`(?i)((?:[a-z]{3,9}\.?\s+)?(?:19|20)\d{2}(?:\s*[./年-]\s*\d{1,2}\s*月?)?)\s*(?:-|–|—|~|～|至|到|to)\s*((?:[a-z]{3,9}\.?\s+)?(?:19|20)\d{2}(?:\s*[./年-]\s*\d{1,2}\s*月?)?|至今|今|现在|present|current|now)`)
+
+var (
+	digitsPattern    = regexp.MustCompile(`\d+`)
+	wideSpacePattern = regexp.MustCompile(`\s{2,}`)
+	anySpacePattern  = regexp.MustCompile(`\s+`)
+)
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+// degreeKeywords 学位关键词
+var degreeKeywords = []string{"博士后", "博士", "硕士", "研究生", "本科", "学士", "大专", "专科", "高中",
+	"PhD", "Ph.D", "Doctor", "MBA", "Master", "Bachelor", "Associate"}
+
+// Parse 按章节标题切分简历文本，分别解析各部分
+func Parse(text string) Result {
+	sections := map[section][]string{}
+	current := sectionNone
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if s, ok := headingOf(line); ok {
+			current = s
+			continue
+		}
+		sections[current] = append(sections[current], line)
+	}
+
+	return Result{
+		Summary:    strings.Join(sections[sectionSummary], "\n"),
+		Education:  ParseEducation(strings.Join(sections[sectionEducation], "\n")),
+		Employment: ParseEmployment(strings.Join(sections[sectionEmployment], "\n")),
+		Skills:     SplitSkills(strings.Join(sections[sectionSkills], "\n")),
+	}
+}
+
+// ParseEducation 解析教育背景，每个时间段开始一段经历
+func ParseEducation(text string) []Education {
+	var entries []Education
+	for _, block := range splitEntries(text) {
+		entry := Education{StartDate: block.start, EndDate: block.end}
+		for _, field := range block.fields {
+			switch {
+			case entry.Degree == "" && hasDegree(field):
+				entry.Degree = field
+			case entry.Institution == "":
+				entry.Institution = field
+			case entry.Major == "":
+				entry.Major = field
+			}
+		}
+		if entry.Institution != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// ParseEmployment 解析工作经历，时间段所在行为公司和职位，其后各行为工作描述
+func ParseEmployment(text string) []Employment {
+	var entries []Employment
+	for _, block := range splitEntries(text) {
+		entry := Employment{StartDate: block.start, EndDate: block.end, Description: strings.Join(block.details, "\n")}
+		for _, field := range block.fields {
+			switch {
+			case entry.Company == "":
+				entry.Company = field
+			case entry.Title == "":
+				entry.Title = field
+			}
+		}
+		if entry.Company != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// SplitSkills 按常见分隔符拆分技能，去掉 "编程语言：" 之类的分类前缀并去重
+func SplitSkills(text string) []string {
+	var skills []string
+	seen := map[string]bool{}
+	for _, line := range strings.Split(text, "\n") {
+		if i := strings.IndexAny(line, ":："); i >= 0 {
+			_, size := utf8.DecodeRuneInString(line[i:])
+			line = line[i+size:]
+		}
+		// "MySQL / Redis" 视为两项，"CI/CD" 保持不变
+		line = strings.ReplaceAll(line, " / ", "、")
+		for _, item := range strings.FieldsFunc(line, func(r rune) bool {
+			return strings.ContainsRune(",，、;；|•·●▪\t", r)
+		}) {
+			item = strings.TrimSpace(strings.Trim(strings.TrimSpace(item), "-*。"))
+			if item == "" || len([]rune(item)) > 50 {
+				continue
+			}
+			key, _ := NormalizeSkill(item)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			skills = append(skills, item)
+		}
+	}
+	return skills
+}
+
+// entryBlock 以时间段开头的一段经历
+type entryBlock struct {
+	start, end string
+	fields     []string // 时间段所在行的其余字段
+	details    []string // 后续各行
+}
+
+// splitEntries 以含时间段的行切分经历。简历若采用标题在时间段之前的排版（第一段之前有内容），
+// 则紧接下一时间段之前的短行也视为下一段的标题
+func splitEntries(text string) []entryBlock {
+	var blocks []entryBlock
+	var pending []string
+	titleFirst := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		loc := dateRangePattern.FindStringSubmatchIndex(line)
+		if loc == nil {
+			if len(blocks) == 0 {
+				pending = append(pending, line)
+			} else {
+				blocks[len(blocks)-1].details = append(blocks[len(blocks)-1].details, line)
+			}
+			continue
+		}
+		if len(blocks) == 0 {
+			titleFirst = len(pending) > 0
+		} else if titleFirst {
+			last := &blocks[len(blocks)-1]
+			if n := len(last.details); n > 0 && looksLikeHeading(last.details[n-1]) {
+				pending = []string{last.details[n-1]}
+				last.details = last.details[:n-1]
+			}
+		}
+		block := entryBlock{
+			start: normalizeDate(line[loc[2]:loc[3]]),
+			end:   normalizeDate(line[loc[4]:loc[5]]),
+		}
+		block.fields = append(splitFields(strings.Join(pending, " | ")), splitFields(line[:loc[0]]+" | "+line[loc[1]:])...)
+		pending = nil
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// looksLikeHeading 不以列表符号开头、不以句号结尾的短行可能是下一段经历的标题
+func looksLikeHeading(line string) bool {
+	if len([]rune(line)) > 40 || strings.IndexAny(line, "-•*·") == 0 {
+		return false
+	}
+	return !strings.HasSuffix(line, "。") && !strings.HasSuffix(line, ".") && !strings.HasSuffix(line, "；")
+}
+
+// splitFields 拆分同一行中的学校、专业、公司、职位等字段。中文字段间以空格分隔，
+// 英文字段内可能含单个空格，仅按显式分隔符或连续空格拆分
+func splitFields(s string) []string {
+	var fields []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool {
+		return strings.ContainsRune("|｜,，、\t", r)
+	}) {
+		pattern := wideSpacePattern
+		if hasHan(part) {
+			pattern = anySpacePattern
+		}
+		for _, field := range pattern.Split(strings.TrimSpace(part), -1) {
+			if field = trimField(field); field != "" {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
+
+func hasHan(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func trimField(s string) string {
+	return strings.TrimFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("-–—:：()（）", r)
+	})
+}
+
+// hasDegree 判断字段是否包含学位关键词
+func hasDegree(field string) bool {
+	lower := strings.ToLower(field)
+	for _, keyword := range degreeKeywords {
+		if strings.Contains(lower, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeDate 将时间段的端点转为 YYYY-MM 或 YYYY，"至今" 等返回空字符串
+func normalizeDate(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	digits := digitsPattern.FindAllString(s, -1)
+	if len(digits) == 0 {
+		return ""
+	}
+	year := digits[0]
+	month := 0
+	if len(digits) > 1 {
+		month, _ = strconv.Atoi(digits[1])
+	} else if len(s) >= 3 {
+		month = monthNames[s[:3]]
+	}
+	if month < 1 || month > 12 {
+		return year
+	}
+	return year + "-" + leftPad(month)
+}
+
+func leftPad(month int) string {
+	if month < 10 {
+		return "0" + strconv.Itoa(month)
+	}
+	return strconv.Itoa(month)
+}
+
+// headingOf 判断一行是否为章节标题
+func headingOf(line string) (section, bool) {
+	normalized := strings.ToLower(strings.TrimFunc(line, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+	}))
+	if normalized == "" || len([]rune(normalized)) > 30 {
+		return sectionNone, false
+	}
+	for s, headings := range sectionHeadings {
+		for _, heading := range headings {
+			if normalized == heading {
+				return s, true
+			}
+		}
+	}
+	return sectionNone, false
+}
+
+// dateLayouts 简历日期支持的格式
+var dateLayouts = []string{"2006-01-02", "2006-01", "2006"}
+
+// ParseDate 解析 YYYY-MM-DD、YYYY-MM 或 YYYY 格式的日期，空字符串返回 nil
+func ParseDate(s string) (*time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("日期格式应为 YYYY-MM-DD、YYYY-MM 或 YYYY: %s", s)
+}
+
+// FormatDate 按 JSON Resume 的习惯格式化日期，日为1号时省略日
+func FormatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	if t.Day() == 1 {
+		return t.Format("2006-01")
+	}
+	return t.Format("2006-01-02")
+}
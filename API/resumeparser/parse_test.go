@@ -0,0 +1,145 @@
+package resumeparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+const sampleResume = `张三
+13800000000 | zhangsan@example.com
+
+个人简介
+五年后端开发经验，熟悉高并发系统设计。
+
+【教育背景】
+2014.09 - 2018.06  北京大学  计算机科学与技术  本科
+
+工作经历：
+2018年7月 至 2021年3月 | 字节跳动 | 后端工程师
+- 负责推荐系统接口开发
+- 优化缓存命中率
+2021.04 - 至今 | 阿里巴巴 | 高级工程师
+- 主导订单服务重构
+
+专业技能
+编程语言：Go、Python、Java
+数据库：MySQL / Redis, PostgreSQL
+
+项目经历
+2020.01 - 2020.06 内部工具平台
+`
+
+func TestParse(t *testing.T) {
+	result := Parse(sampleResume)
+
+	if result.Summary != "五年后端开发经验，熟悉高并发系统设计。" {
+		t.Errorf("Summary = %q", result.Summary)
+	}
+	wantEducation := []Education{{Institution: "北京大学", Degree: "本科", Major: "计算机科学与技术", StartDate: "2014-09", EndDate: "2018-06"}}
+	if !reflect.DeepEqual(result.Education, wantEducation) {
+		t.Errorf("Education = %+v, 期望 %+v", result.Education, wantEducation)
+	}
+	wantEmployment := []Employment{
+		{Company: "字节跳动", Title: "后端工程师", StartDate: "2018-07", EndDate: "2021-03", Description: "- 负责推荐系统接口开发\n- 优化缓存命中率"},
+		{Company: "阿里巴巴", Title: "高级工程师", StartDate: "2021-04", EndDate: "", Description: "- 主导订单服务重构"},
+	}
+	if !reflect.DeepEqual(result.Employment, wantEmployment) {
+		t.Errorf("Employment = %+v, 期望 %+v", result.Employment, wantEmployment)
+	}
+	wantSkills := []string{"Go", "Python", "Java", "MySQL", "Redis", "PostgreSQL"}
+	if !reflect.DeepEqual(result.Skills, wantSkills) {
+		t.Errorf("Skills = %v, 期望 %v", result.Skills, wantSkills)
+	}
+}
+
+func TestHeadingOf(t *testing.T) {
+	tests := []struct {
+		line string
+		want section
+		ok   bool
+	}{
+		{"教育背景", sectionEducation, true},
+		{"【工作经历】", sectionEmployment, true},
+		{"Work Experience:", sectionEmployment, true},
+		{"  SKILLS  ", sectionSkills, true},
+		{"项目经历", sectionOther, true},
+		{"About Me", sectionSummary, true},
+		{"熟悉教育背景调查流程", sectionNone, false},
+		{"---", sectionNone, false},
+	}
+	for _, tt := range tests {
+		got, ok := headingOf(tt.line)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("headingOf(%q) = %v, %v，期望 %v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDateRanges(t *testing.T) {
+	tests := []struct {
+		line       string
+		start, end string
+	}{
+		{"2018.09 - 2022.06 北京大学", "2018-09", "2022-06"},
+		{"2019年3月至今 腾讯", "2019-03", ""},
+		{"2019年3月 - 2020年12月", "2019-03", "2020-12"},
+		{"Jan 2020 – Present  Google", "2020-01", ""},
+		{"Sept. 2016 to Jun 2018", "2016-09", "2018-06"},
+		{"2015 ~ 2017", "2015", "2017"},
+		{"2020/1 — 2021/11", "2020-01", "2021-11"},
+	}
+	for _, tt := range tests {
+		blocks := splitEntries(tt.line)
+		if len(blocks) != 1 {
+			t.Errorf("splitEntries(%q) 得到 %d 段，期望 1 段", tt.line, len(blocks))
+			continue
+		}
+		if blocks[0].start != tt.start || blocks[0].end != tt.end {
+			t.Errorf("splitEntries(%q) 时间段为 %q - %q，期望 %q - %q", tt.line, blocks[0].start, blocks[0].end, tt.start, tt.end)
+		}
+	}
+	if blocks := splitEntries("熟悉 Go 语言，2018 年开始工作"); len(blocks) != 0 {
+		t.Errorf("不含时间段的行不应切分经历，得到 %+v", blocks)
+	}
+}
+
+func TestParseEmploymentTitleFirst(t *testing.T) {
+	// 公司和职位写在时间段之前的排版
+	text := `Acme Inc  Software Engineer
+Mar 2019 - Feb 2021
+Built billing services.
+Globex  Senior Engineer
+Mar 2021 - now
+Led platform team.`
+	want := []Employment{
+		{Company: "Acme Inc", Title: "Software Engineer", StartDate: "2019-03", EndDate: "2021-02", Description: "Built billing services."},
+		{Company: "Globex", Title: "Senior Engineer", StartDate: "2021-03", EndDate: "", Description: "Led platform team."},
+	}
+	if got := ParseEmployment(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseEmployment = %+v, 期望 %+v", got, want)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"2020", "2020-01", false},
+		{"2020-03", "2020-03", false},
+		{"2020-03-15", "2020-03-15", false},
+		{"2020/03", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDate(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDate(%q) 错误为 %v", tt.in, err)
+			continue
+		}
+		if FormatDate(got) != tt.want {
+			t.Errorf("ParseDate(%q) 格式化为 %q，期望 %q", tt.in, FormatDate(got), tt.want)
+		}
+	}
+}
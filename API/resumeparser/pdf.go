@@ -0,0 +1,722 @@
+package resumeparser
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ErrUnreadablePDF PDF 中的文字使用了无法映射到 Unicode 的字体（如缺少 ToUnicode 的 Identity-H 字体），
+// 提取结果会是乱码
+var ErrUnreadablePDF = errors.New("无法解析该PDF，请上传DOCX或手动填写")
+
+// maxUnmappedRatio 复合字体中无法映射的字符超过该比例时视为无法解析
+const maxUnmappedRatio = 0.1
+
+var (
+	pdfObjectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+	pdfRef          = regexp.MustCompile(`^(\d+)\s+(\d+)\s+R\b`)
+	pdfRefs         = regexp.MustCompile(`(\d+)\s+\d+\s+R\b`)
+	pdfNameRef      = regexp.MustCompile(`/([^\s/<>\[\]()]+)\s+(\d+)\s+\d+\s+R\b`)
+	pdfDirectLength = regexp.MustCompile(`/Length\s+(\d+)(\s+\d+\s+R)?`)
+	pdfPageType     = regexp.MustCompile(`/Type\s*/Page\b`)
+	pdfCatalogType  = regexp.MustCompile(`/Type\s*/Catalog\b`)
+)
+
+// pdfObject PDF 中的一个间接对象
+type pdfObject struct {
+	dict    []byte // 对象内容，流对象为流之前的字典
+	raw     []byte // 文件中的原始流内容，无流时为 nil
+	stream  []byte // 解码后的流内容，首次使用时由 pdfDocument.stream 解码
+	decoded bool
+}
+
+// pdfDocument 提取文本所需的对象、字体和统计信息
+type pdfDocument struct {
+	objects  map[int]*pdfObject
+	fonts    map[int]*pdfFont
+	visited  map[int]bool
+	budget   int // 剩余可解压的字节数，全部流共用，防止多个流叠加成压缩炸弹
+	decoded  int // 复合字体解码的字符数
+	unmapped int // 其中无法映射到 Unicode 的字符数
+}
+
+// pdfText 提取PDF的文本层。按页面树顺序解析内容流，使用字体的 ToUnicode 映射解码文字；
+// 仅支持未压缩或 FlateDecode 压缩的流，只解压页面树引用到的流
+func pdfText(data []byte) (string, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF")) {
+		return "", errors.New("不是有效的PDF文件")
+	}
+	doc := newPDFDocument(data)
+
+	var sb strings.Builder
+	pages := doc.pages()
+	for _, page := range pages {
+		doc.pageText(page.object, page.resources, &sb)
+		if sb.Len() > maxExtractedSize {
+			break
+		}
+	}
+	// 找不到页面树时按对象顺序处理全部内容流
+	if len(pages) == 0 {
+		for _, num := range doc.objectNumbers() {
+			obj := doc.objects[num]
+			if obj.raw != nil && !skipPDFStream(obj.dict) {
+				if stream := doc.stream(obj); stream != nil {
+					doc.contentText(stream, nil, &sb)
+				}
+			}
+		}
+	}
+
+	if doc.decoded > 0 && float64(doc.unmapped) > float64(doc.decoded)*maxUnmappedRatio {
+		return "", ErrUnreadablePDF
+	}
+	text := sb.String()
+	if strings.TrimSpace(text) == "" {
+		return "", errors.New("PDF中没有可提取的文本，扫描件请先进行文字识别")
+	}
+	return text, nil
+}
+
+func newPDFDocument(data []byte) *pdfDocument {
+	doc := &pdfDocument{
+		objects: parsePDFObjects(data),
+		fonts:   make(map[int]*pdfFont),
+		visited: make(map[int]bool),
+		budget:  maxExtractedSize,
+	}
+	doc.expandObjectStreams()
+	return doc
+}
+
+// parsePDFObjects 顺序扫描文件中的间接对象，流内容保持未解码
+func parsePDFObjects(data []byte) map[int]*pdfObject {
+	objects := make(map[int]*pdfObject)
+	for pos := 0; pos < len(data); {
+		loc := pdfObjectHeader.FindSubmatchIndex(data[pos:])
+		if loc == nil {
+			break
+		}
+		num, _ := strconv.Atoi(string(data[pos+loc[2] : pos+loc[3]]))
+		body := data[pos+loc[1]:]
+		obj, consumed := parsePDFObjectBody(body)
+		objects[num] = obj
+		pos += loc[1] + consumed
+	}
+	return objects
+}
+
+// expandObjectStreams 展开对象流中压缩存储的对象。页面树本身可能位于对象流中，
+// 因此对象流需要先于页面遍历解压，同样计入解压预算
+func (d *pdfDocument) expandObjectStreams() {
+	for _, num := range d.objectNumbers() {
+		obj := d.objects[num]
+		if obj.raw == nil || !bytes.Contains(obj.dict, []byte("/ObjStm")) {
+			continue
+		}
+		for num, dict := range objectStreamEntries(obj.dict, d.stream(obj)) {
+			if _, ok := d.objects[num]; !ok {
+				d.objects[num] = &pdfObject{dict: dict}
+			}
+		}
+	}
+}
+
+// stream 返回解码后的流内容，每个流只解码一次，无流或无法解码时返回 nil
+func (d *pdfDocument) stream(obj *pdfObject) []byte {
+	if obj.raw == nil {
+		return nil
+	}
+	if !obj.decoded {
+		obj.decoded = true
+		obj.stream = decodePDFStream(obj.dict, obj.raw, &d.budget)
+	}
+	return obj.stream
+}
+
+// parsePDFObjectBody 解析 "obj" 之后的对象内容，返回对象和消耗的字节数
+func parsePDFObjectBody(body []byte) (*pdfObject, int) {
+	obj := &pdfObject{}
+	trimmed := bytes.TrimLeft(body, " \t\r\n\f")
+	offset := len(body) - len(trimmed)
+	if !bytes.HasPrefix(trimmed, []byte("<<")) {
+		end := bytes.Index(body, []byte("endobj"))
+		if end < 0 {
+			obj.dict = body
+			return obj, len(body)
+		}
+		obj.dict = body[:end]
+		return obj, end + len("endobj")
+	}
+
+	dictEnd := pdfDictEnd(trimmed)
+	if dictEnd < 0 {
+		obj.dict = trimmed
+		return obj, len(body)
+	}
+	obj.dict = trimmed[:dictEnd]
+	rest := bytes.TrimLeft(trimmed[dictEnd:], " \t\r\n\f")
+	if !bytes.HasPrefix(rest, []byte("stream")) {
+		end := bytes.Index(trimmed[dictEnd:], []byte("endobj"))
+		if end < 0 {
+			return obj, len(body)
+		}
+		return obj, offset + dictEnd + end + len("endobj")
+	}
+
+	streamStart := len(trimmed) - len(rest) + len("stream")
+	content := trimmed[streamStart:]
+	content = bytes.TrimPrefix(content, []byte("\r"))
+	content = bytes.TrimPrefix(content, []byte("\n"))
+	headerLen := len(trimmed) - len(content)
+
+	// 优先使用直接给出的 /Length，流中可能出现 "endstream" 字样
+	end := -1
+	if m := pdfDirectLength.FindSubmatch(obj.dict); m != nil && len(m[2]) == 0 {
+		if n, err := strconv.Atoi(string(m[1])); err == nil && n <= len(content) &&
+			bytes.HasPrefix(bytes.TrimLeft(content[n:], " \t\r\n\f"), []byte("endstream")) {
+			end = n
+		}
+	}
+	if end < 0 {
+		end = bytes.Index(content, []byte("endstream"))
+	}
+	if end < 0 {
+		return obj, len(body)
+	}
+	obj.raw = content[:end]
+	consumed := offset + headerLen + end + len("endstream")
+	if next := bytes.Index(body[consumed:], []byte("endobj")); next >= 0 && !pdfObjectHeader.Match(body[consumed:consumed+next]) {
+		consumed += next + len("endobj")
+	}
+	return obj, consumed
+}
+
+// decodePDFStream 解码流内容，仅支持 FlateDecode，其他过滤器返回 nil。
+// 解压出的字节从 budget 中扣除，预算用尽后截断，之后的压缩流均返回 nil
+func decodePDFStream(dict, content []byte, budget *int) []byte {
+	if !bytes.Contains(dict, []byte("/Filter")) {
+		return content
+	}
+	if !bytes.Contains(dict, []byte("/FlateDecode")) || *budget <= 0 {
+		return nil
+	}
+	r, err := zlib.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil
+	}
+	defer r.Close()
+	decoded, err := io.ReadAll(io.LimitReader(r, int64(*budget)))
+	*budget -= len(decoded)
+	if err != nil && len(decoded) == 0 {
+		return nil
+	}
+	return decoded
+}
+
+// objectStreamEntries 读取对象流中的对象，返回对象号到对象内容的映射
+func objectStreamEntries(dict, stream []byte) map[int][]byte {
+	n := pdfInt(dictValue(dict, "N"))
+	first := pdfInt(dictValue(dict, "First"))
+	if n <= 0 || first <= 0 || first > len(stream) {
+		return nil
+	}
+	fields := strings.Fields(string(stream[:first]))
+	entries := make(map[int][]byte, n)
+	for i := 0; i+1 < len(fields) && i/2 < n; i += 2 {
+		num, err1 := strconv.Atoi(fields[i])
+		start, err2 := strconv.Atoi(fields[i+1])
+		if err1 != nil || err2 != nil || first+start > len(stream) {
+			continue
+		}
+		end := len(stream)
+		if i+3 < len(fields) {
+			if next, err := strconv.Atoi(fields[i+3]); err == nil && first+next >= first+start && first+next <= end {
+				end = first + next
+			}
+		}
+		entries[num] = bytes.TrimSpace(stream[first+start : end])
+	}
+	return entries
+}
+
+// pdfPage 页面对象及其生效的资源字典（可能继承自上级节点）
+type pdfPage struct {
+	object    *pdfObject
+	resources []byte
+}
+
+// pages 从文档目录按顺序遍历页面树
+func (d *pdfDocument) pages() []pdfPage {
+	var pages []pdfPage
+	for _, num := range d.objectNumbers() {
+		obj := d.objects[num]
+		if !pdfCatalogType.Match(obj.dict) {
+			continue
+		}
+		if root, ok := d.resolve(dictValue(obj.dict, "Pages")); ok {
+			visited := make(map[*pdfObject]bool)
+			d.walkPages(root, nil, visited, &pages)
+		}
+		if len(pages) > 0 {
+			return pages
+		}
+	}
+	// 没有目录时按对象顺序取页面
+	for _, num := range d.objectNumbers() {
+		obj := d.objects[num]
+		if pdfPageType.Match(obj.dict) {
+			pages = append(pages, pdfPage{object: obj, resources: d.resolveDict(dictValue(obj.dict, "Resources"))})
+		}
+	}
+	return pages
+}
+
+func (d *pdfDocument) walkPages(node *pdfObject, inherited []byte, visited map[*pdfObject]bool, pages *[]pdfPage) {
+	if visited[node] {
+		return
+	}
+	visited[node] = true
+	resources := inherited
+	if own := d.resolveDict(dictValue(node.dict, "Resources")); own != nil {
+		resources = own
+	}
+	if pdfPageType.Match(node.dict) {
+		*pages = append(*pages, pdfPage{object: node, resources: resources})
+		return
+	}
+	for _, m := range pdfRefs.FindAllSubmatch(dictValue(node.dict, "Kids"), -1) {
+		if kid, ok := d.objects[pdfInt(m[1])]; ok {
+			d.walkPages(kid, resources, visited, pages)
+		}
+	}
+}
+
+// pageText 提取页面内容流中的文字，页面引用的表单 XObject 随后处理
+func (d *pdfDocument) pageText(page *pdfObject, resources []byte, sb *strings.Builder) {
+	fonts := d.fontResources(resources)
+	for _, m := range pdfRefs.FindAllSubmatch(dictValue(page.dict, "Contents"), -1) {
+		if content, ok := d.objects[pdfInt(m[1])]; ok {
+			if stream := d.stream(content); stream != nil {
+				d.contentText(stream, fonts, sb)
+				sb.WriteByte('\n')
+			}
+		}
+	}
+	d.formText(resources, sb)
+}
+
+// formText 处理资源中引用的表单 XObject，每个只处理一次
+func (d *pdfDocument) formText(resources []byte, sb *strings.Builder) {
+	for _, m := range pdfNameRef.FindAllSubmatch(d.resolveDict(dictValue(resources, "XObject")), -1) {
+		num := pdfInt(m[2])
+		form, ok := d.objects[num]
+		if !ok || d.visited[num] || !bytes.Contains(form.dict, []byte("/Form")) {
+			continue
+		}
+		d.visited[num] = true
+		stream := d.stream(form)
+		if stream == nil {
+			continue
+		}
+		formResources := d.resolveDict(dictValue(form.dict, "Resources"))
+		if formResources == nil {
+			formResources = resources
+		}
+		d.contentText(stream, d.fontResources(formResources), sb)
+		sb.WriteByte('\n')
+		d.formText(formResources, sb)
+	}
+}
+
+// fontResources 读取资源字典中的字体，返回资源名到字体的映射
+func (d *pdfDocument) fontResources(resources []byte) map[string]*pdfFont {
+	fonts := make(map[string]*pdfFont)
+	for _, m := range pdfNameRef.FindAllSubmatch(d.resolveDict(dictValue(resources, "Font")), -1) {
+		if font := d.font(pdfInt(m[2])); font != nil {
+			fonts[string(m[1])] = font
+		}
+	}
+	return fonts
+}
+
+// font 按对象号读取字体并缓存
+func (d *pdfDocument) font(num int) *pdfFont {
+	if font, ok := d.fonts[num]; ok {
+		return font
+	}
+	obj, ok := d.objects[num]
+	if !ok {
+		return nil
+	}
+	font := &pdfFont{composite: bytes.Contains(obj.dict, []byte("/Type0"))}
+	if toUnicode, ok := d.resolve(dictValue(obj.dict, "ToUnicode")); ok {
+		if stream := d.stream(toUnicode); stream != nil {
+			font.cmap = parseToUnicode(stream)
+		}
+	}
+	// Adobe 预定义的 UCS-2/UTF-16 编码可直接按 UTF-16BE 解码
+	encoding := string(dictValue(obj.dict, "Encoding"))
+	font.utf16 = strings.HasPrefix(encoding, "/Uni") && (strings.Contains(encoding, "UCS2") || strings.Contains(encoding, "UTF16"))
+	d.fonts[num] = font
+	return font
+}
+
+// resolve 解析间接引用
+func (d *pdfDocument) resolve(value []byte) (*pdfObject, bool) {
+	m := pdfRef.FindSubmatch(value)
+	if m == nil {
+		return nil, false
+	}
+	obj, ok := d.objects[pdfInt(m[1])]
+	return obj, ok
+}
+
+// resolveDict 返回字典值，间接引用时取被引用对象的字典
+func (d *pdfDocument) resolveDict(value []byte) []byte {
+	if bytes.HasPrefix(value, []byte("<<")) {
+		return value
+	}
+	if obj, ok := d.resolve(value); ok {
+		return obj.dict
+	}
+	return nil
+}
+
+func (d *pdfDocument) objectNumbers() []int {
+	nums := make([]int, 0, len(d.objects))
+	for num := range d.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	return nums
+}
+
+// pdfFont 内容流中使用的字体
+type pdfFont struct {
+	composite bool          // Type0 复合字体，字符编码通常为两个字节
+	utf16     bool          // 使用 UCS-2/UTF-16 预定义编码
+	cmap      *toUnicodeMap // ToUnicode 映射，未提供时为 nil
+}
+
+// decode 将字符串操作数按字体编码转换为文本
+func (d *pdfDocument) decode(font *pdfFont, raw []byte) string {
+	switch {
+	case font == nil:
+		return decodePDFBytes(raw)
+	case font.cmap != nil:
+		text, codes, unmapped := font.cmap.decode(raw)
+		if font.composite {
+			d.decoded += codes
+			d.unmapped += unmapped
+		}
+		return text
+	case font.utf16:
+		return decodeUTF16BE(raw)
+	case font.composite:
+		// 无法确定字符含义，计入无法映射的字符
+		d.decoded += len(raw) / 2
+		d.unmapped += len(raw) / 2
+		return ""
+	}
+	return decodePDFBytes(raw)
+}
+
+// skipPDFStream 跳过图片、字体和对象流等非页面内容的流
+func skipPDFStream(dict []byte) bool {
+	for _, marker := range []string{"/Image", "/Length1", "/Length2", "/FontFile", "/ObjStm", "/XRef", "/Metadata", "/CMapName"} {
+		if bytes.Contains(dict, []byte(marker)) {
+			return true
+		}
+	}
+	return false
+}
+
+// contentText 解析内容流中的文本绘制操作符（Tj、TJ、'、"），换行操作输出换行；
+// fonts 为当前资源中的字体，Tf 操作符切换字体
+func (d *pdfDocument) contentText(content []byte, fonts map[string]*pdfFont, sb *strings.Builder) {
+	var operands []string
+	var font *pdfFont
+	lastName := ""
+	inText := false
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == '(':
+			raw, next := pdfLiteralString(content, i)
+			operands = append(operands, d.decode(font, raw))
+			i = next
+		case c == '<' && i+1 < len(content) && content[i+1] != '<':
+			end := bytes.IndexByte(content[i:], '>')
+			if end < 0 {
+				return
+			}
+			operands = append(operands, d.decode(font, pdfHexString(content[i+1:i+end])))
+			i += end + 1
+		case c == '[':
+			operands = append(operands, "[")
+			i++
+		case c == ']':
+			// 合并数组中的字符串，较大的字距调整视为空格
+			j := len(operands) - 1
+			for j >= 0 && operands[j] != "[" {
+				j--
+			}
+			if j >= 0 {
+				operands = append(operands[:j], strings.Join(operands[j+1:], ""))
+			}
+			i++
+		case c == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case c == '-' || c == '.' || c >= '0' && c <= '9':
+			j := i + 1
+			for j < len(content) && (content[j] == '.' || content[j] >= '0' && content[j] <= '9') {
+				j++
+			}
+			if len(operands) > 0 && operands[len(operands)-1] != "[" && isKerning(string(content[i:j])) {
+				operands = append(operands, " ")
+			}
+			i = j
+		case isPDFRegular(c):
+			j := i + 1
+			for j < len(content) && isPDFRegular(content[j]) {
+				j++
+			}
+			op := string(content[i:j])
+			i = j
+			if op[0] == '/' {
+				lastName = op[1:]
+				continue
+			}
+			switch op {
+			case "BT":
+				inText = true
+			case "ET":
+				inText = false
+				sb.WriteByte('\n')
+			case "Tf":
+				font = fonts[lastName]
+			case "Tj", "TJ":
+				if inText && len(operands) > 0 {
+					sb.WriteString(operands[len(operands)-1])
+				}
+			case "'", "\"":
+				if inText && len(operands) > 0 {
+					sb.WriteByte('\n')
+					sb.WriteString(operands[len(operands)-1])
+				}
+			case "Td", "TD", "T*", "Tm":
+				if inText {
+					sb.WriteByte('\n')
+				}
+			}
+			operands = operands[:0]
+		default:
+			i++
+		}
+	}
+}
+
+// isKerning TJ 数组中超过千分之二百字宽的负向调整通常是词间距
+func isKerning(number string) bool {
+	var value float64
+	if _, err := fmt.Sscan(number, &value); err != nil {
+		return false
+	}
+	return value < -200
+}
+
+// isPDFRegular 判断是否为操作符或名称中的普通字符
+func isPDFRegular(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', '\f', 0, '(', ')', '<', '>', '[', ']', '{', '}', '%':
+		return false
+	}
+	return true
+}
+
+// pdfDictEnd 返回以 "<<" 开头的字典的结束位置（">>" 之后），未闭合时返回 -1
+func pdfDictEnd(b []byte) int {
+	depth := 0
+	for i := 0; i+1 < len(b); i++ {
+		switch {
+		case b[i] == '(':
+			_, next := pdfLiteralString(b, i)
+			i = next - 1
+		case b[i] == '<' && b[i+1] == '<':
+			depth++
+			i++
+		case b[i] == '>' && b[i+1] == '>':
+			depth--
+			i++
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// dictValue 返回字典中某个键的原始值：嵌套字典、数组、间接引用或单个记号
+func dictValue(dict []byte, key string) []byte {
+	needle := []byte("/" + key)
+	for from := 0; ; {
+		idx := bytes.Index(dict[from:], needle)
+		if idx < 0 {
+			return nil
+		}
+		start := from + idx + len(needle)
+		from = start
+		// 排除 /Font 匹配到 /FontFile 等更长的名称
+		if start < len(dict) && isPDFRegular(dict[start]) && dict[start] != '/' {
+			continue
+		}
+		rest := bytes.TrimLeft(dict[start:], " \t\r\n\f")
+		switch {
+		case bytes.HasPrefix(rest, []byte("<<")):
+			if end := pdfDictEnd(rest); end > 0 {
+				return rest[:end]
+			}
+			return rest
+		case bytes.HasPrefix(rest, []byte("[")):
+			if end := bytes.IndexByte(rest, ']'); end > 0 {
+				return rest[:end+1]
+			}
+			return rest
+		}
+		if m := pdfRef.Find(rest); m != nil {
+			return m
+		}
+		end := 1
+		for end < len(rest) && isPDFRegular(rest[end]) && rest[end] != '/' {
+			end++
+		}
+		if end > len(rest) {
+			end = len(rest)
+		}
+		return rest[:end]
+	}
+}
+
+func pdfInt(b []byte) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(string(b)))
+	return n
+}
+
+// pdfLiteralString 解析圆括号字符串，返回原始字节和结束位置
+func pdfLiteralString(content []byte, start int) ([]byte, int) {
+	var buf []byte
+	depth := 0
+	i := start
+	for ; i < len(content); i++ {
+		c := content[i]
+		switch c {
+		case '(':
+			depth++
+			if depth == 1 {
+				continue
+			}
+		case ')':
+			depth--
+			if depth == 0 {
+				return buf, i + 1
+			}
+		case '\\':
+			i++
+			if i >= len(content) {
+				break
+			}
+			switch e := content[i]; e {
+			case 'n':
+				buf = append(buf, '\n')
+			case 'r':
+				buf = append(buf, '\r')
+			case 't':
+				buf = append(buf, '\t')
+			case 'b', 'f':
+			case '\r', '\n':
+				// 续行
+			default:
+				if e >= '0' && e <= '7' {
+					value := 0
+					j := 0
+					for ; j < 3 && i+j < len(content) && content[i+j] >= '0' && content[i+j] <= '7'; j++ {
+						value = value*8 + int(content[i+j]-'0')
+					}
+					buf = append(buf, byte(value))
+					i += j - 1
+				} else {
+					buf = append(buf, e)
+				}
+			}
+			continue
+		}
+		buf = append(buf, c)
+	}
+	return buf, i
+}
+
+// pdfHexString 解析十六进制字符串，返回原始字节
+func pdfHexString(hex []byte) []byte {
+	var buf []byte
+	var high byte
+	half := false
+	for _, c := range hex {
+		var v byte
+		switch {
+		case c >= '0' && c <= '9':
+			v = c - '0'
+		case c >= 'a' && c <= 'f':
+			v = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			v = c - 'A' + 10
+		default:
+			continue
+		}
+		if half {
+			buf = append(buf, high<<4|v)
+		} else {
+			high = v
+		}
+		half = !half
+	}
+	if half {
+		buf = append(buf, high<<4)
+	}
+	return buf
+}
+
+// decodePDFBytes 未知字体的字符串：带BOM的按UTF-16BE解码，其余按单字节编码解码
+func decodePDFBytes(b []byte) string {
+	if len(b) >= 2 && b[0] == 0xfe && b[1] == 0xff {
+		return decodeUTF16BE(b[2:])
+	}
+	if utf8.Valid(b) {
+		return string(b)
+	}
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+// decodeUTF16BE 按UTF-16BE解码
+func decodeUTF16BE(b []byte) string {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
@@ -0,0 +1,289 @@
+package resumeparser
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildPDF 按顺序生成间接对象，对象号从 1 开始；解析器不依赖交叉引用表
+func buildPDF(objects ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n")
+	for i, obj := range objects {
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	buf.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return buf.Bytes()
+}
+
+// pdfStream 生成未压缩的流对象
+func pdfStream(dict, content string) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(content), content)
+}
+
+// flateStream 生成 FlateDecode 压缩的流对象
+func flateStream(dict, content string) string {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write([]byte(content))
+	w.Close()
+	return fmt.Sprintf("<< %s /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream", dict, buf.Len(), buf.String())
+}
+
+// singlePagePDF 单页文档：1 目录、2 页面树、3 页面、4 字体、5 内容流，extra 从 6 开始编号
+func singlePagePDF(font, content string, extra ...string) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 4 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Contents 5 0 R >>",
+		font,
+		pdfStream("", content),
+	}
+	return buildPDF(append(objects, extra...)...)
+}
+
+const identityCMap = `/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+/CMapName /Adobe-Identity-UCS def
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+2 beginbfchar
+<0001> <5F20>
+<0004> <0020>
+endbfchar
+2 beginbfrange
+<0002> <0003> <4E09>
+<0010> <0011> [<5DE5> <7A0B5E08>]
+endbfrange
+endcmap
+end end`
+
+func TestPDFText(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want []string
+	}{
+		{
+			name: "简单字体与字距调整",
+			data: singlePagePDF(
+				"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+				"BT /F1 12 Tf 72 700 Td (Zhang San) Tj 0 -20 Td [(Go)-300(Lang) 20 (uage)] TJ T* (Caf\\351 \\(Beijing\\)) ' ET",
+			),
+			want: []string{"Zhang San", "Go Language", "Café (Beijing)"},
+		},
+		{
+			name: "Identity-H 字体通过 ToUnicode 解码",
+			data: singlePagePDF(
+				"<< /Type /Font /Subtype /Type0 /BaseFont /SimSun /Encoding /Identity-H /ToUnicode 6 0 R >>",
+				"BT /F1 12 Tf 72 700 Td <0001 0002> Tj 0 -20 Td [<0010>-100<0011>] TJ ET",
+				pdfStream("", identityCMap),
+			),
+			want: []string{"张三", "工程师"},
+		},
+		{
+			name: "UCS2 预定义编码",
+			data: singlePagePDF(
+				"<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UCS2-H >>",
+				"BT /F1 12 Tf <5F204E09> Tj ET",
+			),
+			want: []string{"张三"},
+		},
+		{
+			name: "带BOM的字符串",
+			data: singlePagePDF(
+				"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+				"BT /F1 12 Tf <FEFF674E56DB> Tj ET",
+			),
+			want: []string{"李四"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := pdfText(tt.data)
+			if err != nil {
+				t.Fatalf("pdfText: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("提取结果 %q 中缺少 %q", text, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPDFTextCompressedObjectStream(t *testing.T) {
+	// 页面和字体（对象 3、4）压缩存放在对象流 6 中，内容流使用 FlateDecode
+	page := "<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>"
+	font := "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>"
+	header := fmt.Sprintf("3 0 4 %d", len(page)+1)
+	objStm := flateStream(fmt.Sprintf("/Type /ObjStm /N 2 /First %d", len(header)+1), header+"\n"+page+"\n"+font)
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n")
+	for num, obj := range map[int]string{
+		1: "<< /Type /Catalog /Pages 2 0 R >>",
+		2: "<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		5: flateStream("", "BT /F1 11 Tf 50 760 Td (Senior Go Engineer) Tj ET"),
+		6: objStm,
+	} {
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", num, obj)
+	}
+
+	text, err := pdfText(buf.Bytes())
+	if err != nil {
+		t.Fatalf("pdfText: %v", err)
+	}
+	if !strings.Contains(text, "Senior Go Engineer") {
+		t.Errorf("提取结果 %q 中缺少页面文本", text)
+	}
+}
+
+func TestPDFTextErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{
+			name: "不是PDF",
+			data: []byte("hello"),
+			want: "不是有效的PDF文件",
+		},
+		{
+			name: "缺少 ToUnicode 的 Identity-H 字体",
+			data: singlePagePDF(
+				"<< /Type /Font /Subtype /Type0 /BaseFont /SimSun /Encoding /Identity-H >>",
+				"BT /F1 12 Tf <0001000200030004> Tj ET",
+			),
+			want: ErrUnreadablePDF.Error(),
+		},
+		{
+			name: "ToUnicode 覆盖不全",
+			data: singlePagePDF(
+				"<< /Type /Font /Subtype /Type0 /BaseFont /SimSun /Encoding /Identity-H /ToUnicode 6 0 R >>",
+				"BT /F1 12 Tf <00010002009900980097> Tj ET",
+				pdfStream("", identityCMap),
+			),
+			want: ErrUnreadablePDF.Error(),
+		},
+		{
+			name: "没有文本层",
+			data: singlePagePDF(
+				"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+				"q 100 0 0 100 0 0 cm /Im1 Do Q",
+			),
+			want: "PDF中没有可提取的文本，扫描件请先进行文字识别",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := pdfText(tt.data)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("错误为 %v，期望 %s", err, tt.want)
+			}
+		})
+	}
+	if _, err := ExtractText("resume.pdf", singlePagePDF(
+		"<< /Type /Font /Subtype /Type0 /Encoding /Identity-H >>",
+		"BT /F1 12 Tf <0001> Tj ET",
+	)); !errors.Is(err, ErrUnreadablePDF) {
+		t.Errorf("ExtractText 应返回 ErrUnreadablePDF，得到 %v", err)
+	}
+}
+
+func TestParseToUnicode(t *testing.T) {
+	m := parseToUnicode([]byte(identityCMap))
+	tests := []struct {
+		code     []byte
+		want     string
+		unmapped int
+	}{
+		{[]byte{0x00, 0x01}, "张", 0},
+		{[]byte{0x00, 0x02, 0x00, 0x03}, "三上", 0},
+		{[]byte{0x00, 0x10, 0x00, 0x11}, "工程师", 0},
+		{[]byte{0x00, 0x05}, "", 1},
+	}
+	for _, tt := range tests {
+		got, _, unmapped := m.decode(tt.code)
+		if got != tt.want || unmapped != tt.unmapped {
+			t.Errorf("decode(% x) = %q, %d，期望 %q, %d", tt.code, got, unmapped, tt.want, tt.unmapped)
+		}
+	}
+}
+
+func TestPDFDecodesOnlyReachableStreams(t *testing.T) {
+	// 对象 6、7 是页面树之外的压缩流，对象 8 是页面引用的表单
+	data := singlePagePDF(
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"BT /F1 12 Tf (Zhang San) Tj ET",
+		flateStream("", strings.Repeat("BT (unused) Tj ET ", 1000)),
+		flateStream("/Type /XObject /Subtype /Form", "BT (orphan form) Tj ET"),
+		flateStream("/Type /XObject /Subtype /Form", "BT (Go Engineer) Tj ET"),
+	)
+	data = bytes.Replace(data, []byte("/Contents 5 0 R"), []byte("/Contents 5 0 R /Resources << /Font << /F1 4 0 R >> /XObject << /X1 8 0 R >> >>"), 1)
+
+	doc := newPDFDocument(data)
+	var sb strings.Builder
+	for _, page := range doc.pages() {
+		doc.pageText(page.object, page.resources, &sb)
+	}
+	if text := sb.String(); !strings.Contains(text, "Zhang San") || !strings.Contains(text, "Go Engineer") || strings.Contains(text, "orphan") {
+		t.Errorf("提取结果为 %q", text)
+	}
+	for num, want := range map[int]bool{5: true, 6: false, 7: false, 8: true} {
+		if got := doc.objects[num].decoded; got != want {
+			t.Errorf("对象 %d 已解码为 %v，期望 %v", num, got, want)
+		}
+	}
+}
+
+func TestPDFDecompressionBudget(t *testing.T) {
+	first := "BT (Zhang San) Tj ET"
+	data := singlePagePDF(
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"",
+		flateStream("", first),
+		flateStream("", "BT (Go Engineer) Tj ET"),
+		flateStream("", "BT (Beijing) Tj ET"),
+	)
+	data = bytes.Replace(data, []byte("/Contents 5 0 R"), []byte("/Contents [6 0 R 7 0 R 8 0 R]"), 1)
+
+	// 预算在第二个流中途用尽，第二个流被截断，第三个流不再解压
+	doc := newPDFDocument(data)
+	doc.budget = len(first) + 4
+	streams := make([]string, 0, 3)
+	for _, num := range []int{6, 7, 8} {
+		streams = append(streams, string(doc.stream(doc.objects[num])))
+	}
+	if want := []string{first, "BT (", ""}; strings.Join(streams, "|") != strings.Join(want, "|") {
+		t.Errorf("解码结果为 %q，期望 %q", streams, want)
+	}
+	if doc.budget != 0 {
+		t.Errorf("剩余预算为 %d，期望 0", doc.budget)
+	}
+
+	// 多个各自未超限的压缩炸弹合计不超过解压上限
+	bomb := flateStream("", strings.Repeat("0", maxExtractedSize*3/4))
+	data = singlePagePDF("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>", "BT (Zhang San) Tj ET", bomb, bomb)
+	data = bytes.Replace(data, []byte("/Contents 5 0 R"), []byte("/Contents [5 0 R 6 0 R 7 0 R]"), 1)
+	doc = newPDFDocument(data)
+	total := 0
+	for _, num := range []int{6, 7} {
+		total += len(doc.stream(doc.objects[num]))
+	}
+	if total != maxExtractedSize || doc.budget != 0 {
+		t.Errorf("共解压 %d 字节，剩余预算 %d，期望共 %d 字节", total, doc.budget, maxExtractedSize)
+	}
+	text, err := pdfText(data)
+	if err != nil || !strings.Contains(text, "Zhang San") {
+		t.Errorf("pdfText = %.40q, %v", text, err)
+	}
+}
@@ -0,0 +1,51 @@
+package resumeparser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// skillAliases 常见技能别名，键为规范化后的写法，值为统一的技能标识与展示名称
+var skillAliases = map[string][2]string{
+	"golang":              {"go", "Go"},
+	"go语言":                {"go", "Go"},
+	"js":                  {"javascript", "JavaScript"},
+	"ts":                  {"typescript", "TypeScript"},
+	"node":                {"node.js", "Node.js"},
+	"nodejs":              {"node.js", "Node.js"},
+	"reactjs":             {"react", "React"},
+	"react.js":            {"react", "React"},
+	"vuejs":               {"vue", "Vue"},
+	"vue.js":              {"vue", "Vue"},
+	"k8s":                 {"kubernetes", "Kubernetes"},
+	"postgres":            {"postgresql", "PostgreSQL"},
+	"pg":                  {"postgresql", "PostgreSQL"},
+	"py":                  {"python", "Python"},
+	"python3":             {"python", "Python"},
+	"c/c++":               {"c++", "C++"},
+	"cpp":                 {"c++", "C++"},
+	"csharp":              {"c#", "C#"},
+	"dotnet":              {".net", ".NET"},
+	".net":                {".net", ".NET"},
+	"springboot":          {"spring boot", "Spring Boot"},
+	"ml":                  {"machine learning", "Machine Learning"},
+	"机器学习":                {"machine learning", "Machine Learning"},
+	"amazon web services": {"aws", "AWS"},
+}
+
+// NormalizeSkill 返回技能的规范化标识和展示名称，标识用于去重和匹配，
+// 统一小写、合并空白并映射常见别名
+func NormalizeSkill(name string) (key, display string) {
+	display = strings.Join(strings.FieldsFunc(name, unicode.IsSpace), " ")
+	if display == "" {
+		return "", ""
+	}
+	key = strings.ToLower(display)
+	if alias, ok := skillAliases[key]; ok {
+		return alias[0], alias[1]
+	}
+	if alias, ok := skillAliases[strings.ReplaceAll(key, " ", "")]; ok {
+		return alias[0], alias[1]
+	}
+	return key, display
+}
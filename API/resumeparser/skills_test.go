@@ -0,0 +1,49 @@
+package resumeparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeSkill(t *testing.T) {
+	tests := []struct {
+		in           string
+		key, display string
+	}{
+		{"Golang", "go", "Go"},
+		{"  Node  JS ", "node.js", "Node.js"},
+		{"k8s", "kubernetes", "Kubernetes"},
+		{"Spring Boot", "spring boot", "Spring Boot"},
+		{"机器学习", "machine learning", "Machine Learning"},
+		{"Rust", "rust", "Rust"},
+		{"   ", "", ""},
+	}
+	for _, tt := range tests {
+		key, display := NormalizeSkill(tt.in)
+		if key != tt.key || display != tt.display {
+			t.Errorf("NormalizeSkill(%q) = %q, %q，期望 %q, %q", tt.in, key, display, tt.key, tt.display)
+		}
+	}
+}
+
+func TestSplitSkills(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"顿号与分类前缀", "编程语言：Go、Python、Java", []string{"Go", "Python", "Java"}},
+		{"斜杠两侧有空格视为两项", "MySQL / Redis", []string{"MySQL", "Redis"}},
+		{"斜杠无空格保持不变", "CI/CD, Docker", []string{"CI/CD", "Docker"}},
+		{"别名去重", "Golang, Go, go语言\nk8s | Kubernetes", []string{"Golang", "k8s"}},
+		{"列表符号", "• React • Vue\n- TypeScript。", []string{"React", "Vue", "TypeScript"}},
+		{"过长的条目忽略", "熟练掌握各种常见的后端开发技术以及分布式系统的设计与实现方法并且具有丰富的线上故障排查经验和团队管理经验", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitSkills(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitSkills(%q) = %v, 期望 %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
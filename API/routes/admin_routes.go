@@ -1,49 +1,212 @@
 package routes
 
 import (
+	"API/middlewares"
+	"API/models"
+
 	"github.com/gin-gonic/gin"
 )
 
-func setupAdminRoutes(apiV1 *gin.RouterGroup, ctrls Controllers) {
-	apiV1.Group("").Use(adminAuthMiddleware...)
+func setupAdminRoutes(apiV1 *gin.RouterGroup, ctrls Controllers, auth []gin.HandlerFunc, perms middlewares.PermissionResolver) {
+	require := func(code string) gin.HandlerFunc {
+		return middlewares.RequirePermission(perms, code)
+	}
+
+	adminRoutes := apiV1.Group("", auth...)
 	{
 		// 薪资管理
-		salaries := apiV1.Group("/salaries")
+		salaries := adminRoutes.Group("/salaries")
 		{
-			salaries.POST("/generate", ctrls.salary.GenerateSalary)
-			salaries.GET("/history", ctrls.salary.GetSalaryHistory)
+			salaries.POST("/generate", require(models.PermSalaryGenerate), ctrls.salary.GenerateSalary)
+			salaries.GET("/history", require(models.PermSalaryView), ctrls.salary.GetSalaryHistory)
 		}
 
 		// 通知管理
-		notices := apiV1.Group("/notices")
+		notices := adminRoutes.Group("/notices")
 		{
-			notices.POST("", ctrls.notice.CreateNotice)
-			notices.DELETE("/:id", ctrls.notice.DeleteNotice)
-			notices.PUT("/:id", ctrls.notice.UpdateNotice)
+			notices.POST("", require(models.PermNoticeCreate), ctrls.notice.CreateNotice)
+			notices.DELETE("/:id", require(models.PermNoticeDelete), ctrls.notice.DeleteNotice)
+			notices.PUT("/:id", require(models.PermNoticeUpdate), ctrls.notice.UpdateNotice)
 		}
 
 		// 角色管理
-		roles := apiV1.Group("/roles")
+		roles := adminRoutes.Group("/roles")
+		{
+			roles.POST("", require(models.PermRoleCreate), ctrls.role.CreateRole)
+			roles.GET("", require(models.PermRoleView), ctrls.role.GetRoles)
+			roles.GET("/:id", require(models.PermRoleView), ctrls.role.GetRole)
+			roles.PUT("/:id", require(models.PermRoleUpdate), ctrls.role.UpdateRole)
+			roles.DELETE("/:id", require(models.PermRoleDelete), ctrls.role.DeleteRole)
+			roles.POST("/:id/permissions", require(models.PermRoleGrant), ctrls.role.GrantPermissions)
+			roles.DELETE("/:id/permissions", require(models.PermRoleGrant), ctrls.role.RevokePermissions)
+		}
+
+		// 用户角色与权限
+		users := adminRoutes.Group("/users")
 		{
-			roles.POST("", ctrls.role.CreateRole)
-			roles.GET("", ctrls.role.GetRoles)
+			users.POST("/:id/roles", require(models.PermUserAssignRole), ctrls.role.AssignUserRoles)
+			users.DELETE("/:id/roles/:role_id", require(models.PermUserAssignRole), ctrls.role.UnassignUserRole)
+			users.GET("/:id/permissions", require(models.PermUserPermissions), ctrls.permission.GetUserPermissions)
+			users.POST("/:id/tokens/revoke", require(models.PermUserRevokeTokens), ctrls.user.RevokeUserTokens)
+			users.POST("/:id/unlock", require(models.PermUserUnlock), ctrls.user.UnlockAccount)
+			users.POST("/:id/mfa/reset", require(models.PermUserResetMFA), ctrls.mfa.ResetUserMFA)
+			users.GET("/:id/leave-balances", require(models.PermLeaveManage), ctrls.leave.GetUserBalances)
+			users.PUT("/:id/region", require(models.PermHolidayManage), ctrls.holiday.SetUserRegion)
 		}
 
 		// 职位管理
-		jobs := apiV1.Group("/jobs")
+		jobs := adminRoutes.Group("/jobs")
+		{
+			jobs.GET("", require(models.PermJobView), ctrls.job.ListJobs)
+			jobs.POST("", require(models.PermJobCreate), ctrls.job.CreateJob)
+			jobs.PUT("/:id", require(models.PermJobUpdate), ctrls.job.UpdateJob)
+			jobs.POST("/:id/reopen", require(models.PermJobUpdate), ctrls.job.ReopenJob)
+			jobs.POST("/:id/publish", require(models.PermJobPublish), ctrls.job.PublishJob)
+			jobs.POST("/:id/requisition", require(models.PermRequisitionCreate), ctrls.requisition.SubmitRequisition)
+			jobs.POST("/:id/apply", require(models.PermJobApply), ctrls.job.ApplyForJob)
+			jobs.DELETE("/:id", require(models.PermJobDelete), ctrls.job.DeleteJob)
+		}
+
+		// 招聘需求审批
+		requisitions := adminRoutes.Group("/requisitions")
+		{
+			requisitions.GET("", require(models.PermJobView), ctrls.requisition.ListRequisitions)
+			requisitions.GET("/pending", require(models.PermRequisitionApprove), ctrls.requisition.ListPendingRequisitions)
+			requisitions.GET("/:id", require(models.PermJobView), ctrls.requisition.GetRequisition)
+			requisitions.POST("/:id/approve", require(models.PermRequisitionApprove), ctrls.requisition.ApproveRequisition)
+			requisitions.POST("/:id/reject", require(models.PermRequisitionApprove), ctrls.requisition.RejectRequisition)
+		}
+
+		// 考勤统计
+		adminRoutes.GET("/attendance/stats", require(models.PermAttendanceStats), ctrls.attendance.GetAttendanceStats)
+		adminRoutes.GET("/attendance/missing-clock-outs", require(models.PermAttendanceStats), ctrls.correction.ListMissingClockOuts)
+
+		// 补卡审批
+		corrections := adminRoutes.Group("/attendance/corrections")
+		{
+			corrections.GET("/pending", require(models.PermAttendanceCorrect), ctrls.correction.ListPendingCorrections)
+			corrections.POST("/:id/approve", require(models.PermAttendanceCorrect), ctrls.correction.ApproveCorrection)
+			corrections.POST("/:id/reject", require(models.PermAttendanceCorrect), ctrls.correction.RejectCorrection)
+		}
+
+		// 班次管理
+		schedules := adminRoutes.Group("/schedules")
+		{
+			schedules.GET("", require(models.PermScheduleManage), ctrls.schedule.ListSchedules)
+			schedules.POST("", require(models.PermScheduleManage), ctrls.schedule.CreateSchedule)
+			schedules.PUT("/:id", require(models.PermScheduleManage), ctrls.schedule.UpdateSchedule)
+			schedules.DELETE("/:id", require(models.PermScheduleManage), ctrls.schedule.DeleteSchedule)
+		}
+		scheduleAssignments := adminRoutes.Group("/schedule-assignments")
+		{
+			scheduleAssignments.GET("", require(models.PermScheduleManage), ctrls.schedule.ListAssignments)
+			scheduleAssignments.POST("", require(models.PermScheduleManage), ctrls.schedule.CreateAssignment)
+			scheduleAssignments.DELETE("/:id", require(models.PermScheduleManage), ctrls.schedule.DeleteAssignment)
+		}
+
+		// 节假日日历
+		holidayCalendars := adminRoutes.Group("/holiday-calendars")
+		{
+			holidayCalendars.GET("", require(models.PermHolidayManage), ctrls.holiday.ListCalendars)
+			holidayCalendars.POST("", require(models.PermHolidayManage), ctrls.holiday.CreateCalendar)
+			holidayCalendars.PUT("/:id", require(models.PermHolidayManage), ctrls.holiday.UpdateCalendar)
+			holidayCalendars.DELETE("/:id", require(models.PermHolidayManage), ctrls.holiday.DeleteCalendar)
+			holidayCalendars.GET("/:id/holidays", require(models.PermHolidayManage), ctrls.holiday.ListHolidays)
+			holidayCalendars.POST("/:id/holidays", require(models.PermHolidayManage), ctrls.holiday.SetHoliday)
+			holidayCalendars.DELETE("/:id/holidays/:holiday_id", require(models.PermHolidayManage), ctrls.holiday.DeleteHoliday)
+			holidayCalendars.POST("/:id/import", require(models.PermHolidayManage), ctrls.holiday.ImportHolidays)
+		}
+
+		// 请假审批
+		leaveRequests := adminRoutes.Group("/leave/requests")
+		{
+			leaveRequests.GET("/pending", require(models.PermLeaveApprove), ctrls.leave.ListPendingLeaveRequests)
+			leaveRequests.POST("/:id/approve", require(models.PermLeaveApprove), ctrls.leave.ApproveLeaveRequest)
+			leaveRequests.POST("/:id/reject", require(models.PermLeaveApprove), ctrls.leave.RejectLeaveRequest)
+		}
+		adminRoutes.PUT("/leave-types/:id", require(models.PermLeaveManage), ctrls.leave.UpdateLeaveType)
+
+		// 加班审批与统计
+		overtime := adminRoutes.Group("/overtime")
+		{
+			overtime.GET("/requests/pending", require(models.PermOvertimeApprove), ctrls.overtime.ListPendingOvertimeRequests)
+			overtime.POST("/requests/:id/approve", require(models.PermOvertimeApprove), ctrls.overtime.ApproveOvertimeRequest)
+			overtime.POST("/requests/:id/reject", require(models.PermOvertimeApprove), ctrls.overtime.RejectOvertimeRequest)
+			overtime.GET("/monthly", require(models.PermOvertimeView), ctrls.overtime.GetMonthlyOvertime)
+		}
+
+		// 培训管理
+		adminRoutes.POST("/trainings", require(models.PermTrainingCreate), ctrls.training.CreateTraining)
+		adminRoutes.PUT("/training-records/:id", require(models.PermTrainingGrade), ctrls.training.UpdateTrainingRecord)
+
+		// 招聘流程
+		jobs.GET("/:id/pipeline", require(models.PermApplicationReview), ctrls.pipeline.GetPipeline)
+		jobs.PUT("/:id/pipeline", require(models.PermPipelineManage), ctrls.pipeline.UpdatePipeline)
+		jobs.GET("/:id/applications", require(models.PermApplicationReview), ctrls.pipeline.ListJobApplications)
+		jobs.GET("/:id/candidates", require(models.PermApplicationReview), ctrls.matching.RankCandidates)
+		applications := adminRoutes.Group("/applications")
+		{
+			applications.POST("/:id/stage", require(models.PermApplicationReview), ctrls.application.MoveApplicationStage)
+			applications.GET("/:id/history", require(models.PermApplicationReview), ctrls.application.GetApplicationHistory)
+			applications.GET("/:id/resume", require(models.PermApplicationReview), ctrls.application.GetApplicationResume)
+			applications.POST("/:id/interviews", require(models.PermInterviewManage), ctrls.interview.ScheduleInterview)
+			applications.GET("/:id/interviews", require(models.PermApplicationReview), ctrls.interview.ListApplicationInterviews)
+			applications.POST("/:id/offers", require(models.PermOfferManage), ctrls.offer.CreateOffer)
+			applications.GET("/:id/offers", require(models.PermApplicationReview), ctrls.offer.ListApplicationOffers)
+		}
+
+		// 人才库
+		talentPools := adminRoutes.Group("/talent-pools")
+		{
+			talentPools.GET("", require(models.PermTalentManage), ctrls.talent.ListTalentPools)
+			talentPools.POST("", require(models.PermTalentManage), ctrls.talent.CreateTalentPool)
+			talentPools.PUT("/:id", require(models.PermTalentManage), ctrls.talent.UpdateTalentPool)
+			talentPools.DELETE("/:id", require(models.PermTalentManage), ctrls.talent.DeleteTalentPool)
+			talentPools.POST("/:id/members", require(models.PermTalentManage), ctrls.talent.AddTalentPoolMembers)
+			talentPools.DELETE("/:id/members/:user_id", require(models.PermTalentManage), ctrls.talent.RemoveTalentPoolMember)
+		}
+		talentTags := adminRoutes.Group("/talent-tags")
+		{
+			talentTags.GET("", require(models.PermTalentManage), ctrls.talent.ListTalentTags)
+			talentTags.POST("", require(models.PermTalentTagManage), ctrls.talent.CreateTalentTag)
+			talentTags.DELETE("/:id", require(models.PermTalentTagManage), ctrls.talent.DeleteTalentTag)
+		}
+		candidates := adminRoutes.Group("/candidates")
+		{
+			candidates.GET("", require(models.PermTalentManage), ctrls.talent.SearchCandidates)
+			candidates.POST("/:id/tags", require(models.PermTalentManage), ctrls.talent.TagCandidate)
+			candidates.DELETE("/:id/tags/:tag_id", require(models.PermTalentManage), ctrls.talent.UntagCandidate)
+			candidates.GET("/:id/notes", require(models.PermTalentManage), ctrls.talent.ListCandidateNotes)
+			candidates.POST("/:id/notes", require(models.PermTalentManage), ctrls.talent.AddCandidateNote)
+		}
+		adminRoutes.DELETE("/candidate-notes/:id", require(models.PermTalentManage), ctrls.talent.DeleteCandidateNote)
+
+		// 面试管理
+		interviews := adminRoutes.Group("/interviews")
+		{
+			interviews.PUT("/:id", require(models.PermInterviewManage), ctrls.interview.RescheduleInterview)
+			interviews.POST("/:id/cancel", require(models.PermInterviewManage), ctrls.interview.CancelInterview)
+		}
+
+		// 录用通知
+		offerTemplates := adminRoutes.Group("/offer-templates")
+		{
+			offerTemplates.POST("", require(models.PermOfferManage), ctrls.offer.CreateTemplate)
+			offerTemplates.GET("", require(models.PermOfferManage), ctrls.offer.ListTemplates)
+			offerTemplates.PUT("/:id", require(models.PermOfferManage), ctrls.offer.UpdateTemplate)
+		}
+		offers := adminRoutes.Group("/offers")
 		{
-			jobs.GET("", ctrls.job.ListJobs)
-			jobs.POST("", ctrls.job.CreateJob)
-			jobs.PUT("/:id", ctrls.job.UpdateJob)
-			jobs.POST("/:id/apply", ctrls.job.ApplyForJob)
-			jobs.DELETE("/:id", ctrls.job.DeleteJob)
+			offers.PUT("/:id", require(models.PermOfferManage), ctrls.offer.UpdateOffer)
+			offers.POST("/:id/approve", require(models.PermOfferApprove), ctrls.offer.ApproveOffer)
+			offers.POST("/:id/send", require(models.PermOfferManage), ctrls.offer.SendOffer)
 		}
 
 		// 权限管理
-		permission := apiV1.Group("/permissions")
+		permission := adminRoutes.Group("/permissions")
 		{
-			permission.GET("", ctrls.permission.GetPermissions)
-			permission.POST("", ctrls.permission.CreatePermission)
+			permission.GET("", require(models.PermPermissionView), ctrls.permission.GetPermissions)
+			permission.POST("", require(models.PermPermissionCreate), ctrls.permission.CreatePermission)
 		}
 	}
 }
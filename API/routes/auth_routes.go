@@ -4,25 +4,128 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func setupAuthRoutes(apiV1 *gin.RouterGroup, ctrls Controllers) {
+func setupAuthRoutes(apiV1 *gin.RouterGroup, ctrls Controllers, auth []gin.HandlerFunc) {
 	authGroup := apiV1.Group("/auth")
 	{
 		authGroup.POST("/login", ctrls.user.Login)
 		authGroup.POST("/register", ctrls.user.Register)
+		authGroup.POST("/refresh", ctrls.user.RefreshToken)
+		authGroup.POST("/mfa/verify", ctrls.mfa.VerifyLogin)
+		authGroup.POST("/mfa/enroll", ctrls.mfa.EnrollLogin)
+		authGroup.POST("/password/forgot", ctrls.account.ForgotPassword)
+		authGroup.POST("/password/reset", ctrls.account.ResetPassword)
+		authGroup.POST("/email/verify", ctrls.account.VerifyEmail)
 	}
 
-	authRoutes := apiV1.Group("").Use(defaultAuthMiddleware...)
+	// 公开职位
+	jobBoard := apiV1.Group("/job-board")
 	{
+		jobBoard.GET("", ctrls.job.SearchJobs)
+		jobBoard.GET("/:id", ctrls.job.GetPublicJob)
+	}
+
+	authRoutes := apiV1.Group("", auth...)
+	{
+		authRoutes.POST("/auth/logout", ctrls.user.Logout)
+		authRoutes.POST("/auth/password/change", ctrls.user.ChangePassword)
+		authRoutes.POST("/auth/email/resend", ctrls.account.ResendEmailVerification)
+		authRoutes.POST("/auth/mfa/setup", ctrls.mfa.Setup)
+		authRoutes.POST("/auth/mfa/enable", ctrls.mfa.Enable)
+		authRoutes.POST("/auth/mfa/disable", ctrls.mfa.Disable)
+		authRoutes.POST("/auth/mfa/recovery-codes", ctrls.mfa.RegenerateRecoveryCodes)
+
 		authRoutes.GET("/notices", ctrls.notice.GetNotices)
 		authRoutes.GET("/notices/department/:department", ctrls.notice.GetDepartmentNotices)
 		authRoutes.PUT("/notices/:id/read", ctrls.notice.MarkNoticeAsRead)
 
-		users := apiV1.Group("/users")
+		users := authRoutes.Group("/users")
 		{
 			users.GET("/profile", ctrls.user.GetProfile)
 			users.PUT("/profile", ctrls.user.UpdateProfile)
 		}
 
+		// 考勤
+		attendance := authRoutes.Group("/attendance")
+		{
+			attendance.POST("/clock-in", ctrls.attendance.ClockIn)
+			attendance.POST("/clock-out", ctrls.attendance.ClockOut)
+			attendance.GET("/monthly", ctrls.attendance.GetMonthly)
+			attendance.GET("/shift", ctrls.schedule.GetMyShift)
+			attendance.POST("/corrections", ctrls.correction.SubmitCorrection)
+			attendance.GET("/corrections/my", ctrls.correction.GetMyCorrections)
+			attendance.POST("/corrections/:id/cancel", ctrls.correction.CancelCorrection)
+		}
+
+		// 节假日与工作日
+		authRoutes.GET("/holidays", ctrls.holiday.GetMyHolidays)
+		authRoutes.GET("/workdays", ctrls.holiday.GetMyWorkdays)
+
+		// 请假
+		leave := authRoutes.Group("/leave")
+		{
+			leave.GET("/types", ctrls.leave.ListLeaveTypes)
+			leave.GET("/balances", ctrls.leave.GetMyBalances)
+			leave.POST("/requests", ctrls.leave.SubmitLeaveRequest)
+			leave.GET("/requests/my", ctrls.leave.GetMyLeaveRequests)
+			leave.POST("/requests/:id/cancel", ctrls.leave.CancelLeaveRequest)
+		}
+
+		// 加班
+		overtime := authRoutes.Group("/overtime")
+		{
+			overtime.POST("/requests", ctrls.overtime.SubmitOvertimeRequest)
+			overtime.GET("/requests/my", ctrls.overtime.GetMyOvertimeRequests)
+			overtime.POST("/requests/:id/cancel", ctrls.overtime.CancelOvertimeRequest)
+			overtime.GET("/summary", ctrls.overtime.GetMyOvertimeSummary)
+		}
+
+		// 培训
+		trainings := authRoutes.Group("/trainings")
+		{
+			trainings.GET("", ctrls.training.GetTrainings)
+			trainings.GET("/my", ctrls.training.GetMyTrainings)
+			trainings.GET("/:id", ctrls.training.GetTrainingDetail)
+			trainings.POST("/:id/register", ctrls.training.RegisterTraining)
+		}
+		authRoutes.POST("/training-records/:id/cancel", ctrls.training.CancelTrainingRegistration)
+
+		// 简历
+		resumes := authRoutes.Group("/resumes")
+		{
+			resumes.POST("", ctrls.resume.SubmitResume)
+			resumes.GET("", ctrls.resume.GetResume)
+			resumes.POST("/parse", ctrls.resume.ParseResume)
+			resumes.GET("/export", ctrls.resume.ExportResume)
+			resumes.POST("/import", ctrls.resume.ImportResume)
+			resumes.GET("/versions", ctrls.resume.ListResumeVersions)
+			resumes.GET("/versions/diff", ctrls.resume.DiffResumeVersions)
+			resumes.GET("/versions/:version", ctrls.resume.GetResumeVersion)
+		}
+
+		// 职位匹配
+		authRoutes.GET("/jobs/recommended", ctrls.matching.RecommendJobs)
+		authRoutes.GET("/jobs/:id/match", ctrls.matching.MatchJob)
+
+		// 我的申请
+		myApplications := authRoutes.Group("/applications")
+		{
+			myApplications.GET("/my", ctrls.application.GetMyApplications)
+			myApplications.PUT("/:id", ctrls.application.UpdateMyApplication)
+			myApplications.POST("/:id/withdraw", ctrls.application.WithdrawApplication)
+		}
+
+		// 面试官
+		authRoutes.GET("/interviews/my", ctrls.interview.GetMyInterviews)
+		authRoutes.POST("/interviews/:id/scorecard", ctrls.interview.SubmitScorecard)
+
+		// 录用通知
+		authRoutes.GET("/offers/my", ctrls.offer.GetMyOffers)
+		authRoutes.POST("/offers/:id/accept", ctrls.offer.AcceptOffer)
+		authRoutes.POST("/offers/:id/decline", ctrls.offer.DeclineOffer)
+
+		// 本人薪资
+		authRoutes.GET("/salaries/:month", ctrls.salary.GetSalaryDetail)
+
 		authRoutes.POST("/upload", ctrls.upload.UploadFile)
 		authRoutes.GET("/download/:file_id", ctrls.upload.DownloadFile)
 	}
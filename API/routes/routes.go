@@ -20,22 +20,24 @@ import (
 	"go.uber.org/zap"
 )
 
-var (
-	defaultAuthMiddleware = []gin.HandlerFunc{
-		middlewares.JWT(),
+// authMiddleware 需要登录的路由使用的中间件
+func authMiddleware(tokens middlewares.TokenVerifier) []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		middlewares.JWT(tokens),
 		middlewares.EnhancedAuditLogger(zap.L()),
 	}
-
-	adminAuthMiddleware = []gin.HandlerFunc{
-		middlewares.JWT(),
-		middlewares.AdminOnly(),
-		middlewares.EnhancedAuditLogger(zap.L()),
-	}
-)
+}
 
 type Controllers struct {
 	user        *controllers.UserController
+	account     *controllers.AccountController
+	mfa         *controllers.MFAController
 	attendance  *controllers.AttendanceController
+	schedule    *controllers.ScheduleController
+	leave       *controllers.LeaveController
+	holiday     *controllers.HolidayController
+	overtime    *controllers.OvertimeController
+	correction  *controllers.AttendanceCorrectionController
 	training    *controllers.TrainingController
 	salary      *controllers.SalaryController
 	notice      *controllers.NoticeController
@@ -43,6 +45,12 @@ type Controllers struct {
 	resume      *controllers.ResumeController
 	permission  *controllers.PermissionController
 	application *controllers.ApplicationController
+	pipeline    *controllers.PipelineController
+	interview   *controllers.InterviewController
+	offer       *controllers.OfferController
+	requisition *controllers.RequisitionController
+	matching    *controllers.MatchingController
+	talent      *controllers.TalentController
 	role        *controllers.RoleController
 	upload      *controllers.UploadController
 }
@@ -57,7 +65,7 @@ func initSwagger() {
 	docs.SwaggerInfo.Schemes = []string{"http", "https"}
 }
 
-func SetupRouter(userService *services.UserService, jobService *services.JobService) *gin.Engine {
+func SetupRouter(userService *services.UserService, accountService *services.AccountService, mfaService *services.MFAService, jobService *services.JobService, resumeService *services.ResumeService, requisitionService *services.RequisitionService, tokenService *services.TokenService, permissionService *services.PermissionService, scheduleService *services.ScheduleService) *gin.Engine {
 	// 设置Gin模式
 	gin.SetMode(gin.ReleaseMode)
 
@@ -67,18 +75,36 @@ func SetupRouter(userService *services.UserService, jobService *services.JobServ
 	// 创建路由引擎
 	router := gin.New()
 
+	cacheService := cache.NewRedisCacheService(cache.RedisClient)
+	holidayService := services.NewHolidayService(database.DB, services.LoadHolidayConfig())
+	workdays := services.NewWorkdayCalculator(database.DB, scheduleService, holidayService)
+	overtimeService := services.NewOvertimeService(database.DB, workdays, services.LoadOvertimeConfig())
+
 	// 初始化控制器
 	ctrls := Controllers{
-		user:        controllers.NewUserController(userService),
-		attendance:  controllers.NewAttendanceController(services.NewAttendanceService(database.DB)),
+		user:        controllers.NewUserController(userService, accountService),
+		account:     controllers.NewAccountController(accountService),
+		mfa:         controllers.NewMFAController(userService, mfaService),
+		attendance:  controllers.NewAttendanceController(services.NewAttendanceService(database.DB, scheduleService, workdays, overtimeService)),
+		schedule:    controllers.NewScheduleController(scheduleService),
+		leave:       controllers.NewLeaveController(services.NewLeaveService(database.DB, workdays)),
+		holiday:     controllers.NewHolidayController(holidayService, workdays),
+		overtime:    controllers.NewOvertimeController(overtimeService),
+		correction:  controllers.NewAttendanceCorrectionController(services.NewAttendanceCorrectionService(database.DB, scheduleService, overtimeService)),
 		training:    controllers.NewTrainingController(services.NewTrainingService(database.DB)),
-		salary:      controllers.NewSalaryController(services.NewSalaryService(database.DB)),
-		notice:      controllers.NewNoticeController(services.NewNoticeService(database.DB, cache.NewRedisCacheService(cache.RedisClient))),
+		salary:      controllers.NewSalaryController(services.NewSalaryService(database.DB, workdays, overtimeService)),
+		notice:      controllers.NewNoticeController(services.NewNoticeService(database.DB, cacheService)),
 		job:         controllers.NewJobController(jobService),
-		resume:      controllers.NewResumeController(services.NewResumeService(database.DB, cache.NewRedisCacheService(cache.RedisClient))),
-		permission:  controllers.NewPermissionController(services.NewPermissionService(database.DB)),
+		resume:      controllers.NewResumeController(resumeService),
+		permission:  controllers.NewPermissionController(permissionService),
 		application: controllers.NewApplicationController(services.NewApplicationService(database.DB)),
-		role:        controllers.NewRoleController(services.NewRoleService(database.DB)),
+		pipeline:    controllers.NewPipelineController(services.NewPipelineService(database.DB)),
+		interview:   controllers.NewInterviewController(services.NewInterviewService(database.DB)),
+		offer:       controllers.NewOfferController(services.NewOfferService(database.DB, tokenService)),
+		requisition: controllers.NewRequisitionController(requisitionService),
+		matching:    controllers.NewMatchingController(services.NewMatchingService(database.DB)),
+		talent:      controllers.NewTalentController(services.NewTalentService(database.DB, services.LoadTalentConfig())),
+		role:        controllers.NewRoleController(services.NewRoleService(database.DB, cacheService, tokenService)),
 		upload:      controllers.NewUploadController(),
 	}
 
@@ -103,8 +129,9 @@ func SetupRouter(userService *services.UserService, jobService *services.JobServ
 		// 健康检查
 		apiV1.GET("/health", enhancedHealthCheckHandler)
 
-		setupAuthRoutes(apiV1, ctrls)
-		setupAdminRoutes(apiV1, ctrls)
+		auth := authMiddleware(tokenService)
+		setupAuthRoutes(apiV1, ctrls, auth)
+		setupAdminRoutes(apiV1, ctrls, auth, permissionService)
 	}
 
 	// 404处理
@@ -120,6 +147,14 @@ func SetupRouter(userService *services.UserService, jobService *services.JobServ
 	return router
 }
 
+// enhancedHealthCheckHandler 健康检查
+// @Summary 健康检查
+// @Description 检查数据库和Redis连接状态
+// @Tags 系统
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{} "依赖服务异常"
+// @Router /api/v1/health [get]
 func enhancedHealthCheckHandler(c *gin.Context) {
 	type healthCheck func(context.Context) error
 
@@ -0,0 +1,86 @@
+package routes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// routerAnnotation 匹配 swagger 注解，如 "// @Router /api/v1/jobs/{id} [put]"
+var routerAnnotation = regexp.MustCompile(`^//\s*@Router\s+(\S+)\s+\[(\w+)\]`)
+
+// pathParam 将 swagger 路径参数 {id} 转换为 gin 的 :id
+var pathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// annotatedRoutes 收集目录下所有 @Router 注解，返回 "METHOD path" 集合
+func annotatedRoutes(t *testing.T, dirs ...string) map[string]string {
+	t.Helper()
+	routes := make(map[string]string)
+	for _, dir := range dirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			t.Fatalf("列出 %s 失败: %v", dir, err)
+		}
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			content, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("读取 %s 失败: %v", file, err)
+			}
+			for i, line := range strings.Split(string(content), "\n") {
+				m := routerAnnotation.FindStringSubmatch(strings.TrimSpace(line))
+				if m == nil {
+					continue
+				}
+				key := strings.ToUpper(m[2]) + " " + pathParam.ReplaceAllString(m[1], ":$1")
+				if prev, ok := routes[key]; ok {
+					t.Errorf("重复的 @Router 注解 %s: %s 与 %s:%d", key, prev, file, i+1)
+				}
+				routes[key] = fmt.Sprintf("%s:%d", file, i+1)
+			}
+		}
+	}
+	return routes
+}
+
+// TestRoutesMatchSwaggerAnnotations 确保每个 @Router 注解都已注册路由，且每个 API 路由都有注解
+func TestRoutesMatchSwaggerAnnotations(t *testing.T) {
+	router := SetupRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	registered := make(map[string]bool)
+	for _, r := range router.Routes() {
+		if !strings.HasPrefix(r.Path, "/api/") {
+			continue
+		}
+		registered[r.Method+" "+r.Path] = true
+	}
+
+	annotated := annotatedRoutes(t, "../controllers", ".")
+
+	var missing, undocumented []string
+	for key, location := range annotated {
+		if !registered[key] {
+			missing = append(missing, key+" ("+location+")")
+		}
+	}
+	for key := range registered {
+		if _, ok := annotated[key]; !ok {
+			undocumented = append(undocumented, key)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(undocumented)
+
+	for _, key := range missing {
+		t.Errorf("@Router 注解声明的接口未注册路由: %s", key)
+	}
+	for _, key := range undocumented {
+		t.Errorf("路由缺少 @Router 注解: %s", key)
+	}
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"API/mailer"
+	"API/models"
+	"API/storage/cache"
+	"API/utils"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// AccountConfig 密码重置与邮箱验证配置
+type AccountConfig struct {
+	LinkBaseURL    string        // 邮件中链接的前端地址
+	ResetTokenTTL  time.Duration // 密码重置令牌有效期
+	VerifyTokenTTL time.Duration // 邮箱验证令牌有效期
+}
+
+// LoadAccountConfig 从配置文件加载账户配置
+func LoadAccountConfig() AccountConfig {
+	config := AccountConfig{
+		LinkBaseURL:    viper.GetString("mail.link_base_url"),
+		ResetTokenTTL:  viper.GetDuration("mail.reset_token_ttl"),
+		VerifyTokenTTL: viper.GetDuration("mail.verify_token_ttl"),
+	}
+	if config.ResetTokenTTL <= 0 {
+		config.ResetTokenTTL = 30 * time.Minute
+	}
+	if config.VerifyTokenTTL <= 0 {
+		config.VerifyTokenTTL = 24 * time.Hour
+	}
+	return config
+}
+
+// AccountService 负责密码重置和邮箱验证，一次性令牌仅以哈希形式保存在Redis中
+type AccountService struct {
+	db     *gorm.DB
+	cache  cache.Provider
+	mailer mailer.Mailer
+	tokens *TokenService
+	guard  *LoginGuard
+	policy *PasswordPolicy
+	config AccountConfig
+}
+
+func NewAccountService(db *gorm.DB, cache cache.Provider, mailer mailer.Mailer, tokens *TokenService, guard *LoginGuard, policy *PasswordPolicy, config AccountConfig) *AccountService {
+	return &AccountService{
+		db:     db,
+		cache:  cache,
+		mailer: mailer,
+		tokens: tokens,
+		guard:  guard,
+		policy: policy,
+		config: config,
+	}
+}
+
+// emailVerification 邮箱验证令牌对应的数据，邮箱变更后旧令牌失效
+type emailVerification struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+func passwordResetKey(tokenHash string) string {
+	return "password_reset:" + tokenHash
+}
+
+func emailVerifyKey(tokenHash string) string {
+	return "email_verify:" + tokenHash
+}
+
+// RequestPasswordReset 向邮箱发送密码重置链接。
+// 邮箱不存在时同样返回成功，避免泄露账户是否存在。
+func (s *AccountService) RequestPasswordReset(ctx context.Context, email string) error {
+	var user models.User
+	if err := s.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("查询用户失败: %w", err)
+	}
+	if !user.Active {
+		return nil
+	}
+
+	token, err := s.issueToken(ctx, passwordResetKey, user.ID, s.config.ResetTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	s.sendAsync(mailer.Message{
+		To:      []string{user.Email},
+		Subject: "重置密码",
+		Body: fmt.Sprintf("%s，您好：\n\n请在%s内打开以下链接重置密码：\n%s\n\n如非本人操作，请忽略本邮件。",
+			user.Username, s.config.ResetTokenTTL, s.link("/reset-password", token)),
+	})
+	return nil
+}
+
+// ResetPassword 使用一次性令牌重置密码，成功后吊销该用户全部登录令牌并解除登录锁定
+func (s *AccountService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	var userID uint
+	if err := s.lookupToken(ctx, passwordResetKey, token, &userID); err != nil {
+		return err
+	}
+
+	var user models.User
+	if err := s.db.WithContext(ctx).First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.NewValidationError("重置链接无效或已过期", "token")
+		}
+		return fmt.Errorf("查询用户失败: %w", err)
+	}
+
+	// 新密码不符合策略时保留重置链接，便于用户更换密码后重试
+	if err := s.policy.Validate(newPassword, user.Username); err != nil {
+		return err
+	}
+	if err := s.policy.CheckReuse(ctx, s.db, &user, newPassword); err != nil {
+		return err
+	}
+	// 读取与作废为一次原子操作，并发请求中只有一个能使用该令牌
+	if err := s.consumeToken(ctx, passwordResetKey, token, &userID); err != nil {
+		return err
+	}
+
+	extra := map[string]interface{}{}
+	// 能收到重置邮件即证明邮箱归属
+	if user.EmailVerifiedAt == nil {
+		extra["email_verified_at"] = time.Now()
+	}
+	if err := setPassword(ctx, s.db, s.policy, &user, newPassword, extra); err != nil {
+		return err
+	}
+
+	if err := s.tokens.RevokeUserTokens(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := s.guard.Unlock(ctx, user.Username); err != nil {
+		log.Printf("解除登录锁定失败: %v", err)
+	}
+	return nil
+}
+
+// SendEmailVerification 向用户当前邮箱发送验证链接
+func (s *AccountService) SendEmailVerification(ctx context.Context, userID uint) error {
+	var user models.User
+	if err := s.db.WithContext(ctx).First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.NewNotFoundError("用户不存在", "user")
+		}
+		return fmt.Errorf("查询用户失败: %w", err)
+	}
+	if user.Email == "" {
+		return utils.NewValidationError("未设置邮箱", "email")
+	}
+	if user.EmailVerifiedAt != nil {
+		return utils.NewValidationError("邮箱已验证", "email")
+	}
+
+	token, err := s.issueToken(ctx, emailVerifyKey, emailVerification{UserID: user.ID, Email: user.Email}, s.config.VerifyTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	s.sendAsync(mailer.Message{
+		To:      []string{user.Email},
+		Subject: "验证邮箱",
+		Body: fmt.Sprintf("%s，您好：\n\n请在%s内打开以下链接完成邮箱验证：\n%s",
+			user.Username, s.config.VerifyTokenTTL, s.link("/verify-email", token)),
+	})
+	return nil
+}
+
+// VerifyEmail 使用一次性令牌确认邮箱
+func (s *AccountService) VerifyEmail(ctx context.Context, token string) error {
+	var verification emailVerification
+	if err := s.consumeToken(ctx, emailVerifyKey, token, &verification); err != nil {
+		return err
+	}
+
+	result := s.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND email = ?", verification.UserID, verification.Email).
+		Update("email_verified_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("更新邮箱验证状态失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return utils.NewValidationError("验证链接无效或已过期", "token")
+	}
+	return nil
+}
+
+// issueToken 生成随机令牌，以其哈希为键保存 value
+func (s *AccountService) issueToken(ctx context.Context, keyFunc func(string) string, value interface{}, ttl time.Duration) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	if err := s.cache.SetObject(ctx, keyFunc(hashToken(token)), value, ttl); err != nil {
+		return "", fmt.Errorf("保存令牌失败: %w", err)
+	}
+	return token, nil
+}
+
+// lookupToken 读取一次性令牌对应的数据，不作废令牌
+func (s *AccountService) lookupToken(ctx context.Context, keyFunc func(string) string, token string, dest interface{}) error {
+	if err := s.cache.GetObject(ctx, keyFunc(hashToken(token)), dest); err != nil {
+		if errors.Is(err, cache.ErrNotFound) {
+			return utils.NewValidationError("链接无效或已过期", "token")
+		}
+		return fmt.Errorf("查询令牌失败: %w", err)
+	}
+	return nil
+}
+
+// consumeToken 使用 GETDEL 读取并删除一次性令牌
+func (s *AccountService) consumeToken(ctx context.Context, keyFunc func(string) string, token string, dest interface{}) error {
+	if err := s.cache.GetDelObject(ctx, keyFunc(hashToken(token)), dest); err != nil {
+		if errors.Is(err, cache.ErrNotFound) {
+			return utils.NewValidationError("链接无效或已过期", "token")
+		}
+		return fmt.Errorf("作废令牌失败: %w", err)
+	}
+	return nil
+}
+
+// link 拼接邮件中的前端链接
+func (s *AccountService) link(path, token string) string {
+	return s.config.LinkBaseURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// sendAsync 异步发送邮件，避免响应耗时暴露账户是否存在
+func (s *AccountService) sendAsync(msg mailer.Message) {
+	sendMailAsync(s.mailer, msg)
+}
+
+// sendMailAsync 在后台发送邮件，发送失败仅记录日志
+func sendMailAsync(m mailer.Mailer, msg mailer.Message) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := m.Send(ctx, msg); err != nil {
+			log.Printf("发送邮件失败: %v | 收件人: %v", err, msg.To)
+		}
+	}()
+}
+
+// randomToken 生成256位随机令牌
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成令牌失败: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashToken 计算令牌的SHA-256摘要
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
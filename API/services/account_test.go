@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"API/mailer"
+	"API/models"
+	"API/utils"
+)
+
+// validationMessage 返回校验错误的提示信息，不是校验错误时返回空字符串
+func validationMessage(err error) string {
+	var validation *utils.ValidationError
+	if errors.As(err, &validation) {
+		return validation.Message
+	}
+	return ""
+}
+
+// recordingMailer 测试用的邮件发送器，记录发送的邮件
+type recordingMailer struct {
+	mu   sync.Mutex
+	sent []mailer.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func newTestAccountService(t *testing.T, store *memoryCache) *AccountService {
+	t.Helper()
+	policy, err := NewPasswordPolicy(PasswordPolicyConfig{MinLength: 8, MaxLength: 72})
+	if err != nil {
+		t.Fatalf("NewPasswordPolicy: %v", err)
+	}
+	db := newTestDB(t, &models.User{}, &models.PasswordHistory{})
+	return NewAccountService(db, store, &recordingMailer{}, newTestTokenService(t, store),
+		NewLoginGuard(store, testGuardConfig()), policy,
+		AccountConfig{LinkBaseURL: "https://hrms.test", ResetTokenTTL: 30 * time.Minute, VerifyTokenTTL: time.Hour})
+}
+
+func TestResetPasswordConsumesToken(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryCache()
+	svc := newTestAccountService(t, store)
+	user := models.User{Username: "alice", Email: "alice@example.com", Phone: "13800000001", PasswordHash: "x"}
+	if err := svc.db.Create(&user).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+	token, err := svc.issueToken(ctx, passwordResetKey, user.ID, time.Hour)
+	if err != nil {
+		t.Fatalf("issueToken: %v", err)
+	}
+
+	// 新密码不符合策略时保留令牌
+	if err := svc.ResetPassword(ctx, token, "short"); validationMessage(err) != "密码长度不能少于8个字符" {
+		t.Fatalf("期望密码策略错误，得到 %v", err)
+	}
+	if !store.has(passwordResetKey(hashToken(token))) {
+		t.Fatal("密码不符合策略时不应作废令牌")
+	}
+
+	// 并发使用同一令牌时只有一个请求成功
+	const workers = 5
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- svc.ResetPassword(ctx, token, "correct-horse-battery")
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case validationMessage(err) != "链接无效或已过期":
+			t.Errorf("重复使用令牌的错误为 %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("成功次数为 %d，期望 1", succeeded)
+	}
+	if store.has(passwordResetKey(hashToken(token))) {
+		t.Error("使用后令牌仍存在")
+	}
+}
+
+func TestVerifyEmailConsumesToken(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryCache()
+	svc := newTestAccountService(t, store)
+	user := models.User{Username: "alice", Email: "alice@example.com", Phone: "13800000001", PasswordHash: "x"}
+	if err := svc.db.Create(&user).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+	token, err := svc.issueToken(ctx, emailVerifyKey, emailVerification{UserID: user.ID, Email: user.Email}, time.Hour)
+	if err != nil {
+		t.Fatalf("issueToken: %v", err)
+	}
+
+	if err := svc.VerifyEmail(ctx, token); err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+	if err := svc.VerifyEmail(ctx, token); validationMessage(err) != "链接无效或已过期" {
+		t.Errorf("重复验证应失败，得到 %v", err)
+	}
+	if err := svc.db.First(&user, user.ID).Error; err != nil || user.EmailVerifiedAt == nil {
+		t.Errorf("邮箱验证时间未写入: %v", err)
+	}
+}
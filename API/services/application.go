@@ -3,9 +3,13 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"API/models"
+	"API/utils"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ApplicationService struct {
@@ -16,15 +20,286 @@ func NewApplicationService(db *gorm.DB) *ApplicationService {
 	return &ApplicationService{db: db}
 }
 
-// UpdateApplicationStatus 更新申请状态
-func (s *ApplicationService) UpdateApplicationStatus(ctx context.Context, applicationID uint, status string) error {
+// MoveStage 将申请流转到目标阶段，校验流转规则并记录操作人与原因
+func (s *ApplicationService) MoveStage(ctx context.Context, applicationID, stageID, operatorID uint, reason string) (*models.Application, error) {
 	var application models.Application
-	if err := s.db.WithContext(ctx).First(&application, applicationID).Error; err != nil {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&application, applicationID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewNotFoundError("申请记录不存在", "application")
+			}
+			return fmt.Errorf("查询申请失败: %w", err)
+		}
+
+		stages, err := ensurePipeline(tx, application.JobID)
+		if err != nil {
+			return err
+		}
+		if application.StageID == nil {
+			// 职位已有流程但申请尚未归入任何阶段时，视为处于第一个阶段
+			first := firstActiveStage(stages).ID
+			application.StageID = &first
+		}
+		from, err := findStage(stages, *application.StageID)
+		if err != nil {
+			return err
+		}
+		to, err := findStage(stages, stageID)
+		if err != nil {
+			return err
+		}
+		if err := canTransition(stages, from, to); err != nil {
+			return err
+		}
+
+		application.StageID = &to.ID
+		application.Status = stageStatus(stages, to)
+		if err := tx.Model(&application).Updates(map[string]interface{}{
+			"stage_id": to.ID,
+			"status":   application.Status,
+		}).Error; err != nil {
+			return fmt.Errorf("更新申请阶段失败: %w", err)
+		}
+
+		return tx.Create(&models.ApplicationStageHistory{
+			ApplicationID: application.ID,
+			FromStageID:   &from.ID,
+			ToStageID:     to.ID,
+			MovedBy:       operatorID,
+			Reason:        reason,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+// GetStageHistory 获取申请的阶段流转记录，按时间先后排列
+func (s *ApplicationService) GetStageHistory(ctx context.Context, applicationID uint) ([]models.ApplicationStageHistory, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.Application{}).Where("id = ?", applicationID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("查询申请失败: %w", err)
+	}
+	if count == 0 {
+		return nil, utils.NewNotFoundError("申请记录不存在", "application")
+	}
+
+	// 已从流程中移除的阶段仍需显示在历史中
+	withDeleted := func(db *gorm.DB) *gorm.DB { return db.Unscoped() }
+	var history []models.ApplicationStageHistory
+	if err := s.db.WithContext(ctx).
+		Preload("FromStage", withDeleted).
+		Preload("ToStage", withDeleted).
+		Where("application_id = ?", applicationID).
+		Order("id ASC").
+		Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("查询流转记录失败: %w", err)
+	}
+	return history, nil
+}
+
+// GetApplicationResume 获取申请投递时固定的简历版本；启用版本管理前的申请返回简历当前内容，版本号为0
+func (s *ApplicationService) GetApplicationResume(ctx context.Context, applicationID uint) (*ResumeVersionDetail, error) {
+	var application models.Application
+	if err := s.db.WithContext(ctx).Preload("ResumeVersion").First(&application, applicationID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("申请记录不存在")
+			return nil, utils.NewNotFoundError("申请记录不存在", "application")
+		}
+		return nil, fmt.Errorf("查询申请失败: %w", err)
+	}
+	if application.ResumeVersion != nil {
+		return versionDetail(*application.ResumeVersion)
+	}
+	if application.ResumeID == nil {
+		return nil, utils.NewNotFoundError("申请未附带简历", "resume")
+	}
+	var resume models.Resume
+	if err := s.db.WithContext(ctx).Scopes(withResumeSections).First(&resume, *application.ResumeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("简历不存在", "resume")
+		}
+		return nil, fmt.Errorf("查询简历失败: %w", err)
+	}
+	return &ResumeVersionDetail{CreatedAt: resume.UpdatedAt, Content: resumeContent(resume)}, nil
+}
+
+// ListMyApplications 获取候选人本人的全部申请，含职位信息和当前阶段
+func (s *ApplicationService) ListMyApplications(ctx context.Context, userID uint) ([]models.Application, error) {
+	var applications []models.Application
+	if err := s.db.WithContext(ctx).
+		Preload("Job").
+		Preload("Stage", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("ResumeVersion").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&applications).Error; err != nil {
+		return nil, fmt.Errorf("查询申请失败: %w", err)
+	}
+	return applications, nil
+}
+
+// UpdateMyApplication 候选人在申请进行中时更换附带的简历或求职信
+func (s *ApplicationService) UpdateMyApplication(ctx context.Context, userID, applicationID uint, input ApplyInput) (*models.Application, error) {
+	var application models.Application
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOwnApplication(tx, userID, applicationID, &application); err != nil {
+			return err
+		}
+		if !applicationInProgress(application) {
+			return utils.NewValidationError("申请已结束，不能再修改", "application")
 		}
-		return err
+		resumeID, versionID, err := resolveResume(tx, userID, input.ResumeID, input.ResumeVersion)
+		if err != nil {
+			return err
+		}
+		application.ResumeID = resumeID
+		application.ResumeVersionID = versionID
+		application.CoverLetter = input.CoverLetter
+		return tx.Model(&application).Updates(map[string]interface{}{
+			"resume_id":         resumeID,
+			"resume_version_id": versionID,
+			"cover_letter":      input.CoverLetter,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+// WithdrawApplication 候选人撤回进行中或已录用但尚未接受录用通知的申请：
+// 申请移入淘汰阶段，待进行的面试取消，尚未答复的录用通知作废
+func (s *ApplicationService) WithdrawApplication(ctx context.Context, userID, applicationID uint, reason string) (*models.Application, error) {
+	var application models.Application
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOwnApplication(tx, userID, applicationID, &application); err != nil {
+			return err
+		}
+		if application.Status == "hired" {
+			var accepted int64
+			if err := tx.Model(&models.Offer{}).
+				Where("application_id = ? AND status = ?", application.ID, models.OfferAccepted).
+				Count(&accepted).Error; err != nil {
+				return fmt.Errorf("查询录用通知失败: %w", err)
+			}
+			if accepted > 0 {
+				return utils.NewValidationError("已接受录用通知，不能撤回申请", "application")
+			}
+		} else if !applicationInProgress(application) {
+			return utils.NewValidationError("申请已结束，不能撤回", "application")
+		}
+
+		stages, err := ensurePipeline(tx, application.JobID)
+		if err != nil {
+			return err
+		}
+		var rejected models.PipelineStage
+		for _, stage := range stages {
+			if stage.Kind == models.StageKindRejected {
+				rejected = stage
+			}
+		}
+
+		if reason == "" {
+			reason = "候选人撤回申请"
+		}
+		history := models.ApplicationStageHistory{
+			ApplicationID: application.ID,
+			FromStageID:   application.StageID,
+			ToStageID:     rejected.ID,
+			MovedBy:       userID,
+			Reason:        reason,
+		}
+		application.StageID = &rejected.ID
+		application.Status = "withdrawn"
+		if err := tx.Model(&application).Updates(map[string]interface{}{
+			"stage_id": rejected.ID,
+			"status":   application.Status,
+		}).Error; err != nil {
+			return fmt.Errorf("撤回申请失败: %w", err)
+		}
+		if err := tx.Create(&history).Error; err != nil {
+			return fmt.Errorf("记录流转失败: %w", err)
+		}
+		if err := tx.Model(&models.Interview{}).
+			Where("application_id = ? AND status = ?", application.ID, models.InterviewScheduled).
+			Update("status", models.InterviewCanceled).Error; err != nil {
+			return fmt.Errorf("取消面试失败: %w", err)
+		}
+		return voidOpenOffers(tx, application.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+// lockOwnApplication 锁定本人的申请，他人的申请按不存在处理
+func lockOwnApplication(tx *gorm.DB, userID, applicationID uint, application *models.Application) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		First(application, applicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.NewNotFoundError("申请记录不存在", "application")
+		}
+		return fmt.Errorf("查询申请失败: %w", err)
+	}
+	return nil
+}
+
+// applicationInProgress 申请尚未录用、淘汰或撤回
+func applicationInProgress(application models.Application) bool {
+	return application.Status == "pending" || application.Status == "interviewed"
+}
+
+// resolveResume 校验申请附带的简历属于本人并返回投递时固定的版本；未指定简历时使用本人当前简历，
+// 未指定版本时使用最新版本，没有简历时均返回 nil
+func resolveResume(tx *gorm.DB, userID uint, resumeID *uint, version *int) (*uint, *uint, error) {
+	var resume models.Resume
+	query := tx.Where("user_id = ?", userID)
+	if resumeID != nil {
+		query = query.Where("id = ?", *resumeID)
+	}
+	if err := query.First(&resume).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, fmt.Errorf("查询简历失败: %w", err)
+		}
+		if resumeID != nil {
+			return nil, nil, utils.NewValidationError("简历不存在或不属于本人", "resume_id")
+		}
+		if version != nil {
+			return nil, nil, utils.NewValidationError("尚未提交简历", "resume_version")
+		}
+		return nil, nil, nil
+	}
+
+	if version != nil {
+		var record models.ResumeVersion
+		if err := tx.Where("resume_id = ? AND version = ?", resume.ID, *version).First(&record).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, utils.NewValidationError("简历版本不存在", "resume_version")
+			}
+			return nil, nil, fmt.Errorf("查询简历版本失败: %w", err)
+		}
+		return &resume.ID, &record.ID, nil
+	}
+
+	var latest models.ResumeVersion
+	err := tx.Where("resume_id = ? AND version = ?", resume.ID, resume.Version).First(&latest).Error
+	if err == nil {
+		return &resume.ID, &latest.ID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, fmt.Errorf("查询简历版本失败: %w", err)
+	}
+	// 启用版本管理前提交的简历没有版本，投递时补建
+	if err := tx.Scopes(withResumeSections).First(&resume, resume.ID).Error; err != nil {
+		return nil, nil, fmt.Errorf("查询简历失败: %w", err)
+	}
+	record, err := recordResumeVersion(tx, &resume)
+	if err != nil {
+		return nil, nil, err
 	}
-	application.Status = status
-	return s.db.WithContext(ctx).Save(&application).Error
+	return &resume.ID, &record.ID, nil
 }
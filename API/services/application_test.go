@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"API/models"
+	"API/utils"
+)
+
+func TestWithdrawApplication(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name       string
+		status     string
+		owner      bool
+		reason     string
+		wantErr    string
+		wantReason string
+	}{
+		{"待筛选", "pending", true, "", "", "候选人撤回申请"},
+		{"面试中并填写原因", "interviewed", true, "已接受其他机会", "", "已接受其他机会"},
+		{"已录用但未接受录用通知", "hired", true, "", "", "候选人撤回申请"},
+		{"已淘汰", "rejected", true, "", "申请已结束，不能撤回", ""},
+		{"已撤回", "withdrawn", true, "", "申请已结束，不能撤回", ""},
+		{"他人的申请", "pending", false, "", "申请记录不存在", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t, &models.User{}, &models.Job{}, &models.PipelineStage{}, &models.Application{},
+				&models.ApplicationStageHistory{}, &models.Interview{}, &models.Offer{})
+			svc := NewApplicationService(db)
+			job := models.Job{Title: "后端工程师", Status: models.JobOpen}
+			if err := db.Create(&job).Error; err != nil {
+				t.Fatalf("创建职位失败: %v", err)
+			}
+			stages, err := ensurePipeline(db, job.ID)
+			if err != nil {
+				t.Fatalf("ensurePipeline: %v", err)
+			}
+			stageByStatus := map[string]uint{}
+			for _, stage := range stages {
+				if _, ok := stageByStatus[stageStatus(stages, stage)]; !ok {
+					stageByStatus[stageStatus(stages, stage)] = stage.ID
+				}
+			}
+			stageByStatus["withdrawn"] = stageByStatus["rejected"]
+			stageID := stageByStatus[tt.status]
+			application := models.Application{UserID: 1, JobID: job.ID, Status: tt.status, StageID: &stageID}
+			if err := db.Create(&application).Error; err != nil {
+				t.Fatalf("创建申请失败: %v", err)
+			}
+			start := time.Now().Add(24 * time.Hour)
+			interviews := []models.Interview{
+				{ApplicationID: application.ID, StartTime: start, EndTime: start.Add(time.Hour), Status: models.InterviewScheduled},
+				{ApplicationID: application.ID, StartTime: start.Add(-48 * time.Hour), EndTime: start.Add(-47 * time.Hour), Status: models.InterviewCompleted},
+			}
+			if err := db.Create(&interviews).Error; err != nil {
+				t.Fatalf("创建面试失败: %v", err)
+			}
+
+			userID := uint(1)
+			if !tt.owner {
+				userID = 2
+			}
+			got, err := svc.WithdrawApplication(ctx, userID, application.ID, tt.reason)
+			if tt.wantErr != "" {
+				var notFound *utils.NotFoundError
+				if validationMessage(err) != tt.wantErr && !(errors.As(err, &notFound) && notFound.Message == tt.wantErr) {
+					t.Fatalf("期望错误 %q，得到 %v", tt.wantErr, err)
+				}
+				var unchanged models.Application
+				db.First(&unchanged, application.ID)
+				if unchanged.Status != tt.status {
+					t.Errorf("撤回失败时状态变为 %s", unchanged.Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WithdrawApplication: %v", err)
+			}
+			if got.Status != "withdrawn" || *got.StageID != stageByStatus["rejected"] {
+				t.Errorf("撤回后状态为 %s，阶段为 %d", got.Status, *got.StageID)
+			}
+
+			var history models.ApplicationStageHistory
+			if err := db.Where("application_id = ?", application.ID).Last(&history).Error; err != nil {
+				t.Fatalf("查询流转记录失败: %v", err)
+			}
+			if *history.FromStageID != stageID || history.ToStageID != stageByStatus["rejected"] ||
+				history.MovedBy != 1 || history.Reason != tt.wantReason {
+				t.Errorf("流转记录为 %+v", history)
+			}
+
+			// 待进行的面试取消，已完成的面试保留
+			for i, want := range []string{models.InterviewCanceled, models.InterviewCompleted} {
+				var interview models.Interview
+				db.First(&interview, interviews[i].ID)
+				if interview.Status != want {
+					t.Errorf("面试 %d 状态为 %s，期望 %s", i, interview.Status, want)
+				}
+			}
+		})
+	}
+}
@@ -2,79 +2,192 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"API/models"
+	"API/utils"
+
 	"gorm.io/gorm"
 )
 
 type AttendanceService struct {
-	db *gorm.DB
+	db        *gorm.DB
+	schedules *ScheduleService
+	workdays  *WorkdayCalculator
+	overtime  *OvertimeService
 }
 
-func NewAttendanceService(db *gorm.DB) *AttendanceService {
-	return &AttendanceService{db: db}
+func NewAttendanceService(db *gorm.DB, schedules *ScheduleService, workdays *WorkdayCalculator, overtime *OvertimeService) *AttendanceService {
+	return &AttendanceService{db: db, schedules: schedules, workdays: workdays, overtime: overtime}
 }
 
-func (s *AttendanceService) ClockIn(ctx context.Context, userID uint) error {
+// ClockIn 上班打卡，按员工当日适用的班次判断迟到。夜班在次日下班前打卡计入前一日的班次
+func (s *AttendanceService) ClockIn(ctx context.Context, userID uint) (*models.Attendance, error) {
 	now := time.Now().Local()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	db := s.db.WithContext(ctx)
+
+	shift, err := s.currentShift(db, userID, now)
+	if err != nil {
+		return nil, err
+	}
 
 	// 检查是否已打卡
 	var existing models.Attendance
-	if err := s.db.WithContext(ctx).
-		Where("user_id = ? AND date = ?", userID, today).
-		First(&existing).Error; err == nil {
-		return fmt.Errorf("今日已打卡，时间：%s", existing.ClockIn.Format(time.RFC3339))
+	err = db.Where("user_id = ? AND date = ?", userID, shift.Date).First(&existing).Error
+	if err == nil {
+		return nil, utils.NewValidationError(fmt.Sprintf("今日已打卡，时间：%s", existing.ClockIn.Format(time.RFC3339)), "clock_in")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询打卡记录失败: %w", err)
 	}
 
 	attendance := models.Attendance{
-		UserID:  userID,
-		ClockIn: now,
-		//DateClockIn: now.Truncate(24 * time.Hour),
+		UserID:      userID,
+		ClockIn:     now,
+		Date:        shift.Date,
+		LateMinutes: shift.LateMinutes(now),
+	}
+	if shift.Schedule.ID != 0 {
+		attendance.ScheduleID = &shift.Schedule.ID
 	}
+	attendance.Status = attendanceStatus(attendance.LateMinutes, 0)
 
-	// 迟到判断（9:30后算迟到）
-	if now.After(today.Add(9*time.Hour + 30*time.Minute)) {
-		attendance.Status = "late"
+	if err := db.Create(&attendance).Error; err != nil {
+		return nil, err
 	}
+	return &attendance, nil
+}
 
-	return s.db.WithContext(ctx).Create(&attendance).Error
+// ClockOut 下班打卡，按上班打卡时适用的班次判断早退并记录超出班次的加班时长
+func (s *AttendanceService) ClockOut(ctx context.Context, userID uint) (*models.Attendance, error) {
+	now := time.Now().Local()
+	db := s.db.WithContext(ctx)
+
+	// 夜班的下班打卡在次日，查找前一日起尚未下班打卡的记录
+	var attendance models.Attendance
+	if err := db.
+		Where("user_id = ? AND date >= ? AND clock_out IS NULL", userID, dateOf(now).AddDate(0, 0, -1)).
+		Order("date DESC").
+		First(&attendance).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewValidationError("今日未上班打卡或已下班打卡", "clock_out")
+		}
+		return nil, fmt.Errorf("查询打卡记录失败: %w", err)
+	}
+
+	schedule, err := s.schedules.scheduleByID(db, attendance.ScheduleID)
+	if err != nil {
+		return nil, err
+	}
+	shift := newShiftInfo(*schedule, attendance.Date)
+	if attendance.ClockOutMissing || now.After(s.schedules.clockOutDeadline(shift)) {
+		return nil, utils.NewValidationError("上班打卡的班次已结束，请提交补卡申请", "clock_out")
+	}
+
+	overtimeMinutes, overtimeKind, err := s.overtime.detect(db, attendance.UserID, shift, attendance.ClockIn, now)
+	if err != nil {
+		return nil, err
+	}
+
+	attendance.ClockOut = &now
+	attendance.EarlyLeaveMinutes = shift.EarlyLeaveMinutes(attendance.ClockIn, now)
+	attendance.OvertimeMinutes = overtimeMinutes
+	attendance.OvertimeKind = overtimeKind
+	attendance.Status = attendanceStatus(attendance.LateMinutes, attendance.EarlyLeaveMinutes)
+	if err := db.Model(&attendance).Updates(map[string]interface{}{
+		"clock_out":           now,
+		"status":              attendance.Status,
+		"early_leave_minutes": attendance.EarlyLeaveMinutes,
+		"overtime_minutes":    overtimeMinutes,
+		"overtime_kind":       overtimeKind,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return &attendance, nil
 }
 
-func (s *AttendanceService) ClockOut(ctx context.Context, userID uint) error {
-	now := time.Now()
-	return s.db.WithContext(ctx).Model(&models.Attendance{}).
-		Where("user_id = ? AND clock_out IS NULL AND date_clock_in = ?", userID, now.Truncate(24*time.Hour)).
-		Order("clock_in DESC").Limit(1).
-		Updates(map[string]interface{}{
-			"clock_out": now,
-			"status":    gorm.Expr("IF(clock_out < '18:00:00', 'early_leave', status)"),
-		}).Error
+// attendanceStatus 考勤状态只能记录一种，既迟到又早退时记为迟到；
+// 迟到和早退分别以分钟数记录，统计时按分钟数计数
+func attendanceStatus(lateMinutes, earlyLeaveMinutes int) string {
+	switch {
+	case lateMinutes > 0:
+		return "late"
+	case earlyLeaveMinutes > 0:
+		return "early_leave"
+	}
+	return "normal"
+}
+
+// currentShift 确定打卡所属的班次：前一日为夜班且尚未到下班时间时属于前一日的班次，否则属于当日班次
+func (s *AttendanceService) currentShift(db *gorm.DB, userID uint, now time.Time) (*ShiftInfo, error) {
+	yesterday := dateOf(now).AddDate(0, 0, -1)
+	schedule, err := s.schedules.scheduleFor(db, userID, yesterday)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Kind == models.ScheduleNight {
+		if shift := newShiftInfo(*schedule, yesterday); now.Before(shift.End) {
+			return shift, nil
+		}
+	}
+	schedule, err = s.schedules.scheduleFor(db, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	return newShiftInfo(*schedule, now), nil
+}
+
+// MonthlyAttendance 月度考勤，Summary 仅在查看本人考勤时返回
+type MonthlyAttendance struct {
+	Records []models.Attendance   `json:"records"`
+	Leaves  []models.LeaveRequest `json:"leaves"` // 与该月重叠的已批准请假
+	Summary *AttendanceSummary    `json:"summary,omitempty"`
+}
+
+// AttendanceSummary 月度出勤汇总，统计截至今日的工作日（不含节假日），已批准请假的日期不计为缺勤
+type AttendanceSummary struct {
+	WorkDays   int `json:"work_days"`
+	Present    int `json:"present"`
+	LeaveDays  int `json:"leave_days"`
+	Absent     int `json:"absent"`
+	Late       int `json:"late"`
+	EarlyLeave int `json:"early_leave"`
 }
 
 // GetMonthlyAttendance 获取月度考勤（支持管理员查看所有记录）
-func (s *AttendanceService) GetMonthlyAttendance(ctx context.Context, userID uint, month string, isAdmin bool) ([]models.Attendance, error) {
-	startTime, err := time.Parse("2006-01", month)
+func (s *AttendanceService) GetMonthlyAttendance(ctx context.Context, userID uint, month string, isAdmin bool) (*MonthlyAttendance, error) {
+	startTime, err := time.ParseInLocation("2006-01", month, time.Local)
 	if err != nil {
 		return nil, fmt.Errorf("invalid month format: %w", err)
 	}
 	endTime := startTime.AddDate(0, 1, 0)
+	db := s.db.WithContext(ctx)
 
-	query := s.db.WithContext(ctx).
+	query := db.
 		Preload("User").
 		Where("clock_in >= ? AND clock_in < ?", startTime, endTime).
 		Order("clock_in DESC")
+	leaveQuery := db.
+		Preload("User").
+		Preload("LeaveType").
+		Where("status = ? AND start_date < ? AND end_date >= ?", models.LeaveApproved, endTime, startTime).
+		Order("start_date ASC")
 
 	if !isAdmin {
 		query = query.Where("user_id = ?", userID)
+		leaveQuery = leaveQuery.Where("user_id = ?", userID)
 	}
 
 	var records []models.Attendance
 	if err := query.Find(&records).Error; err != nil {
 		return nil, fmt.Errorf("查询失败: %w", err)
 	}
+	var leaves []models.LeaveRequest
+	if err := leaveQuery.Find(&leaves).Error; err != nil {
+		return nil, fmt.Errorf("查询请假记录失败: %w", err)
+	}
 
 	// 计算每日时长
 	for i := range records {
@@ -83,16 +196,82 @@ func (s *AttendanceService) GetMonthlyAttendance(ctx context.Context, userID uin
 		}
 	}
 
-	return records, nil
+	result := &MonthlyAttendance{Records: records, Leaves: leaves}
+	if !isAdmin {
+		summary, err := s.summarize(db, userID, startTime, endTime, records, leaves)
+		if err != nil {
+			return nil, err
+		}
+		result.Summary = summary
+	}
+	return result, nil
+}
+
+// summarize 按员工的工作日逐日汇总出勤，今日尚未打卡不计为缺勤
+func (s *AttendanceService) summarize(db *gorm.DB, userID uint, start, end time.Time,
+	records []models.Attendance, leaves []models.LeaveRequest) (*AttendanceSummary, error) {
+	summary := &AttendanceSummary{}
+	present := make(map[string]bool, len(records))
+	for _, record := range records {
+		present[record.Date.Format("2006-01-02")] = true
+		if record.LateMinutes > 0 {
+			summary.Late++
+		}
+		if record.EarlyLeaveMinutes > 0 {
+			summary.EarlyLeave++
+		}
+	}
+
+	// 只统计到今日
+	today := dateOf(time.Now())
+	last := end.AddDate(0, 0, -1)
+	if today.Before(last) {
+		last = today
+	}
+	workdays, err := s.workdays.WorkingDays(db, userID, start, last)
+	if err != nil {
+		return nil, err
+	}
+	for _, day := range workdays {
+		summary.WorkDays++
+		switch {
+		case onLeave(leaves, day):
+			summary.LeaveDays++
+		case present[day.Format("2006-01-02")]:
+			summary.Present++
+		case day.Before(today):
+			summary.Absent++
+		}
+	}
+	return summary, nil
+}
+
+// onLeave 判断该日是否在已批准的请假期间内
+func onLeave(leaves []models.LeaveRequest, day time.Time) bool {
+	for _, leave := range leaves {
+		if !day.Before(dateOf(leave.StartDate)) && !day.After(dateOf(leave.EndDate)) {
+			return true
+		}
+	}
+	return false
 }
 
 // GetAttendanceStats 获取考勤统计
 func (s *AttendanceService) GetAttendanceStats(ctx context.Context) (map[string]interface{}, error) {
-	var lateCount int64
-	if err := s.db.WithContext(ctx).Model(&models.Attendance{}).Where("status = ?", "late").Count(&lateCount).Error; err != nil {
+	var lateCount, earlyLeaveCount int64
+	if err := s.db.WithContext(ctx).Model(&models.Attendance{}).Where("late_minutes > 0").Count(&lateCount).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Model(&models.Attendance{}).Where("early_leave_minutes > 0").Count(&earlyLeaveCount).Error; err != nil {
+		return nil, err
+	}
+	var missingClockOutCount int64
+	if err := s.db.WithContext(ctx).Model(&models.Attendance{}).Where("clock_out_missing = ?", true).Count(&missingClockOutCount).Error; err != nil {
 		return nil, err
 	}
 	return map[string]interface{}{
-		"late_count": lateCount,
+		"late_count":              lateCount,
+		"early_leave_count":       earlyLeaveCount,
+		"missing_clock_out_count": missingClockOutCount,
 	}, nil
 }
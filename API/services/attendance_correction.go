@@ -0,0 +1,388 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"API/models"
+	"API/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// correctionTimeLayout 补卡申请中上下班时间的格式
+const correctionTimeLayout = "2006-01-02 15:04"
+
+// AttendanceCorrectionService 补卡申请、审批及未下班打卡记录的查询
+type AttendanceCorrectionService struct {
+	db        *gorm.DB
+	schedules *ScheduleService
+	overtime  *OvertimeService
+}
+
+func NewAttendanceCorrectionService(db *gorm.DB, schedules *ScheduleService, overtime *OvertimeService) *AttendanceCorrectionService {
+	return &AttendanceCorrectionService{db: db, schedules: schedules, overtime: overtime}
+}
+
+// CorrectionInput 提交补卡申请的参数。日期格式为 YYYY-MM-DD，
+// 上下班时间格式为 YYYY-MM-DD HH:MM，夜班的下班时间可为次日
+type CorrectionInput struct {
+	Date     string `json:"date" binding:"required"`
+	Kind     string `json:"kind" binding:"required,oneof=missing_clock_in missing_clock_out wrong_time"`
+	ClockIn  string `json:"clock_in"`
+	ClockOut string `json:"clock_out"`
+	Reason   string `json:"reason" binding:"required,max=500"`
+}
+
+// Submit 提交补卡申请。漏打上班卡须填写上班时间，漏打下班卡须填写下班时间，
+// 打卡时间有误至少填写一项；同一日期只能有一个待审批的申请
+func (s *AttendanceCorrectionService) Submit(ctx context.Context, userID uint, input CorrectionInput) (*models.AttendanceCorrection, error) {
+	date, err := time.ParseInLocation("2006-01-02", input.Date, time.Local)
+	if err != nil {
+		return nil, utils.NewValidationError("日期格式应为 YYYY-MM-DD", "date")
+	}
+	now := time.Now()
+	if date.After(now) {
+		return nil, utils.NewValidationError("不能为未来的日期补卡", "date")
+	}
+	clockIn, err := parseCorrectionTime(input.ClockIn, "clock_in")
+	if err != nil {
+		return nil, err
+	}
+	clockOut, err := parseCorrectionTime(input.ClockOut, "clock_out")
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case input.Kind == models.CorrectionMissingClockIn && clockIn == nil:
+		return nil, utils.NewValidationError("请填写上班时间", "clock_in")
+	case input.Kind == models.CorrectionMissingClockOut && clockOut == nil:
+		return nil, utils.NewValidationError("请填写下班时间", "clock_out")
+	case clockIn == nil && clockOut == nil:
+		return nil, utils.NewValidationError("请填写更正后的上班或下班时间", "clock_in")
+	}
+	if clockIn != nil && !dateOf(*clockIn).Equal(date) {
+		return nil, utils.NewValidationError("上班时间应在考勤日期当天", "clock_in")
+	}
+	if clockOut != nil {
+		if day := dateOf(*clockOut); day.Before(date) || day.After(date.AddDate(0, 0, 1)) {
+			return nil, utils.NewValidationError("下班时间应在考勤日期当天或次日", "clock_out")
+		}
+		if clockOut.After(now) {
+			return nil, utils.NewValidationError("下班时间不能晚于当前时间", "clock_out")
+		}
+	}
+
+	var correction models.AttendanceCorrection
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 锁定员工，避免同一日期重复提交
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return fmt.Errorf("查询员工失败: %w", err)
+		}
+		var pending int64
+		if err := tx.Model(&models.AttendanceCorrection{}).
+			Where("user_id = ? AND date = ? AND status = ?", userID, date, models.CorrectionPending).
+			Count(&pending).Error; err != nil {
+			return fmt.Errorf("查询补卡申请失败: %w", err)
+		}
+		if pending > 0 {
+			return utils.NewValidationError("该日期已有待审批的补卡申请", "date")
+		}
+
+		attendance, err := findAttendance(tx, userID, date)
+		if err != nil {
+			return err
+		}
+		switch input.Kind {
+		case models.CorrectionMissingClockIn:
+			if attendance != nil {
+				return utils.NewValidationError("该日已有上班打卡记录，如时间有误请选择打卡时间有误", "kind")
+			}
+		case models.CorrectionMissingClockOut:
+			if attendance == nil && clockIn == nil {
+				return utils.NewValidationError("该日没有上班打卡记录，请同时填写上班时间", "clock_in")
+			}
+			if attendance != nil && attendance.ClockOut != nil {
+				return utils.NewValidationError("该日已下班打卡，如时间有误请选择打卡时间有误", "kind")
+			}
+		case models.CorrectionWrongTime:
+			if attendance == nil {
+				return utils.NewValidationError("该日没有打卡记录，请选择漏打上班卡", "kind")
+			}
+		}
+		if err := checkCorrectedRange(attendance, clockIn, clockOut); err != nil {
+			return err
+		}
+
+		correction = models.AttendanceCorrection{
+			UserID:   userID,
+			Date:     date,
+			Kind:     input.Kind,
+			ClockIn:  clockIn,
+			ClockOut: clockOut,
+			Reason:   strings.TrimSpace(input.Reason),
+			Status:   models.CorrectionPending,
+		}
+		if attendance != nil {
+			correction.AttendanceID = &attendance.ID
+		}
+		return tx.Create(&correction).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &correction, nil
+}
+
+// Approve 批准补卡申请，按申请的时间改写打卡记录并重新计算迟到、早退和加班，
+// 改写前的值保留在补卡申请中
+func (s *AttendanceCorrectionService) Approve(ctx context.Context, correctionID, approverID uint, comment string) (*models.AttendanceCorrection, error) {
+	return s.decide(ctx, correctionID, approverID, models.CorrectionApproved, comment, s.apply)
+}
+
+// Reject 驳回补卡申请，需填写驳回意见
+func (s *AttendanceCorrectionService) Reject(ctx context.Context, correctionID, approverID uint, comment string) (*models.AttendanceCorrection, error) {
+	if strings.TrimSpace(comment) == "" {
+		return nil, utils.NewValidationError("驳回时需填写审批意见", "comment")
+	}
+	return s.decide(ctx, correctionID, approverID, models.CorrectionRejected, comment, nil)
+}
+
+// decide 校验审批人并更新申请状态，apply 在状态更新前执行
+func (s *AttendanceCorrectionService) decide(ctx context.Context, correctionID, approverID uint, status, comment string,
+	apply func(tx *gorm.DB, correction *models.AttendanceCorrection) error) (*models.AttendanceCorrection, error) {
+	var correction models.AttendanceCorrection
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("User").
+			First(&correction, correctionID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewNotFoundError("补卡申请不存在", "attendance_correction")
+			}
+			return fmt.Errorf("查询补卡申请失败: %w", err)
+		}
+		if correction.Status != models.CorrectionPending {
+			return utils.NewValidationError("补卡申请已处理", "attendance_correction")
+		}
+		if correction.UserID == approverID {
+			return utils.NewForbiddenError("不能审批自己的补卡申请")
+		}
+		allowed, err := canApproveEmployee(tx, approverID, correction.User.Department)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return utils.NewForbiddenError("仅员工所在部门的负责人或人事可以审批")
+		}
+
+		if apply != nil {
+			if err := apply(tx, &correction); err != nil {
+				return err
+			}
+		}
+		now := time.Now()
+		correction.Status = status
+		correction.ApproverID = &approverID
+		correction.Comment = comment
+		correction.DecidedAt = &now
+		return tx.Model(&correction).Updates(map[string]interface{}{
+			"status":             status,
+			"approver_id":        approverID,
+			"comment":            comment,
+			"decided_at":         now,
+			"attendance_id":      correction.AttendanceID,
+			"original_clock_in":  correction.OriginalClockIn,
+			"original_clock_out": correction.OriginalClockOut,
+			"original_status":    correction.OriginalStatus,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &correction, nil
+}
+
+// apply 按补卡申请改写打卡记录，记录不存在时新建
+func (s *AttendanceCorrectionService) apply(tx *gorm.DB, correction *models.AttendanceCorrection) error {
+	attendance, err := findAttendance(tx.Clauses(clause.Locking{Strength: "UPDATE"}), correction.UserID, correction.Date)
+	if err != nil {
+		return err
+	}
+	// 提交后打卡记录可能已变化，按当前记录重新校验
+	if err := checkCorrectedRange(attendance, correction.ClockIn, correction.ClockOut); err != nil {
+		return err
+	}
+
+	var schedule *models.WorkSchedule
+	if attendance == nil {
+		if correction.ClockIn == nil {
+			return utils.NewValidationError("打卡记录不存在，无法仅更正下班时间", "clock_in")
+		}
+		if schedule, err = s.schedules.scheduleFor(tx, correction.UserID, correction.Date); err != nil {
+			return err
+		}
+		attendance = &models.Attendance{UserID: correction.UserID, Date: correction.Date}
+		if schedule.ID != 0 {
+			attendance.ScheduleID = &schedule.ID
+		}
+	} else {
+		if schedule, err = s.schedules.scheduleByID(tx, attendance.ScheduleID); err != nil {
+			return err
+		}
+		original := attendance.ClockIn
+		correction.OriginalClockIn = &original
+		correction.OriginalClockOut = attendance.ClockOut
+		correction.OriginalStatus = attendance.Status
+	}
+
+	if correction.ClockIn != nil {
+		attendance.ClockIn = *correction.ClockIn
+	}
+	if correction.ClockOut != nil {
+		attendance.ClockOut = correction.ClockOut
+	}
+
+	shift := newShiftInfo(*schedule, attendance.Date)
+	attendance.LateMinutes = shift.LateMinutes(attendance.ClockIn)
+	attendance.EarlyLeaveMinutes = 0
+	attendance.OvertimeMinutes = 0
+	attendance.OvertimeKind = ""
+	if attendance.ClockOut != nil {
+		attendance.ClockOutMissing = false
+		attendance.EarlyLeaveMinutes = shift.EarlyLeaveMinutes(attendance.ClockIn, *attendance.ClockOut)
+		attendance.OvertimeMinutes, attendance.OvertimeKind, err = s.overtime.detect(tx, attendance.UserID, shift, attendance.ClockIn, *attendance.ClockOut)
+		if err != nil {
+			return err
+		}
+	}
+	attendance.Status = attendanceStatus(attendance.LateMinutes, attendance.EarlyLeaveMinutes)
+
+	if err := tx.Save(attendance).Error; err != nil {
+		return fmt.Errorf("更正打卡记录失败: %w", err)
+	}
+	correction.AttendanceID = &attendance.ID
+	return nil
+}
+
+// Cancel 员工撤销本人待审批的补卡申请
+func (s *AttendanceCorrectionService) Cancel(ctx context.Context, correctionID, userID uint) (*models.AttendanceCorrection, error) {
+	var correction models.AttendanceCorrection
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", userID).
+			First(&correction, correctionID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewNotFoundError("补卡申请不存在", "attendance_correction")
+			}
+			return fmt.Errorf("查询补卡申请失败: %w", err)
+		}
+		if correction.Status != models.CorrectionPending {
+			return utils.NewValidationError("补卡申请已处理，不能撤销", "attendance_correction")
+		}
+		correction.Status = models.CorrectionCanceled
+		return tx.Model(&correction).Update("status", correction.Status).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &correction, nil
+}
+
+// ListMyCorrections 获取员工本人的补卡申请，可按状态筛选
+func (s *AttendanceCorrectionService) ListMyCorrections(ctx context.Context, userID uint, status string) ([]models.AttendanceCorrection, error) {
+	query := s.db.WithContext(ctx).Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var corrections []models.AttendanceCorrection
+	if err := query.Order("date DESC").Find(&corrections).Error; err != nil {
+		return nil, fmt.Errorf("查询补卡申请失败: %w", err)
+	}
+	return corrections, nil
+}
+
+// ListPendingForApprover 获取待该用户审批的补卡申请：人事可审批全部员工，部门负责人仅限本部门
+func (s *AttendanceCorrectionService) ListPendingForApprover(ctx context.Context, approverID uint) ([]models.AttendanceCorrection, error) {
+	db := s.db.WithContext(ctx)
+	department, ok, err := approvalDepartment(db, approverID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []models.AttendanceCorrection{}, nil
+	}
+	query := db.Preload("User").
+		Joins("JOIN users ON users.id = attendance_corrections.user_id").
+		Where("attendance_corrections.status = ? AND attendance_corrections.user_id <> ?", models.CorrectionPending, approverID)
+	if department != "" {
+		query = query.Where("users.department = ?", department)
+	}
+
+	var corrections []models.AttendanceCorrection
+	if err := query.Order("attendance_corrections.date ASC").Find(&corrections).Error; err != nil {
+		return nil, fmt.Errorf("查询补卡申请失败: %w", err)
+	}
+	return corrections, nil
+}
+
+// ListMissingClockOuts 获取被标记为未下班打卡且尚未更正的记录，可按部门筛选
+func (s *AttendanceCorrectionService) ListMissingClockOuts(ctx context.Context, department string) ([]models.Attendance, error) {
+	query := s.db.WithContext(ctx).Preload("User").
+		Where("attendances.clock_out_missing = ? AND attendances.clock_out IS NULL", true)
+	if department != "" {
+		query = query.Joins("JOIN users ON users.id = attendances.user_id").
+			Where("users.department = ?", department)
+	}
+	var records []models.Attendance
+	if err := query.Order("attendances.date DESC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("查询未下班打卡记录失败: %w", err)
+	}
+	return records, nil
+}
+
+// findAttendance 查询员工某日的打卡记录，不存在时返回 nil
+func findAttendance(tx *gorm.DB, userID uint, date time.Time) (*models.Attendance, error) {
+	var attendance models.Attendance
+	err := tx.Where("user_id = ? AND date = ?", userID, date).First(&attendance).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询打卡记录失败: %w", err)
+	}
+	return &attendance, nil
+}
+
+// checkCorrectedRange 校验更正后的下班时间晚于上班时间，未更正的一项沿用打卡记录中的值
+func checkCorrectedRange(attendance *models.Attendance, clockIn, clockOut *time.Time) error {
+	in, out := clockIn, clockOut
+	if attendance != nil {
+		if in == nil {
+			in = &attendance.ClockIn
+		}
+		if out == nil {
+			out = attendance.ClockOut
+		}
+	}
+	if in != nil && out != nil && !out.After(*in) {
+		return utils.NewValidationError("下班时间应晚于上班时间", "clock_out")
+	}
+	return nil
+}
+
+// parseCorrectionTime 解析补卡时间，为空时返回 nil
+func parseCorrectionTime(value, field string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.ParseInLocation(correctionTimeLayout, value, time.Local)
+	if err != nil {
+		return nil, utils.NewValidationError("时间格式应为 YYYY-MM-DD HH:MM", field)
+	}
+	return &t, nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"API/models"
+	"API/utils"
+)
+
+func timePtr(t time.Time) *time.Time { return &t }
+
+func TestCheckCorrectedRange(t *testing.T) {
+	open := &models.Attendance{ClockIn: at(3, 9, 0)}
+	closed := &models.Attendance{ClockIn: at(3, 9, 0), ClockOut: timePtr(at(3, 18, 0))}
+
+	tests := []struct {
+		name              string
+		attendance        *models.Attendance
+		clockIn, clockOut *time.Time
+		wantErr           bool
+	}{
+		{"新补录的上下班时间", nil, timePtr(at(3, 9, 0)), timePtr(at(3, 18, 0)), false},
+		{"新补录的下班时间早于上班时间", nil, timePtr(at(3, 18, 0)), timePtr(at(3, 9, 0)), true},
+		{"下班时间与上班时间相同", nil, timePtr(at(3, 9, 0)), timePtr(at(3, 9, 0)), true},
+		{"只补录上班时间", nil, timePtr(at(3, 9, 0)), nil, false},
+		{"补录下班时间沿用原上班时间", open, nil, timePtr(at(3, 18, 0)), false},
+		{"补录的下班时间早于原上班时间", open, nil, timePtr(at(3, 8, 0)), true},
+		{"夜班补录次日下班时间", open, nil, timePtr(at(4, 6, 0)), false},
+		{"更正上班时间沿用原下班时间", closed, timePtr(at(3, 8, 30)), nil, false},
+		{"更正的上班时间晚于原下班时间", closed, timePtr(at(3, 19, 0)), nil, true},
+		{"未下班打卡时只更正上班时间", open, timePtr(at(3, 8, 30)), nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkCorrectedRange(tt.attendance, tt.clockIn, tt.clockOut)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("期望通过，得到 %v", err)
+				}
+				return
+			}
+			var validation *utils.ValidationError
+			if !errors.As(err, &validation) || validation.Field != "clock_out" {
+				t.Fatalf("期望 clock_out 验证错误，得到 %v", err)
+			}
+		})
+	}
+}
+
+func TestParseCorrectionTime(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    *time.Time
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"2025-03-03 09:05", timePtr(at(3, 9, 5)), false},
+		{"2025-03-03 9:05", timePtr(at(3, 9, 5)), false},
+		{"2025-03-03T09:05", nil, true},
+		{"2025-03-03 09:05:00", nil, true},
+		{"2025-03-03", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseCorrectionTime(tt.value, "clock_in")
+		if tt.wantErr {
+			var validation *utils.ValidationError
+			if !errors.As(err, &validation) || validation.Field != "clock_in" {
+				t.Errorf("parseCorrectionTime(%q) 期望 clock_in 验证错误，得到 %v", tt.value, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseCorrectionTime(%q) 错误为 %v", tt.value, err)
+			continue
+		}
+		if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+			t.Errorf("parseCorrectionTime(%q) = %v, 期望 %v", tt.value, got, tt.want)
+		}
+	}
+}
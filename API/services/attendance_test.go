@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"API/models"
+)
+
+func TestClockOutKeepsLateStatus(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	if now.Sub(dateOf(now)) < 10*time.Minute || dateOf(now).AddDate(0, 0, 1).Sub(now) < 10*time.Minute {
+		t.Skip("临近零点，当日班次无法覆盖当前时间")
+	}
+	db := newTestDB(t, &models.User{}, &models.Role{}, &models.Attendance{}, &models.WorkSchedule{},
+		&models.ScheduleAssignment{}, &models.HolidayCalendar{}, &models.Holiday{})
+	// 默认班次在当前时间前后各 5 分钟，上班打卡迟到，下班打卡早退
+	schedules := NewScheduleService(db, ScheduleConfig{Default: models.WorkSchedule{
+		Kind:      models.ScheduleFixed,
+		StartTime: now.Add(-5 * time.Minute).Format("15:04"),
+		EndTime:   now.Add(5 * time.Minute).Format("15:04"),
+		WorkDays:  "0,1,2,3,4,5,6",
+	}, ClockOutWindow: time.Hour})
+	workdays := NewWorkdayCalculator(db, schedules, NewHolidayService(db, HolidayConfig{}))
+	svc := NewAttendanceService(db, schedules, workdays, NewOvertimeService(db, workdays, OvertimeConfig{}))
+	user := models.User{Username: "alice", Email: "alice@example.com", Phone: "alice", Usertype: "employee", Active: true}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+
+	clockIn, err := svc.ClockIn(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("ClockIn: %v", err)
+	}
+	if clockIn.Status != "late" || clockIn.LateMinutes <= 0 {
+		t.Fatalf("上班打卡状态为 %s，迟到 %d 分钟", clockIn.Status, clockIn.LateMinutes)
+	}
+	clockOut, err := svc.ClockOut(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("ClockOut: %v", err)
+	}
+	if clockOut.EarlyLeaveMinutes <= 0 || clockOut.LateMinutes != clockIn.LateMinutes {
+		t.Errorf("下班打卡后迟到 %d 分钟，早退 %d 分钟", clockOut.LateMinutes, clockOut.EarlyLeaveMinutes)
+	}
+
+	var stored models.Attendance
+	if err := db.First(&stored, clockIn.ID).Error; err != nil {
+		t.Fatalf("查询打卡记录失败: %v", err)
+	}
+	if stored.Status != "late" || stored.LateMinutes <= 0 || stored.EarlyLeaveMinutes <= 0 {
+		t.Errorf("打卡记录状态为 %s，迟到 %d 分钟，早退 %d 分钟", stored.Status, stored.LateMinutes, stored.EarlyLeaveMinutes)
+	}
+
+	// 只早退的记录按早退计数，迟到仍计入迟到次数
+	if err := db.Create(&models.Attendance{UserID: user.ID, ClockIn: now.AddDate(0, 0, -1), Date: dateOf(now).AddDate(0, 0, -1),
+		ClockOut: timePtr(now.AddDate(0, 0, -1).Add(time.Hour)), EarlyLeaveMinutes: 30, Status: "early_leave"}).Error; err != nil {
+		t.Fatalf("创建打卡记录失败: %v", err)
+	}
+	stats, err := svc.GetAttendanceStats(ctx)
+	if err != nil {
+		t.Fatalf("GetAttendanceStats: %v", err)
+	}
+	if stats["late_count"] != int64(1) || stats["early_leave_count"] != int64(2) {
+		t.Errorf("统计为 %v，期望迟到 1 次、早退 2 次", stats)
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"API/storage/cache"
+)
+
+// memoryCache 测试用的内存缓存，实现 cache.Provider，记录每个键的过期时间但不会自动过期
+type memoryCache struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	ttls   map[string]time.Duration
+}
+
+var _ cache.Provider = (*memoryCache)(nil)
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{values: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (m *memoryCache) GetObject(ctx context.Context, key string, dest interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	val, ok := m.values[key]
+	if !ok {
+		return fmt.Errorf("key值 %s 找不到: %w", key, cache.ErrNotFound)
+	}
+	return json.Unmarshal(val, dest)
+}
+
+func (m *memoryCache) GetDelObject(ctx context.Context, key string, dest interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	val, ok := m.values[key]
+	if !ok {
+		return fmt.Errorf("key值 %s 找不到: %w", key, cache.ErrNotFound)
+	}
+	delete(m.values, key)
+	delete(m.ttls, key)
+	return json.Unmarshal(val, dest)
+}
+
+func (m *memoryCache) SetObject(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = data
+	m.ttls[key] = expiration
+	return nil
+}
+
+func (m *memoryCache) Del(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		delete(m.values, key)
+		delete(m.ttls, key)
+	}
+	return nil
+}
+
+func (m *memoryCache) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var count int64
+	if val, ok := m.values[key]; ok {
+		if err := json.Unmarshal(val, &count); err != nil {
+			return 0, err
+		}
+	} else {
+		m.ttls[key] = expiration
+	}
+	count++
+	m.values[key] = []byte(fmt.Sprint(count))
+	return count, nil
+}
+
+// has 判断键是否存在
+func (m *memoryCache) has(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.values[key]
+	return ok
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// newTestDB 创建内存 SQLite 数据库并迁移指定的表，MySQL 的 ENUM 列按文本列创建
+func newTestDB(t *testing.T, tables ...interface{}) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	// 内存数据库随连接存在，限制为单个连接
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取测试数据库连接失败: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	for _, table := range tables {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(table); err != nil {
+			t.Fatalf("解析模型失败: %v", err)
+		}
+		replaceEnums(stmt.Schema, map[*schema.Schema]bool{})
+	}
+	if err := db.AutoMigrate(tables...); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	return db
+}
+
+// replaceEnums 将模型及其关联模型中的 ENUM 列改为文本列，迁移时会一并创建关联的表
+func replaceEnums(s *schema.Schema, seen map[*schema.Schema]bool) {
+	if seen[s] {
+		return
+	}
+	seen[s] = true
+	for _, field := range s.Fields {
+		if strings.HasPrefix(strings.ToUpper(string(field.DataType)), "ENUM(") {
+			field.DataType = schema.String
+		}
+	}
+	for _, rel := range s.Relationships.Relations {
+		replaceEnums(rel.FieldSchema, seen)
+		if rel.JoinTable != nil {
+			replaceEnums(rel.JoinTable, seen)
+		}
+	}
+}
@@ -0,0 +1,494 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"API/models"
+	"API/utils"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxHolidaySpan iCalendar 中单个事件最多展开的天数，超出的事件视为无效
+const maxHolidaySpan = 31
+
+// HolidayConfig 节假日日历配置
+type HolidayConfig struct {
+	WorkdayKeywords []string // 导入 iCalendar 时，标题包含这些关键字的事件视为调休上班日
+}
+
+// LoadHolidayConfig 从配置文件加载节假日日历配置
+func LoadHolidayConfig() HolidayConfig {
+	return HolidayConfig{WorkdayKeywords: viper.GetStringSlice("holidays.workday_keywords")}
+}
+
+// HolidayService 节假日日历及调休上班日的维护
+type HolidayService struct {
+	db     *gorm.DB
+	config HolidayConfig
+}
+
+func NewHolidayService(db *gorm.DB, config HolidayConfig) *HolidayService {
+	return &HolidayService{db: db, config: config}
+}
+
+// CalendarInput 创建或修改节假日日历的参数
+type CalendarInput struct {
+	Name        string `json:"name" binding:"required,max=50"`
+	Region      string `json:"region" binding:"required,max=50"` // 适用地区，与员工的工作地区对应
+	IsDefault   bool   `json:"is_default"`                       // 设为默认后其他日历自动取消默认
+	Description string `json:"description" binding:"max=200"`
+}
+
+// HolidayInput 设置某日为节假日或调休上班日的参数，日期格式为 YYYY-MM-DD
+type HolidayInput struct {
+	Date string `json:"date" binding:"required"`
+	Name string `json:"name" binding:"required,max=100"`
+	Kind string `json:"kind" binding:"required,oneof=holiday workday"`
+}
+
+// HolidayImportResult iCalendar 导入结果
+type HolidayImportResult struct {
+	Holidays  int      `json:"holidays"`           // 导入的放假天数
+	Workdays  int      `json:"workdays"`           // 导入的调休上班天数
+	Skipped   int      `json:"skipped"`            // 日期无效或跨度过长而跳过的事件数
+	Canceled  int      `json:"canceled"`           // 已取消而跳过的事件数
+	Recurring int      `json:"recurring"`          // 含重复规则（RRULE）而未导入的事件数
+	Warnings  []string `json:"warnings,omitempty"` // 未导入的重复事件说明
+}
+
+// ListCalendars 获取全部节假日日历
+func (s *HolidayService) ListCalendars(ctx context.Context) ([]models.HolidayCalendar, error) {
+	var calendars []models.HolidayCalendar
+	if err := s.db.WithContext(ctx).Order("id ASC").Find(&calendars).Error; err != nil {
+		return nil, fmt.Errorf("查询节假日日历失败: %w", err)
+	}
+	return calendars, nil
+}
+
+// CreateCalendar 创建节假日日历
+func (s *HolidayService) CreateCalendar(ctx context.Context, input CalendarInput) (*models.HolidayCalendar, error) {
+	calendar := models.HolidayCalendar{
+		Name:        strings.TrimSpace(input.Name),
+		Region:      strings.TrimSpace(input.Region),
+		IsDefault:   input.IsDefault,
+		Description: input.Description,
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkCalendarUnique(tx, calendar, 0); err != nil {
+			return err
+		}
+		if err := tx.Create(&calendar).Error; err != nil {
+			return fmt.Errorf("创建节假日日历失败: %w", err)
+		}
+		return resetDefaultCalendar(tx, calendar)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+// UpdateCalendar 修改节假日日历
+func (s *HolidayService) UpdateCalendar(ctx context.Context, calendarID uint, input CalendarInput) (*models.HolidayCalendar, error) {
+	var calendar models.HolidayCalendar
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		found, err := findCalendar(tx, calendarID)
+		if err != nil {
+			return err
+		}
+		calendar = *found
+		calendar.Name = strings.TrimSpace(input.Name)
+		calendar.Region = strings.TrimSpace(input.Region)
+		calendar.IsDefault = input.IsDefault
+		calendar.Description = input.Description
+		if err := checkCalendarUnique(tx, calendar, calendarID); err != nil {
+			return err
+		}
+		if err := tx.Save(&calendar).Error; err != nil {
+			return fmt.Errorf("更新节假日日历失败: %w", err)
+		}
+		return resetDefaultCalendar(tx, calendar)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+// DeleteCalendar 删除节假日日历及其全部日期
+func (s *HolidayService) DeleteCalendar(ctx context.Context, calendarID uint) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := findCalendar(tx, calendarID); err != nil {
+			return err
+		}
+		if err := tx.Where("calendar_id = ?", calendarID).Delete(&models.Holiday{}).Error; err != nil {
+			return fmt.Errorf("删除节假日失败: %w", err)
+		}
+		// 直接删除以便重新创建同名、同地区的日历
+		if err := tx.Unscoped().Delete(&models.HolidayCalendar{}, calendarID).Error; err != nil {
+			return fmt.Errorf("删除节假日日历失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListHolidays 获取日历某年度的节假日和调休上班日
+func (s *HolidayService) ListHolidays(ctx context.Context, calendarID uint, year int) ([]models.Holiday, error) {
+	db := s.db.WithContext(ctx)
+	if _, err := findCalendar(db, calendarID); err != nil {
+		return nil, err
+	}
+	return listHolidays(db, calendarID, year)
+}
+
+// SetHoliday 将某日设为节假日或调休上班日，已有设置的日期会被覆盖
+func (s *HolidayService) SetHoliday(ctx context.Context, calendarID uint, input HolidayInput) (*models.Holiday, error) {
+	date, err := time.ParseInLocation("2006-01-02", input.Date, time.Local)
+	if err != nil {
+		return nil, utils.NewValidationError("日期格式应为 YYYY-MM-DD", "date")
+	}
+	db := s.db.WithContext(ctx)
+	if _, err := findCalendar(db, calendarID); err != nil {
+		return nil, err
+	}
+	holiday := models.Holiday{CalendarID: calendarID, Date: date, Name: strings.TrimSpace(input.Name), Kind: input.Kind}
+	if err := upsertHolidays(db, []models.Holiday{holiday}); err != nil {
+		return nil, err
+	}
+	if err := db.Where("calendar_id = ? AND date = ?", calendarID, date).First(&holiday).Error; err != nil {
+		return nil, fmt.Errorf("查询节假日失败: %w", err)
+	}
+	return &holiday, nil
+}
+
+// DeleteHoliday 删除日历中的某个日期，恢复按班次判断是否上班
+func (s *HolidayService) DeleteHoliday(ctx context.Context, calendarID, holidayID uint) error {
+	result := s.db.WithContext(ctx).Where("calendar_id = ?", calendarID).Delete(&models.Holiday{}, holidayID)
+	if result.Error != nil {
+		return fmt.Errorf("删除节假日失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return utils.NewNotFoundError("节假日不存在", "holiday")
+	}
+	return nil
+}
+
+// ImportICS 从 iCalendar 文件导入节假日，每个全天事件按起止日期展开；
+// 标题包含调休上班关键字的事件导入为调休上班日。已取消的事件跳过，含重复规则的事件不导入并返回警告。
+// 已有设置的日期会被覆盖
+func (s *HolidayService) ImportICS(ctx context.Context, calendarID uint, data []byte) (*HolidayImportResult, error) {
+	db := s.db.WithContext(ctx)
+	if _, err := findCalendar(db, calendarID); err != nil {
+		return nil, err
+	}
+	holidays, result, err := parseICS(data, s.config.WorkdayKeywords)
+	if err != nil {
+		return nil, err
+	}
+	for i := range holidays {
+		holidays[i].CalendarID = calendarID
+		if holidays[i].Kind == models.HolidayWorkday {
+			result.Workdays++
+		} else {
+			result.Holidays++
+		}
+	}
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return upsertHolidays(tx, holidays)
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetUserRegion 设置员工的工作地区，用于匹配节假日日历
+func (s *HolidayService) SetUserRegion(ctx context.Context, userID uint, region string) error {
+	result := s.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Update("region", strings.TrimSpace(region))
+	if result.Error != nil {
+		return fmt.Errorf("更新工作地区失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := s.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Count(&count).Error; err != nil {
+			return fmt.Errorf("查询员工失败: %w", err)
+		}
+		if count == 0 {
+			return utils.NewNotFoundError("员工不存在", "user")
+		}
+	}
+	return nil
+}
+
+// MyHolidays 获取员工适用日历某年度的节假日和调休上班日，没有适用日历时返回空列表
+func (s *HolidayService) MyHolidays(ctx context.Context, userID uint, year int) ([]models.Holiday, error) {
+	db := s.db.WithContext(ctx)
+	calendar, err := s.calendarFor(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if calendar == nil {
+		return []models.Holiday{}, nil
+	}
+	return listHolidays(db, calendar.ID, year)
+}
+
+// calendarFor 确定员工适用的日历：优先匹配员工的工作地区，其次为默认日历，均不存在时返回 nil
+func (s *HolidayService) calendarFor(tx *gorm.DB, userID uint) (*models.HolidayCalendar, error) {
+	var user models.User
+	if err := tx.Select("id", "region").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("员工不存在", "user")
+		}
+		return nil, fmt.Errorf("查询员工失败: %w", err)
+	}
+	var calendar models.HolidayCalendar
+	if user.Region != "" {
+		err := tx.Where("region = ?", user.Region).First(&calendar).Error
+		if err == nil {
+			return &calendar, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("查询节假日日历失败: %w", err)
+		}
+	}
+	err := tx.Where("is_default = ?", true).First(&calendar).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询节假日日历失败: %w", err)
+	}
+	return &calendar, nil
+}
+
+// holidaysBetween 返回日历在 [start, end] 期间设置过的日期，键为 YYYY-MM-DD，值为日期类型
+func (s *HolidayService) holidaysBetween(tx *gorm.DB, calendarID uint, start, end time.Time) (map[string]string, error) {
+	var holidays []models.Holiday
+	if err := tx.Where("calendar_id = ? AND date BETWEEN ? AND ?", calendarID, dateOf(start), dateOf(end)).
+		Find(&holidays).Error; err != nil {
+		return nil, fmt.Errorf("查询节假日失败: %w", err)
+	}
+	kinds := make(map[string]string, len(holidays))
+	for _, holiday := range holidays {
+		kinds[holiday.Date.Format("2006-01-02")] = holiday.Kind
+	}
+	return kinds, nil
+}
+
+func findCalendar(tx *gorm.DB, calendarID uint) (*models.HolidayCalendar, error) {
+	var calendar models.HolidayCalendar
+	if err := tx.First(&calendar, calendarID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("节假日日历不存在", "holiday_calendar")
+		}
+		return nil, fmt.Errorf("查询节假日日历失败: %w", err)
+	}
+	return &calendar, nil
+}
+
+// checkCalendarUnique 校验日历名称和地区未被其他日历使用
+func checkCalendarUnique(tx *gorm.DB, calendar models.HolidayCalendar, excludeID uint) error {
+	var existing models.HolidayCalendar
+	err := tx.Where("(name = ? OR region = ?) AND id <> ?", calendar.Name, calendar.Region, excludeID).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("查询节假日日历失败: %w", err)
+	}
+	if existing.Name == calendar.Name {
+		return utils.NewValidationError("日历名称已存在", "name")
+	}
+	return utils.NewValidationError("该地区已有节假日日历", "region")
+}
+
+// resetDefaultCalendar 日历设为默认时取消其他日历的默认
+func resetDefaultCalendar(tx *gorm.DB, calendar models.HolidayCalendar) error {
+	if !calendar.IsDefault {
+		return nil
+	}
+	if err := tx.Model(&models.HolidayCalendar{}).
+		Where("id <> ? AND is_default = ?", calendar.ID, true).
+		Update("is_default", false).Error; err != nil {
+		return fmt.Errorf("更新默认日历失败: %w", err)
+	}
+	return nil
+}
+
+func listHolidays(tx *gorm.DB, calendarID uint, year int) ([]models.Holiday, error) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	var holidays []models.Holiday
+	if err := tx.Where("calendar_id = ? AND date >= ? AND date < ?", calendarID, start, start.AddDate(1, 0, 0)).
+		Order("date ASC").
+		Find(&holidays).Error; err != nil {
+		return nil, fmt.Errorf("查询节假日失败: %w", err)
+	}
+	return holidays, nil
+}
+
+// upsertHolidays 写入节假日，同一日历中已存在的日期更新名称和类型
+func upsertHolidays(tx *gorm.DB, holidays []models.Holiday) error {
+	if len(holidays) == 0 {
+		return nil
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"name", "kind"}),
+	}).CreateInBatches(holidays, 100).Error; err != nil {
+		return fmt.Errorf("保存节假日失败: %w", err)
+	}
+	return nil
+}
+
+// parseICS 解析 iCalendar 文件中的事件并按日期展开，同一日期以文件中靠后的事件为准。
+// 返回的导入结果仅包含跳过的事件数和警告
+func parseICS(data []byte, workdayKeywords []string) ([]models.Holiday, *HolidayImportResult, error) {
+	// 展开折行：以空格或制表符开头的行是上一行的延续
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.NewReplacer("\n ", "", "\n\t", "").Replace(text)
+	if !strings.Contains(text, "BEGIN:VCALENDAR") {
+		return nil, nil, utils.NewValidationError("不是有效的 iCalendar 文件", "file")
+	}
+
+	type event struct{ summary, start, end, status, rrule string }
+	var (
+		events  []event
+		current *event
+	)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch line {
+		case "BEGIN:VEVENT":
+			current = &event{}
+			continue
+		case "END:VEVENT":
+			if current != nil {
+				events = append(events, *current)
+			}
+			current = nil
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		colon := strings.Index(line, ":")
+		if colon < 0 {
+			continue
+		}
+		name, value := line[:colon], line[colon+1:]
+		if semi := strings.Index(name, ";"); semi >= 0 {
+			name = name[:semi]
+		}
+		switch strings.ToUpper(name) {
+		case "SUMMARY":
+			current.summary = strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(value)
+		case "DTSTART":
+			current.start = value
+		case "DTEND":
+			current.end = value
+		case "STATUS":
+			current.status = strings.ToUpper(strings.TrimSpace(value))
+		case "RRULE":
+			current.rrule = value
+		}
+	}
+
+	result := &HolidayImportResult{}
+	byDate := make(map[string]models.Holiday)
+	var order []string
+	for _, e := range events {
+		if e.status == "CANCELLED" {
+			result.Canceled++
+			continue
+		}
+		// 重复规则按节假日日历的用途无法可靠展开，要求导入方提供展开后的事件
+		if e.rrule != "" {
+			result.Recurring++
+			result.Warnings = append(result.Warnings, fmt.Sprintf("事件「%s」包含重复规则，未导入，请展开为单独的事件后重新导入", e.summary))
+			continue
+		}
+		start, end, ok := icsDateRange(e.start, e.end)
+		if !ok || e.summary == "" {
+			result.Skipped++
+			continue
+		}
+		kind := models.HolidayOff
+		for _, keyword := range workdayKeywords {
+			if keyword != "" && strings.Contains(e.summary, keyword) {
+				kind = models.HolidayWorkday
+				break
+			}
+		}
+		name := e.summary
+		if len([]rune(name)) > 100 {
+			name = string([]rune(name)[:100])
+		}
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			key := day.Format("2006-01-02")
+			if _, exists := byDate[key]; !exists {
+				order = append(order, key)
+			}
+			byDate[key] = models.Holiday{Date: day, Name: name, Kind: kind}
+		}
+	}
+
+	holidays := make([]models.Holiday, 0, len(order))
+	for _, key := range order {
+		holidays = append(holidays, byDate[key])
+	}
+	return holidays, result, nil
+}
+
+// icsDateRange 解析事件的起止日期（含）。全天事件的 DTEND 不含当天；
+// 缺少 DTEND 时为单日事件，跨度超过 maxHolidaySpan 天的事件视为无效
+func icsDateRange(startValue, endValue string) (time.Time, time.Time, bool) {
+	startTime, _, ok := icsTime(startValue)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	start := dateOf(startTime)
+	end := start
+	if endValue != "" {
+		endTime, allDay, ok := icsTime(endValue)
+		if !ok {
+			return time.Time{}, time.Time{}, false
+		}
+		// 全天事件或结束于零点的事件不含结束当天
+		last := dateOf(endTime)
+		if allDay || endTime.Equal(last) {
+			last = last.AddDate(0, 0, -1)
+		}
+		if last.After(start) {
+			end = last
+		}
+	}
+	if end.Sub(start) >= maxHolidaySpan*24*time.Hour {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+// icsTime 解析 DATE 或 DATE-TIME 值，allDay 表示仅有日期。以 Z 结尾的 UTC 时间转换为本地时间，
+// 其余时间（包括带 TZID 的时间）按本地时间解析
+func icsTime(value string) (t time.Time, allDay bool, ok bool) {
+	var err error
+	switch {
+	case len(value) == 8:
+		t, err = time.ParseInLocation("20060102", value, time.Local)
+		allDay = true
+	case len(value) == 16 && strings.HasSuffix(value, "Z"):
+		t, err = time.Parse("20060102T150405Z", value)
+		t = t.Local()
+	case len(value) == 15:
+		t, err = time.ParseInLocation("20060102T150405", value, time.Local)
+	default:
+		return time.Time{}, false, false
+	}
+	return t, allDay, err == nil
+}
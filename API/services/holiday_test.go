@@ -0,0 +1,120 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"API/models"
+)
+
+// withLocal 在测试期间将本地时区设为东八区
+func withLocal(t *testing.T) {
+	t.Helper()
+	original := time.Local
+	time.Local = time.FixedZone("CST", 8*3600)
+	t.Cleanup(func() { time.Local = original })
+}
+
+func TestICSDateRange(t *testing.T) {
+	withLocal(t)
+	tests := []struct {
+		name       string
+		start, end string
+		want       [2]string
+		ok         bool
+	}{
+		{"全天单日事件", "20250101", "20250102", [2]string{"2025-01-01", "2025-01-01"}, true},
+		{"缺少 DTEND", "20250101", "", [2]string{"2025-01-01", "2025-01-01"}, true},
+		{"全天多日事件不含结束当天", "20250128", "20250205", [2]string{"2025-01-28", "2025-02-04"}, true},
+		{"本地时间结束于零点", "20250501T000000", "20250506T000000", [2]string{"2025-05-01", "2025-05-05"}, true},
+		{"本地时间结束于白天", "20250501T090000", "20250502T180000", [2]string{"2025-05-01", "2025-05-02"}, true},
+		{"UTC 时间转换为本地日期", "20241231T160000Z", "20250101T160000Z", [2]string{"2025-01-01", "2025-01-01"}, true},
+		{"UTC 时间跨过本地零点", "20250930T170000Z", "20251008T160000Z", [2]string{"2025-10-01", "2025-10-08"}, true},
+		{"结束早于开始按单日处理", "20250110", "20250105", [2]string{"2025-01-10", "2025-01-10"}, true},
+		{"日期无效", "2025011", "", [2]string{}, false},
+		{"结束日期无效", "20250101", "2025-01-02", [2]string{}, false},
+		{"跨度过长", "20250101", "20250301", [2]string{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, ok := icsDateRange(tt.start, tt.end)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, 期望 %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			got := [2]string{start.Format("2006-01-02"), end.Format("2006-01-02")}
+			if got != tt.want {
+				t.Errorf("日期范围为 %v, 期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+const sampleICS = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\nSUMMARY:元旦\r\nDTSTART;VALUE=DATE:20250101\r\nDTEND;VALUE=DATE:20250102\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nSUMMARY:春节\r\nDTSTART;VALUE=DATE:20250128\r\nDTEND;VALUE=DATE:20250205\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nSUMMARY:春节调休\\, 上班\r\nDTSTART;VALUE=DATE:20250126\r\nEND:VEVENT\r\n" +
+	// 折行的标题，UTC 时间表示的本地全天
+	"BEGIN:VEVENT\r\nSUMMARY:劳动\r\n 节\r\nDTSTART:20250430T160000Z\r\nDTEND:20250505T160000Z\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nSUMMARY:已取消的假期\r\nSTATUS:CANCELLED\r\nDTSTART;VALUE=DATE:20250601\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nSUMMARY:每周团建\r\nRRULE:FREQ=WEEKLY;COUNT=4\r\nDTSTART;VALUE=DATE:20250606\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nSUMMARY:无效日期\r\nDTSTART:2025\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20250701\r\nEND:VEVENT\r\n" +
+	// 同一日期以靠后的事件为准
+	"BEGIN:VEVENT\r\nSUMMARY:元旦（更正）\r\nDTSTART;VALUE=DATE:20250101\r\nEND:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	withLocal(t)
+	holidays, result, err := parseICS([]byte(sampleICS), []string{"上班", "补班"})
+	if err != nil {
+		t.Fatalf("parseICS: %v", err)
+	}
+	if result.Canceled != 1 || result.Recurring != 1 || result.Skipped != 2 {
+		t.Errorf("跳过统计为 取消%d 重复%d 无效%d, 期望 1、1、2", result.Canceled, result.Recurring, result.Skipped)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "每周团建") {
+		t.Errorf("警告为 %v", result.Warnings)
+	}
+
+	got := make(map[string]models.Holiday, len(holidays))
+	for _, holiday := range holidays {
+		got[holiday.Date.Format("2006-01-02")] = holiday
+	}
+	// 元旦 1 天、春节 8 天、调休 1 天、劳动节 5 天
+	if len(holidays) != 15 {
+		t.Errorf("导入 %d 天, 期望 15 天", len(holidays))
+	}
+	tests := []struct {
+		date, name, kind string
+	}{
+		{"2025-01-01", "元旦（更正）", models.HolidayOff},
+		{"2025-01-28", "春节", models.HolidayOff},
+		{"2025-02-04", "春节", models.HolidayOff},
+		{"2025-01-26", "春节调休, 上班", models.HolidayWorkday},
+		{"2025-05-01", "劳动节", models.HolidayOff},
+		{"2025-05-05", "劳动节", models.HolidayOff},
+	}
+	for _, tt := range tests {
+		holiday, ok := got[tt.date]
+		if !ok {
+			t.Errorf("缺少 %s", tt.date)
+			continue
+		}
+		if holiday.Name != tt.name || holiday.Kind != tt.kind {
+			t.Errorf("%s 为 %s/%s, 期望 %s/%s", tt.date, holiday.Name, holiday.Kind, tt.name, tt.kind)
+		}
+	}
+	for _, date := range []string{"2025-02-05", "2025-04-30", "2025-05-06", "2025-06-01", "2025-06-06", "2025-07-01"} {
+		if _, ok := got[date]; ok {
+			t.Errorf("%s 不应导入", date)
+		}
+	}
+
+	if _, _, err := parseICS([]byte("hello"), nil); err == nil {
+		t.Error("非 iCalendar 文件应返回错误")
+	}
+}
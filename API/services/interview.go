@@ -0,0 +1,456 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"API/models"
+	"API/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InterviewInput 安排或改期面试时提交的信息
+type InterviewInput struct {
+	StartTime      time.Time `json:"start_time" binding:"required"`
+	EndTime        time.Time `json:"end_time" binding:"required"`
+	Location       string    `json:"location" binding:"max=100"`
+	InterviewerIDs []uint    `json:"interviewer_ids" binding:"required,min=1"`
+	Notes          string    `json:"notes"`
+}
+
+// ScorecardInput 面试官提交的评分表
+type ScorecardInput struct {
+	Recommendation string                 `json:"recommendation" binding:"required,oneof=strong_yes yes no strong_no"`
+	Summary        string                 `json:"summary"`
+	Ratings        []ScorecardRatingInput `json:"ratings" binding:"required,min=1,dive"`
+}
+
+// ScorecardRatingInput 单个考察项的评分
+type ScorecardRatingInput struct {
+	Criterion string `json:"criterion" binding:"required,max=50"`
+	Rating    uint8  `json:"rating" binding:"required,min=1,max=5"`
+	Comment   string `json:"comment" binding:"max=500"`
+}
+
+// ApplicationInterviews 申请的全部面试及评分汇总
+type ApplicationInterviews struct {
+	Interviews     []models.Interview              `json:"interviews"`
+	Recommendation *models.InterviewRecommendation `json:"recommendation"`
+}
+
+// InterviewService 面试安排与评分
+type InterviewService struct {
+	db *gorm.DB
+}
+
+func NewInterviewService(db *gorm.DB) *InterviewService {
+	return &InterviewService{db: db}
+}
+
+// ScheduleInterview 为申请安排面试，面试官不能与其他面试或已报名的培训时间冲突
+func (s *InterviewService) ScheduleInterview(ctx context.Context, applicationID, operatorID uint, input InterviewInput) (*models.Interview, error) {
+	if err := validateInterviewTime(input); err != nil {
+		return nil, err
+	}
+
+	var interview models.Interview
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var application models.Application
+		if err := tx.First(&application, applicationID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewNotFoundError("申请记录不存在", "application")
+			}
+			return fmt.Errorf("查询申请失败: %w", err)
+		}
+		if !applicationInProgress(application) {
+			return utils.NewValidationError("申请已结束，不能再安排面试", "application")
+		}
+
+		interviewers, err := lockInterviewers(tx, input.InterviewerIDs)
+		if err != nil {
+			return err
+		}
+		if err := checkInterviewerConflicts(tx, interviewers, input.StartTime, input.EndTime, 0); err != nil {
+			return err
+		}
+
+		interview = models.Interview{
+			ApplicationID: application.ID,
+			StageID:       application.StageID,
+			StartTime:     input.StartTime,
+			EndTime:       input.EndTime,
+			Location:      strings.TrimSpace(input.Location),
+			Status:        models.InterviewScheduled,
+			Notes:         input.Notes,
+			CreatedBy:     operatorID,
+			Interviewers:  interviewers,
+		}
+		if err := tx.Omit("Interviewers.*").Create(&interview).Error; err != nil {
+			return fmt.Errorf("创建面试失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &interview, nil
+}
+
+// RescheduleInterview 调整尚未评分的面试的时间、地点和面试官
+func (s *InterviewService) RescheduleInterview(ctx context.Context, interviewID uint, input InterviewInput) (*models.Interview, error) {
+	if err := validateInterviewTime(input); err != nil {
+		return nil, err
+	}
+
+	var interview models.Interview
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockInterview(tx, interviewID, &interview); err != nil {
+			return err
+		}
+		if interview.Status != models.InterviewScheduled {
+			return utils.NewValidationError("只能调整待进行的面试", "interview")
+		}
+		var submitted int64
+		if err := tx.Model(&models.Scorecard{}).Where("interview_id = ?", interview.ID).Count(&submitted).Error; err != nil {
+			return fmt.Errorf("查询评分表失败: %w", err)
+		}
+		if submitted > 0 {
+			return utils.NewValidationError("面试已有评分，不能再调整", "interview")
+		}
+
+		interviewers, err := lockInterviewers(tx, input.InterviewerIDs)
+		if err != nil {
+			return err
+		}
+		if err := checkInterviewerConflicts(tx, interviewers, input.StartTime, input.EndTime, interview.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&interview).Updates(map[string]interface{}{
+			"start_time": input.StartTime,
+			"end_time":   input.EndTime,
+			"location":   strings.TrimSpace(input.Location),
+			"notes":      input.Notes,
+		}).Error; err != nil {
+			return fmt.Errorf("更新面试失败: %w", err)
+		}
+		if err := tx.Model(&interview).Omit("Interviewers.*").Association("Interviewers").Replace(interviewers); err != nil {
+			return fmt.Errorf("更新面试官失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.getInterview(ctx, interview.ID)
+}
+
+// CancelInterview 取消待进行的面试
+func (s *InterviewService) CancelInterview(ctx context.Context, interviewID uint) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var interview models.Interview
+		if err := lockInterview(tx, interviewID, &interview); err != nil {
+			return err
+		}
+		if interview.Status != models.InterviewScheduled {
+			return utils.NewValidationError("只能取消待进行的面试", "interview")
+		}
+		return tx.Model(&interview).Update("status", models.InterviewCanceled).Error
+	})
+}
+
+// SubmitScorecard 面试官提交或修改本人的评分表；全部面试官提交后面试标记为已完成
+func (s *InterviewService) SubmitScorecard(ctx context.Context, interviewID, interviewerID uint, input ScorecardInput) (*models.Scorecard, error) {
+	if _, ok := models.RecommendationScores[input.Recommendation]; !ok {
+		return nil, utils.NewValidationError("无效的推荐意见", "recommendation")
+	}
+	ratings := make([]models.ScorecardRating, 0, len(input.Ratings))
+	seen := make(map[string]bool, len(input.Ratings))
+	for _, r := range input.Ratings {
+		criterion := strings.TrimSpace(r.Criterion)
+		if criterion == "" {
+			return nil, utils.NewValidationError("考察项不能为空", "ratings")
+		}
+		if seen[criterion] {
+			return nil, utils.NewValidationError("考察项重复: "+criterion, "ratings")
+		}
+		seen[criterion] = true
+		if r.Rating < 1 || r.Rating > 5 {
+			return nil, utils.NewValidationError("评分必须在1到5之间", "ratings")
+		}
+		ratings = append(ratings, models.ScorecardRating{Criterion: criterion, Rating: r.Rating, Comment: r.Comment})
+	}
+
+	var scorecard models.Scorecard
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var interview models.Interview
+		if err := lockInterview(tx, interviewID, &interview); err != nil {
+			return err
+		}
+		var assigned int64
+		if err := tx.Table("interview_interviewers").
+			Where("interview_id = ? AND user_id = ?", interview.ID, interviewerID).
+			Count(&assigned).Error; err != nil {
+			return fmt.Errorf("查询面试官失败: %w", err)
+		}
+		if assigned == 0 {
+			return utils.NewForbiddenError("您不是该面试的面试官")
+		}
+		if interview.Status == models.InterviewCanceled {
+			return utils.NewValidationError("面试已取消", "interview")
+		}
+		if time.Now().Before(interview.StartTime) {
+			return utils.NewValidationError("面试尚未开始，不能提交评分", "interview")
+		}
+
+		err := tx.Where("interview_id = ? AND interviewer_id = ?", interview.ID, interviewerID).First(&scorecard).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			scorecard = models.Scorecard{InterviewID: interview.ID, InterviewerID: interviewerID}
+		case err != nil:
+			return fmt.Errorf("查询评分表失败: %w", err)
+		default:
+			if err := tx.Unscoped().Where("scorecard_id = ?", scorecard.ID).Delete(&models.ScorecardRating{}).Error; err != nil {
+				return fmt.Errorf("清除原评分失败: %w", err)
+			}
+		}
+		scorecard.Recommendation = input.Recommendation
+		scorecard.Summary = input.Summary
+		scorecard.Ratings = ratings
+		if err := tx.Save(&scorecard).Error; err != nil {
+			return fmt.Errorf("保存评分表失败: %w", err)
+		}
+
+		if interview.Status == models.InterviewScheduled {
+			var pending int64
+			if err := tx.Table("interview_interviewers").
+				Where("interview_id = ?", interview.ID).
+				Where("user_id NOT IN (?)", tx.Model(&models.Scorecard{}).Select("interviewer_id").Where("interview_id = ?", interview.ID)).
+				Count(&pending).Error; err != nil {
+				return fmt.Errorf("查询评分进度失败: %w", err)
+			}
+			if pending == 0 {
+				return tx.Model(&interview).Update("status", models.InterviewCompleted).Error
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &scorecard, nil
+}
+
+// ListApplicationInterviews 获取申请的全部面试、评分表及汇总推荐意见
+func (s *InterviewService) ListApplicationInterviews(ctx context.Context, applicationID uint) (*ApplicationInterviews, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.Application{}).Where("id = ?", applicationID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("查询申请失败: %w", err)
+	}
+	if count == 0 {
+		return nil, utils.NewNotFoundError("申请记录不存在", "application")
+	}
+
+	var interviews []models.Interview
+	if err := s.db.WithContext(ctx).
+		Preload("Interviewers").
+		Preload("Scorecards.Interviewer").
+		Preload("Scorecards.Ratings").
+		Where("application_id = ?", applicationID).
+		Order("start_time ASC").
+		Find(&interviews).Error; err != nil {
+		return nil, fmt.Errorf("查询面试失败: %w", err)
+	}
+
+	recommendations, err := interviewRecommendations(s.db.WithContext(ctx), []uint{applicationID})
+	if err != nil {
+		return nil, err
+	}
+	return &ApplicationInterviews{Interviews: interviews, Recommendation: recommendations[applicationID]}, nil
+}
+
+// ListMyInterviews 获取当前用户作为面试官的待进行面试
+func (s *InterviewService) ListMyInterviews(ctx context.Context, userID uint) ([]models.Interview, error) {
+	var interviews []models.Interview
+	err := s.db.WithContext(ctx).
+		Preload("Application.Job").
+		Preload("Application.User").
+		Preload("Interviewers").
+		Joins("JOIN interview_interviewers ON interview_interviewers.interview_id = interviews.id").
+		Where("interview_interviewers.user_id = ? AND interviews.status = ? AND interviews.end_time > ?", userID, models.InterviewScheduled, time.Now()).
+		Order("interviews.start_time ASC").
+		Find(&interviews).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询面试失败: %w", err)
+	}
+	return interviews, nil
+}
+
+func (s *InterviewService) getInterview(ctx context.Context, interviewID uint) (*models.Interview, error) {
+	var interview models.Interview
+	if err := s.db.WithContext(ctx).Preload("Interviewers").First(&interview, interviewID).Error; err != nil {
+		return nil, fmt.Errorf("查询面试失败: %w", err)
+	}
+	return &interview, nil
+}
+
+func validateInterviewTime(input InterviewInput) error {
+	if !input.EndTime.After(input.StartTime) {
+		return utils.NewValidationError("结束时间必须晚于开始时间", "end_time")
+	}
+	if input.StartTime.Before(time.Now()) {
+		return utils.NewValidationError("面试开始时间不能早于当前时间", "start_time")
+	}
+	return nil
+}
+
+func lockInterview(tx *gorm.DB, interviewID uint, interview *models.Interview) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(interview, interviewID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.NewNotFoundError("面试不存在", "interview")
+		}
+		return fmt.Errorf("查询面试失败: %w", err)
+	}
+	return nil
+}
+
+// lockInterviewers 查询并锁定面试官，面试官必须是在职员工；
+// 锁定用户行使并发安排同一面试官的请求串行执行，避免冲突检测被绕过
+func lockInterviewers(tx *gorm.DB, ids []uint) ([]models.User, error) {
+	unique := make([]uint, 0, len(ids))
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	var interviewers []models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ? AND usertype IN ? AND active = ?", unique, []string{"admin", "employee"}, true).
+		Order("id ASC").
+		Find(&interviewers).Error; err != nil {
+		return nil, fmt.Errorf("查询面试官失败: %w", err)
+	}
+	if len(interviewers) != len(unique) {
+		return nil, utils.NewValidationError("面试官必须是在职员工", "interviewer_ids")
+	}
+	return interviewers, nil
+}
+
+// checkInterviewerConflicts 检查面试官在该时段是否已有其他面试或已报名的培训，exclude 为正在调整的面试
+func checkInterviewerConflicts(tx *gorm.DB, interviewers []models.User, start, end time.Time, exclude uint) error {
+	for _, interviewer := range interviewers {
+		var other models.Interview
+		err := tx.Joins("JOIN interview_interviewers ON interview_interviewers.interview_id = interviews.id").
+			Where("interview_interviewers.user_id = ? AND interviews.status = ? AND interviews.id <> ?", interviewer.ID, models.InterviewScheduled, exclude).
+			Where("interviews.start_time < ? AND interviews.end_time > ?", end, start).
+			First(&other).Error
+		if err == nil {
+			return utils.NewValidationError(fmt.Sprintf("面试官 %s 在 %s - %s 已有其他面试",
+				interviewer.Username, other.StartTime.Format("2006-01-02 15:04"), other.EndTime.Format("15:04")), "interviewer_ids")
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("检查面试时间冲突失败: %w", err)
+		}
+
+		var training models.Training
+		err = tx.Joins("JOIN training_records ON training_records.training_id = trainings.id AND training_records.deleted_at IS NULL").
+			Where("training_records.user_id = ? AND training_records.status = ?", interviewer.ID, "registered").
+			Where("trainings.start_time < ? AND trainings.end_time > ?", end, start).
+			First(&training).Error
+		if err == nil {
+			return utils.NewValidationError(fmt.Sprintf("面试官 %s 在该时段需参加培训「%s」", interviewer.Username, training.Title), "interviewer_ids")
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("检查培训时间冲突失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// interviewRecommendations 汇总各申请未取消面试的评分表，没有评分的申请不在结果中
+func interviewRecommendations(db *gorm.DB, applicationIDs []uint) (map[uint]*models.InterviewRecommendation, error) {
+	result := make(map[uint]*models.InterviewRecommendation)
+	if len(applicationIDs) == 0 {
+		return result, nil
+	}
+
+	var interviews []models.Interview
+	if err := db.Select("id", "application_id").
+		Where("application_id IN ? AND status <> ?", applicationIDs, models.InterviewCanceled).
+		Find(&interviews).Error; err != nil {
+		return nil, fmt.Errorf("查询面试失败: %w", err)
+	}
+	if len(interviews) == 0 {
+		return result, nil
+	}
+	applicationOf := make(map[uint]uint, len(interviews))
+	interviewIDs := make([]uint, 0, len(interviews))
+	for _, interview := range interviews {
+		applicationOf[interview.ID] = interview.ApplicationID
+		interviewIDs = append(interviewIDs, interview.ID)
+	}
+
+	var scorecards []models.Scorecard
+	if err := db.Preload("Ratings").Where("interview_id IN ?", interviewIDs).Find(&scorecards).Error; err != nil {
+		return nil, fmt.Errorf("查询评分表失败: %w", err)
+	}
+
+	type totals struct {
+		score    int
+		criteria map[string][2]int // 总分, 次数
+	}
+	sums := make(map[uint]*totals)
+	for _, sc := range scorecards {
+		applicationID := applicationOf[sc.InterviewID]
+		t, ok := sums[applicationID]
+		if !ok {
+			t = &totals{criteria: make(map[string][2]int)}
+			sums[applicationID] = t
+			result[applicationID] = &models.InterviewRecommendation{Criteria: make(map[string]float64)}
+		}
+		result[applicationID].Scorecards++
+		t.score += models.RecommendationScores[sc.Recommendation]
+		for _, r := range sc.Ratings {
+			c := t.criteria[r.Criterion]
+			t.criteria[r.Criterion] = [2]int{c[0] + int(r.Rating), c[1] + 1}
+		}
+	}
+
+	for id, t := range sums {
+		rec := result[id]
+		rec.Score = round2(float64(t.score) / float64(rec.Scorecards))
+		rec.Recommendation = aggregateRecommendation(rec.Score)
+		for criterion, c := range t.criteria {
+			rec.Criteria[criterion] = round2(float64(c[0]) / float64(c[1]))
+		}
+	}
+	return result, nil
+}
+
+// aggregateRecommendation 将平均分映射回推荐意见
+func aggregateRecommendation(score float64) string {
+	switch {
+	case score >= 1.5:
+		return models.RecommendStrongYes
+	case score > 0:
+		return models.RecommendYes
+	case score <= -1.5:
+		return models.RecommendStrongNo
+	case score < 0:
+		return models.RecommendNo
+	}
+	return "mixed"
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"API/models"
+)
+
+func TestInterviewerConflicts(t *testing.T) {
+	ctx := context.Background()
+	// 面试须安排在将来，以两天后的整点为基准
+	base := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	slot := func(startHour, endHour int) (time.Time, time.Time) {
+		return base.Add(time.Duration(startHour) * time.Hour), base.Add(time.Duration(endHour) * time.Hour)
+	}
+
+	setup := func(t *testing.T) (*InterviewService, models.Interview) {
+		db := newTestDB(t, &models.User{}, &models.Application{}, &models.Interview{}, &models.Scorecard{}, &models.Training{}, &models.TrainingRecord{})
+		users := []models.User{
+			{Username: "alice", Email: "alice@example.com", Phone: "1", Usertype: "employee", Active: true},
+			{Username: "bob", Email: "bob@example.com", Phone: "2", Usertype: "employee", Active: true},
+			{Username: "carol", Email: "carol@example.com", Phone: "3", Usertype: "candidate", Active: true},
+			{Username: "dave", Email: "dave@example.com", Phone: "4", Usertype: "employee", Active: true},
+		}
+		if err := db.Create(&users).Error; err != nil {
+			t.Fatalf("创建用户失败: %v", err)
+		}
+		db.Model(&users[3]).Update("active", false)
+		applications := []models.Application{
+			{UserID: users[2].ID, JobID: 1, Status: "interviewed"},
+			{UserID: users[2].ID, JobID: 2, Status: "rejected"},
+		}
+		if err := db.Create(&applications).Error; err != nil {
+			t.Fatalf("创建申请失败: %v", err)
+		}
+
+		svc := NewInterviewService(db)
+		// alice 10:00-11:00 已有面试，14:00-15:00 的面试已取消
+		start, end := slot(10, 11)
+		existing := models.Interview{ApplicationID: applications[0].ID, StartTime: start, EndTime: end, Status: models.InterviewScheduled, Interviewers: users[:1]}
+		start, end = slot(14, 15)
+		canceled := models.Interview{ApplicationID: applications[0].ID, StartTime: start, EndTime: end, Status: models.InterviewCanceled, Interviewers: users[:1]}
+		for _, interview := range []*models.Interview{&existing, &canceled} {
+			if err := db.Omit("Interviewers.*").Create(interview).Error; err != nil {
+				t.Fatalf("创建面试失败: %v", err)
+			}
+		}
+		// bob 16:00-17:00 已报名培训，alice 已取消报名
+		start, end = slot(16, 17)
+		training := models.Training{Title: "安全培训", StartTime: start, EndTime: end}
+		if err := db.Create(&training).Error; err != nil {
+			t.Fatalf("创建培训失败: %v", err)
+		}
+		records := []models.TrainingRecord{
+			{UserID: users[1].ID, TrainingID: training.ID, Status: "registered"},
+			{UserID: users[0].ID, TrainingID: training.ID, Status: "canceled"},
+		}
+		if err := db.Create(&records).Error; err != nil {
+			t.Fatalf("创建培训记录失败: %v", err)
+		}
+		return svc, existing
+	}
+
+	tests := []struct {
+		name         string
+		application  uint
+		start, end   int
+		interviewers []uint
+		reschedule   bool
+		wantErr      string
+	}{
+		{"时间重叠", 1, 10, 12, []uint{1}, false, "面试官 alice 在"},
+		{"包含已有面试", 1, 9, 12, []uint{2, 1}, false, "面试官 alice 在"},
+		{"首尾相接不冲突", 1, 11, 12, []uint{1}, false, ""},
+		{"已取消的面试不占用时间", 1, 14, 15, []uint{1}, false, ""},
+		{"其他面试官不受影响", 1, 10, 11, []uint{2}, false, ""},
+		{"已报名的培训", 1, 16, 17, []uint{1, 2}, false, "面试官 bob 在该时段需参加培训「安全培训」"},
+		{"已取消报名的培训", 1, 16, 17, []uint{1}, false, ""},
+		{"面试官不能是候选人", 1, 12, 13, []uint{3}, false, "面试官必须是在职员工"},
+		{"面试官不能是停用员工", 1, 12, 13, []uint{4}, false, "面试官必须是在职员工"},
+		{"申请已结束", 2, 12, 13, []uint{1}, false, "申请已结束，不能再安排面试"},
+		{"改期不与自身冲突", 1, 10, 12, []uint{1}, true, ""},
+		{"改期仍检查其他面试官", 1, 16, 17, []uint{1, 2}, true, "面试官 bob 在该时段需参加培训「安全培训」"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, existing := setup(t)
+			start, end := slot(tt.start, tt.end)
+			input := InterviewInput{StartTime: start, EndTime: end, InterviewerIDs: tt.interviewers}
+			var err error
+			if tt.reschedule {
+				_, err = svc.RescheduleInterview(ctx, existing.ID, input)
+			} else {
+				_, err = svc.ScheduleInterview(ctx, tt.application, 1, input)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("期望安排成功，得到 %v", err)
+				}
+				return
+			}
+			if !strings.HasPrefix(validationMessage(err), tt.wantErr) {
+				t.Errorf("期望错误 %q，得到 %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"time"
 
 	"API/models"
+	"API/utils"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type JobService struct {
@@ -22,54 +26,246 @@ func NewJobService(db *gorm.DB) *JobService {
 	}
 }
 
-// CreateJob 创建新职位
+// currencyPattern ISO 4217 币种代码
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// CreateJob 创建草稿职位，需发起招聘需求并审批通过后才能发布
 func (s *JobService) CreateJob(ctx context.Context, job *models.Job) error {
-	return s.db.WithContext(ctx).Create(job).Error
+	job.Status = models.JobDraft
+	if job.SalaryCurrency == "" {
+		job.SalaryCurrency = "CNY"
+	}
+	if job.SalaryPeriod == "" {
+		job.SalaryPeriod = models.SalaryPerMonth
+	}
+	if err := validateJob(job); err != nil {
+		return err
+	}
+	skills := job.Skills
+	job.Skills = nil
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+		return replaceJobSkills(tx, job, skills)
+	})
 }
 
-// UpdateJob 更新职位信息
+// UpdateJob 更新职位信息，未提供的字段保持不变；职位状态通过重新开放等操作单独变更
 func (s *JobService) UpdateJob(ctx context.Context, id uint, job *models.Job) error {
 	var existingJob models.Job
 	if err := s.db.WithContext(ctx).First(&existingJob, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return fmt.Errorf("职位不存在")
+			return utils.NewNotFoundError("职位不存在", "job")
 		}
 		return fmt.Errorf("查询职位失败: %w", err)
 	}
 
-	// 更新职位信息
-	return s.db.WithContext(ctx).Model(&existingJob).Updates(job).Error
+	// 与现有值合并后再校验，避免只更新上限或下限时出现上限低于下限
+	merged := existingJob
+	if job.SalaryMin != nil {
+		merged.SalaryMin = job.SalaryMin
+	}
+	if job.SalaryMax != nil {
+		merged.SalaryMax = job.SalaryMax
+	}
+	if job.SalaryCurrency != "" {
+		merged.SalaryCurrency = job.SalaryCurrency
+	}
+	if job.SalaryPeriod != "" {
+		merged.SalaryPeriod = job.SalaryPeriod
+	}
+	if job.ExpirationDate != nil {
+		merged.ExpirationDate = job.ExpirationDate
+		if !job.ExpirationDate.After(time.Now()) {
+			return utils.NewValidationError("截止日期必须晚于当前时间", "expiration_date")
+		}
+	}
+	if err := validateSalary(&merged); err != nil {
+		return err
+	}
+
+	job.Status = ""
+	skills := job.Skills
+	job.Skills = nil
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&existingJob).Updates(job).Error; err != nil {
+			return err
+		}
+		return replaceJobSkills(tx, &existingJob, skills)
+	})
+}
+
+// replaceJobSkills 按名称规范化后替换职位的技能要求，skills 为 nil 时保持不变
+func replaceJobSkills(tx *gorm.DB, job *models.Job, skills []models.Skill) error {
+	if skills == nil {
+		return nil
+	}
+	names := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		names = append(names, skill.Name)
+	}
+	normalized, err := ensureSkills(tx, names)
+	if err != nil {
+		return err
+	}
+	if err := tx.Model(job).Association("Skills").Replace(normalized); err != nil {
+		return fmt.Errorf("保存职位技能要求失败: %w", err)
+	}
+	job.Skills = normalized
+	return nil
 }
 
-// GetOpenJobs 获取开放职位列表
-func (s *JobService) GetOpenJobs(ctx context.Context, page, pageSize int) ([]models.Job, int64, error) {
+// ReopenJob 重新开放已关闭的职位，必须同时设置新的截止日期
+func (s *JobService) ReopenJob(ctx context.Context, id uint, expirationDate time.Time) (*models.Job, error) {
+	if !expirationDate.After(time.Now()) {
+		return nil, utils.NewValidationError("截止日期必须晚于当前时间", "expiration_date")
+	}
+	var job models.Job
+	if err := s.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("职位不存在", "job")
+		}
+		return nil, fmt.Errorf("查询职位失败: %w", err)
+	}
+	switch {
+	case job.Status == models.JobDraft || job.Status == models.JobPendingApproval:
+		return nil, utils.NewValidationError("职位尚未发布，需审批通过后发布", "job")
+	case job.Status == models.JobOpen && (job.ExpirationDate == nil || job.ExpirationDate.After(time.Now())):
+		return nil, utils.NewValidationError("职位正在开放中", "job")
+	}
+
+	job.Status = models.JobOpen
+	job.ExpirationDate = &expirationDate
+	if err := s.db.WithContext(ctx).Model(&job).Updates(map[string]interface{}{
+		"status":          job.Status,
+		"expiration_date": expirationDate,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("重新开放职位失败: %w", err)
+	}
+	return &job, nil
+}
+
+// PublishJob 发布招聘需求已审批通过的草稿职位
+func (s *JobService) PublishJob(ctx context.Context, id uint) (*models.Job, error) {
+	var job models.Job
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&job, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewNotFoundError("职位不存在", "job")
+			}
+			return fmt.Errorf("查询职位失败: %w", err)
+		}
+		switch job.Status {
+		case models.JobPendingApproval:
+			return utils.NewValidationError("招聘需求正在审批中", "job")
+		case models.JobOpen, models.JobClosed:
+			return utils.NewValidationError("职位已发布", "job")
+		}
+		var approved int64
+		if err := tx.Model(&models.Requisition{}).
+			Where("job_id = ? AND status = ?", id, models.RequisitionApproved).
+			Count(&approved).Error; err != nil {
+			return fmt.Errorf("查询招聘需求失败: %w", err)
+		}
+		if approved == 0 {
+			return utils.NewValidationError("职位的招聘需求尚未审批通过", "job")
+		}
+		if job.ExpirationDate != nil && !job.ExpirationDate.After(time.Now()) {
+			return utils.NewValidationError("截止日期已过，请先修改截止日期", "expiration_date")
+		}
+		job.Status = models.JobOpen
+		return tx.Model(&job).Update("status", job.Status).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// CloseExpiredJobs 关闭已过截止日期仍在开放的职位，返回关闭的数量
+func (s *JobService) CloseExpiredJobs(ctx context.Context) (int64, error) {
+	result := s.db.WithContext(ctx).Model(&models.Job{}).
+		Where("status = ? AND expiration_date IS NOT NULL AND expiration_date <= ?", models.JobOpen, time.Now()).
+		Update("status", models.JobClosed)
+	if result.Error != nil {
+		return 0, fmt.Errorf("关闭过期职位失败: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// validateJob 校验新建职位的截止日期和薪资
+func validateJob(job *models.Job) error {
+	if job.ExpirationDate != nil && !job.ExpirationDate.After(time.Now()) {
+		return utils.NewValidationError("截止日期必须晚于当前时间", "expiration_date")
+	}
+	return validateSalary(job)
+}
+
+// validateSalary 校验薪资上下限、币种和计算周期
+func validateSalary(job *models.Job) error {
+	if job.SalaryMin != nil && *job.SalaryMin < 0 || job.SalaryMax != nil && *job.SalaryMax < 0 {
+		return utils.NewValidationError("薪资不能为负数", "salary_min")
+	}
+	if job.SalaryMin != nil && job.SalaryMax != nil && *job.SalaryMin > *job.SalaryMax {
+		return utils.NewValidationError("薪资下限不能高于上限", "salary_max")
+	}
+	if !currencyPattern.MatchString(job.SalaryCurrency) {
+		return utils.NewValidationError("币种必须是三位大写字母代码，如 CNY", "salary_currency")
+	}
+	if _, ok := models.MonthlySalaryFactors[job.SalaryPeriod]; !ok {
+		return utils.NewValidationError("薪资计算周期必须是 hour、day、month 或 year", "salary_period")
+	}
+	return nil
+}
+
+// GetJobs 按状态获取职位列表，status 为空时返回开放职位
+func (s *JobService) GetJobs(ctx context.Context, status string, page, pageSize int) ([]models.Job, int64, error) {
 	var jobs []models.Job
 	var total int64
 
+	if status == "" {
+		status = models.JobOpen
+	}
 	query := s.db.WithContext(ctx).Model(&models.Job{}).
-		Where("status = 'open'").
+		Where("status = ?", status).
 		Order("created_at DESC")
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := query.Offset((page - 1) * pageSize).
+	err := query.Preload("Skills").
+		Offset((page - 1) * pageSize).
 		Limit(pageSize).
 		Find(&jobs).Error
 
 	return jobs, total, err
 }
 
+// ApplyInput 投递申请时附带的简历和求职信
+type ApplyInput struct {
+	ResumeID      *uint  `json:"resume_id"`      // 为空时附带本人当前简历
+	ResumeVersion *int   `json:"resume_version"` // 为空时使用简历的最新版本
+	CoverLetter   string `json:"cover_letter" binding:"max=5000"`
+}
+
 // ApplyForJob 申请职位
-func (s *JobService) ApplyForJob(ctx context.Context, userID, jobID uint) error {
+func (s *JobService) ApplyForJob(ctx context.Context, userID, jobID uint, input ApplyInput) error {
 	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var job models.Job
 		if err := tx.First(&job, jobID).Error; err != nil {
-			return err // 职位不存在
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewNotFoundError("职位不存在", "job")
+			}
+			return err
+		}
+		if job.Status != models.JobOpen {
+			return utils.NewValidationError("该职位已关闭申请", "job")
 		}
-		if job.Status != "open" {
-			return errors.New("该职位已关闭申请")
+		// 过期检查任务运行前也不接受申请
+		if job.ExpirationDate != nil && !job.ExpirationDate.After(time.Now()) {
+			return utils.NewValidationError("该职位已过申请截止日期", "job")
 		}
 		var count int64
 		if err := tx.Model(&models.Application{}).
@@ -78,14 +274,35 @@ func (s *JobService) ApplyForJob(ctx context.Context, userID, jobID uint) error
 			return err
 		}
 		if count > 0 {
-			return errors.New("已申请过该职位")
+			return utils.NewValidationError("已申请过该职位", "job")
+		}
+		resumeID, versionID, err := resolveResume(tx, userID, input.ResumeID, input.ResumeVersion)
+		if err != nil {
+			return err
 		}
+		stages, err := ensurePipeline(tx, jobID)
+		if err != nil {
+			return err
+		}
+		first := firstActiveStage(stages)
 		application := models.Application{
-			UserID: userID,
-			JobID:  jobID,
-			Status: "pending",
+			UserID:          userID,
+			JobID:           jobID,
+			Status:          stageStatus(stages, first),
+			StageID:         &first.ID,
+			ResumeID:        resumeID,
+			ResumeVersionID: versionID,
+			CoverLetter:     input.CoverLetter,
+		}
+		if err := tx.Create(&application).Error; err != nil {
+			return err
 		}
-		return tx.Create(&application).Error
+		return tx.Create(&models.ApplicationStageHistory{
+			ApplicationID: application.ID,
+			ToStageID:     first.ID,
+			MovedBy:       userID,
+			Reason:        "投递申请",
+		}).Error
 	})
 }
 
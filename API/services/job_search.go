@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"API/models"
+	"API/utils"
+
+	"gorm.io/gorm"
+)
+
+// JobSearchQuery 公开职位搜索条件
+type JobSearchQuery struct {
+	Keyword    string   `form:"q" binding:"max=100"`
+	Category   string   `form:"category"`
+	Location   string   `form:"location"`
+	Experience *uint    `form:"experience"` // 求职者工作年限，仅返回要求不超过该年限的职位
+	SalaryMin  *float64 `form:"salary_min" binding:"omitempty,gte=0"`
+	SalaryMax  *float64 `form:"salary_max" binding:"omitempty,gte=0"`
+	Currency   string   `form:"currency"`                                        // 薪资币种，默认 CNY
+	Sort       string   `form:"sort" binding:"omitempty,oneof=relevance recent"` // 有关键词时默认 relevance，否则 recent
+	Page       int      `form:"-"`
+	Size       int      `form:"-"`
+}
+
+// JobSearchHit 搜索结果中的职位及其相关度
+type JobSearchHit struct {
+	models.Job
+	Relevance float64 `json:"relevance"`
+}
+
+// FacetCount 分面统计项
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// JobSearchResult 公开职位搜索结果
+type JobSearchResult struct {
+	Jobs   []JobSearchHit          `json:"jobs"`
+	Total  int64                   `json:"total"`
+	Page   int                     `json:"page"`
+	Size   int                     `json:"size"`
+	Facets map[string][]FacetCount `json:"facets"` // category、location 各取值的职位数
+}
+
+// SearchJobs 搜索开放且未过期的职位。MySQL 使用全文索引按相关度排序，
+// 其他数据库退化为 LIKE 匹配；分面统计不受自身维度筛选的影响，便于切换筛选项
+func (s *JobService) SearchJobs(ctx context.Context, query JobSearchQuery) (*JobSearchResult, error) {
+	query.Keyword = strings.TrimSpace(query.Keyword)
+	order := query.Sort
+	if order == "" && query.Keyword != "" {
+		order = "relevance"
+	}
+
+	db := s.db.WithContext(ctx)
+	fulltext := db.Dialector.Name() == "mysql"
+
+	var total int64
+	if err := s.searchScope(db, query, fulltext, "").Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("统计职位失败: %w", err)
+	}
+
+	relevance, args := relevanceExpr(query.Keyword, fulltext, likeEscape(db))
+	search := s.searchScope(db, query, fulltext, "").
+		Select("jobs.*, "+relevance+" AS relevance", args...)
+	if order == "relevance" {
+		search = search.Order("relevance DESC")
+	}
+	hits := []JobSearchHit{}
+	if err := search.Order("jobs.created_at DESC").
+		Offset((query.Page - 1) * query.Size).
+		Limit(query.Size).
+		Find(&hits).Error; err != nil {
+		return nil, fmt.Errorf("搜索职位失败: %w", err)
+	}
+
+	facets := make(map[string][]FacetCount, 2)
+	for _, dimension := range []string{"category", "location"} {
+		counts := []FacetCount{}
+		if err := s.searchScope(db, query, fulltext, dimension).
+			Select("jobs." + dimension + " AS value, COUNT(*) AS count").
+			Where("jobs." + dimension + " <> ''").
+			Group("jobs." + dimension).
+			Order("count DESC").
+			Scan(&counts).Error; err != nil {
+			return nil, fmt.Errorf("统计分面失败: %w", err)
+		}
+		facets[dimension] = counts
+	}
+
+	return &JobSearchResult{Jobs: hits, Total: total, Page: query.Page, Size: query.Size, Facets: facets}, nil
+}
+
+// GetPublicJob 获取开放且未过期的职位详情
+func (s *JobService) GetPublicJob(ctx context.Context, jobID uint) (*models.Job, error) {
+	var job models.Job
+	err := s.db.WithContext(ctx).Preload("Skills").
+		Where("status = ? AND (expiration_date IS NULL OR expiration_date > ?)", models.JobOpen, time.Now()).
+		First(&job, jobID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("职位不存在或已关闭", "job")
+		}
+		return nil, fmt.Errorf("查询职位失败: %w", err)
+	}
+	return &job, nil
+}
+
+// searchScope 构建筛选条件，skip 为分面统计时忽略的筛选维度
+func (s *JobService) searchScope(db *gorm.DB, query JobSearchQuery, fulltext bool, skip string) *gorm.DB {
+	scope := db.Model(&models.Job{}).
+		Where("jobs.status = ? AND (jobs.expiration_date IS NULL OR jobs.expiration_date > ?)", models.JobOpen, time.Now())
+
+	if query.Keyword != "" {
+		if fulltext {
+			scope = scope.Where("MATCH(jobs.title, jobs.description, jobs.requirements) AGAINST (? IN NATURAL LANGUAGE MODE)", query.Keyword)
+		} else {
+			like, escape := "%"+escapeLike(query.Keyword)+"%", likeEscape(db)
+			scope = scope.Where(fmt.Sprintf("(jobs.title LIKE ? %[1]s OR jobs.description LIKE ? %[1]s OR jobs.requirements LIKE ? %[1]s)", escape), like, like, like)
+		}
+	}
+	if query.Category != "" && skip != "category" {
+		scope = scope.Where("jobs.category = ?", query.Category)
+	}
+	if query.Location != "" && skip != "location" {
+		scope = scope.Where("jobs.location = ?", query.Location)
+	}
+	if query.Experience != nil {
+		scope = scope.Where("jobs.experience <= ?", *query.Experience)
+	}
+	// 折算为月薪后区间有交集即匹配，未填写薪资或币种不同的职位不参与薪资筛选
+	if query.SalaryMin != nil || query.SalaryMax != nil {
+		currency := strings.ToUpper(query.Currency)
+		if currency == "" {
+			currency = "CNY"
+		}
+		scope = scope.Where("jobs.salary_currency = ?", currency)
+	}
+	if query.SalaryMin != nil {
+		scope = scope.Where("jobs.salary_max * "+monthlyFactorExpr+" >= ?", *query.SalaryMin)
+	}
+	if query.SalaryMax != nil {
+		scope = scope.Where("jobs.salary_min * "+monthlyFactorExpr+" <= ?", *query.SalaryMax)
+	}
+	return scope
+}
+
+// monthlyFactorExpr 按薪资计算周期折算为月薪的系数表达式
+var monthlyFactorExpr = func() string {
+	periods := make([]string, 0, len(models.MonthlySalaryFactors))
+	for period := range models.MonthlySalaryFactors {
+		periods = append(periods, period)
+	}
+	sort.Strings(periods)
+	var sb strings.Builder
+	sb.WriteString("(CASE jobs.salary_period")
+	for _, period := range periods {
+		fmt.Fprintf(&sb, " WHEN '%s' THEN %g", period, models.MonthlySalaryFactors[period])
+	}
+	sb.WriteString(" ELSE 1 END)")
+	return sb.String()
+}()
+
+// relevanceExpr 返回相关度表达式；LIKE 模式下按命中字段加权，标题权重最高，escape 为 likeEscape 返回的子句
+func relevanceExpr(keyword string, fulltext bool, escape string) (string, []interface{}) {
+	if keyword == "" {
+		return "0", nil
+	}
+	if fulltext {
+		return "MATCH(jobs.title, jobs.description, jobs.requirements) AGAINST (? IN NATURAL LANGUAGE MODE)", []interface{}{keyword}
+	}
+	like := "%" + escapeLike(keyword) + "%"
+	return fmt.Sprintf("(CASE WHEN jobs.title LIKE ? %[1]s THEN 3 ELSE 0 END + CASE WHEN jobs.requirements LIKE ? %[1]s THEN 2 ELSE 0 END"+
+		" + CASE WHEN jobs.description LIKE ? %[1]s THEN 1 ELSE 0 END)", escape), []interface{}{like, like, like}
+}
+
+// escapeLike 转义 LIKE 通配符，须与 likeEscape 返回的 ESCAPE 子句一起使用
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// likeEscape 返回指定反斜杠为转义字符的 ESCAPE 子句。SQLite 等没有默认转义字符，
+// MySQL 字符串字面量中的反斜杠本身需要转义
+func likeEscape(db *gorm.DB) string {
+	if db.Dialector.Name() == "mysql" {
+		return `ESCAPE '\\'`
+	}
+	return `ESCAPE '\'`
+}
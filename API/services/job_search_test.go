@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"API/models"
+)
+
+func TestEscapeLike(t *testing.T) {
+	tests := map[string]string{
+		"golang":     "golang",
+		"100%":       `100\%`,
+		"snake_case": `snake\_case`,
+		`C:\dir`:     `C:\\dir`,
+		`%_\`:        `\%\_\\`,
+		"前端开发":       "前端开发",
+	}
+	for input, want := range tests {
+		if got := escapeLike(input); got != want {
+			t.Errorf("escapeLike(%q) = %q, 期望 %q", input, got, want)
+		}
+	}
+}
+
+func TestRelevanceExpr(t *testing.T) {
+	const escape = `ESCAPE '\'`
+	expr, args := relevanceExpr("", false, escape)
+	if expr != "0" || args != nil {
+		t.Errorf("无关键词时应返回常量 0，得到 %q %v", expr, args)
+	}
+
+	expr, args = relevanceExpr("go_lang", true, escape)
+	if !strings.HasPrefix(expr, "MATCH(jobs.title, jobs.description, jobs.requirements) AGAINST") {
+		t.Errorf("全文模式表达式错误: %s", expr)
+	}
+	if len(args) != 1 || args[0] != "go_lang" {
+		t.Errorf("全文模式应直接使用关键词，得到 %v", args)
+	}
+
+	expr, args = relevanceExpr("go_lang", false, escape)
+	if strings.Count(expr, "?") != len(args) || len(args) != 3 {
+		t.Fatalf("LIKE 模式的占位符与参数数量不一致: %s %v", expr, args)
+	}
+	for _, arg := range args {
+		if arg != `%go\_lang%` {
+			t.Errorf("LIKE 参数应转义通配符，得到 %v", arg)
+		}
+	}
+	title := strings.Index(expr, "jobs.title LIKE ? "+escape+" THEN 3")
+	requirements := strings.Index(expr, "jobs.requirements LIKE ? "+escape+" THEN 2")
+	description := strings.Index(expr, "jobs.description LIKE ? "+escape+" THEN 1")
+	if title < 0 || requirements < 0 || description < 0 {
+		t.Errorf("LIKE 模式应按标题、要求、描述加权: %s", expr)
+	}
+}
+
+// TestSearchJobsLikeFallback 非 MySQL 数据库使用 LIKE 匹配，关键词中的通配符按字面匹配
+func TestSearchJobsLikeFallback(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &models.Job{})
+	svc := NewJobService(db)
+	expired := time.Now().Add(-time.Hour)
+	jobs := []models.Job{
+		{Title: "Go 工程师", Description: "负责后端服务", Requirements: "熟悉 snake_case 命名", Category: "研发", Location: "上海", Status: models.JobOpen},
+		{Title: "snakeXcase 测试", Description: "自动化测试", Requirements: "无", Category: "测试", Location: "北京", Status: models.JobOpen},
+		{Title: "增长 100% 运营", Description: "用户增长", Requirements: "无", Category: "运营", Location: "上海", Status: models.JobOpen},
+		{Title: "1000 人团队负责人", Description: "管理", Requirements: "无", Category: "管理", Location: "上海", Status: models.JobOpen},
+		{Title: "Windows 运维", Description: `维护 C:\tools 目录`, Requirements: "无", Category: "运维", Location: "北京", Status: models.JobOpen},
+		{Title: "Go 实习生", Description: "已关闭", Requirements: "无", Category: "研发", Location: "上海", Status: models.JobClosed},
+		{Title: "Go 架构师", Description: "已过期", Requirements: "无", Category: "研发", Location: "上海", Status: models.JobOpen, ExpirationDate: &expired},
+	}
+	if err := db.Create(&jobs).Error; err != nil {
+		t.Fatalf("创建职位失败: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		query  JobSearchQuery
+		titles []string
+	}{
+		{"下划线按字面匹配", JobSearchQuery{Keyword: "snake_case"}, []string{"Go 工程师"}},
+		{"百分号按字面匹配", JobSearchQuery{Keyword: "100%"}, []string{"增长 100% 运营"}},
+		{"反斜杠按字面匹配", JobSearchQuery{Keyword: `C:\tools`}, []string{"Windows 运维"}},
+		{"只返回开放且未过期的职位", JobSearchQuery{Keyword: "Go"}, []string{"Go 工程师"}},
+		{"标题命中优先", JobSearchQuery{Keyword: "snake"}, []string{"snakeXcase 测试", "Go 工程师"}},
+		{"关键词与地点同时筛选", JobSearchQuery{Keyword: "运", Location: "北京"}, []string{"Windows 运维"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Page, tt.query.Size = 1, 10
+			result, err := svc.SearchJobs(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("SearchJobs: %v", err)
+			}
+			titles := []string{}
+			for _, hit := range result.Jobs {
+				titles = append(titles, hit.Title)
+			}
+			if !reflect.DeepEqual(titles, tt.titles) || result.Total != int64(len(tt.titles)) {
+				t.Errorf("结果为 %v（共 %d 个），期望 %v", titles, result.Total, tt.titles)
+			}
+		})
+	}
+
+	// 分面统计不受自身维度筛选的影响
+	result, err := svc.SearchJobs(ctx, JobSearchQuery{Keyword: "运", Location: "北京", Page: 1, Size: 10})
+	if err != nil {
+		t.Fatalf("SearchJobs: %v", err)
+	}
+	want := []FacetCount{{Value: "上海", Count: 1}, {Value: "北京", Count: 1}}
+	locations := result.Facets["location"]
+	sort.Slice(locations, func(i, j int) bool { return locations[i].Value < locations[j].Value })
+	if !reflect.DeepEqual(locations, want) {
+		t.Errorf("地点分面为 %v，期望 %v", locations, want)
+	}
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"API/models"
+)
+
+func TestValidateSalary(t *testing.T) {
+	tests := []struct {
+		name    string
+		job     models.Job
+		wantErr string
+	}{
+		{"未填写薪资", models.Job{SalaryCurrency: "CNY", SalaryPeriod: models.SalaryPerMonth}, ""},
+		{"完整的薪资区间", models.Job{SalaryMin: floatPtr(10000), SalaryMax: floatPtr(20000), SalaryCurrency: "USD", SalaryPeriod: models.SalaryPerYear}, ""},
+		{"上下限相同", models.Job{SalaryMin: floatPtr(200), SalaryMax: floatPtr(200), SalaryCurrency: "CNY", SalaryPeriod: models.SalaryPerDay}, ""},
+		{"下限为负数", models.Job{SalaryMin: floatPtr(-1), SalaryCurrency: "CNY", SalaryPeriod: models.SalaryPerMonth}, "薪资不能为负数"},
+		{"上限为负数", models.Job{SalaryMax: floatPtr(-1), SalaryCurrency: "CNY", SalaryPeriod: models.SalaryPerMonth}, "薪资不能为负数"},
+		{"下限高于上限", models.Job{SalaryMin: floatPtr(30000), SalaryMax: floatPtr(20000), SalaryCurrency: "CNY", SalaryPeriod: models.SalaryPerMonth}, "薪资下限不能高于上限"},
+		{"币种小写", models.Job{SalaryCurrency: "cny", SalaryPeriod: models.SalaryPerMonth}, "币种必须是三位大写字母代码，如 CNY"},
+		{"币种位数错误", models.Job{SalaryCurrency: "RMBY", SalaryPeriod: models.SalaryPerMonth}, "币种必须是三位大写字母代码，如 CNY"},
+		{"未知的计算周期", models.Job{SalaryCurrency: "CNY", SalaryPeriod: "week"}, "薪资计算周期必须是 hour、day、month 或 year"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateSalary(&tt.job); validationMessage(err) != tt.wantErr {
+				t.Errorf("期望错误 %q，得到 %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestJobExpiration(t *testing.T) {
+	ctx := context.Background()
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(24*time.Hour)
+	setup := func(t *testing.T) (*JobService, map[string]models.Job) {
+		db := newTestDB(t, &models.Job{}, &models.PipelineStage{}, &models.Application{},
+			&models.ApplicationStageHistory{}, &models.Resume{})
+		jobs := map[string]models.Job{
+			"开放":     {Title: "开放", Status: models.JobOpen, ExpirationDate: &future},
+			"长期开放":   {Title: "长期开放", Status: models.JobOpen},
+			"已过期未关闭": {Title: "已过期未关闭", Status: models.JobOpen, ExpirationDate: &past},
+			"已关闭":    {Title: "已关闭", Status: models.JobClosed, ExpirationDate: &past},
+			"草稿":     {Title: "草稿", Status: models.JobDraft},
+		}
+		for name, job := range jobs {
+			if err := db.Create(&job).Error; err != nil {
+				t.Fatalf("创建职位失败: %v", err)
+			}
+			jobs[name] = job
+		}
+		return NewJobService(db), jobs
+	}
+
+	t.Run("申请", func(t *testing.T) {
+		svc, jobs := setup(t)
+		tests := map[string]string{
+			"开放":     "",
+			"长期开放":   "",
+			"已过期未关闭": "该职位已过申请截止日期",
+			"已关闭":    "该职位已关闭申请",
+			"草稿":     "该职位已关闭申请",
+		}
+		for name, wantErr := range tests {
+			if err := svc.ApplyForJob(ctx, 7, jobs[name].ID, ApplyInput{}); validationMessage(err) != wantErr {
+				t.Errorf("申请%s职位期望错误 %q，得到 %v", name, wantErr, err)
+			}
+		}
+	})
+
+	t.Run("关闭过期职位", func(t *testing.T) {
+		svc, jobs := setup(t)
+		closed, err := svc.CloseExpiredJobs(ctx)
+		if err != nil {
+			t.Fatalf("CloseExpiredJobs: %v", err)
+		}
+		if closed != 1 {
+			t.Errorf("关闭了 %d 个职位，期望 1", closed)
+		}
+		for name, want := range map[string]string{"开放": models.JobOpen, "长期开放": models.JobOpen, "已过期未关闭": models.JobClosed, "草稿": models.JobDraft} {
+			var job models.Job
+			svc.db.First(&job, jobs[name].ID)
+			if job.Status != want {
+				t.Errorf("%s职位状态为 %s，期望 %s", name, job.Status, want)
+			}
+		}
+	})
+
+	t.Run("重新开放", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			job        string
+			expiration time.Time
+			wantErr    string
+		}{
+			{"已关闭的职位", "已关闭", future, ""},
+			{"已过期未关闭的职位", "已过期未关闭", future, ""},
+			{"新截止日期已过", "已关闭", past, "截止日期必须晚于当前时间"},
+			{"正在开放", "开放", future, "职位正在开放中"},
+			{"尚未发布", "草稿", future, "职位尚未发布，需审批通过后发布"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				svc, jobs := setup(t)
+				job, err := svc.ReopenJob(ctx, jobs[tt.job].ID, tt.expiration)
+				if validationMessage(err) != tt.wantErr {
+					t.Fatalf("期望错误 %q，得到 %v", tt.wantErr, err)
+				}
+				if tt.wantErr == "" && (job.Status != models.JobOpen || !job.ExpirationDate.Equal(tt.expiration)) {
+					t.Errorf("重新开放后为 %s %v", job.Status, job.ExpirationDate)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,514 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"API/models"
+	"API/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LeaveService 假期额度、请假申请与审批
+type LeaveService struct {
+	db       *gorm.DB
+	workdays *WorkdayCalculator
+}
+
+func NewLeaveService(db *gorm.DB, workdays *WorkdayCalculator) *LeaveService {
+	return &LeaveService{db: db, workdays: workdays}
+}
+
+// LeaveTypeInput 修改假期类型额度规则的参数，额度单位为天
+type LeaveTypeInput struct {
+	Name            string  `json:"name" binding:"required,max=50"`
+	Paid            bool    `json:"paid"`
+	RequiresBalance bool    `json:"requires_balance"`
+	MonthlyAccrual  float64 `json:"monthly_accrual" binding:"gte=0,lte=31"`
+	MaxBalance      float64 `json:"max_balance" binding:"gte=0,lte=366"`    // 0表示不限
+	MaxCarryOver    float64 `json:"max_carry_over" binding:"gte=0,lte=366"` // 可结转至次年的天数上限
+}
+
+// LeaveRequestInput 提交请假申请的参数，日期格式为 YYYY-MM-DD
+type LeaveRequestInput struct {
+	LeaveType string `json:"leave_type" binding:"required"`
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+	Reason    string `json:"reason" binding:"max=500"`
+}
+
+// LeaveBalanceSummary 某类假期的年度额度
+type LeaveBalanceSummary struct {
+	LeaveType       string  `json:"leave_type"`
+	Name            string  `json:"name"`
+	Year            int     `json:"year"`
+	RequiresBalance bool    `json:"requires_balance"`
+	CarriedOver     float64 `json:"carried_over"`
+	Accrued         float64 `json:"accrued"`
+	Used            float64 `json:"used"`
+	Pending         float64 `json:"pending"`   // 待审批占用的天数
+	Available       float64 `json:"available"` // 扣除待审批后的可用天数
+}
+
+// ListTypes 获取全部假期类型
+func (s *LeaveService) ListTypes(ctx context.Context) ([]models.LeaveType, error) {
+	var types []models.LeaveType
+	if err := s.db.WithContext(ctx).Order("id ASC").Find(&types).Error; err != nil {
+		return nil, fmt.Errorf("查询假期类型失败: %w", err)
+	}
+	return types, nil
+}
+
+// UpdateType 修改假期类型的额度规则，已累计的额度不受影响
+func (s *LeaveService) UpdateType(ctx context.Context, typeID uint, input LeaveTypeInput) (*models.LeaveType, error) {
+	var leaveType models.LeaveType
+	if err := s.db.WithContext(ctx).First(&leaveType, typeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("假期类型不存在", "leave_type")
+		}
+		return nil, fmt.Errorf("查询假期类型失败: %w", err)
+	}
+	leaveType.Name = strings.TrimSpace(input.Name)
+	leaveType.Paid = input.Paid
+	leaveType.RequiresBalance = input.RequiresBalance
+	leaveType.MonthlyAccrual = input.MonthlyAccrual
+	leaveType.MaxBalance = input.MaxBalance
+	leaveType.MaxCarryOver = input.MaxCarryOver
+	if err := s.db.WithContext(ctx).Save(&leaveType).Error; err != nil {
+		return nil, fmt.Errorf("更新假期类型失败: %w", err)
+	}
+	return &leaveType, nil
+}
+
+// GetBalances 获取员工某年度各类假期的额度，按月累计至当前月份
+func (s *LeaveService) GetBalances(ctx context.Context, userID uint, year int) ([]LeaveBalanceSummary, error) {
+	var summaries []LeaveBalanceSummary
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewNotFoundError("员工不存在", "user")
+			}
+			return fmt.Errorf("查询员工失败: %w", err)
+		}
+		var types []models.LeaveType
+		if err := tx.Order("id ASC").Find(&types).Error; err != nil {
+			return fmt.Errorf("查询假期类型失败: %w", err)
+		}
+		for _, leaveType := range types {
+			summary := LeaveBalanceSummary{
+				LeaveType:       leaveType.Code,
+				Name:            leaveType.Name,
+				Year:            year,
+				RequiresBalance: leaveType.RequiresBalance,
+			}
+			balance, err := ensureLeaveBalance(tx, user, leaveType, year, time.Now())
+			if err != nil {
+				return err
+			}
+			pending, err := pendingLeaveDays(tx, userID, leaveType.ID, year)
+			if err != nil {
+				return err
+			}
+			summary.CarriedOver = balance.CarriedOver
+			summary.Accrued = balance.Accrued
+			summary.Used = balance.Used
+			summary.Pending = pending
+			summary.Available = round2(balance.Available() - pending)
+			summaries = append(summaries, summary)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// Submit 提交请假申请。按员工班次和节假日日历计算工作日天数，不能与进行中或已批准的申请重叠，
+// 占用额度的假期需有足够的可用天数（已扣除待审批的申请）
+func (s *LeaveService) Submit(ctx context.Context, userID uint, input LeaveRequestInput) (*models.LeaveRequest, error) {
+	start, err := time.ParseInLocation("2006-01-02", input.StartDate, time.Local)
+	if err != nil {
+		return nil, utils.NewValidationError("开始日期格式应为 YYYY-MM-DD", "start_date")
+	}
+	end, err := time.ParseInLocation("2006-01-02", input.EndDate, time.Local)
+	if err != nil {
+		return nil, utils.NewValidationError("结束日期格式应为 YYYY-MM-DD", "end_date")
+	}
+	if end.Before(start) {
+		return nil, utils.NewValidationError("结束日期不能早于开始日期", "end_date")
+	}
+	if end.Year() != start.Year() {
+		return nil, utils.NewValidationError("跨年度的请假请按年度分别申请", "end_date")
+	}
+
+	var request models.LeaveRequest
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 锁定员工，避免并发提交绕过重叠和额度校验
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return fmt.Errorf("查询员工失败: %w", err)
+		}
+		var leaveType models.LeaveType
+		if err := tx.Where("code = ?", input.LeaveType).First(&leaveType).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewValidationError("假期类型不存在", "leave_type")
+			}
+			return fmt.Errorf("查询假期类型失败: %w", err)
+		}
+
+		var overlapping int64
+		if err := tx.Model(&models.LeaveRequest{}).
+			Where("user_id = ? AND status IN ? AND start_date <= ? AND end_date >= ?",
+				userID, []string{models.LeavePending, models.LeaveApproved}, end, start).
+			Count(&overlapping).Error; err != nil {
+			return fmt.Errorf("查询请假申请失败: %w", err)
+		}
+		if overlapping > 0 {
+			return utils.NewValidationError("与已有的请假申请时间重叠", "start_date")
+		}
+
+		days, err := s.countLeaveDays(tx, userID, start, end)
+		if err != nil {
+			return err
+		}
+		if days == 0 {
+			return utils.NewValidationError("所选日期均为非工作日", "start_date")
+		}
+
+		if leaveType.RequiresBalance {
+			balance, err := ensureLeaveBalance(tx, user, leaveType, start.Year(), time.Now())
+			if err != nil {
+				return err
+			}
+			pending, err := pendingLeaveDays(tx, userID, leaveType.ID, start.Year())
+			if err != nil {
+				return err
+			}
+			if available := round2(balance.Available() - pending); available < days {
+				return utils.NewValidationError(fmt.Sprintf("%s余额不足，可用 %.2f 天，本次需 %.2f 天", leaveType.Name, available, days), "leave_type")
+			}
+		}
+
+		request = models.LeaveRequest{
+			UserID:      userID,
+			LeaveTypeID: leaveType.ID,
+			StartDate:   start,
+			EndDate:     end,
+			Days:        days,
+			Reason:      input.Reason,
+			Status:      models.LeavePending,
+			LeaveType:   leaveType,
+		}
+		return tx.Create(&request).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// Approve 审批通过请假申请并扣减额度
+func (s *LeaveService) Approve(ctx context.Context, requestID, approverID uint, comment string) (*models.LeaveRequest, error) {
+	return s.decide(ctx, requestID, approverID, models.LeaveApproved, comment, func(tx *gorm.DB, request *models.LeaveRequest) error {
+		if !request.LeaveType.RequiresBalance {
+			return nil
+		}
+		balance, err := ensureLeaveBalance(tx, request.User, request.LeaveType, request.StartDate.Year(), time.Now())
+		if err != nil {
+			return err
+		}
+		if available := round2(balance.Available()); available < request.Days {
+			return utils.NewValidationError(fmt.Sprintf("%s余额不足，可用 %.2f 天", request.LeaveType.Name, available), "leave_type")
+		}
+		return tx.Model(balance).Update("used", gorm.Expr("used + ?", request.Days)).Error
+	})
+}
+
+// Reject 驳回请假申请，需填写驳回意见
+func (s *LeaveService) Reject(ctx context.Context, requestID, approverID uint, comment string) (*models.LeaveRequest, error) {
+	if strings.TrimSpace(comment) == "" {
+		return nil, utils.NewValidationError("驳回时需填写审批意见", "comment")
+	}
+	return s.decide(ctx, requestID, approverID, models.LeaveRejected, comment, nil)
+}
+
+// decide 校验审批人并更新申请状态，apply 在状态更新前执行
+func (s *LeaveService) decide(ctx context.Context, requestID, approverID uint, status, comment string,
+	apply func(tx *gorm.DB, request *models.LeaveRequest) error) (*models.LeaveRequest, error) {
+	var request models.LeaveRequest
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("User").
+			Preload("LeaveType").
+			First(&request, requestID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewNotFoundError("请假申请不存在", "leave_request")
+			}
+			return fmt.Errorf("查询请假申请失败: %w", err)
+		}
+		if request.Status != models.LeavePending {
+			return utils.NewValidationError("请假申请已处理", "leave_request")
+		}
+		if request.UserID == approverID {
+			return utils.NewForbiddenError("不能审批自己的请假申请")
+		}
+		allowed, err := canApproveEmployee(tx, approverID, request.User.Department)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return utils.NewForbiddenError("仅员工所在部门的负责人或人事可以审批")
+		}
+
+		if apply != nil {
+			if err := apply(tx, &request); err != nil {
+				return err
+			}
+		}
+		now := time.Now()
+		request.Status = status
+		request.ApproverID = &approverID
+		request.Comment = comment
+		request.DecidedAt = &now
+		return tx.Model(&request).Updates(map[string]interface{}{
+			"status":      status,
+			"approver_id": approverID,
+			"comment":     comment,
+			"decided_at":  now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// Cancel 员工撤销本人的请假申请：待审批的可随时撤销，已批准的仅在开始日期之前可撤销并退回额度
+func (s *LeaveService) Cancel(ctx context.Context, requestID, userID uint) (*models.LeaveRequest, error) {
+	var request models.LeaveRequest
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("User").
+			Preload("LeaveType").
+			Where("user_id = ?", userID).
+			First(&request, requestID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewNotFoundError("请假申请不存在", "leave_request")
+			}
+			return fmt.Errorf("查询请假申请失败: %w", err)
+		}
+		switch request.Status {
+		case models.LeavePending:
+		case models.LeaveApproved:
+			if !request.StartDate.After(time.Now()) {
+				return utils.NewValidationError("假期已开始，不能撤销", "leave_request")
+			}
+			if request.LeaveType.RequiresBalance {
+				balance, err := ensureLeaveBalance(tx, request.User, request.LeaveType, request.StartDate.Year(), time.Now())
+				if err != nil {
+					return err
+				}
+				if err := tx.Model(balance).Update("used", gorm.Expr("used - ?", request.Days)).Error; err != nil {
+					return fmt.Errorf("退回假期额度失败: %w", err)
+				}
+			}
+		default:
+			return utils.NewValidationError("请假申请已结束，不能撤销", "leave_request")
+		}
+		request.Status = models.LeaveCanceled
+		return tx.Model(&request).Update("status", request.Status).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// ListMyRequests 获取员工本人的请假申请，可按状态筛选
+func (s *LeaveService) ListMyRequests(ctx context.Context, userID uint, status string) ([]models.LeaveRequest, error) {
+	query := s.db.WithContext(ctx).Preload("LeaveType").Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var requests []models.LeaveRequest
+	if err := query.Order("start_date DESC").Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("查询请假申请失败: %w", err)
+	}
+	return requests, nil
+}
+
+// ListPendingForApprover 获取待该用户审批的请假申请：人事可审批全部员工，部门负责人仅限本部门
+func (s *LeaveService) ListPendingForApprover(ctx context.Context, approverID uint) ([]models.LeaveRequest, error) {
+	db := s.db.WithContext(ctx)
+	department, ok, err := approvalDepartment(db, approverID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []models.LeaveRequest{}, nil
+	}
+	query := db.Preload("User").Preload("LeaveType").
+		Joins("JOIN users ON users.id = leave_requests.user_id").
+		Where("leave_requests.status = ? AND leave_requests.user_id <> ?", models.LeavePending, approverID)
+	if department != "" {
+		query = query.Where("users.department = ?", department)
+	}
+
+	var requests []models.LeaveRequest
+	if err := query.Order("leave_requests.start_date ASC").Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("查询请假申请失败: %w", err)
+	}
+	return requests, nil
+}
+
+// countLeaveDays 统计请假期间员工的工作日天数，节假日不计入
+func (s *LeaveService) countLeaveDays(tx *gorm.DB, userID uint, start, end time.Time) (float64, error) {
+	days, err := s.workdays.CountWorkingDays(tx, userID, start, end)
+	if err != nil {
+		return 0, err
+	}
+	return float64(days), nil
+}
+
+// canApproveEmployee 判断能否审批员工的请假、加班等申请：人事和管理员可审批全部员工，部门负责人可审批本部门员工；department 为空时仅判断前者
+func canApproveEmployee(tx *gorm.DB, userID uint, department string) (bool, error) {
+	for _, role := range []string{models.RoleHR, models.RoleAdmin} {
+		ok, err := canApprove(tx, userID, role, "")
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	if department == "" {
+		return false, nil
+	}
+	return canApprove(tx, userID, models.RoleDepartmentHead, department)
+}
+
+// approvalDepartment 确定审批人可审批的员工范围：人事和管理员返回空部门表示全部员工，
+// 部门负责人返回其所在部门，均不是时 ok 为 false
+func approvalDepartment(tx *gorm.DB, approverID uint) (department string, ok bool, err error) {
+	all, err := canApproveEmployee(tx, approverID, "")
+	if err != nil || all {
+		return "", all, err
+	}
+	var approver models.User
+	if err := tx.First(&approver, approverID).Error; err != nil {
+		return "", false, fmt.Errorf("查询用户失败: %w", err)
+	}
+	if approver.Department == "" {
+		return "", false, nil
+	}
+	head, err := canApprove(tx, approverID, models.RoleDepartmentHead, approver.Department)
+	if err != nil || !head {
+		return "", false, err
+	}
+	return approver.Department, true, nil
+}
+
+// pendingLeaveDays 统计某年度待审批申请占用的天数
+func pendingLeaveDays(tx *gorm.DB, userID, leaveTypeID uint, year int) (float64, error) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	var pending float64
+	if err := tx.Model(&models.LeaveRequest{}).
+		Select("COALESCE(SUM(days), 0)").
+		Where("user_id = ? AND leave_type_id = ? AND status = ? AND start_date >= ? AND start_date < ?",
+			userID, leaveTypeID, models.LeavePending, start, start.AddDate(1, 0, 0)).
+		Scan(&pending).Error; err != nil {
+		return 0, fmt.Errorf("统计待审批请假失败: %w", err)
+	}
+	return pending, nil
+}
+
+// ensureLeaveBalance 锁定并返回员工某年度的假期额度，不存在时创建并结转上年余额，
+// 随后按月补足累计额度。入职当年从入职月份开始累计
+func ensureLeaveBalance(tx *gorm.DB, user models.User, leaveType models.LeaveType, year int, now time.Time) (*models.LeaveBalance, error) {
+	var balance models.LeaveBalance
+	query := func(year int) *gorm.DB {
+		return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND leave_type_id = ? AND year = ?", user.ID, leaveType.ID, year)
+	}
+	err := query(year).First(&balance).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询假期额度失败: %w", err)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		balance = models.LeaveBalance{UserID: user.ID, LeaveTypeID: leaveType.ID, Year: year}
+		if user.HireDate != nil && user.HireDate.Year() >= year {
+			balance.AccruedMonth = 12
+			if user.HireDate.Year() == year {
+				balance.AccruedMonth = int(user.HireDate.Month()) - 1
+			}
+		}
+
+		// 结转上年余额，上年额度先补足全年累计。额度按需创建，上年已入职但未建立额度时先建立上年额度，
+		// 逐年向前直到入职年度
+		var previous *models.LeaveBalance
+		var last models.LeaveBalance
+		err := query(year - 1).First(&last).Error
+		switch {
+		case err == nil:
+			previous = &last
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, fmt.Errorf("查询上年假期额度失败: %w", err)
+		case user.HireDate != nil && user.HireDate.Year() < year:
+			if previous, err = ensureLeaveBalance(tx, user, leaveType, year-1, now); err != nil {
+				return nil, err
+			}
+		}
+		if previous != nil {
+			if err := accrueLeave(tx, previous, leaveType, 12); err != nil {
+				return nil, err
+			}
+			balance.CarriedOver = math.Max(0, math.Min(previous.Available(), leaveType.MaxCarryOver))
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&balance).Error; err != nil {
+			return nil, fmt.Errorf("创建假期额度失败: %w", err)
+		}
+		if err := query(year).First(&balance).Error; err != nil {
+			return nil, fmt.Errorf("查询假期额度失败: %w", err)
+		}
+	}
+
+	month := 0
+	switch {
+	case year < now.Year():
+		month = 12
+	case year == now.Year():
+		month = int(now.Month())
+	}
+	if err := accrueLeave(tx, &balance, leaveType, month); err != nil {
+		return nil, err
+	}
+	balance.LeaveType = leaveType
+	return &balance, nil
+}
+
+// accrueLeave 将额度按月累计至指定月份，不超过额度上限
+func accrueLeave(tx *gorm.DB, balance *models.LeaveBalance, leaveType models.LeaveType, month int) error {
+	if balance.AccruedMonth >= month {
+		return nil
+	}
+	for balance.AccruedMonth < month {
+		amount := leaveType.MonthlyAccrual
+		if leaveType.MaxBalance > 0 {
+			amount = math.Max(0, math.Min(amount, leaveType.MaxBalance-balance.Available()))
+		}
+		balance.Accrued = round2(balance.Accrued + amount)
+		balance.AccruedMonth++
+	}
+	if err := tx.Model(balance).Updates(map[string]interface{}{
+		"accrued":       balance.Accrued,
+		"accrued_month": balance.AccruedMonth,
+	}).Error; err != nil {
+		return fmt.Errorf("累计假期额度失败: %w", err)
+	}
+	return nil
+}
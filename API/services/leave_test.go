@@ -0,0 +1,131 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"API/models"
+)
+
+var annualLeave = models.LeaveType{ID: 1, Code: models.LeaveAnnual, Name: "年假", Paid: true, RequiresBalance: true,
+	MonthlyAccrual: 1.25, MaxBalance: 20, MaxCarryOver: 5}
+
+func testUser(id uint, hireDate *time.Time) models.User {
+	user := models.User{HireDate: hireDate}
+	user.ID = id
+	return user
+}
+
+func TestAccrueLeaveCap(t *testing.T) {
+	db := newTestDB(t, &models.LeaveBalance{})
+	leaveType := annualLeave
+	leaveType.MaxBalance = 5
+
+	tests := []struct {
+		name        string
+		balance     models.LeaveBalance
+		month       int
+		wantAccrued float64
+		wantMonth   int
+	}{
+		{"按月累计", models.LeaveBalance{}, 3, 3.75, 3},
+		{"累计到上限为止", models.LeaveBalance{}, 12, 5, 12},
+		{"结转天数计入上限", models.LeaveBalance{CarriedOver: 3}, 12, 2, 12},
+		{"已用天数释放上限", models.LeaveBalance{CarriedOver: 3, Used: 2}, 12, 4, 12},
+		{"已累计到的月份不重复累计", models.LeaveBalance{Accrued: 2.5, AccruedMonth: 6}, 4, 2.5, 6},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balance := tt.balance
+			balance.UserID, balance.LeaveTypeID, balance.Year = uint(i+1), leaveType.ID, 2025
+			if err := db.Create(&balance).Error; err != nil {
+				t.Fatalf("创建额度失败: %v", err)
+			}
+			if err := accrueLeave(db, &balance, leaveType, tt.month); err != nil {
+				t.Fatalf("accrueLeave: %v", err)
+			}
+			var stored models.LeaveBalance
+			db.First(&stored, balance.ID)
+			if stored.Accrued != tt.wantAccrued || stored.AccruedMonth != tt.wantMonth {
+				t.Errorf("累计 %v 天至 %d 月，期望 %v 天至 %d 月", stored.Accrued, stored.AccruedMonth, tt.wantAccrued, tt.wantMonth)
+			}
+		})
+	}
+}
+
+func TestEnsureLeaveBalanceCarryOver(t *testing.T) {
+	now := time.Date(2025, 6, 10, 9, 0, 0, 0, time.Local)
+	db := newTestDB(t, &models.LeaveBalance{})
+
+	// 上年余额 7 天，结转上限 5 天
+	previous := models.LeaveBalance{UserID: 1, LeaveTypeID: annualLeave.ID, Year: 2024, Accrued: 15, AccruedMonth: 12, Used: 8}
+	if err := db.Create(&previous).Error; err != nil {
+		t.Fatalf("创建上年额度失败: %v", err)
+	}
+	hired := time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local)
+	balance, err := ensureLeaveBalance(db, testUser(1, &hired), annualLeave, 2025, now)
+	if err != nil {
+		t.Fatalf("ensureLeaveBalance: %v", err)
+	}
+	if balance.CarriedOver != 5 || balance.Accrued != 7.5 || balance.AccruedMonth != 6 {
+		t.Errorf("额度为 结转%v 累计%v 至%d月，期望 结转5 累计7.5 至6月", balance.CarriedOver, balance.Accrued, balance.AccruedMonth)
+	}
+
+	// 余额不足上限时全部结转
+	previous = models.LeaveBalance{UserID: 2, LeaveTypeID: annualLeave.ID, Year: 2024, Accrued: 15, AccruedMonth: 12, Used: 13}
+	db.Create(&previous)
+	balance, err = ensureLeaveBalance(db, testUser(2, &hired), annualLeave, 2025, now)
+	if err != nil {
+		t.Fatalf("ensureLeaveBalance: %v", err)
+	}
+	if balance.CarriedOver != 2 {
+		t.Errorf("结转 %v 天，期望 2 天", balance.CarriedOver)
+	}
+}
+
+func TestEnsureLeaveBalanceCreatesMissingPreviousYears(t *testing.T) {
+	now := time.Date(2025, 6, 10, 9, 0, 0, 0, time.Local)
+	db := newTestDB(t, &models.LeaveBalance{})
+
+	// 2023年3月入职，此前从未查询过额度
+	hired := time.Date(2023, 3, 15, 0, 0, 0, 0, time.Local)
+	balance, err := ensureLeaveBalance(db, testUser(1, &hired), annualLeave, 2025, now)
+	if err != nil {
+		t.Fatalf("ensureLeaveBalance: %v", err)
+	}
+
+	var balances []models.LeaveBalance
+	db.Where("user_id = ?", 1).Order("year").Find(&balances)
+	if len(balances) != 3 || balances[0].Year != 2023 {
+		t.Fatalf("应从入职年度起逐年建立额度，得到 %+v", balances)
+	}
+	want := []struct {
+		carried, accrued float64
+		month            int
+	}{
+		{0, 12.5, 12}, // 3月起累计10个月
+		{5, 15, 12},   // 结转上限5天，累计至上限20天
+		{5, 7.5, 6},
+	}
+	for i, w := range want {
+		b := balances[i]
+		if b.CarriedOver != w.carried || b.Accrued != w.accrued || b.AccruedMonth != w.month {
+			t.Errorf("%d年额度为 结转%v 累计%v 至%d月，期望 结转%v 累计%v 至%d月",
+				b.Year, b.CarriedOver, b.Accrued, b.AccruedMonth, w.carried, w.accrued, w.month)
+		}
+	}
+	if balance.CarriedOver != 5 {
+		t.Errorf("本年结转 %v 天，期望 5 天", balance.CarriedOver)
+	}
+
+	// 未设置入职日期时不向前建立额度
+	balance, err = ensureLeaveBalance(db, testUser(2, nil), annualLeave, 2025, now)
+	if err != nil {
+		t.Fatalf("ensureLeaveBalance: %v", err)
+	}
+	var count int64
+	db.Model(&models.LeaveBalance{}).Where("user_id = ?", 2).Count(&count)
+	if count != 1 || balance.CarriedOver != 0 {
+		t.Errorf("未设置入职日期时建立了 %d 条额度，结转 %v 天", count, balance.CarriedOver)
+	}
+}
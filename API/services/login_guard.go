@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"API/storage/cache"
+	"API/utils"
+
+	"github.com/spf13/viper"
+)
+
+// LoginGuardConfig 登录防护配置
+type LoginGuardConfig struct {
+	MaxAccountFailures int           // 账户连续失败多少次后锁定
+	MaxIPFailures      int           // 同一IP失败多少次后锁定
+	FailureWindow      time.Duration // 失败计数的统计窗口
+	LockoutDuration    time.Duration // 首次锁定时长，之后每次锁定翻倍
+	MaxLockoutDuration time.Duration // 锁定时长上限
+	BackoffBase        time.Duration // 每次失败后的退避基数，按失败次数指数增长
+	BackoffMax         time.Duration // 退避时长上限
+}
+
+// LoadLoginGuardConfig 从配置文件加载登录防护配置
+func LoadLoginGuardConfig() LoginGuardConfig {
+	config := LoginGuardConfig{
+		MaxAccountFailures: viper.GetInt("login_guard.max_account_failures"),
+		MaxIPFailures:      viper.GetInt("login_guard.max_ip_failures"),
+		FailureWindow:      viper.GetDuration("login_guard.failure_window"),
+		LockoutDuration:    viper.GetDuration("login_guard.lockout_duration"),
+		MaxLockoutDuration: viper.GetDuration("login_guard.max_lockout_duration"),
+		BackoffBase:        viper.GetDuration("login_guard.backoff_base"),
+		BackoffMax:         viper.GetDuration("login_guard.backoff_max"),
+	}
+
+	// 确保配置有效
+	if config.MaxAccountFailures <= 0 {
+		config.MaxAccountFailures = 5
+	}
+	if config.MaxIPFailures <= 0 {
+		config.MaxIPFailures = 50
+	}
+	if config.FailureWindow <= 0 {
+		config.FailureWindow = 15 * time.Minute
+	}
+	if config.LockoutDuration <= 0 {
+		config.LockoutDuration = 15 * time.Minute
+	}
+	if config.MaxLockoutDuration < config.LockoutDuration {
+		config.MaxLockoutDuration = 24 * time.Hour
+	}
+	if config.BackoffBase <= 0 {
+		config.BackoffBase = time.Second
+	}
+	if config.BackoffMax <= 0 {
+		config.BackoffMax = 30 * time.Second
+	}
+	return config
+}
+
+// LoginGuard 基于Redis记录登录失败次数，实现按账户和IP的退避与临时锁定
+type LoginGuard struct {
+	cache  cache.Provider
+	config LoginGuardConfig
+}
+
+func NewLoginGuard(cache cache.Provider, config LoginGuardConfig) *LoginGuard {
+	return &LoginGuard{cache: cache, config: config}
+}
+
+// 登录防护相关缓存键，scope 为 account 或 ip
+func loginFailuresKey(scope, subject string) string {
+	return fmt.Sprintf("login_failures:%s:%s", scope, subject)
+}
+
+func loginLockKey(scope, subject string) string {
+	return fmt.Sprintf("login_lock:%s:%s", scope, subject)
+}
+
+func loginLockoutsKey(account string) string {
+	return "login_lockouts:account:" + account
+}
+
+func loginBackoffKey(account string) string {
+	return "login_backoff:account:" + account
+}
+
+// normalizeAccount 统一账户标识，避免大小写或空白绕过计数
+func normalizeAccount(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
+
+// Check 登录前检查账户和IP是否处于锁定或退避期
+func (g *LoginGuard) Check(ctx context.Context, account, ip string) error {
+	account = normalizeAccount(account)
+	checks := []struct {
+		key     string
+		message string
+	}{
+		{loginLockKey("ip", ip), "登录失败次数过多，请稍后再试"},
+		{loginLockKey("account", account), "登录失败次数过多，账户已临时锁定"},
+		{loginBackoffKey(account), "登录尝试过于频繁，请稍后再试"},
+	}
+
+	for _, check := range checks {
+		var until int64
+		if err := g.cache.GetObject(ctx, check.key, &until); err != nil {
+			if errors.Is(err, cache.ErrNotFound) {
+				continue
+			}
+			return fmt.Errorf("查询登录限制失败: %w", err)
+		}
+		if wait := time.Until(time.Unix(until, 0)); wait > 0 {
+			return utils.NewTooManyAttemptsError(check.message, wait)
+		}
+	}
+	return nil
+}
+
+// RecordFailure 记录一次失败登录，达到阈值时锁定账户或IP
+func (g *LoginGuard) RecordFailure(ctx context.Context, account, ip string) error {
+	account = normalizeAccount(account)
+
+	failures, err := g.cache.Incr(ctx, loginFailuresKey("account", account), g.config.FailureWindow)
+	if err != nil {
+		return fmt.Errorf("记录登录失败次数失败: %w", err)
+	}
+	if failures >= int64(g.config.MaxAccountFailures) {
+		if err := g.lockAccount(ctx, account); err != nil {
+			return err
+		}
+	} else if err := g.setUntil(ctx, loginBackoffKey(account), g.backoff(failures)); err != nil {
+		return err
+	}
+
+	ipFailures, err := g.cache.Incr(ctx, loginFailuresKey("ip", ip), g.config.FailureWindow)
+	if err != nil {
+		return fmt.Errorf("记录登录失败次数失败: %w", err)
+	}
+	if ipFailures >= int64(g.config.MaxIPFailures) {
+		if err := g.setUntil(ctx, loginLockKey("ip", ip), g.config.LockoutDuration); err != nil {
+			return err
+		}
+		if err := g.cache.Del(ctx, loginFailuresKey("ip", ip)); err != nil {
+			return fmt.Errorf("重置登录失败次数失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// Reset 登录成功后清除账户的失败记录，IP计数保留以防多账户撞库
+func (g *LoginGuard) Reset(ctx context.Context, account string) error {
+	account = normalizeAccount(account)
+	return g.cache.Del(ctx, loginFailuresKey("account", account), loginBackoffKey(account))
+}
+
+// Unlock 解除账户锁定并清除失败记录
+func (g *LoginGuard) Unlock(ctx context.Context, account string) error {
+	account = normalizeAccount(account)
+	return g.cache.Del(ctx,
+		loginFailuresKey("account", account),
+		loginBackoffKey(account),
+		loginLockKey("account", account),
+		loginLockoutsKey(account),
+	)
+}
+
+// lockAccount 锁定账户，锁定时长随近期锁定次数翻倍
+func (g *LoginGuard) lockAccount(ctx context.Context, account string) error {
+	lockouts, err := g.cache.Incr(ctx, loginLockoutsKey(account), g.config.MaxLockoutDuration)
+	if err != nil {
+		return fmt.Errorf("记录锁定次数失败: %w", err)
+	}
+
+	duration := g.config.LockoutDuration
+	for i := int64(1); i < lockouts && duration < g.config.MaxLockoutDuration; i++ {
+		duration *= 2
+	}
+	if duration > g.config.MaxLockoutDuration {
+		duration = g.config.MaxLockoutDuration
+	}
+
+	if err := g.setUntil(ctx, loginLockKey("account", account), duration); err != nil {
+		return err
+	}
+	// 锁定后重新计数
+	if err := g.cache.Del(ctx, loginFailuresKey("account", account), loginBackoffKey(account)); err != nil {
+		return fmt.Errorf("重置登录失败次数失败: %w", err)
+	}
+	return nil
+}
+
+// backoff 计算第 failures 次失败后的退避时长
+func (g *LoginGuard) backoff(failures int64) time.Duration {
+	delay := g.config.BackoffBase
+	for i := int64(1); i < failures && delay < g.config.BackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > g.config.BackoffMax {
+		delay = g.config.BackoffMax
+	}
+	return delay
+}
+
+// setUntil 写入截止时间戳，到期自动删除
+func (g *LoginGuard) setUntil(ctx context.Context, key string, duration time.Duration) error {
+	until := time.Now().Add(duration).Unix()
+	if err := g.cache.SetObject(ctx, key, until, duration); err != nil {
+		return fmt.Errorf("写入登录限制失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"API/utils"
+)
+
+func testGuardConfig() LoginGuardConfig {
+	return LoginGuardConfig{
+		MaxAccountFailures: 3,
+		MaxIPFailures:      5,
+		FailureWindow:      15 * time.Minute,
+		LockoutDuration:    15 * time.Minute,
+		MaxLockoutDuration: time.Hour,
+		BackoffBase:        time.Second,
+		BackoffMax:         30 * time.Second,
+	}
+}
+
+// lockUntil 读取限制键中的截止时间，返回剩余时长
+func lockUntil(t *testing.T, c *memoryCache, key string) time.Duration {
+	t.Helper()
+	var until int64
+	if err := c.GetObject(context.Background(), key, &until); err != nil {
+		t.Fatalf("读取 %s 失败: %v", key, err)
+	}
+	return time.Until(time.Unix(until, 0))
+}
+
+// approx 判断时长是否在期望值附近（截止时间按秒存储）
+func approx(got, want time.Duration) bool {
+	return got > want-2*time.Second && got <= want
+}
+
+func TestLoginGuardBackoff(t *testing.T) {
+	guard := NewLoginGuard(newMemoryCache(), testGuardConfig())
+	tests := []struct {
+		failures int64
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{5, 16 * time.Second},
+		{6, 30 * time.Second},
+		{20, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := guard.backoff(tt.failures); got != tt.want {
+			t.Errorf("backoff(%d) = %s, 期望 %s", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestLoginGuardLocksAccountAtThreshold(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryCache()
+	guard := NewLoginGuard(store, testGuardConfig())
+
+	for i := 0; i < 2; i++ {
+		if err := guard.RecordFailure(ctx, " Alice@Example.com ", "10.0.0.1"); err != nil {
+			t.Fatalf("RecordFailure: %v", err)
+		}
+	}
+	var limited *utils.TooManyAttemptsError
+	err := guard.Check(ctx, "alice@example.com", "10.0.0.1")
+	if !errors.As(err, &limited) || limited.Message != "登录尝试过于频繁，请稍后再试" {
+		t.Fatalf("未达阈值时应处于退避期，得到 %v", err)
+	}
+	if store.has(loginLockKey("account", "alice@example.com")) {
+		t.Fatal("未达阈值时不应锁定账户")
+	}
+
+	if err := guard.RecordFailure(ctx, "alice@example.com", "10.0.0.1"); err != nil {
+		t.Fatalf("RecordFailure: %v", err)
+	}
+	err = guard.Check(ctx, "alice@example.com", "10.0.0.2")
+	if !errors.As(err, &limited) || limited.Message != "登录失败次数过多，账户已临时锁定" {
+		t.Fatalf("达到阈值后应锁定账户，得到 %v", err)
+	}
+	if store.has(loginFailuresKey("account", "alice@example.com")) {
+		t.Error("锁定后应重置失败计数")
+	}
+	if ttl := store.ttls[loginFailuresKey("ip", "10.0.0.1")]; ttl != 15*time.Minute {
+		t.Errorf("IP 失败计数的过期时间为 %s，期望 15m", ttl)
+	}
+
+	if err := guard.Unlock(ctx, "alice@example.com"); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if err := guard.Check(ctx, "alice@example.com", "10.0.0.2"); err != nil {
+		t.Errorf("解锁后应允许登录，得到 %v", err)
+	}
+}
+
+func TestLoginGuardLockoutDoubles(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryCache()
+	guard := NewLoginGuard(store, testGuardConfig())
+
+	for _, want := range []time.Duration{15 * time.Minute, 30 * time.Minute, time.Hour, time.Hour} {
+		if err := guard.lockAccount(ctx, "bob"); err != nil {
+			t.Fatalf("lockAccount: %v", err)
+		}
+		if got := lockUntil(t, store, loginLockKey("account", "bob")); !approx(got, want) {
+			t.Errorf("锁定时长为 %s，期望 %s", got, want)
+		}
+	}
+}
+
+func TestLoginGuardLocksIPAcrossAccounts(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryCache()
+	guard := NewLoginGuard(store, testGuardConfig())
+
+	for _, account := range []string{"a", "b", "c", "d", "e"} {
+		if err := guard.RecordFailure(ctx, account, "10.0.0.9"); err != nil {
+			t.Fatalf("RecordFailure: %v", err)
+		}
+	}
+	var limited *utils.TooManyAttemptsError
+	err := guard.Check(ctx, "f", "10.0.0.9")
+	if !errors.As(err, &limited) || limited.Message != "登录失败次数过多，请稍后再试" {
+		t.Fatalf("同一 IP 达到阈值后应锁定，得到 %v", err)
+	}
+	if got := lockUntil(t, store, loginLockKey("ip", "10.0.0.9")); !approx(got, 15*time.Minute) {
+		t.Errorf("IP 锁定时长为 %s，期望 15m", got)
+	}
+	if err := guard.Check(ctx, "f", "10.0.0.10"); err != nil {
+		t.Errorf("其他 IP 不应受影响，得到 %v", err)
+	}
+}
+
+func TestLoginGuardThresholds(t *testing.T) {
+	// attempt 为一次登录尝试，success 表示登录成功
+	type attempt struct {
+		account string
+		success bool
+	}
+	fail := func(account string, n int) []attempt {
+		attempts := make([]attempt, n)
+		for i := range attempts {
+			attempts[i] = attempt{account: account}
+		}
+		return attempts
+	}
+	tests := []struct {
+		name        string
+		attempts    []attempt
+		accountLock bool
+		ipLock      bool
+	}{
+		{"账户未达阈值", fail("a", 2), false, false},
+		{"账户达到阈值", fail("a", 3), true, false},
+		{"登录成功后重新计数", append(append(fail("a", 2), attempt{"a", true}), fail("a", 2)...), false, false},
+		{"IP未达阈值", append(fail("a", 2), fail("b", 2)...), false, false},
+		{"多账户累计达到IP阈值", append(append(fail("a", 2), fail("b", 2)...), fail("c", 1)...), false, true},
+		{"登录成功不清除IP计数", append(append(fail("a", 2), attempt{"a", true}), fail("b", 3)...), false, true},
+		{"账户和IP同时锁定", append(fail("b", 2), fail("a", 3)...), true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newMemoryCache()
+			guard := NewLoginGuard(store, testGuardConfig())
+			for _, a := range tt.attempts {
+				var err error
+				if a.success {
+					err = guard.Reset(ctx, a.account)
+				} else {
+					err = guard.RecordFailure(ctx, a.account, "10.0.0.1")
+				}
+				if err != nil {
+					t.Fatalf("记录登录结果失败: %v", err)
+				}
+			}
+			if got := store.has(loginLockKey("account", "a")); got != tt.accountLock {
+				t.Errorf("账户锁定为 %v, 期望 %v", got, tt.accountLock)
+			}
+			if got := store.has(loginLockKey("ip", "10.0.0.1")); got != tt.ipLock {
+				t.Errorf("IP 锁定为 %v, 期望 %v", got, tt.ipLock)
+			}
+		})
+	}
+}
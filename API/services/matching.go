@@ -0,0 +1,480 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"API/models"
+	"API/utils"
+
+	"gorm.io/gorm"
+)
+
+// 匹配度各项的权重，不适用的项不参与计算，其余项按权重重新归一
+var matchWeights = map[string]float64{
+	"skills":     0.5,
+	"experience": 0.2,
+	"salary":     0.15,
+	"location":   0.15,
+}
+
+// MatchComponent 匹配度中单一维度的得分及说明
+type MatchComponent struct {
+	Name       string  `json:"name"`       // skills、experience、salary、location
+	Weight     float64 `json:"weight"`     // 配置权重
+	Score      float64 `json:"score"`      // 0-1
+	Applicable bool    `json:"applicable"` // 职位或简历缺少相关信息时不参与计算
+	Detail     string  `json:"detail"`
+}
+
+// MatchResult 候选人与职位的匹配度，Score 为0-100
+type MatchResult struct {
+	Score           float64          `json:"score"`
+	Components      []MatchComponent `json:"components"`
+	MatchedSkills   []string         `json:"matched_skills"`
+	MissingSkills   []string         `json:"missing_skills"`
+	ExperienceYears float64          `json:"experience_years"`
+}
+
+// CandidateMatch 职位的候选人排名项
+type CandidateMatch struct {
+	UserID        uint   `json:"user_id"`
+	Username      string `json:"username"`
+	ResumeID      uint   `json:"resume_id"`
+	ResumeVersion int    `json:"resume_version,omitempty"` // 申请固定的简历版本号
+	ApplicationID *uint  `json:"application_id,omitempty"`
+	MatchResult
+
+	resume models.Resume // 参与评分的简历
+}
+
+// JobMatch 候选人的推荐职位项
+type JobMatch struct {
+	Job models.Job `json:"job"`
+	MatchResult
+}
+
+// MatchingService 计算候选人与职位的匹配度
+type MatchingService struct {
+	db *gorm.DB
+}
+
+func NewMatchingService(db *gorm.DB) *MatchingService {
+	return &MatchingService{db: db}
+}
+
+// 全部简历范围内先按技能重合在数据库中初筛，参与评分的简历数为返回条数的倍数，且不超过上限
+const (
+	rankPoolFactor = 5
+	maxRankPool    = 500
+)
+
+// RankCandidates 按匹配度对职位的候选人排序。scope 为 applicants 时仅包含未撤回的申请人，
+// 按投递时固定的简历版本评分；为 all 时包含账户有效且填写了技能的用户，使用当前简历
+func (s *MatchingService) RankCandidates(ctx context.Context, jobID uint, scope string, limit int) ([]CandidateMatch, error) {
+	db := s.db.WithContext(ctx)
+	var job models.Job
+	if err := db.Preload("Skills").First(&job, jobID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("职位不存在", "job")
+		}
+		return nil, fmt.Errorf("查询职位失败: %w", err)
+	}
+	required, inferred, err := s.requiredSkills(db, job)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []CandidateMatch
+	if scope == "all" {
+		candidates, err = s.poolCandidates(db, required, limit)
+	} else {
+		candidates, err = s.applicantCandidates(db, jobID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	matches := make([]CandidateMatch, 0, len(candidates))
+	for _, candidate := range candidates {
+		candidate.MatchResult = scoreMatch(job, required, inferred, candidate.resume, now)
+		matches = append(matches, candidate)
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// poolCandidates 查询账户有效且填写了技能的用户的当前简历，按与职位技能要求的重合数初筛
+func (s *MatchingService) poolCandidates(db *gorm.DB, required []models.Skill, limit int) ([]CandidateMatch, error) {
+	pool := limit * rankPoolFactor
+	if pool > maxRankPool || pool <= 0 {
+		pool = maxRankPool
+	}
+	query := db.Table("resumes").
+		Select("resumes.id").
+		Joins("JOIN users ON users.id = resumes.user_id AND users.deleted_at IS NULL").
+		Joins("JOIN resume_skills ON resume_skills.resume_id = resumes.id").
+		Where("resumes.deleted_at IS NULL AND users.active = ?", true).
+		Group("resumes.id")
+	if len(required) > 0 {
+		skillIDs := make([]uint, 0, len(required))
+		for _, skill := range required {
+			skillIDs = append(skillIDs, skill.ID)
+		}
+		query = query.Order(gorm.Expr("SUM(CASE WHEN resume_skills.skill_id IN ? THEN 1 ELSE 0 END) DESC", skillIDs))
+	}
+	var resumeIDs []uint
+	if err := query.Order("MAX(resumes.updated_at) DESC").Limit(pool).Pluck("resumes.id", &resumeIDs).Error; err != nil {
+		return nil, fmt.Errorf("查询候选简历失败: %w", err)
+	}
+	if len(resumeIDs) == 0 {
+		return nil, nil
+	}
+
+	var resumes []models.Resume
+	if err := db.Scopes(withResumeSections).Preload("User").Where("id IN ?", resumeIDs).Find(&resumes).Error; err != nil {
+		return nil, fmt.Errorf("查询简历失败: %w", err)
+	}
+	candidates := make([]CandidateMatch, 0, len(resumes))
+	for _, resume := range resumes {
+		candidates = append(candidates, CandidateMatch{
+			UserID:   resume.UserID,
+			Username: resume.User.Username,
+			ResumeID: resume.ID,
+			resume:   resume,
+		})
+	}
+	return candidates, nil
+}
+
+// applicantCandidates 查询职位未撤回的申请人，使用申请固定的简历版本；
+// 启用版本管理前的申请使用申请人的当前简历
+func (s *MatchingService) applicantCandidates(db *gorm.DB, jobID uint) ([]CandidateMatch, error) {
+	var applications []models.Application
+	if err := db.Preload("User").Preload("ResumeVersion").
+		Where("job_id = ? AND status <> ?", jobID, "withdrawn").
+		Find(&applications).Error; err != nil {
+		return nil, fmt.Errorf("查询申请失败: %w", err)
+	}
+
+	var unversioned []uint
+	for _, application := range applications {
+		if application.ResumeVersion == nil {
+			unversioned = append(unversioned, application.UserID)
+		}
+	}
+	current := make(map[uint]models.Resume, len(unversioned))
+	if len(unversioned) > 0 {
+		var resumes []models.Resume
+		if err := db.Scopes(withResumeSections).Where("user_id IN ?", unversioned).Find(&resumes).Error; err != nil {
+			return nil, fmt.Errorf("查询简历失败: %w", err)
+		}
+		for _, resume := range resumes {
+			current[resume.UserID] = resume
+		}
+	}
+
+	candidates := make([]CandidateMatch, 0, len(applications))
+	for _, application := range applications {
+		var resume models.Resume
+		if application.ResumeVersion != nil {
+			snapshot, err := snapshotResume(*application.ResumeVersion)
+			if err != nil {
+				return nil, err
+			}
+			resume = snapshot
+		} else if latest, ok := current[application.UserID]; ok {
+			resume = latest
+		} else {
+			continue
+		}
+		id := application.ID
+		candidates = append(candidates, CandidateMatch{
+			UserID:        application.UserID,
+			Username:      application.User.Username,
+			ResumeID:      resume.ID,
+			ResumeVersion: resume.Version,
+			ApplicationID: &id,
+			resume:        resume,
+		})
+	}
+	return candidates, nil
+}
+
+// RecommendJobs 按匹配度为候选人推荐开放中且尚未申请的职位
+func (s *MatchingService) RecommendJobs(ctx context.Context, userID uint, limit int) ([]JobMatch, error) {
+	db := s.db.WithContext(ctx)
+	resume, err := s.userResume(db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []models.Job
+	if err := db.Preload("Skills").
+		Where("status = ? AND (expiration_date IS NULL OR expiration_date > ?)", models.JobOpen, time.Now()).
+		Where("id NOT IN (?)", db.Model(&models.Application{}).Select("job_id").Where("user_id = ?", userID)).
+		Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("查询职位失败: %w", err)
+	}
+
+	known, err := s.knownSkills(db)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	matches := make([]JobMatch, 0, len(jobs))
+	for _, job := range jobs {
+		required, inferred := job.Skills, false
+		if len(required) == 0 {
+			required, inferred = inferSkills(job.Requirements, known), true
+		}
+		matches = append(matches, JobMatch{Job: job, MatchResult: scoreMatch(job, required, inferred, *resume, now)})
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// MatchJob 计算当前用户与开放职位的匹配度
+func (s *MatchingService) MatchJob(ctx context.Context, userID, jobID uint) (*MatchResult, error) {
+	db := s.db.WithContext(ctx)
+	var job models.Job
+	if err := db.Preload("Skills").
+		Where("status = ? AND (expiration_date IS NULL OR expiration_date > ?)", models.JobOpen, time.Now()).
+		First(&job, jobID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("职位不存在或已关闭", "job")
+		}
+		return nil, fmt.Errorf("查询职位失败: %w", err)
+	}
+	resume, err := s.userResume(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	required, inferred, err := s.requiredSkills(db, job)
+	if err != nil {
+		return nil, err
+	}
+	result := scoreMatch(job, required, inferred, *resume, time.Now())
+	return &result, nil
+}
+
+// userResume 查询用户当前简历及其各部分
+func (s *MatchingService) userResume(db *gorm.DB, userID uint) (*models.Resume, error) {
+	var resume models.Resume
+	if err := db.Scopes(withResumeSections).Where("user_id = ?", userID).First(&resume).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("请先填写简历", "resume")
+		}
+		return nil, fmt.Errorf("查询简历失败: %w", err)
+	}
+	return &resume, nil
+}
+
+// requiredSkills 职位的技能要求；未设置时从职位要求文本中识别已知技能，inferred 标记为识别所得
+func (s *MatchingService) requiredSkills(db *gorm.DB, job models.Job) (skills []models.Skill, inferred bool, err error) {
+	if len(job.Skills) > 0 {
+		return job.Skills, false, nil
+	}
+	known, err := s.knownSkills(db)
+	if err != nil {
+		return nil, false, err
+	}
+	return inferSkills(job.Requirements, known), true, nil
+}
+
+func (s *MatchingService) knownSkills(db *gorm.DB) ([]models.Skill, error) {
+	var skills []models.Skill
+	if err := db.Find(&skills).Error; err != nil {
+		return nil, fmt.Errorf("查询技能失败: %w", err)
+	}
+	return skills, nil
+}
+
+// inferSkills 返回在文本中出现的已知技能，英文技能需按整词出现
+func inferSkills(text string, known []models.Skill) []models.Skill {
+	lower := strings.ToLower(text)
+	var found []models.Skill
+	for _, skill := range known {
+		if containsWord(lower, skill.Key) || containsWord(lower, strings.ToLower(skill.Name)) {
+			found = append(found, skill)
+		}
+	}
+	return found
+}
+
+// containsWord 判断 word 是否在 text 中出现，且前后不紧邻字母或数字（中文词不受此限制）
+func containsWord(text, word string) bool {
+	if word == "" {
+		return false
+	}
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], word)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(word)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+		offset = end
+	}
+}
+
+func isWordRune(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// scoreMatch 按技能、工作年限、期望薪资和工作地点计算匹配度
+func scoreMatch(job models.Job, required []models.Skill, inferred bool, resume models.Resume, now time.Time) MatchResult {
+	result := MatchResult{
+		MatchedSkills:   []string{},
+		MissingSkills:   []string{},
+		ExperienceYears: experienceYears(resume.Employment, now),
+	}
+
+	// 技能重合
+	skills := MatchComponent{Name: "skills", Weight: matchWeights["skills"]}
+	if len(required) > 0 {
+		have := make(map[string]bool, len(resume.Skills))
+		for _, skill := range resume.Skills {
+			have[skill.Key] = true
+		}
+		for _, skill := range required {
+			if have[skill.Key] {
+				result.MatchedSkills = append(result.MatchedSkills, skill.Name)
+			} else {
+				result.MissingSkills = append(result.MissingSkills, skill.Name)
+			}
+		}
+		skills.Applicable = true
+		skills.Score = float64(len(result.MatchedSkills)) / float64(len(required))
+		skills.Detail = fmt.Sprintf("具备 %d/%d 项技能要求", len(result.MatchedSkills), len(required))
+		if inferred {
+			skills.Detail += "（技能要求从职位要求文本中识别）"
+		}
+	} else {
+		skills.Detail = "职位未设置技能要求"
+	}
+
+	// 工作年限
+	experience := MatchComponent{Name: "experience", Weight: matchWeights["experience"], Applicable: true}
+	if job.Experience == 0 {
+		experience.Score = 1
+		experience.Detail = "职位不要求工作年限"
+	} else {
+		experience.Score = math.Min(1, result.ExperienceYears/float64(job.Experience))
+		experience.Detail = fmt.Sprintf("工作 %.1f 年，职位要求 %d 年", result.ExperienceYears, job.Experience)
+	}
+
+	// 期望薪资，简历期望月薪按人民币计
+	salary := MatchComponent{Name: "salary", Weight: matchWeights["salary"]}
+	switch {
+	case resume.ExpectedSalary <= 0:
+		salary.Detail = "简历未填写期望薪资"
+	case job.SalaryMin == nil && job.SalaryMax == nil:
+		salary.Detail = "职位未设置薪资范围"
+	case job.SalaryCurrency != "CNY":
+		salary.Detail = "职位薪资币种为 " + job.SalaryCurrency + "，无法与期望薪资比较"
+	default:
+		factor := models.MonthlySalaryFactors[job.SalaryPeriod]
+		if factor == 0 {
+			factor = 1
+		}
+		salary.Applicable = true
+		if job.SalaryMax == nil || resume.ExpectedSalary <= *job.SalaryMax*factor {
+			salary.Score = 1
+			salary.Detail = fmt.Sprintf("期望月薪 %.0f 在职位薪资范围内", resume.ExpectedSalary)
+		} else {
+			high := *job.SalaryMax * factor
+			salary.Score = math.Max(0, 1-(resume.ExpectedSalary-high)/high)
+			salary.Detail = fmt.Sprintf("期望月薪 %.0f 高于职位上限 %.0f", resume.ExpectedSalary, high)
+		}
+	}
+
+	// 工作地点
+	location := MatchComponent{Name: "location", Weight: matchWeights["location"]}
+	switch {
+	case job.Location == "":
+		location.Detail = "职位未设置工作地点"
+	case resume.Location == "":
+		location.Detail = "简历未填写期望工作地点"
+	default:
+		location.Applicable = true
+		jobLocation, wanted := strings.ToLower(job.Location), strings.ToLower(resume.Location)
+		if strings.Contains(jobLocation, wanted) || strings.Contains(wanted, jobLocation) {
+			location.Score = 1
+			location.Detail = "期望工作地点与职位一致"
+		} else {
+			location.Detail = fmt.Sprintf("期望工作地点 %s，职位位于 %s", resume.Location, job.Location)
+		}
+	}
+
+	result.Components = []MatchComponent{skills, experience, salary, location}
+	var total, weights float64
+	for _, component := range result.Components {
+		if component.Applicable {
+			total += component.Score * component.Weight
+			weights += component.Weight
+		}
+	}
+	if weights > 0 {
+		result.Score = round2(total / weights * 100)
+	}
+	return result
+}
+
+// experienceYears 合并重叠的工作经历后计算总工作年限，未填写开始日期的经历不计入
+func experienceYears(employment []models.ResumeEmployment, now time.Time) float64 {
+	type period struct{ start, end time.Time }
+	periods := make([]period, 0, len(employment))
+	for _, e := range employment {
+		if e.StartDate == nil {
+			continue
+		}
+		end := now
+		if e.EndDate != nil && e.EndDate.Before(now) {
+			end = *e.EndDate
+		}
+		if end.After(*e.StartDate) {
+			periods = append(periods, period{*e.StartDate, end})
+		}
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].start.Before(periods[j].start) })
+
+	var total time.Duration
+	var current *period
+	for i := range periods {
+		p := periods[i]
+		switch {
+		case current == nil:
+			current = &p
+		case !p.start.After(current.end):
+			if p.end.After(current.end) {
+				current.end = p.end
+			}
+		default:
+			total += current.end.Sub(current.start)
+			current = &p
+		}
+	}
+	if current != nil {
+		total += current.end.Sub(current.start)
+	}
+	return round2(total.Hours() / 24 / 365.25)
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"API/models"
+)
+
+func date(year int, month time.Month, day int) *time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	return &t
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestExperienceYears(t *testing.T) {
+	now := *date(2025, 1, 1)
+	tests := []struct {
+		name       string
+		employment []models.ResumeEmployment
+		want       float64
+	}{
+		{"无工作经历", nil, 0},
+		{
+			name: "重叠经历合并计算",
+			employment: []models.ResumeEmployment{
+				{StartDate: date(2019, 1, 1), EndDate: date(2021, 1, 1)},
+				{StartDate: date(2018, 1, 1), EndDate: date(2020, 1, 1)},
+			},
+			want: 3,
+		},
+		{
+			name: "不相连的经历分别累加",
+			employment: []models.ResumeEmployment{
+				{StartDate: date(2016, 1, 1), EndDate: date(2017, 1, 1)},
+				{StartDate: date(2020, 1, 1), EndDate: date(2022, 1, 1)},
+			},
+			want: 3,
+		},
+		{
+			name: "至今的经历计算到当前",
+			employment: []models.ResumeEmployment{
+				{StartDate: date(2023, 1, 1)},
+			},
+			want: 2,
+		},
+		{
+			name: "忽略缺少开始日期或结束早于开始的经历，结束日期晚于当前按当前计",
+			employment: []models.ResumeEmployment{
+				{EndDate: date(2020, 1, 1)},
+				{StartDate: date(2021, 1, 1), EndDate: date(2020, 1, 1)},
+				{StartDate: date(2024, 1, 1), EndDate: date(2026, 1, 1)},
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := experienceYears(tt.employment, now); got != tt.want {
+				t.Errorf("experienceYears = %v, 期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInferSkills(t *testing.T) {
+	known := []models.Skill{
+		{ID: 1, Key: "go", Name: "Go"},
+		{ID: 2, Key: "java", Name: "Java"},
+		{ID: 3, Key: "javascript", Name: "JavaScript"},
+		{ID: 4, Key: "c++", Name: "C++"},
+		{ID: 5, Key: "machine learning", Name: "机器学习"},
+	}
+	tests := []struct {
+		text string
+		want []uint
+	}{
+		{"熟悉Golang或Go语言，了解JavaScript", []uint{1, 3}},
+		{"精通C++，有机器学习项目经验", []uint{4, 5}},
+		{"Java/Go 均可", []uint{1, 2}},
+		{"熟悉 Django 和 MongoDB", nil},
+	}
+	for _, tt := range tests {
+		var got []uint
+		for _, skill := range inferSkills(tt.text, known) {
+			got = append(got, skill.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("inferSkills(%q) = %v, 期望 %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestScoreMatch(t *testing.T) {
+	now := *date(2025, 1, 1)
+	required := []models.Skill{{Key: "go", Name: "Go"}, {Key: "mysql", Name: "MySQL"}}
+	twoYears := []models.ResumeEmployment{{StartDate: date(2023, 1, 1)}}
+
+	tests := []struct {
+		name       string
+		job        models.Job
+		required   []models.Skill
+		resume     models.Resume
+		want       float64
+		applicable []bool // skills、experience、salary、location
+	}{
+		{
+			name: "全部符合",
+			job: models.Job{
+				SalaryMax: floatPtr(20000), SalaryCurrency: "CNY", SalaryPeriod: models.SalaryPerMonth,
+				Location: "北京市海淀区",
+			},
+			required: required,
+			resume: models.Resume{
+				ExpectedSalary: 15000, Location: "北京",
+				Skills: []models.Skill{{Key: "go"}, {Key: "mysql"}, {Key: "redis"}},
+			},
+			want:       100,
+			applicable: []bool{true, true, true, true},
+		},
+		{
+			name: "技能、年限、薪资部分符合，地点不符",
+			job: models.Job{
+				Experience: 4, SalaryMax: floatPtr(20000), SalaryCurrency: "CNY", SalaryPeriod: models.SalaryPerMonth,
+				Location: "上海",
+			},
+			required: required,
+			resume: models.Resume{
+				ExpectedSalary: 30000, Location: "深圳", Employment: twoYears,
+				Skills: []models.Skill{{Key: "go"}},
+			},
+			// 0.5×0.5 + 0.5×0.2 + 0.5×0.15 + 0×0.15
+			want:       42.5,
+			applicable: []bool{true, true, true, true},
+		},
+		{
+			name: "年薪折算为月薪",
+			job: models.Job{
+				SalaryMin: floatPtr(120000), SalaryMax: floatPtr(240000), SalaryCurrency: "CNY", SalaryPeriod: models.SalaryPerYear,
+			},
+			resume:     models.Resume{ExpectedSalary: 20000},
+			want:       100,
+			applicable: []bool{false, true, true, false},
+		},
+		{
+			name: "不适用的项按剩余权重归一",
+			job: models.Job{
+				Experience: 4, SalaryMax: floatPtr(5000), SalaryCurrency: "USD", SalaryPeriod: models.SalaryPerMonth,
+				Location: "杭州",
+			},
+			resume:     models.Resume{ExpectedSalary: 30000, Employment: twoYears},
+			want:       50,
+			applicable: []bool{false, true, false, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := scoreMatch(tt.job, tt.required, false, tt.resume, now)
+			if result.Score != tt.want {
+				t.Errorf("Score = %v, 期望 %v", result.Score, tt.want)
+			}
+			for i, component := range result.Components {
+				if component.Applicable != tt.applicable[i] {
+					t.Errorf("%s 是否适用为 %v, 期望 %v", component.Name, component.Applicable, tt.applicable[i])
+				}
+			}
+		})
+	}
+
+	result := scoreMatch(models.Job{}, required, true, models.Resume{Skills: []models.Skill{{Key: "mysql"}}}, now)
+	if !reflect.DeepEqual(result.MatchedSkills, []string{"MySQL"}) || !reflect.DeepEqual(result.MissingSkills, []string{"Go"}) {
+		t.Errorf("技能匹配结果为 %v / %v", result.MatchedSkills, result.MissingSkills)
+	}
+	if detail := result.Components[0].Detail; detail != "具备 1/2 项技能要求（技能要求从职位要求文本中识别）" {
+		t.Errorf("技能说明为 %q", detail)
+	}
+}
+
+func TestSnapshotResume(t *testing.T) {
+	record := models.ResumeVersion{
+		ID: 7, ResumeID: 3, UserID: 9, Version: 2,
+		Content: `{"expected_salary":15000,"location":"北京","employment":[{"company":"Acme","start_date":"2023-01"}],"skills":["Golang","MySQL"]}`,
+	}
+	resume, err := snapshotResume(record)
+	if err != nil {
+		t.Fatalf("snapshotResume: %v", err)
+	}
+	if resume.ID != 3 || resume.UserID != 9 || resume.Version != 2 || resume.ExpectedSalary != 15000 || resume.Location != "北京" {
+		t.Errorf("简历基本信息为 %+v", resume)
+	}
+	if len(resume.Employment) != 1 || !resume.Employment[0].StartDate.Equal(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("工作经历为 %+v", resume.Employment)
+	}
+	want := []models.Skill{{Key: "go", Name: "Go"}, {Key: "mysql", Name: "MySQL"}}
+	if !reflect.DeepEqual(resume.Skills, want) {
+		t.Errorf("技能为 %+v, 期望 %+v", resume.Skills, want)
+	}
+}
@@ -0,0 +1,479 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	appconfig "API/config"
+	"API/models"
+	"API/storage/cache"
+	"API/utils"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// MFAConfig 两步验证配置
+type MFAConfig struct {
+	Issuer            string        // 验证器应用中显示的签发方名称
+	EnforceAdmin      bool          // 拥有管理类权限的用户必须启用两步验证
+	ChallengeTTL      time.Duration // 登录第二步令牌有效期
+	MaxAttempts       int           // 统计窗口内允许的验证码错误次数
+	RecoveryCodeCount int           // 每次生成的恢复码数量
+	ClockSkew         int           // 允许前后偏差的时间步数
+	SecretKey         string        // 加密 TOTP 密钥的 AES-256 密钥，Base64 编码
+}
+
+// LoadMFAConfig 从配置文件加载两步验证配置
+func LoadMFAConfig() MFAConfig {
+	config := MFAConfig{
+		Issuer:            viper.GetString("mfa.issuer"),
+		EnforceAdmin:      viper.GetBool("mfa.enforce_admin"),
+		ChallengeTTL:      viper.GetDuration("mfa.challenge_ttl"),
+		MaxAttempts:       viper.GetInt("mfa.max_attempts"),
+		RecoveryCodeCount: viper.GetInt("mfa.recovery_codes"),
+		ClockSkew:         viper.GetInt("mfa.clock_skew"),
+		SecretKey:         viper.GetString("mfa.secret_key"),
+	}
+	if config.Issuer == "" {
+		config.Issuer = "HRMS"
+	}
+	if config.ChallengeTTL <= 0 {
+		config.ChallengeTTL = 5 * time.Minute
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.RecoveryCodeCount <= 0 {
+		config.RecoveryCodeCount = 10
+	}
+	if config.ClockSkew < 0 {
+		config.ClockSkew = 0
+	}
+	return config
+}
+
+// MFASetup 开始绑定验证器时返回的密钥信息
+type MFASetup struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// mfaChallenge 密码验证通过后等待第二步验证的登录请求
+type mfaChallenge struct {
+	UserID uint `json:"user_id"`
+	Enroll bool `json:"enroll"` // 策略要求但尚未绑定，需先完成绑定
+}
+
+// MFAService 负责 TOTP 两步验证的绑定、校验和恢复码管理
+type MFAService struct {
+	db          *gorm.DB
+	cache       cache.Provider
+	permissions *PermissionService
+	tokens      *TokenService
+	secrets     *utils.SecretBox
+	config      MFAConfig
+}
+
+// NewMFAService 创建两步验证服务，TOTP 密钥使用 config.SecretKey 加密存储
+func NewMFAService(db *gorm.DB, cache cache.Provider, permissions *PermissionService, tokens *TokenService, config MFAConfig) (*MFAService, error) {
+	if config.SecretKey == appconfig.DefaultInsecureMFAKey {
+		return nil, errors.New("禁止使用默认的不安全密钥，请配置 mfa.secret_key")
+	}
+	secrets, err := utils.NewSecretBox(config.SecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("mfa.secret_key 无效: %w", err)
+	}
+	return &MFAService{
+		db:          db,
+		cache:       cache,
+		permissions: permissions,
+		tokens:      tokens,
+		secrets:     secrets,
+		config:      config,
+	}, nil
+}
+
+// 两步验证相关缓存键
+func mfaChallengeKey(tokenHash string) string {
+	return "mfa_challenge:" + tokenHash
+}
+
+func mfaFailuresKey(userID uint) string {
+	return fmt.Sprintf("mfa_failures:%d", userID)
+}
+
+func mfaLastStepKey(userID uint) string {
+	return fmt.Sprintf("mfa_last_step:%d", userID)
+}
+
+// Required 判断用户是否必须启用两步验证：所属角色开启了强制策略，
+// 或启用了 mfa.enforce_admin 且用户拥有管理类权限
+func (s *MFAService) Required(ctx context.Context, userID uint) (bool, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.Role{}).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ? AND roles.require_mfa = ?", userID, true).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("查询角色策略失败: %w", err)
+	}
+	if count > 0 {
+		return true, nil
+	}
+	if !s.config.EnforceAdmin {
+		return false, nil
+	}
+
+	_, codes, err := s.permissions.GetUserPermissionCodes(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, code := range codes {
+		if models.IsAdminPermission(code) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CreateChallenge 为已通过密码验证的用户创建登录第二步令牌
+func (s *MFAService) CreateChallenge(ctx context.Context, userID uint, enroll bool) (string, time.Time, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	challenge := mfaChallenge{UserID: userID, Enroll: enroll}
+	if err := s.cache.SetObject(ctx, mfaChallengeKey(hashToken(token)), challenge, s.config.ChallengeTTL); err != nil {
+		return "", time.Time{}, fmt.Errorf("保存登录验证状态失败: %w", err)
+	}
+	return token, time.Now().Add(s.config.ChallengeTTL), nil
+}
+
+// EnrollChallenge 在登录过程中为尚未绑定的用户生成密钥，仅适用于需要先绑定的登录请求
+func (s *MFAService) EnrollChallenge(ctx context.Context, challengeToken string) (*MFASetup, error) {
+	challenge, err := s.loadChallenge(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.Enroll {
+		return nil, utils.NewValidationError("该账户已绑定两步验证", "mfa_token")
+	}
+	return s.Setup(ctx, challenge.UserID)
+}
+
+// CompleteChallenge 校验登录第二步的验证码，成功后作废令牌并返回用户。
+// 需要先绑定的登录请求在此完成绑定，并返回新生成的恢复码。
+func (s *MFAService) CompleteChallenge(ctx context.Context, challengeToken, code string) (*models.User, []string, error) {
+	challenge, err := s.loadChallenge(ctx, challengeToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := s.findUser(ctx, challenge.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var recoveryCodes []string
+	if challenge.Enroll {
+		if recoveryCodes, err = s.enable(ctx, user, code); err != nil {
+			return nil, nil, err
+		}
+	} else if err := s.verify(ctx, user, code); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.cache.Del(ctx, mfaChallengeKey(hashToken(challengeToken))); err != nil {
+		return nil, nil, fmt.Errorf("作废登录验证状态失败: %w", err)
+	}
+	return user, recoveryCodes, nil
+}
+
+// Setup 生成新的 TOTP 密钥，待用户使用验证码确认后才会启用
+func (s *MFAService) Setup(ctx context.Context, userID uint) (*MFASetup, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, utils.NewValidationError("已启用两步验证，请先停用", "mfa")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.secrets.Seal(secret)
+	if err != nil {
+		return nil, fmt.Errorf("加密TOTP密钥失败: %w", err)
+	}
+	if err := s.db.WithContext(ctx).Model(user).Update("mfa_secret", sealed).Error; err != nil {
+		return nil, fmt.Errorf("保存TOTP密钥失败: %w", err)
+	}
+	return &MFASetup{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(s.config.Issuer, user.Username, secret),
+	}, nil
+}
+
+// Enable 使用验证码确认绑定并启用两步验证，返回恢复码（仅此一次明文返回）
+func (s *MFAService) Enable(ctx context.Context, userID uint, code string) ([]string, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, utils.NewValidationError("已启用两步验证", "mfa")
+	}
+	return s.enable(ctx, user, code)
+}
+
+// Disable 校验验证码后停用两步验证，策略强制的用户不可停用
+func (s *MFAService) Disable(ctx context.Context, userID uint, code string) error {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.MFAEnabled {
+		return utils.NewValidationError("未启用两步验证", "mfa")
+	}
+	required, err := s.Required(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return utils.NewValidationError("当前角色要求启用两步验证，不可停用", "mfa")
+	}
+	if err := s.verify(ctx, user, code); err != nil {
+		return err
+	}
+	return s.clear(ctx, user)
+}
+
+// RegenerateRecoveryCodes 校验验证码后重新生成恢复码，旧恢复码全部作废
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.MFAEnabled {
+		return nil, utils.NewValidationError("未启用两步验证", "mfa")
+	}
+	if err := s.verify(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		codes, err = s.replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	return codes, err
+}
+
+// Reset 管理员重置用户的两步验证（如丢失设备），并吊销其全部令牌
+func (s *MFAService) Reset(ctx context.Context, userID uint) error {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.clear(ctx, user); err != nil {
+		return err
+	}
+	return s.tokens.RevokeUserTokens(ctx, user.ID)
+}
+
+// enable 校验待确认密钥的验证码，启用两步验证并生成恢复码
+func (s *MFAService) enable(ctx context.Context, user *models.User, code string) ([]string, error) {
+	if user.MFASecret == "" {
+		return nil, utils.NewValidationError("请先生成两步验证密钥", "mfa")
+	}
+	if err := s.verifyTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Update("mfa_enabled", true).Error; err != nil {
+			return fmt.Errorf("启用两步验证失败: %w", err)
+		}
+		var err error
+		codes, err = s.replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// clear 清除密钥和恢复码
+func (s *MFAService) clear(ctx context.Context, user *models.User) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Updates(map[string]interface{}{"mfa_enabled": false, "mfa_secret": ""}).Error; err != nil {
+			return fmt.Errorf("停用两步验证失败: %w", err)
+		}
+		if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+			return fmt.Errorf("删除恢复码失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// verify 校验 TOTP 验证码或恢复码
+func (s *MFAService) verify(ctx context.Context, user *models.User, code string) error {
+	if !user.MFAEnabled || user.MFASecret == "" {
+		return utils.NewValidationError("未启用两步验证", "mfa")
+	}
+	// 恢复码长度与验证码不同，据此区分
+	if len(strings.TrimSpace(code)) == utils.TOTPDigits {
+		return s.verifyTOTP(ctx, user, code)
+	}
+	return s.useRecoveryCode(ctx, user, code)
+}
+
+// verifyTOTP 校验 TOTP 验证码，同一时间步的验证码不能重复使用
+func (s *MFAService) verifyTOTP(ctx context.Context, user *models.User, code string) error {
+	if err := s.checkFailures(ctx, user.ID); err != nil {
+		return err
+	}
+
+	// 密钥仅在此处解密
+	secret, err := s.secrets.Open(user.MFASecret)
+	if err != nil {
+		return fmt.Errorf("解密TOTP密钥失败: %w", err)
+	}
+	step, ok := utils.ValidateTOTP(secret, code, time.Now(), s.config.ClockSkew)
+	if !ok {
+		return s.recordFailure(ctx, user.ID)
+	}
+
+	var lastStep int64
+	if err := s.cache.GetObject(ctx, mfaLastStepKey(user.ID), &lastStep); err != nil && !errors.Is(err, cache.ErrNotFound) {
+		return fmt.Errorf("查询验证码使用记录失败: %w", err)
+	}
+	if step <= lastStep {
+		return s.recordFailure(ctx, user.ID)
+	}
+	ttl := time.Duration(2*s.config.ClockSkew+1) * utils.TOTPPeriod
+	if err := s.cache.SetObject(ctx, mfaLastStepKey(user.ID), step, ttl); err != nil {
+		return fmt.Errorf("记录验证码使用失败: %w", err)
+	}
+	return s.resetFailures(ctx, user.ID)
+}
+
+// useRecoveryCode 校验并作废恢复码
+func (s *MFAService) useRecoveryCode(ctx context.Context, user *models.User, code string) error {
+	if err := s.checkFailures(ctx, user.ID); err != nil {
+		return err
+	}
+
+	result := s.db.WithContext(ctx).Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, hashToken(normalizeRecoveryCode(code))).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("校验恢复码失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return s.recordFailure(ctx, user.ID)
+	}
+	return s.resetFailures(ctx, user.ID)
+}
+
+// replaceRecoveryCodes 删除旧恢复码并生成新的一组
+func (s *MFAService) replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+		return nil, fmt.Errorf("删除恢复码失败: %w", err)
+	}
+
+	codes := make([]string, 0, s.config.RecoveryCodeCount)
+	records := make([]models.MFARecoveryCode, 0, s.config.RecoveryCodeCount)
+	for i := 0; i < s.config.RecoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		records = append(records, models.MFARecoveryCode{UserID: userID, CodeHash: hashToken(normalizeRecoveryCode(code))})
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, fmt.Errorf("保存恢复码失败: %w", err)
+	}
+	return codes, nil
+}
+
+// checkFailures 验证码错误次数过多时拒绝继续尝试
+func (s *MFAService) checkFailures(ctx context.Context, userID uint) error {
+	var failures int64
+	if err := s.cache.GetObject(ctx, mfaFailuresKey(userID), &failures); err != nil {
+		if errors.Is(err, cache.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("查询验证失败次数失败: %w", err)
+	}
+	if failures >= int64(s.config.MaxAttempts) {
+		return utils.NewTooManyAttemptsError("验证码错误次数过多，请稍后再试", s.config.ChallengeTTL)
+	}
+	return nil
+}
+
+// recordFailure 记录一次验证码错误并返回统一的认证错误
+func (s *MFAService) recordFailure(ctx context.Context, userID uint) error {
+	if _, err := s.cache.Incr(ctx, mfaFailuresKey(userID), s.config.ChallengeTTL); err != nil {
+		return fmt.Errorf("记录验证失败次数失败: %w", err)
+	}
+	return utils.NewAuthError("验证码错误")
+}
+
+func (s *MFAService) resetFailures(ctx context.Context, userID uint) error {
+	if err := s.cache.Del(ctx, mfaFailuresKey(userID)); err != nil {
+		return fmt.Errorf("重置验证失败次数失败: %w", err)
+	}
+	return nil
+}
+
+func (s *MFAService) loadChallenge(ctx context.Context, token string) (*mfaChallenge, error) {
+	var challenge mfaChallenge
+	if err := s.cache.GetObject(ctx, mfaChallengeKey(hashToken(token)), &challenge); err != nil {
+		if errors.Is(err, cache.ErrNotFound) {
+			return nil, utils.NewAuthError("登录验证已过期，请重新登录")
+		}
+		return nil, fmt.Errorf("查询登录验证状态失败: %w", err)
+	}
+	return &challenge, nil
+}
+
+func (s *MFAService) findUser(ctx context.Context, userID uint) (*models.User, error) {
+	var user models.User
+	if err := s.db.WithContext(ctx).First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("用户不存在", "user")
+		}
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+	return &user, nil
+}
+
+// generateRecoveryCode 生成形如 abcd-efgh-ijkl 的恢复码
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成恢复码失败: %w", err)
+	}
+	raw := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf))[:12]
+	return raw[:4] + "-" + raw[4:8] + "-" + raw[8:], nil
+}
+
+// normalizeRecoveryCode 忽略大小写、空白和分隔符
+func normalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(code)))
+}
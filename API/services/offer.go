@@ -0,0 +1,503 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"API/models"
+	"API/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OfferLetterData 渲染录用通知模板时可使用的字段，如 {{.Position}}、{{money .Salary}}、{{date .StartDate}}
+type OfferLetterData struct {
+	CandidateName string
+	Email         string
+	JobTitle      string
+	Position      string
+	Department    string
+	Salary        float64
+	StartDate     time.Time
+	ExpiresAt     time.Time
+}
+
+// offerTemplateFuncs 模板中可用的格式化函数
+var offerTemplateFuncs = template.FuncMap{
+	"date":  func(t time.Time) string { return t.Format("2006-01-02") },
+	"money": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+}
+
+// OfferInput 起草或修改录用通知时提交的信息
+type OfferInput struct {
+	TemplateID uint      `json:"template_id" binding:"required"`
+	Position   string    `json:"position" binding:"required,max=50"`
+	Department string    `json:"department" binding:"required,max=50"`
+	SalaryBase float64   `json:"salary_base" binding:"required,gt=0"`
+	StartDate  time.Time `json:"start_date" binding:"required"`
+	ExpiresAt  time.Time `json:"expires_at" binding:"required"`
+}
+
+// OfferService 录用通知模板与录用流程
+type OfferService struct {
+	db     *gorm.DB
+	tokens *TokenService
+}
+
+func NewOfferService(db *gorm.DB, tokens *TokenService) *OfferService {
+	return &OfferService{db: db, tokens: tokens}
+}
+
+// CreateTemplate 创建录用通知模板，保存前校验模板语法和字段
+func (s *OfferService) CreateTemplate(ctx context.Context, tpl *models.OfferTemplate) error {
+	if err := validateOfferTemplate(tpl.Name, tpl.Body); err != nil {
+		return err
+	}
+	if err := checkTemplateName(s.db.WithContext(ctx), tpl.Name, 0); err != nil {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Create(tpl).Error; err != nil {
+		return fmt.Errorf("创建模板失败: %w", err)
+	}
+	return nil
+}
+
+// ListTemplates 获取全部录用通知模板
+func (s *OfferService) ListTemplates(ctx context.Context) ([]models.OfferTemplate, error) {
+	var templates []models.OfferTemplate
+	if err := s.db.WithContext(ctx).Order("name ASC").Find(&templates).Error; err != nil {
+		return nil, fmt.Errorf("查询模板失败: %w", err)
+	}
+	return templates, nil
+}
+
+// UpdateTemplate 更新录用通知模板，已生成的录用通知正文不受影响
+func (s *OfferService) UpdateTemplate(ctx context.Context, templateID uint, name, body string) (*models.OfferTemplate, error) {
+	if err := validateOfferTemplate(name, body); err != nil {
+		return nil, err
+	}
+	var tpl models.OfferTemplate
+	if err := s.db.WithContext(ctx).First(&tpl, templateID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("模板不存在", "offer_template")
+		}
+		return nil, fmt.Errorf("查询模板失败: %w", err)
+	}
+	if err := checkTemplateName(s.db.WithContext(ctx), name, tpl.ID); err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Model(&tpl).Updates(map[string]interface{}{"name": name, "body": body}).Error; err != nil {
+		return nil, fmt.Errorf("更新模板失败: %w", err)
+	}
+	return &tpl, nil
+}
+
+// CreateOffer 为已进入录用阶段的申请起草录用通知，每个申请同时只能有一份有效的录用通知
+func (s *OfferService) CreateOffer(ctx context.Context, applicationID, operatorID uint, input OfferInput) (*models.Offer, error) {
+	if err := validateOfferInput(input); err != nil {
+		return nil, err
+	}
+
+	var offer models.Offer
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var application models.Application
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("User").Preload("Job").
+			First(&application, applicationID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewNotFoundError("申请记录不存在", "application")
+			}
+			return fmt.Errorf("查询申请失败: %w", err)
+		}
+		if application.Status != "hired" {
+			return utils.NewValidationError("申请进入录用阶段后才能发放录用通知", "application")
+		}
+
+		var open int64
+		if err := tx.Model(&models.Offer{}).
+			Where("application_id = ? AND status IN ?", application.ID,
+				[]string{models.OfferDraft, models.OfferApproved, models.OfferSent, models.OfferAccepted}).
+			Count(&open).Error; err != nil {
+			return fmt.Errorf("查询录用通知失败: %w", err)
+		}
+		if open > 0 {
+			return utils.NewValidationError("该申请已有进行中的录用通知", "application")
+		}
+
+		offer = models.Offer{
+			ApplicationID: application.ID,
+			Status:        models.OfferDraft,
+			CreatedBy:     operatorID,
+		}
+		return renderOffer(tx, &offer, application, input)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// UpdateOffer 修改尚未发送的录用通知并重新生成正文，修改后回到草稿状态等待审批
+func (s *OfferService) UpdateOffer(ctx context.Context, offerID uint, input OfferInput) (*models.Offer, error) {
+	if err := validateOfferInput(input); err != nil {
+		return nil, err
+	}
+
+	var offer models.Offer
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOffer(tx, offerID, &offer); err != nil {
+			return err
+		}
+		if offer.Status != models.OfferDraft && offer.Status != models.OfferApproved {
+			return utils.NewValidationError("只能修改尚未发送的录用通知", "offer")
+		}
+		// 修改已审批的录用通知需重新审批
+		offer.Status = models.OfferDraft
+		offer.ApprovedBy = nil
+		offer.ApprovedAt = nil
+		var application models.Application
+		if err := tx.Preload("User").Preload("Job").First(&application, offer.ApplicationID).Error; err != nil {
+			return fmt.Errorf("查询申请失败: %w", err)
+		}
+		return renderOffer(tx, &offer, application, input)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// ApproveOffer 审批草稿录用通知，审批人不能是起草人
+func (s *OfferService) ApproveOffer(ctx context.Context, offerID, approverID uint) (*models.Offer, error) {
+	var offer models.Offer
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOffer(tx, offerID, &offer); err != nil {
+			return err
+		}
+		if offer.Status != models.OfferDraft {
+			return utils.NewValidationError("只能审批草稿状态的录用通知", "offer")
+		}
+		if offer.CreatedBy == approverID {
+			return utils.NewForbiddenError("不能审批自己起草的录用通知")
+		}
+		now := time.Now()
+		offer.Status = models.OfferApproved
+		offer.ApprovedBy = &approverID
+		offer.ApprovedAt = &now
+		return tx.Model(&offer).Updates(map[string]interface{}{
+			"status":      offer.Status,
+			"approved_by": approverID,
+			"approved_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// SendOffer 将已审批的录用通知发送给候选人
+func (s *OfferService) SendOffer(ctx context.Context, offerID uint) (*models.Offer, error) {
+	var offer models.Offer
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOffer(tx, offerID, &offer); err != nil {
+			return err
+		}
+		if offer.Status != models.OfferApproved {
+			return utils.NewValidationError("录用通知审批通过后才能发送", "offer")
+		}
+		if !offer.ExpiresAt.After(time.Now()) {
+			return utils.NewValidationError("答复截止时间已过，请修改录用通知后重新审批", "expires_at")
+		}
+		now := time.Now()
+		offer.Status = models.OfferSent
+		offer.SentAt = &now
+		return tx.Model(&offer).Updates(map[string]interface{}{"status": offer.Status, "sent_at": now}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// ListApplicationOffers 获取申请的全部录用通知
+func (s *OfferService) ListApplicationOffers(ctx context.Context, applicationID uint) ([]models.Offer, error) {
+	var offers []models.Offer
+	if err := s.db.WithContext(ctx).Preload("Template").
+		Where("application_id = ?", applicationID).
+		Order("id DESC").
+		Find(&offers).Error; err != nil {
+		return nil, fmt.Errorf("查询录用通知失败: %w", err)
+	}
+	markExpired(offers, time.Now())
+	return offers, nil
+}
+
+// ListMyOffers 获取发给当前用户的录用通知，不含尚未发送的草稿和发送前作废的录用通知
+func (s *OfferService) ListMyOffers(ctx context.Context, userID uint) ([]models.Offer, error) {
+	var offers []models.Offer
+	if err := s.db.WithContext(ctx).Preload("Application.Job").
+		Joins("JOIN applications ON applications.id = offers.application_id").
+		Where("applications.user_id = ?", userID).
+		Where("offers.status IN ? OR (offers.status = ? AND offers.sent_at IS NOT NULL)",
+			[]string{models.OfferSent, models.OfferAccepted, models.OfferDeclined, models.OfferExpired}, models.OfferVoided).
+		Order("offers.id DESC").
+		Find(&offers).Error; err != nil {
+		return nil, fmt.Errorf("查询录用通知失败: %w", err)
+	}
+	markExpired(offers, time.Now())
+	return offers, nil
+}
+
+// AcceptOffer 候选人接受录用通知，并按录用通知将其转为员工
+func (s *OfferService) AcceptOffer(ctx context.Context, offerID, userID uint) (*models.Offer, error) {
+	offer, err := s.respond(ctx, offerID, userID, func(tx *gorm.DB, offer *models.Offer, application models.Application) error {
+		now := time.Now()
+		offer.Status = models.OfferAccepted
+		offer.RespondedAt = &now
+		if err := tx.Model(offer).Updates(map[string]interface{}{"status": offer.Status, "responded_at": now}).Error; err != nil {
+			return fmt.Errorf("更新录用通知失败: %w", err)
+		}
+		return convertToEmployee(tx, application.UserID, *offer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 令牌中携带角色，转为员工后需重新登录
+	if err := s.tokens.RevokeUserTokens(ctx, userID); err != nil {
+		return nil, err
+	}
+	return offer, nil
+}
+
+// DeclineOffer 候选人拒绝录用通知
+func (s *OfferService) DeclineOffer(ctx context.Context, offerID, userID uint, reason string) (*models.Offer, error) {
+	return s.respond(ctx, offerID, userID, func(tx *gorm.DB, offer *models.Offer, _ models.Application) error {
+		now := time.Now()
+		offer.Status = models.OfferDeclined
+		offer.RespondedAt = &now
+		offer.DeclineReason = reason
+		return tx.Model(offer).Updates(map[string]interface{}{
+			"status":         offer.Status,
+			"responded_at":   now,
+			"decline_reason": reason,
+		}).Error
+	})
+}
+
+// respond 校验录用通知属于该候选人且仍在答复期内，再执行答复操作。
+// 先锁定申请再锁定录用通知，与撤回申请的加锁顺序一致，避免撤回与接受并发
+func (s *OfferService) respond(ctx context.Context, offerID, userID uint, fn func(tx *gorm.DB, offer *models.Offer, application models.Application) error) (*models.Offer, error) {
+	var offer models.Offer
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&offer, offerID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewNotFoundError("录用通知不存在", "offer")
+			}
+			return fmt.Errorf("查询录用通知失败: %w", err)
+		}
+		var application models.Application
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&application, offer.ApplicationID).Error; err != nil {
+			return fmt.Errorf("查询申请失败: %w", err)
+		}
+		if err := lockOffer(tx, offerID, &offer); err != nil {
+			return err
+		}
+		// 不向其他用户暴露录用通知是否存在
+		if application.UserID != userID || offer.SentAt == nil {
+			return utils.NewNotFoundError("录用通知不存在", "offer")
+		}
+		if offer.Status == models.OfferVoided {
+			return utils.NewValidationError("申请已撤回或淘汰，录用通知已作废", "offer")
+		}
+		if offer.Status != models.OfferSent {
+			return utils.NewValidationError("录用通知已答复或已过期", "offer")
+		}
+		if !offer.ExpiresAt.After(time.Now()) {
+			return utils.NewValidationError("录用通知已过期", "offer")
+		}
+		return fn(tx, &offer, application)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// openOfferStatuses 尚未答复的录用通知状态
+var openOfferStatuses = []string{models.OfferDraft, models.OfferApproved, models.OfferSent}
+
+// voidOpenOffers 申请撤回或淘汰时作废其尚未答复的录用通知，applicationIDs 可以是申请ID或申请ID子查询
+func voidOpenOffers(tx *gorm.DB, applicationIDs interface{}) error {
+	if err := tx.Model(&models.Offer{}).
+		Where("application_id IN (?) AND status IN ?", applicationIDs, openOfferStatuses).
+		Update("status", models.OfferVoided).Error; err != nil {
+		return fmt.Errorf("作废录用通知失败: %w", err)
+	}
+	return nil
+}
+
+// convertToEmployee 按录用通知更新用户的员工信息，并将候选人角色替换为员工角色
+func convertToEmployee(tx *gorm.DB, userID uint, offer models.Offer) error {
+	var user models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+		return fmt.Errorf("查询用户失败: %w", err)
+	}
+	if err := tx.Model(&user).Updates(map[string]interface{}{
+		"usertype":    "employee",
+		"hire_date":   offer.StartDate,
+		"department":  offer.Department,
+		"position":    offer.Position,
+		"salary_base": offer.SalaryBase,
+	}).Error; err != nil {
+		return fmt.Errorf("更新员工信息失败: %w", err)
+	}
+
+	var candidate, employee models.Role
+	if err := tx.Where("name = ?", models.RoleCandidate).First(&candidate).Error; err != nil {
+		return fmt.Errorf("查询候选人角色失败: %w", err)
+	}
+	if err := tx.Where("name = ?", models.RoleEmployee).First(&employee).Error; err != nil {
+		return fmt.Errorf("查询员工角色失败: %w", err)
+	}
+	if err := tx.Model(&user).Association("Roles").Delete(&candidate); err != nil {
+		return fmt.Errorf("移除候选人角色失败: %w", err)
+	}
+	if err := tx.Model(&user).Association("Roles").Append(&employee); err != nil {
+		return fmt.Errorf("分配员工角色失败: %w", err)
+	}
+	return nil
+}
+
+// renderOffer 按模板生成录用通知正文并保存
+func renderOffer(tx *gorm.DB, offer *models.Offer, application models.Application, input OfferInput) error {
+	var tpl models.OfferTemplate
+	if err := tx.First(&tpl, input.TemplateID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.NewNotFoundError("模板不存在", "offer_template")
+		}
+		return fmt.Errorf("查询模板失败: %w", err)
+	}
+
+	offer.TemplateID = tpl.ID
+	offer.Position = strings.TrimSpace(input.Position)
+	offer.Department = strings.TrimSpace(input.Department)
+	offer.SalaryBase = input.SalaryBase
+	offer.StartDate = input.StartDate
+	offer.ExpiresAt = input.ExpiresAt
+
+	content, err := executeOfferTemplate(tpl.Name, tpl.Body, OfferLetterData{
+		CandidateName: application.User.Username,
+		Email:         application.User.Email,
+		JobTitle:      application.Job.Title,
+		Position:      offer.Position,
+		Department:    offer.Department,
+		Salary:        offer.SalaryBase,
+		StartDate:     offer.StartDate,
+		ExpiresAt:     offer.ExpiresAt,
+	})
+	if err != nil {
+		return utils.NewValidationError("生成录用通知失败: "+err.Error(), "template_id")
+	}
+	offer.Content = content
+
+	if err := tx.Save(offer).Error; err != nil {
+		return fmt.Errorf("保存录用通知失败: %w", err)
+	}
+	return nil
+}
+
+func executeOfferTemplate(name, body string, data OfferLetterData) (string, error) {
+	tpl, err := template.New(name).Funcs(offerTemplateFuncs).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := tpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// validateOfferTemplate 用示例数据试渲染，提前发现语法错误和不存在的字段
+func validateOfferTemplate(name, body string) error {
+	if strings.TrimSpace(name) == "" || strings.TrimSpace(body) == "" {
+		return utils.NewValidationError("模板名称和正文不能为空", "body")
+	}
+	_, err := executeOfferTemplate(name, body, OfferLetterData{
+		CandidateName: "张三",
+		Email:         "zhangsan@example.com",
+		JobTitle:      "后端工程师",
+		Position:      "后端工程师",
+		Department:    "研发部",
+		Salary:        10000,
+		StartDate:     time.Now(),
+		ExpiresAt:     time.Now(),
+	})
+	if err != nil {
+		return utils.NewValidationError("模板无效: "+err.Error(), "body")
+	}
+	return nil
+}
+
+// checkTemplateName 检查模板名称是否已被其他模板使用
+func checkTemplateName(db *gorm.DB, name string, excludeID uint) error {
+	var count int64
+	if err := db.Unscoped().Model(&models.OfferTemplate{}).
+		Where("name = ? AND id <> ?", name, excludeID).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("查询模板失败: %w", err)
+	}
+	if count > 0 {
+		return utils.NewValidationError("模板名称已存在", "name")
+	}
+	return nil
+}
+
+func validateOfferInput(input OfferInput) error {
+	if input.SalaryBase <= 0 {
+		return utils.NewValidationError("基本工资必须大于0", "salary_base")
+	}
+	if !input.ExpiresAt.After(time.Now()) {
+		return utils.NewValidationError("答复截止时间必须晚于当前时间", "expires_at")
+	}
+	if input.StartDate.Before(input.ExpiresAt.Truncate(24 * time.Hour)) {
+		return utils.NewValidationError("入职日期不能早于答复截止日期", "start_date")
+	}
+	return nil
+}
+
+func lockOffer(tx *gorm.DB, offerID uint, offer *models.Offer) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(offer, offerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.NewNotFoundError("录用通知不存在", "offer")
+		}
+		return fmt.Errorf("查询录用通知失败: %w", err)
+	}
+	return nil
+}
+
+// ExpireOffers 将超过答复截止时间仍未答复的录用通知标记为已过期，返回标记的数量，由后台任务定期执行
+func (s *OfferService) ExpireOffers(ctx context.Context) (int64, error) {
+	result := s.db.WithContext(ctx).Model(&models.Offer{}).
+		Where("status = ? AND expires_at <= ?", models.OfferSent, time.Now()).
+		Update("status", models.OfferExpired)
+	if result.Error != nil {
+		return 0, fmt.Errorf("更新过期录用通知失败: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// markExpired 将已过答复截止时间、后台任务尚未处理的录用通知按已过期返回，不写入数据库
+func markExpired(offers []models.Offer, now time.Time) {
+	for i := range offers {
+		if offers[i].Status == models.OfferSent && !offers[i].ExpiresAt.After(now) {
+			offers[i].Status = models.OfferExpired
+		}
+	}
+}
@@ -0,0 +1,469 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"API/models"
+	"API/utils"
+)
+
+// offerFixture 录用通知测试数据：hr 起草、approver 审批，candidate 的申请已录用
+type offerFixture struct {
+	svc         *OfferService
+	store       *memoryCache
+	hr          models.User
+	approver    models.User
+	candidate   models.User
+	application models.Application
+	template    models.OfferTemplate
+}
+
+func newOfferFixture(t *testing.T) *offerFixture {
+	t.Helper()
+	db := newTestDB(t, &models.User{}, &models.Role{}, &models.Job{}, &models.PipelineStage{},
+		&models.Application{}, &models.ApplicationStageHistory{}, &models.Interview{}, &models.OfferTemplate{}, &models.Offer{})
+	store := newMemoryCache()
+	f := &offerFixture{svc: NewOfferService(db, newTestTokenService(t, store)), store: store}
+
+	roles := []models.Role{{Name: models.RoleCandidate}, {Name: models.RoleEmployee}}
+	if err := db.Create(&roles).Error; err != nil {
+		t.Fatalf("创建角色失败: %v", err)
+	}
+	users := []*models.User{&f.hr, &f.approver, &f.candidate}
+	for i, name := range []string{"hr", "approver", "张三"} {
+		*users[i] = models.User{Username: name, Email: name + "@example.com", Phone: name, Usertype: "employee", Active: true}
+	}
+	f.candidate.Usertype = "candidate"
+	f.candidate.Roles = roles[:1]
+	for _, user := range users {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("创建用户失败: %v", err)
+		}
+	}
+
+	job := models.Job{Title: "后端工程师", Status: models.JobOpen}
+	if err := db.Create(&job).Error; err != nil {
+		t.Fatalf("创建职位失败: %v", err)
+	}
+	stages, err := ensurePipeline(db, job.ID)
+	if err != nil {
+		t.Fatalf("ensurePipeline: %v", err)
+	}
+	var hired uint
+	for _, stage := range stages {
+		if stage.Kind == models.StageKindHired {
+			hired = stage.ID
+		}
+	}
+	f.application = models.Application{UserID: f.candidate.ID, JobID: job.ID, Status: "hired", StageID: &hired}
+	if err := db.Create(&f.application).Error; err != nil {
+		t.Fatalf("创建申请失败: %v", err)
+	}
+
+	f.template = models.OfferTemplate{
+		Name: "标准录用通知",
+		Body: "{{.CandidateName}}：录用您为{{.Department}}{{.Position}}（应聘{{.JobTitle}}），月薪{{money .Salary}}元，{{date .StartDate}}入职。",
+	}
+	if err := f.svc.CreateTemplate(context.Background(), &f.template); err != nil {
+		t.Fatalf("CreateTemplate: %v", err)
+	}
+	return f
+}
+
+// input 返回一周后截止、两周后入职的录用通知信息
+func (f *offerFixture) input() OfferInput {
+	start := time.Now().AddDate(0, 0, 14)
+	return OfferInput{
+		TemplateID: f.template.ID,
+		Position:   " 高级工程师 ",
+		Department: "研发部",
+		SalaryBase: 20000,
+		StartDate:  time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local),
+		ExpiresAt:  time.Now().AddDate(0, 0, 7),
+	}
+}
+
+// sentOffer 起草、审批并发送一份录用通知
+func (f *offerFixture) sentOffer(t *testing.T) *models.Offer {
+	t.Helper()
+	ctx := context.Background()
+	offer, err := f.svc.CreateOffer(ctx, f.application.ID, f.hr.ID, f.input())
+	if err != nil {
+		t.Fatalf("CreateOffer: %v", err)
+	}
+	if _, err := f.svc.ApproveOffer(ctx, offer.ID, f.approver.ID); err != nil {
+		t.Fatalf("ApproveOffer: %v", err)
+	}
+	offer, err = f.svc.SendOffer(ctx, offer.ID)
+	if err != nil {
+		t.Fatalf("SendOffer: %v", err)
+	}
+	return offer
+}
+
+// offerStatus 查询录用通知的当前状态
+func (f *offerFixture) offerStatus(t *testing.T, offerID uint) string {
+	t.Helper()
+	var offer models.Offer
+	if err := f.svc.db.First(&offer, offerID).Error; err != nil {
+		t.Fatalf("查询录用通知失败: %v", err)
+	}
+	return offer.Status
+}
+
+func TestMarkExpired(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	offers := []models.Offer{
+		{Status: models.OfferSent, ExpiresAt: now.Add(-time.Minute)},
+		{Status: models.OfferSent, ExpiresAt: now},
+		{Status: models.OfferSent, ExpiresAt: now.Add(time.Minute)},
+		{Status: models.OfferAccepted, ExpiresAt: now.Add(-time.Hour)},
+		{Status: models.OfferApproved, ExpiresAt: now.Add(-time.Hour)},
+	}
+	markExpired(offers, now)
+
+	want := []string{models.OfferExpired, models.OfferExpired, models.OfferSent, models.OfferAccepted, models.OfferApproved}
+	for i, offer := range offers {
+		if offer.Status != want[i] {
+			t.Errorf("第%d个录用通知状态为 %s，期望 %s", i, offer.Status, want[i])
+		}
+	}
+}
+
+func TestExecuteOfferTemplate(t *testing.T) {
+	data := OfferLetterData{
+		CandidateName: "张三",
+		Position:      "后端工程师",
+		Salary:        12345.6,
+		StartDate:     time.Date(2026, 4, 1, 0, 0, 0, 0, time.Local),
+	}
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{"字段与格式化函数", "{{.CandidateName}}，{{.Position}}，{{money .Salary}}，{{date .StartDate}}", "张三，后端工程师，12345.60，2026-04-01", false},
+		{"不存在的字段", "{{.Bonus}}", "", true},
+		{"语法错误", "{{.Position", "", true},
+		{"未定义的函数", "{{upper .Position}}", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := executeOfferTemplate(tt.name, tt.body, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("executeOfferTemplate 错误为 %v, 期望出错 %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("正文为 %q, 期望 %q", got, tt.want)
+			}
+			// 保存模板时同样拒绝无效模板
+			if err := validateOfferTemplate(tt.name, tt.body); (err != nil) != tt.wantErr {
+				t.Errorf("validateOfferTemplate 错误为 %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateOffer(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, f *offerFixture) (uint, OfferInput)
+		wantErr string
+	}{
+		{"渲染模板生成正文", func(t *testing.T, f *offerFixture) (uint, OfferInput) {
+			return f.application.ID, f.input()
+		}, ""},
+		{"申请尚未录用", func(t *testing.T, f *offerFixture) (uint, OfferInput) {
+			f.svc.db.Model(&f.application).Update("status", "interviewed")
+			return f.application.ID, f.input()
+		}, "申请进入录用阶段后才能发放录用通知"},
+		{"已有进行中的录用通知", func(t *testing.T, f *offerFixture) (uint, OfferInput) {
+			f.sentOffer(t)
+			return f.application.ID, f.input()
+		}, "该申请已有进行中的录用通知"},
+		{"候选人拒绝后可重新起草", func(t *testing.T, f *offerFixture) (uint, OfferInput) {
+			offer := f.sentOffer(t)
+			if _, err := f.svc.DeclineOffer(ctx, offer.ID, f.candidate.ID, "薪资不符"); err != nil {
+				t.Fatalf("DeclineOffer: %v", err)
+			}
+			return f.application.ID, f.input()
+		}, ""},
+		{"答复截止时间已过", func(t *testing.T, f *offerFixture) (uint, OfferInput) {
+			input := f.input()
+			input.ExpiresAt = time.Now().Add(-time.Minute)
+			return f.application.ID, input
+		}, "答复截止时间必须晚于当前时间"},
+		{"入职日期早于截止日期", func(t *testing.T, f *offerFixture) (uint, OfferInput) {
+			input := f.input()
+			input.StartDate = time.Now().AddDate(0, 0, 3)
+			return f.application.ID, input
+		}, "入职日期不能早于答复截止日期"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOfferFixture(t)
+			applicationID, input := tt.prepare(t, f)
+			offer, err := f.svc.CreateOffer(ctx, applicationID, f.hr.ID, input)
+			if tt.wantErr != "" {
+				if validationMessage(err) != tt.wantErr {
+					t.Fatalf("期望错误 %q，得到 %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateOffer: %v", err)
+			}
+			want := "张三：录用您为研发部高级工程师（应聘后端工程师），月薪20000.00元，" + input.StartDate.Format("2006-01-02") + "入职。"
+			if offer.Status != models.OfferDraft || offer.Content != want {
+				t.Errorf("录用通知为 %s %q, 期望草稿 %q", offer.Status, offer.Content, want)
+			}
+		})
+	}
+
+	f := newOfferFixture(t)
+	input := f.input()
+	input.TemplateID = 99
+	var notFound *utils.NotFoundError
+	if _, err := f.svc.CreateOffer(ctx, f.application.ID, f.hr.ID, input); !errors.As(err, &notFound) {
+		t.Errorf("模板不存在时期望 NotFoundError，得到 %v", err)
+	}
+}
+
+func TestOfferApprovalFlow(t *testing.T) {
+	ctx := context.Background()
+	f := newOfferFixture(t)
+	offer, err := f.svc.CreateOffer(ctx, f.application.ID, f.hr.ID, f.input())
+	if err != nil {
+		t.Fatalf("CreateOffer: %v", err)
+	}
+
+	steps := []struct {
+		name    string
+		run     func() (*models.Offer, error)
+		wantErr string
+		status  string
+	}{
+		{"草稿不能发送", func() (*models.Offer, error) { return f.svc.SendOffer(ctx, offer.ID) }, "录用通知审批通过后才能发送", models.OfferDraft},
+		{"起草人不能审批", func() (*models.Offer, error) { return f.svc.ApproveOffer(ctx, offer.ID, f.hr.ID) }, "不能审批自己起草的录用通知", models.OfferDraft},
+		{"其他人审批", func() (*models.Offer, error) { return f.svc.ApproveOffer(ctx, offer.ID, f.approver.ID) }, "", models.OfferApproved},
+		{"已审批的不能重复审批", func() (*models.Offer, error) { return f.svc.ApproveOffer(ctx, offer.ID, f.approver.ID) }, "只能审批草稿状态的录用通知", models.OfferApproved},
+		{"修改后需重新审批", func() (*models.Offer, error) {
+			input := f.input()
+			input.SalaryBase = 22000
+			return f.svc.UpdateOffer(ctx, offer.ID, input)
+		}, "", models.OfferDraft},
+		{"重新审批", func() (*models.Offer, error) { return f.svc.ApproveOffer(ctx, offer.ID, f.approver.ID) }, "", models.OfferApproved},
+		{"发送", func() (*models.Offer, error) { return f.svc.SendOffer(ctx, offer.ID) }, "", models.OfferSent},
+		{"已发送的不能修改", func() (*models.Offer, error) { return f.svc.UpdateOffer(ctx, offer.ID, f.input()) }, "只能修改尚未发送的录用通知", models.OfferSent},
+	}
+	for _, step := range steps {
+		_, err := step.run()
+		switch {
+		case step.wantErr == "" && err != nil:
+			t.Fatalf("%s: %v", step.name, err)
+		case step.wantErr != "" && !strings.Contains(err.Error(), step.wantErr):
+			t.Fatalf("%s: 期望错误 %q，得到 %v", step.name, step.wantErr, err)
+		}
+		if got := f.offerStatus(t, offer.ID); got != step.status {
+			t.Fatalf("%s: 状态为 %s，期望 %s", step.name, got, step.status)
+		}
+	}
+}
+
+func TestRespondToOffer(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, f *offerFixture) uint // 返回录用通知ID
+		user    func(f *offerFixture) uint
+		accept  bool
+		wantErr string
+		status  string
+	}{
+		{"接受", func(t *testing.T, f *offerFixture) uint { return f.sentOffer(t).ID },
+			func(f *offerFixture) uint { return f.candidate.ID }, true, "", models.OfferAccepted},
+		{"拒绝", func(t *testing.T, f *offerFixture) uint { return f.sentOffer(t).ID },
+			func(f *offerFixture) uint { return f.candidate.ID }, false, "", models.OfferDeclined},
+		{"其他用户不可见", func(t *testing.T, f *offerFixture) uint { return f.sentOffer(t).ID },
+			func(f *offerFixture) uint { return f.hr.ID }, true, "录用通知不存在", models.OfferSent},
+		{"尚未发送不可见", func(t *testing.T, f *offerFixture) uint {
+			offer, err := f.svc.CreateOffer(ctx, f.application.ID, f.hr.ID, f.input())
+			if err != nil {
+				t.Fatalf("CreateOffer: %v", err)
+			}
+			return offer.ID
+		}, func(f *offerFixture) uint { return f.candidate.ID }, true, "录用通知不存在", models.OfferDraft},
+		{"不能重复答复", func(t *testing.T, f *offerFixture) uint {
+			offer := f.sentOffer(t)
+			if _, err := f.svc.DeclineOffer(ctx, offer.ID, f.candidate.ID, ""); err != nil {
+				t.Fatalf("DeclineOffer: %v", err)
+			}
+			return offer.ID
+		}, func(f *offerFixture) uint { return f.candidate.ID }, true, "录用通知已答复或已过期", models.OfferDeclined},
+		{"已过答复截止时间", func(t *testing.T, f *offerFixture) uint {
+			offer := f.sentOffer(t)
+			f.svc.db.Model(offer).Update("expires_at", time.Now().Add(-time.Minute))
+			return offer.ID
+		}, func(f *offerFixture) uint { return f.candidate.ID }, true, "录用通知已过期", models.OfferSent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOfferFixture(t)
+			offerID := tt.prepare(t, f)
+			var err error
+			if tt.accept {
+				_, err = f.svc.AcceptOffer(ctx, offerID, tt.user(f))
+			} else {
+				_, err = f.svc.DeclineOffer(ctx, offerID, tt.user(f), "已接受其他机会")
+			}
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("答复录用通知失败: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("期望错误 %q，得到 %v", tt.wantErr, err)
+			}
+			if got := f.offerStatus(t, offerID); got != tt.status {
+				t.Errorf("状态为 %s，期望 %s", got, tt.status)
+			}
+		})
+	}
+}
+
+func TestAcceptOfferConvertsToEmployee(t *testing.T) {
+	ctx := context.Background()
+	f := newOfferFixture(t)
+	offer := f.sentOffer(t)
+	pair, err := f.svc.tokens.IssueTokenPair(ctx, f.candidate.ID, []string{models.RoleCandidate})
+	if err != nil {
+		t.Fatalf("IssueTokenPair: %v", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	if _, err := f.svc.AcceptOffer(ctx, offer.ID, f.candidate.ID); err != nil {
+		t.Fatalf("AcceptOffer: %v", err)
+	}
+	var user models.User
+	if err := f.svc.db.Preload("Roles").First(&user, f.candidate.ID).Error; err != nil {
+		t.Fatalf("查询用户失败: %v", err)
+	}
+	if user.Usertype != "employee" || user.Position != "高级工程师" || user.Department != "研发部" ||
+		user.SalaryBase != 20000 || user.HireDate == nil || !user.HireDate.Equal(offer.StartDate) {
+		t.Errorf("员工信息为 %s %s %s %.2f %v", user.Usertype, user.Position, user.Department, user.SalaryBase, user.HireDate)
+	}
+	if len(user.Roles) != 1 || user.Roles[0].Name != models.RoleEmployee {
+		t.Errorf("角色为 %+v，期望仅有员工角色", user.Roles)
+	}
+
+	// 令牌中的角色已过时，需重新登录
+	access, err := f.svc.tokens.ParseAccessToken(pair.AccessToken)
+	if err != nil {
+		t.Fatalf("ParseAccessToken: %v", err)
+	}
+	if revoked, err := f.svc.tokens.IsRevoked(ctx, access); err != nil || !revoked {
+		t.Errorf("转为员工后原令牌应被吊销，得到 %v, %v", revoked, err)
+	}
+}
+
+func TestWithdrawVoidsOpenOffers(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, f *offerFixture) uint
+		wantErr string
+		status  string
+	}{
+		{"作废草稿", func(t *testing.T, f *offerFixture) uint {
+			offer, err := f.svc.CreateOffer(ctx, f.application.ID, f.hr.ID, f.input())
+			if err != nil {
+				t.Fatalf("CreateOffer: %v", err)
+			}
+			return offer.ID
+		}, "", models.OfferVoided},
+		{"作废已审批", func(t *testing.T, f *offerFixture) uint {
+			offer, err := f.svc.CreateOffer(ctx, f.application.ID, f.hr.ID, f.input())
+			if err != nil {
+				t.Fatalf("CreateOffer: %v", err)
+			}
+			if _, err := f.svc.ApproveOffer(ctx, offer.ID, f.approver.ID); err != nil {
+				t.Fatalf("ApproveOffer: %v", err)
+			}
+			return offer.ID
+		}, "", models.OfferVoided},
+		{"作废已发送未答复", func(t *testing.T, f *offerFixture) uint { return f.sentOffer(t).ID }, "", models.OfferVoided},
+		{"已拒绝的保持不变", func(t *testing.T, f *offerFixture) uint {
+			offer := f.sentOffer(t)
+			if _, err := f.svc.DeclineOffer(ctx, offer.ID, f.candidate.ID, ""); err != nil {
+				t.Fatalf("DeclineOffer: %v", err)
+			}
+			return offer.ID
+		}, "", models.OfferDeclined},
+		{"已接受不能撤回", func(t *testing.T, f *offerFixture) uint {
+			offer := f.sentOffer(t)
+			if _, err := f.svc.AcceptOffer(ctx, offer.ID, f.candidate.ID); err != nil {
+				t.Fatalf("AcceptOffer: %v", err)
+			}
+			return offer.ID
+		}, "已接受录用通知，不能撤回申请", models.OfferAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOfferFixture(t)
+			offerID := tt.prepare(t, f)
+			_, err := NewApplicationService(f.svc.db).WithdrawApplication(ctx, f.candidate.ID, f.application.ID, "")
+			if validationMessage(err) != tt.wantErr {
+				t.Fatalf("期望错误 %q，得到 %v", tt.wantErr, err)
+			}
+			if got := f.offerStatus(t, offerID); got != tt.status {
+				t.Errorf("状态为 %s，期望 %s", got, tt.status)
+			}
+		})
+	}
+
+	// 作废后候选人不能再接受
+	f := newOfferFixture(t)
+	offer := f.sentOffer(t)
+	if _, err := NewApplicationService(f.svc.db).WithdrawApplication(ctx, f.candidate.ID, f.application.ID, ""); err != nil {
+		t.Fatalf("WithdrawApplication: %v", err)
+	}
+	if _, err := f.svc.AcceptOffer(ctx, offer.ID, f.candidate.ID); validationMessage(err) != "申请已撤回或淘汰，录用通知已作废" {
+		t.Errorf("接受已作废的录用通知应失败，得到 %v", err)
+	}
+	offers, err := f.svc.ListMyOffers(ctx, f.candidate.ID)
+	if err != nil || len(offers) != 1 || offers[0].Status != models.OfferVoided {
+		t.Errorf("候选人应能看到已作废的录用通知，得到 %+v, %v", offers, err)
+	}
+}
+
+func TestRejectStageVoidsOpenOffers(t *testing.T) {
+	ctx := context.Background()
+	f := newOfferFixture(t)
+	offer := f.sentOffer(t)
+
+	// 录用阶段与淘汰阶段互换类型后，原录用阶段中申请的录用通知作废
+	stages, err := ensurePipeline(f.svc.db, f.application.JobID)
+	if err != nil {
+		t.Fatalf("ensurePipeline: %v", err)
+	}
+	inputs := make([]PipelineStageInput, len(stages))
+	for i, stage := range stages {
+		inputs[i] = PipelineStageInput{Code: stage.Code, Name: stage.Name, Kind: stage.Kind}
+		switch stage.Kind {
+		case models.StageKindHired:
+			inputs[i].Kind = models.StageKindRejected
+		case models.StageKindRejected:
+			inputs[i].Kind = models.StageKindHired
+		}
+	}
+	if _, err := NewPipelineService(f.svc.db).UpdatePipeline(ctx, f.application.JobID, inputs); err != nil {
+		t.Fatalf("UpdatePipeline: %v", err)
+	}
+	if got := f.offerStatus(t, offer.ID); got != models.OfferVoided {
+		t.Errorf("状态为 %s，期望 %s", got, models.OfferVoided)
+	}
+}
@@ -0,0 +1,405 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"API/models"
+	"API/utils"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OvertimeConfig 加班时长判定和计薪规则
+type OvertimeConfig struct {
+	Multipliers map[string]float64 // 各加班类型的计薪倍数
+	MinMinutes  int                // 超出班次不足该分钟数的不计加班
+	DailyHours  float64            // 计算小时工资的每日标准工时
+}
+
+// LoadOvertimeConfig 从配置文件加载加班规则
+func LoadOvertimeConfig() OvertimeConfig {
+	return OvertimeConfig{
+		Multipliers: map[string]float64{
+			models.OvertimeWeekday: viper.GetFloat64("overtime.multipliers.weekday"),
+			models.OvertimeWeekend: viper.GetFloat64("overtime.multipliers.weekend"),
+			models.OvertimeHoliday: viper.GetFloat64("overtime.multipliers.holiday"),
+		},
+		MinMinutes: viper.GetInt("overtime.min_minutes"),
+		DailyHours: viper.GetFloat64("overtime.standard_daily_hours"),
+	}
+}
+
+// OvertimeService 加班申请、审批及月度加班统计
+type OvertimeService struct {
+	db       *gorm.DB
+	workdays *WorkdayCalculator
+	config   OvertimeConfig
+}
+
+func NewOvertimeService(db *gorm.DB, workdays *WorkdayCalculator, config OvertimeConfig) *OvertimeService {
+	return &OvertimeService{db: db, workdays: workdays, config: config}
+}
+
+// OvertimeInput 提交加班申请的参数，日期格式为 YYYY-MM-DD
+type OvertimeInput struct {
+	Date   string  `json:"date" binding:"required"`
+	Hours  float64 `json:"hours" binding:"required,gt=0,lte=24"`
+	Reason string  `json:"reason" binding:"required,max=500"`
+}
+
+// OvertimeKindTotal 某类加班的月度时长
+type OvertimeKindTotal struct {
+	Hours         float64 `json:"hours"`          // 计薪时长
+	Multiplier    float64 `json:"multiplier"`     // 计薪倍数
+	WeightedHours float64 `json:"weighted_hours"` // 计薪时长乘以倍数
+}
+
+// OvertimeSummary 员工的月度加班统计，计薪时长为已批准时长与打卡记录中加班时长的较小值
+type OvertimeSummary struct {
+	UserID        uint                         `json:"user_id"`
+	Username      string                       `json:"username,omitempty"`
+	Month         string                       `json:"month"`
+	Kinds         map[string]OvertimeKindTotal `json:"kinds"`
+	TotalHours    float64                      `json:"total_hours"`
+	WeightedHours float64                      `json:"weighted_hours"` // 供薪资核算使用
+	DetectedHours float64                      `json:"detected_hours"` // 打卡记录中超出班次的时长，含未申请的部分
+}
+
+// Submit 提交加班申请。今天及以后的日期为事前申请；过去的日期为事后补报，
+// 须有已下班打卡的记录且申请时长不超过打卡记录中的加班时长
+func (s *OvertimeService) Submit(ctx context.Context, userID uint, input OvertimeInput) (*models.OvertimeRequest, error) {
+	date, err := time.ParseInLocation("2006-01-02", input.Date, time.Local)
+	if err != nil {
+		return nil, utils.NewValidationError("日期格式应为 YYYY-MM-DD", "date")
+	}
+
+	var request models.OvertimeRequest
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 锁定员工，避免同一日期重复提交
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return fmt.Errorf("查询员工失败: %w", err)
+		}
+		var existing int64
+		if err := tx.Model(&models.OvertimeRequest{}).
+			Where("user_id = ? AND date = ? AND status IN ?", userID, date, []string{models.OvertimePending, models.OvertimeApproved}).
+			Count(&existing).Error; err != nil {
+			return fmt.Errorf("查询加班申请失败: %w", err)
+		}
+		if existing > 0 {
+			return utils.NewValidationError("该日期已有待审批或已批准的加班申请", "date")
+		}
+
+		kind, err := s.workdays.DayKind(tx, userID, date)
+		if err != nil {
+			return err
+		}
+		request = models.OvertimeRequest{
+			UserID: userID,
+			Date:   date,
+			Hours:  round2(input.Hours),
+			Kind:   kind,
+			Reason: strings.TrimSpace(input.Reason),
+			Status: models.OvertimePending,
+		}
+
+		if date.Before(dateOf(time.Now())) {
+			var attendance models.Attendance
+			if err := tx.Where("user_id = ? AND date = ? AND clock_out IS NOT NULL", userID, date).
+				First(&attendance).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return utils.NewValidationError("该日期没有完整的打卡记录，无法补报加班", "date")
+				}
+				return fmt.Errorf("查询打卡记录失败: %w", err)
+			}
+			detected := round2(float64(attendance.OvertimeMinutes) / 60)
+			if request.Hours > detected {
+				return utils.NewValidationError(fmt.Sprintf("补报时长超过打卡记录中的加班时长 %.2f 小时", detected), "hours")
+			}
+			request.PostHoc = true
+			request.AttendanceID = &attendance.ID
+		}
+		return tx.Create(&request).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// Approve 批准加班申请
+func (s *OvertimeService) Approve(ctx context.Context, requestID, approverID uint, comment string) (*models.OvertimeRequest, error) {
+	return s.decide(ctx, requestID, approverID, models.OvertimeApproved, comment)
+}
+
+// Reject 驳回加班申请，需填写驳回意见
+func (s *OvertimeService) Reject(ctx context.Context, requestID, approverID uint, comment string) (*models.OvertimeRequest, error) {
+	if strings.TrimSpace(comment) == "" {
+		return nil, utils.NewValidationError("驳回时需填写审批意见", "comment")
+	}
+	return s.decide(ctx, requestID, approverID, models.OvertimeRejected, comment)
+}
+
+// decide 校验审批人并更新申请状态
+func (s *OvertimeService) decide(ctx context.Context, requestID, approverID uint, status, comment string) (*models.OvertimeRequest, error) {
+	var request models.OvertimeRequest
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("User").
+			First(&request, requestID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewNotFoundError("加班申请不存在", "overtime_request")
+			}
+			return fmt.Errorf("查询加班申请失败: %w", err)
+		}
+		if request.Status != models.OvertimePending {
+			return utils.NewValidationError("加班申请已处理", "overtime_request")
+		}
+		if request.UserID == approverID {
+			return utils.NewForbiddenError("不能审批自己的加班申请")
+		}
+		allowed, err := canApproveEmployee(tx, approverID, request.User.Department)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return utils.NewForbiddenError("仅员工所在部门的负责人或人事可以审批")
+		}
+
+		now := time.Now()
+		request.Status = status
+		request.ApproverID = &approverID
+		request.Comment = comment
+		request.DecidedAt = &now
+		return tx.Model(&request).Updates(map[string]interface{}{
+			"status":      status,
+			"approver_id": approverID,
+			"comment":     comment,
+			"decided_at":  now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// Cancel 员工撤销本人的加班申请：待审批的可随时撤销，已批准的仅在加班日期之前可撤销
+func (s *OvertimeService) Cancel(ctx context.Context, requestID, userID uint) (*models.OvertimeRequest, error) {
+	var request models.OvertimeRequest
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", userID).
+			First(&request, requestID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewNotFoundError("加班申请不存在", "overtime_request")
+			}
+			return fmt.Errorf("查询加班申请失败: %w", err)
+		}
+		switch request.Status {
+		case models.OvertimePending:
+		case models.OvertimeApproved:
+			if !request.Date.After(time.Now()) {
+				return utils.NewValidationError("加班日期已到，不能撤销", "overtime_request")
+			}
+		default:
+			return utils.NewValidationError("加班申请已结束，不能撤销", "overtime_request")
+		}
+		request.Status = models.OvertimeCanceled
+		return tx.Model(&request).Update("status", request.Status).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// ListMyRequests 获取员工本人的加班申请，可按状态筛选
+func (s *OvertimeService) ListMyRequests(ctx context.Context, userID uint, status string) ([]models.OvertimeRequest, error) {
+	query := s.db.WithContext(ctx).Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var requests []models.OvertimeRequest
+	if err := query.Order("date DESC").Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("查询加班申请失败: %w", err)
+	}
+	return requests, nil
+}
+
+// ListPendingForApprover 获取待该用户审批的加班申请：人事可审批全部员工，部门负责人仅限本部门
+func (s *OvertimeService) ListPendingForApprover(ctx context.Context, approverID uint) ([]models.OvertimeRequest, error) {
+	db := s.db.WithContext(ctx)
+	department, ok, err := approvalDepartment(db, approverID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []models.OvertimeRequest{}, nil
+	}
+	query := db.Preload("User").
+		Joins("JOIN users ON users.id = overtime_requests.user_id").
+		Where("overtime_requests.status = ? AND overtime_requests.user_id <> ?", models.OvertimePending, approverID)
+	if department != "" {
+		query = query.Where("users.department = ?", department)
+	}
+
+	var requests []models.OvertimeRequest
+	if err := query.Order("overtime_requests.date ASC").Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("查询加班申请失败: %w", err)
+	}
+	return requests, nil
+}
+
+// MonthlySummary 获取员工的月度加班统计
+func (s *OvertimeService) MonthlySummary(ctx context.Context, userID uint, month string) (*OvertimeSummary, error) {
+	summaries, err := s.monthlySummaries(s.db.WithContext(ctx), month, &userID)
+	if err != nil {
+		return nil, err
+	}
+	if summary, ok := summaries[userID]; ok {
+		return summary, nil
+	}
+	return s.newSummary(userID, "", month), nil
+}
+
+// MonthlyTotals 获取全部员工的月度加班统计，仅包含当月有加班记录的员工，供薪资核算使用
+func (s *OvertimeService) MonthlyTotals(ctx context.Context, month string) ([]OvertimeSummary, error) {
+	summaries, err := s.monthlySummaries(s.db.WithContext(ctx), month, nil)
+	if err != nil {
+		return nil, err
+	}
+	totals := make([]OvertimeSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		totals = append(totals, *summary)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].UserID < totals[j].UserID })
+	return totals, nil
+}
+
+// monthlySummaries 汇总当月的加班，userID 为空时统计全部员工
+func (s *OvertimeService) monthlySummaries(tx *gorm.DB, month string, userID *uint) (map[uint]*OvertimeSummary, error) {
+	start, err := time.ParseInLocation("2006-01", month, time.Local)
+	if err != nil {
+		return nil, utils.NewValidationError("月份格式应为 YYYY-MM", "month")
+	}
+	end := start.AddDate(0, 1, 0)
+
+	attendanceQuery := tx.Preload("User").
+		Where("date >= ? AND date < ? AND overtime_minutes > 0", start, end)
+	requestQuery := tx.Where("date >= ? AND date < ? AND status = ?", start, end, models.OvertimeApproved)
+	if userID != nil {
+		attendanceQuery = attendanceQuery.Where("user_id = ?", *userID)
+		requestQuery = requestQuery.Where("user_id = ?", *userID)
+	}
+	var attendances []models.Attendance
+	if err := attendanceQuery.Find(&attendances).Error; err != nil {
+		return nil, fmt.Errorf("查询打卡记录失败: %w", err)
+	}
+	var requests []models.OvertimeRequest
+	if err := requestQuery.Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("查询加班申请失败: %w", err)
+	}
+
+	return s.summarize(month, attendances, requests), nil
+}
+
+// summarize 汇总月度加班：打卡记录给出检测时长，批准的加班申请按类型计薪，
+// 每份申请的计薪时长取申请时长与当日检测时长中的较小值
+func (s *OvertimeService) summarize(month string, attendances []models.Attendance, requests []models.OvertimeRequest) map[uint]*OvertimeSummary {
+	summaries := make(map[uint]*OvertimeSummary)
+	detected := make(map[string]float64, len(attendances))
+	for _, attendance := range attendances {
+		hours := float64(attendance.OvertimeMinutes) / 60
+		detected[fmt.Sprintf("%d:%s", attendance.UserID, attendance.Date.Format("2006-01-02"))] = hours
+		summary, ok := summaries[attendance.UserID]
+		if !ok {
+			summary = s.newSummary(attendance.UserID, attendance.User.Username, month)
+			summaries[attendance.UserID] = summary
+		}
+		summary.DetectedHours = round2(summary.DetectedHours + hours)
+	}
+
+	for _, request := range requests {
+		summary, ok := summaries[request.UserID]
+		if !ok {
+			// 有批准的加班但当日没有打卡记录中的加班时长，计薪时长为 0
+			continue
+		}
+		hours := math.Min(request.Hours, detected[fmt.Sprintf("%d:%s", request.UserID, request.Date.Format("2006-01-02"))])
+		total := summary.Kinds[request.Kind]
+		total.Hours = round2(total.Hours + hours)
+		total.WeightedHours = round2(total.Hours * total.Multiplier)
+		summary.Kinds[request.Kind] = total
+	}
+
+	for _, summary := range summaries {
+		for _, total := range summary.Kinds {
+			summary.TotalHours = round2(summary.TotalHours + total.Hours)
+			summary.WeightedHours = round2(summary.WeightedHours + total.WeightedHours)
+		}
+	}
+	return summaries
+}
+
+func (s *OvertimeService) newSummary(userID uint, username, month string) *OvertimeSummary {
+	kinds := make(map[string]OvertimeKindTotal, len(s.config.Multipliers))
+	for kind, multiplier := range s.config.Multipliers {
+		kinds[kind] = OvertimeKindTotal{Multiplier: multiplier}
+	}
+	return &OvertimeSummary{UserID: userID, Username: username, Month: month, Kinds: kinds}
+}
+
+// overtimePay 按月度加班统计计算加班工资，小时工资为基本工资除以当月工作日和每日标准工时
+func (s *OvertimeService) overtimePay(summary *OvertimeSummary, salaryBase float64, workDays int) float64 {
+	if workDays == 0 || s.config.DailyHours <= 0 {
+		return 0
+	}
+	hourly := salaryBase / float64(workDays) / s.config.DailyHours
+	return round2(summary.WeightedHours * hourly)
+}
+
+// detect 计算打卡记录超出班次的加班分钟数和加班类型。休息日和节假日的出勤全部计为加班；
+// 工作日固定班次和夜班按下班时间之后的时长计算，弹性班次按超出每日最低工时的时长计算
+func (s *OvertimeService) detect(tx *gorm.DB, userID uint, shift *ShiftInfo, clockIn, clockOut time.Time) (int, string, error) {
+	kind, err := s.workdays.DayKind(tx, userID, shift.Date)
+	if err != nil {
+		return 0, "", err
+	}
+	return s.overtimeMinutes(kind, shift, clockIn, clockOut), kind, nil
+}
+
+// overtimeMinutes 按日期类型和班次计算加班分钟数，不足最短加班时长时为0
+func (s *OvertimeService) overtimeMinutes(kind string, shift *ShiftInfo, clockIn, clockOut time.Time) int {
+	var overtime time.Duration
+	switch {
+	case kind != models.OvertimeWeekday:
+		overtime = clockOut.Sub(clockIn)
+	case shift.Schedule.Kind == models.ScheduleFlexible:
+		required := time.Duration(shift.Schedule.RequiredHours * float64(time.Hour))
+		if required == 0 {
+			required = shift.End.Sub(shift.Start)
+		}
+		overtime = clockOut.Sub(clockIn) - required
+	default:
+		from := shift.End
+		if clockIn.After(from) {
+			from = clockIn
+		}
+		overtime = clockOut.Sub(from)
+	}
+
+	minutes := int(overtime / time.Minute)
+	if minutes <= 0 || minutes < s.config.MinMinutes {
+		return 0
+	}
+	return minutes
+}
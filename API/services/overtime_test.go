@@ -0,0 +1,90 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"API/models"
+)
+
+func TestOvertimeMinutes(t *testing.T) {
+	unset := flexibleSchedule
+	unset.RequiredHours = 0
+
+	tests := []struct {
+		name              string
+		kind              string
+		schedule          models.WorkSchedule
+		date              time.Time
+		clockIn, clockOut time.Time
+		want              int
+	}{
+		{"固定班次下班后加班", models.OvertimeWeekday, fixedSchedule, at(3, 0, 0), at(3, 9, 0), at(3, 20, 30), 150},
+		{"固定班次未到下班时间", models.OvertimeWeekday, fixedSchedule, at(3, 0, 0), at(3, 9, 0), at(3, 17, 0), 0},
+		{"下班时间之后才上班打卡", models.OvertimeWeekday, fixedSchedule, at(3, 0, 0), at(3, 19, 0), at(3, 21, 0), 120},
+		{"不足最短加班时长", models.OvertimeWeekday, fixedSchedule, at(3, 0, 0), at(3, 9, 0), at(3, 18, 20), 0},
+		{"弹性班次超出每日工时", models.OvertimeWeekday, flexibleSchedule, at(3, 0, 0), at(3, 9, 0), at(3, 19, 0), 120},
+		{"弹性班次未设工时按核心时间计算", models.OvertimeWeekday, unset, at(3, 0, 0), at(3, 9, 0), at(3, 17, 0), 120},
+		{"夜班次日下班后加班", models.OvertimeWeekday, nightSchedule, at(3, 0, 0), at(3, 22, 0), at(4, 7, 30), 90},
+		{"休息日出勤全部计加班", models.OvertimeWeekend, fixedSchedule, at(8, 0, 0), at(8, 10, 0), at(8, 15, 0), 300},
+		{"节假日出勤全部计加班", models.OvertimeHoliday, fixedSchedule, at(4, 0, 0), at(4, 9, 0), at(4, 12, 0), 180},
+		{"休息日出勤不足最短加班时长", models.OvertimeWeekend, fixedSchedule, at(8, 0, 0), at(8, 10, 0), at(8, 10, 20), 0},
+	}
+	s := &OvertimeService{config: OvertimeConfig{MinMinutes: 30}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shift := newShiftInfo(tt.schedule, tt.date)
+			if got := s.overtimeMinutes(tt.kind, shift, tt.clockIn, tt.clockOut); got != tt.want {
+				t.Errorf("overtimeMinutes = %d, 期望 %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := &OvertimeService{config: OvertimeConfig{Multipliers: map[string]float64{
+		models.OvertimeWeekday: 1.5,
+		models.OvertimeWeekend: 2,
+		models.OvertimeHoliday: 3,
+	}}}
+	attendances := []models.Attendance{
+		{UserID: 1, Date: at(3, 0, 0), OvertimeMinutes: 120, User: models.User{Username: "alice"}},
+		{UserID: 1, Date: at(8, 0, 0), OvertimeMinutes: 300, User: models.User{Username: "alice"}},
+		{UserID: 2, Date: at(4, 0, 0), OvertimeMinutes: 90, User: models.User{Username: "bob"}},
+	}
+	requests := []models.OvertimeRequest{
+		{UserID: 1, Date: at(3, 0, 0), Hours: 3, Kind: models.OvertimeWeekday}, // 检测到 2 小时，按 2 小时计
+		{UserID: 1, Date: at(8, 0, 0), Hours: 4, Kind: models.OvertimeWeekend}, // 检测到 5 小时，按申请的 4 小时计
+		{UserID: 1, Date: at(5, 0, 0), Hours: 2, Kind: models.OvertimeWeekday}, // 当日没有检测到加班，不计
+		{UserID: 2, Date: at(4, 0, 0), Hours: 1, Kind: models.OvertimeHoliday},
+		{UserID: 3, Date: at(4, 0, 0), Hours: 2, Kind: models.OvertimeWeekday}, // 当月没有检测到加班，不计
+	}
+
+	summaries := s.summarize("2025-03", attendances, requests)
+	if len(summaries) != 2 || summaries[3] != nil {
+		t.Fatalf("统计了 %d 名员工, 期望 2 名且不含员工 3", len(summaries))
+	}
+
+	alice := summaries[1]
+	if alice.Username != "alice" || alice.Month != "2025-03" {
+		t.Errorf("员工信息为 %s %s", alice.Username, alice.Month)
+	}
+	wantKinds := map[string]OvertimeKindTotal{
+		models.OvertimeWeekday: {Hours: 2, Multiplier: 1.5, WeightedHours: 3},
+		models.OvertimeWeekend: {Hours: 4, Multiplier: 2, WeightedHours: 8},
+		models.OvertimeHoliday: {Hours: 0, Multiplier: 3, WeightedHours: 0},
+	}
+	for kind, want := range wantKinds {
+		if got := alice.Kinds[kind]; got != want {
+			t.Errorf("%s 加班为 %+v, 期望 %+v", kind, got, want)
+		}
+	}
+	if alice.TotalHours != 6 || alice.WeightedHours != 11 || alice.DetectedHours != 7 {
+		t.Errorf("合计为 %v / %v / %v, 期望 6 / 11 / 7", alice.TotalHours, alice.WeightedHours, alice.DetectedHours)
+	}
+
+	bob := summaries[2]
+	if bob.TotalHours != 1 || bob.WeightedHours != 3 || bob.DetectedHours != 1.5 {
+		t.Errorf("合计为 %v / %v / %v, 期望 1 / 3 / 1.5", bob.TotalHours, bob.WeightedHours, bob.DetectedHours)
+	}
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"API/models"
+	"API/utils"
+
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// PasswordPolicyConfig 密码策略配置
+type PasswordPolicyConfig struct {
+	MinLength     int    // 最小长度
+	MaxLength     int    // 最大长度，bcrypt 仅使用前72字节
+	MinClasses    int    // 至少包含的字符类别数（大写、小写、数字、符号）
+	RequireUpper  bool   // 必须包含大写字母
+	RequireLower  bool   // 必须包含小写字母
+	RequireDigit  bool   // 必须包含数字
+	RequireSymbol bool   // 必须包含符号
+	DenylistFile  string // 已泄露/弱密码列表文件，每行一个，忽略大小写
+	HistorySize   int    // 禁止重复使用最近多少个历史密码，0 表示不限制
+}
+
+// LoadPasswordPolicyConfig 从配置文件加载密码策略
+func LoadPasswordPolicyConfig() PasswordPolicyConfig {
+	config := PasswordPolicyConfig{
+		MinLength:     viper.GetInt("password_policy.min_length"),
+		MaxLength:     viper.GetInt("password_policy.max_length"),
+		MinClasses:    viper.GetInt("password_policy.min_classes"),
+		RequireUpper:  viper.GetBool("password_policy.require_upper"),
+		RequireLower:  viper.GetBool("password_policy.require_lower"),
+		RequireDigit:  viper.GetBool("password_policy.require_digit"),
+		RequireSymbol: viper.GetBool("password_policy.require_symbol"),
+		DenylistFile:  viper.GetString("password_policy.denylist_file"),
+		HistorySize:   viper.GetInt("password_policy.history_size"),
+	}
+	if config.MinLength <= 0 {
+		config.MinLength = 8
+	}
+	if config.MaxLength <= 0 || config.MaxLength > 72 {
+		config.MaxLength = 72
+	}
+	if config.HistorySize < 0 {
+		config.HistorySize = 0
+	}
+	return config
+}
+
+// PasswordPolicy 校验新密码强度，并防止重复使用历史密码
+type PasswordPolicy struct {
+	config   PasswordPolicyConfig
+	denylist map[string]struct{}
+}
+
+// NewPasswordPolicy 构建密码策略，配置了弱密码列表时读取该文件
+func NewPasswordPolicy(config PasswordPolicyConfig) (*PasswordPolicy, error) {
+	policy := &PasswordPolicy{config: config, denylist: make(map[string]struct{})}
+	if config.DenylistFile == "" {
+		return policy, nil
+	}
+
+	file, err := os.Open(config.DenylistFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取弱密码列表失败: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		policy.denylist[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取弱密码列表失败: %w", err)
+	}
+	return policy, nil
+}
+
+// Validate 校验密码是否满足策略
+func (p *PasswordPolicy) Validate(password, username string) error {
+	length := len([]rune(password))
+	if length < p.config.MinLength {
+		return utils.NewValidationError(fmt.Sprintf("密码长度不能少于%d个字符", p.config.MinLength), "password")
+	}
+	if len(password) > p.config.MaxLength {
+		return utils.NewValidationError(fmt.Sprintf("密码长度不能超过%d个字节", p.config.MaxLength), "password")
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	switch {
+	case p.config.RequireUpper && !upper:
+		return utils.NewValidationError("密码必须包含大写字母", "password")
+	case p.config.RequireLower && !lower:
+		return utils.NewValidationError("密码必须包含小写字母", "password")
+	case p.config.RequireDigit && !digit:
+		return utils.NewValidationError("密码必须包含数字", "password")
+	case p.config.RequireSymbol && !symbol:
+		return utils.NewValidationError("密码必须包含符号", "password")
+	}
+	classes := 0
+	for _, ok := range []bool{upper, lower, digit, symbol} {
+		if ok {
+			classes++
+		}
+	}
+	if classes < p.config.MinClasses {
+		return utils.NewValidationError(fmt.Sprintf("密码至少需要包含大写字母、小写字母、数字、符号中的%d类", p.config.MinClasses), "password")
+	}
+
+	if username != "" && strings.EqualFold(password, strings.TrimSpace(username)) {
+		return utils.NewValidationError("密码不能与用户名相同", "password")
+	}
+	if _, ok := p.denylist[strings.ToLower(password)]; ok {
+		return utils.NewValidationError("密码过于常见或已泄露，请更换", "password")
+	}
+	return nil
+}
+
+// CheckReuse 检查新密码是否与当前密码或最近的历史密码相同
+func (p *PasswordPolicy) CheckReuse(ctx context.Context, db *gorm.DB, user *models.User, password string) error {
+	if p.config.HistorySize == 0 {
+		return nil
+	}
+	if user.PasswordHash != "" && bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) == nil {
+		return utils.NewValidationError("新密码不能与当前密码相同", "password")
+	}
+
+	var history []models.PasswordHistory
+	if err := db.WithContext(ctx).Where("user_id = ?", user.ID).
+		Order("id DESC").Limit(p.config.HistorySize).
+		Find(&history).Error; err != nil {
+		return fmt.Errorf("查询历史密码失败: %w", err)
+	}
+	for _, h := range history {
+		if bcrypt.CompareHashAndPassword([]byte(h.PasswordHash), []byte(password)) == nil {
+			return utils.NewValidationError(fmt.Sprintf("新密码不能与最近%d次使用过的密码相同", p.config.HistorySize), "password")
+		}
+	}
+	return nil
+}
+
+// RecordHistory 记录新密码哈希，并清理超出保留数量的历史记录
+func (p *PasswordPolicy) RecordHistory(tx *gorm.DB, userID uint, passwordHash string) error {
+	if p.config.HistorySize == 0 {
+		return nil
+	}
+	if err := tx.Create(&models.PasswordHistory{UserID: userID, PasswordHash: passwordHash}).Error; err != nil {
+		return fmt.Errorf("记录历史密码失败: %w", err)
+	}
+
+	var keepIDs []uint
+	if err := tx.Model(&models.PasswordHistory{}).Where("user_id = ?", userID).
+		Order("id DESC").Limit(p.config.HistorySize).
+		Pluck("id", &keepIDs).Error; err != nil {
+		return fmt.Errorf("查询历史密码失败: %w", err)
+	}
+	if err := tx.Unscoped().Where("user_id = ? AND id NOT IN ?", userID, keepIDs).
+		Delete(&models.PasswordHistory{}).Error; err != nil {
+		return fmt.Errorf("清理历史密码失败: %w", err)
+	}
+	return nil
+}
+
+// setPassword 校验策略与历史后更新用户密码，extra 为需要同时更新的其他字段
+func setPassword(ctx context.Context, db *gorm.DB, policy *PasswordPolicy, user *models.User, password string, extra map[string]interface{}) error {
+	if err := policy.Validate(password, user.Username); err != nil {
+		return err
+	}
+	if err := policy.CheckReuse(ctx, db, user, password); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("密码加密失败: %w", err)
+	}
+	updates := map[string]interface{}{"password_hash": string(hashedPassword)}
+	for k, v := range extra {
+		updates[k] = v
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Updates(updates).Error; err != nil {
+			return fmt.Errorf("更新密码失败: %w", err)
+		}
+		return policy.RecordHistory(tx, user.ID, string(hashedPassword))
+	})
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"API/utils"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	dir := t.TempDir()
+	denylist := filepath.Join(dir, "denylist.txt")
+	if err := os.WriteFile(denylist, []byte("# 常见密码\nPassw0rd!\n\nqwerty123\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := NewPasswordPolicy(PasswordPolicyConfig{
+		MinLength:    8,
+		MaxLength:    72,
+		MinClasses:   3,
+		RequireDigit: true,
+		DenylistFile: denylist,
+	})
+	if err != nil {
+		t.Fatalf("NewPasswordPolicy: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		username string
+		wantErr  string
+	}{
+		{"满足策略", "Tr0ub4dor&3", "alice", ""},
+		{"三类字符不含符号", "Tr0ub4dor3", "alice", ""},
+		{"过短", "Ab1!", "alice", "密码长度不能少于8个字符"},
+		{"按字符计算长度", "密码Ab1!密码", "alice", ""},
+		{"超过字节上限", "Aa1!" + strings.Repeat("x", 69), "alice", "密码长度不能超过72个字节"},
+		{"缺少数字", "Abcdefgh!", "alice", "密码必须包含数字"},
+		{"字符类别不足", "abcdefgh1", "alice", "密码至少需要包含大写字母、小写字母、数字、符号中的3类"},
+		{"与用户名相同", "Alice2024!", " alice2024! ", "密码不能与用户名相同"},
+		{"命中弱密码列表且忽略大小写", "PASSW0RD!", "alice", "密码过于常见或已泄露，请更换"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password, tt.username)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("期望通过，得到 %v", err)
+				}
+				return
+			}
+			var validation *utils.ValidationError
+			if !errors.As(err, &validation) || validation.Message != tt.wantErr {
+				t.Fatalf("期望错误 %q，得到 %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyRequiredClasses(t *testing.T) {
+	policy, err := NewPasswordPolicy(PasswordPolicyConfig{MinLength: 1, MaxLength: 72, RequireUpper: true, RequireLower: true, RequireSymbol: true})
+	if err != nil {
+		t.Fatalf("NewPasswordPolicy: %v", err)
+	}
+	tests := map[string]string{
+		"lower!": "密码必须包含大写字母",
+		"UPPER!": "密码必须包含小写字母",
+		"Mixed1": "密码必须包含符号",
+		"Mixed ": "",
+		"Mixed¥": "",
+	}
+	for password, want := range tests {
+		err := policy.Validate(password, "")
+		if want == "" {
+			if err != nil {
+				t.Errorf("%q 期望通过，得到 %v", password, err)
+			}
+			continue
+		}
+		var validation *utils.ValidationError
+		if !errors.As(err, &validation) || validation.Message != want {
+			t.Errorf("%q 期望错误 %q，得到 %v", password, want, err)
+		}
+	}
+}
+
+func TestNewPasswordPolicyMissingDenylist(t *testing.T) {
+	if _, err := NewPasswordPolicy(PasswordPolicyConfig{DenylistFile: filepath.Join(t.TempDir(), "missing.txt")}); err == nil {
+		t.Error("弱密码列表不存在时应返回错误")
+	}
+}
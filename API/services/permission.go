@@ -2,17 +2,31 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"time"
 
 	"API/models"
+	"API/storage/cache"
+
 	"gorm.io/gorm"
 )
 
+// rolePermissionsCacheTTL 角色权限缓存有效期
+const rolePermissionsCacheTTL = 30 * time.Minute
+
+// rolePermissionsCacheKey 角色权限代码的缓存键
+func rolePermissionsCacheKey(role string) string {
+	return "role_permissions:" + role
+}
+
 type PermissionService struct {
-	db *gorm.DB
+	db    *gorm.DB
+	cache cache.Provider
 }
 
-func NewPermissionService(db *gorm.DB) *PermissionService {
-	return &PermissionService{db: db}
+func NewPermissionService(db *gorm.DB, cache cache.Provider) *PermissionService {
+	return &PermissionService{db: db, cache: cache}
 }
 
 func (s *PermissionService) CreatePermission(ctx context.Context, permission *models.Permission) error {
@@ -24,3 +38,81 @@ func (s *PermissionService) GetPermissions(ctx context.Context) ([]models.Permis
 	err := s.db.WithContext(ctx).Find(&permissions).Error
 	return permissions, err
 }
+
+// GetPermissionCodesByRoles 将角色解析为去重后的权限代码，优先读取缓存
+func (s *PermissionService) GetPermissionCodesByRoles(ctx context.Context, roles []string) ([]string, error) {
+	seen := make(map[string]struct{})
+	var codes []string
+	for _, role := range roles {
+		roleCodes, err := s.getRolePermissionCodes(ctx, role)
+		if err != nil {
+			return nil, err
+		}
+		for _, code := range roleCodes {
+			if _, ok := seen[code]; ok {
+				continue
+			}
+			seen[code] = struct{}{}
+			codes = append(codes, code)
+		}
+	}
+	return codes, nil
+}
+
+// GetUserPermissionCodes 获取用户当前的角色名称及其有效权限代码
+func (s *PermissionService) GetUserPermissionCodes(ctx context.Context, userID uint) ([]string, []string, error) {
+	var roles []string
+	if err := s.db.WithContext(ctx).Model(&models.Role{}).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Pluck("roles.name", &roles).Error; err != nil {
+		return nil, nil, fmt.Errorf("查询用户角色失败: %w", err)
+	}
+
+	codes, err := s.GetPermissionCodesByRoles(ctx, roles)
+	if err != nil {
+		return nil, nil, err
+	}
+	return roles, codes, nil
+}
+
+// getRolePermissionCodes 获取单个角色的权限代码
+func (s *PermissionService) getRolePermissionCodes(ctx context.Context, role string) ([]string, error) {
+	cacheKey := rolePermissionsCacheKey(role)
+	var codes []string
+	if err := s.cache.GetObject(ctx, cacheKey, &codes); err == nil {
+		return codes, nil
+	}
+
+	if err := s.db.WithContext(ctx).Model(&models.Permission{}).
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id AND roles.deleted_at IS NULL").
+		Where("roles.name = ?", role).
+		Pluck("permissions.code", &codes).Error; err != nil {
+		return nil, fmt.Errorf("查询角色权限失败: %w", err)
+	}
+
+	if err := s.cache.SetObject(ctx, cacheKey, codes, rolePermissionsCacheTTL); err != nil {
+		log.Printf("设置角色权限缓存失败: %v", err)
+	}
+	return codes, nil
+}
+
+// InvalidateRoles 清除指定角色的权限缓存，用于启动时补授默认权限之后
+func (s *PermissionService) InvalidateRoles(ctx context.Context, roles ...string) {
+	invalidateRolePermissions(ctx, s.cache, roles...)
+}
+
+// invalidateRolePermissions 清除角色权限缓存，角色或其权限变更后调用
+func invalidateRolePermissions(ctx context.Context, c cache.Provider, roles ...string) {
+	if len(roles) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(roles))
+	for _, role := range roles {
+		keys = append(keys, rolePermissionsCacheKey(role))
+	}
+	if err := c.Del(ctx, keys...); err != nil {
+		log.Printf("清除角色权限缓存失败: %v", err)
+	}
+}
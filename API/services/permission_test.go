@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"API/models"
+)
+
+// seedRoles 创建角色及其权限，返回角色名到角色的映射
+func seedRoles(t *testing.T, svc *PermissionService, roles map[string][]string) map[string]models.Role {
+	t.Helper()
+	created := make(map[string]models.Role, len(roles))
+	for name, codes := range roles {
+		role := models.Role{Name: name}
+		for _, code := range codes {
+			var permission models.Permission
+			if err := svc.db.Where(models.Permission{Code: code}).FirstOrCreate(&permission).Error; err != nil {
+				t.Fatalf("创建权限失败: %v", err)
+			}
+			role.Permissions = append(role.Permissions, permission)
+		}
+		if err := svc.db.Create(&role).Error; err != nil {
+			t.Fatalf("创建角色失败: %v", err)
+		}
+		created[name] = role
+	}
+	return created
+}
+
+func TestGetPermissionCodesByRoles(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryCache()
+	svc := NewPermissionService(newTestDB(t, &models.Permission{}, &models.Role{}), store)
+	roles := seedRoles(t, svc, map[string][]string{
+		"hr":      {models.PermJobView, models.PermJobCreate},
+		"auditor": {models.PermJobView, models.PermSalaryView},
+		"retired": {models.PermRoleGrant},
+	})
+	svc.db.Delete(&models.Role{}, roles["retired"].ID)
+
+	tests := []struct {
+		name  string
+		roles []string
+		want  []string
+	}{
+		{"单个角色", []string{"hr"}, []string{models.PermJobCreate, models.PermJobView}},
+		{"多个角色去重", []string{"hr", "auditor"}, []string{models.PermJobCreate, models.PermJobView, models.PermSalaryView}},
+		{"已删除的角色没有权限", []string{"retired"}, nil},
+		{"不存在的角色", []string{"ghost"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.GetPermissionCodesByRoles(ctx, tt.roles)
+			if err != nil {
+				t.Fatalf("GetPermissionCodesByRoles: %v", err)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("权限为 %v, 期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRolePermissionCache(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryCache()
+	svc := NewPermissionService(newTestDB(t, &models.Permission{}, &models.Role{}), store)
+	roles := seedRoles(t, svc, map[string][]string{"hr": {models.PermJobView}})
+	key := rolePermissionsCacheKey("hr")
+
+	// 未命中缓存时查询数据库并写入缓存
+	if _, err := svc.GetPermissionCodesByRoles(ctx, []string{"hr"}); err != nil {
+		t.Fatalf("GetPermissionCodesByRoles: %v", err)
+	}
+	if !store.has(key) || store.ttls[key] != rolePermissionsCacheTTL {
+		t.Fatalf("未写入角色权限缓存或有效期为 %s", store.ttls[key])
+	}
+
+	// 命中缓存时不再读取数据库，数据库中的变更在清除缓存前不生效
+	create := models.Permission{Code: models.PermJobCreate}
+	if err := svc.db.Create(&create).Error; err != nil {
+		t.Fatalf("创建权限失败: %v", err)
+	}
+	role := roles["hr"]
+	if err := svc.db.Model(&role).Association("Permissions").Append(&create); err != nil {
+		t.Fatalf("授予权限失败: %v", err)
+	}
+	got, err := svc.GetPermissionCodesByRoles(ctx, []string{"hr"})
+	if err != nil {
+		t.Fatalf("GetPermissionCodesByRoles: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{models.PermJobView}) {
+		t.Errorf("命中缓存时权限为 %v, 期望缓存中的 [%s]", got, models.PermJobView)
+	}
+
+	svc.InvalidateRoles(ctx, "hr")
+	if store.has(key) {
+		t.Fatal("清除后缓存仍存在")
+	}
+	got, err = svc.GetPermissionCodesByRoles(ctx, []string{"hr"})
+	if err != nil {
+		t.Fatalf("GetPermissionCodesByRoles: %v", err)
+	}
+	sort.Strings(got)
+	if want := []string{models.PermJobCreate, models.PermJobView}; !reflect.DeepEqual(got, want) {
+		t.Errorf("清除缓存后权限为 %v, 期望 %v", got, want)
+	}
+}
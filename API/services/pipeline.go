@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"API/models"
+	"API/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PipelineStageInput 配置招聘流程时提交的阶段
+type PipelineStageInput struct {
+	Code string `json:"code" binding:"required"`
+	Name string `json:"name" binding:"required"`
+	Kind string `json:"kind"` // active（默认）、hired、rejected
+}
+
+// StageApplications 某一阶段及其中的申请
+type StageApplications struct {
+	Stage        models.PipelineStage `json:"stage"`
+	Applications []models.Application `json:"applications"`
+}
+
+// PipelineService 管理职位的招聘流程阶段
+type PipelineService struct {
+	db *gorm.DB
+}
+
+func NewPipelineService(db *gorm.DB) *PipelineService {
+	return &PipelineService{db: db}
+}
+
+// GetPipeline 获取职位的招聘阶段，未配置时初始化为默认流程
+func (s *PipelineService) GetPipeline(ctx context.Context, jobID uint) ([]models.PipelineStage, error) {
+	if err := findJob(s.db.WithContext(ctx), jobID); err != nil {
+		return nil, err
+	}
+	return ensurePipeline(s.db.WithContext(ctx), jobID)
+}
+
+// UpdatePipeline 按提交顺序重新配置职位的招聘阶段。
+// 已有阶段按 code 匹配并保留ID；被移除的阶段中不能仍有申请。
+func (s *PipelineService) UpdatePipeline(ctx context.Context, jobID uint, inputs []PipelineStageInput) ([]models.PipelineStage, error) {
+	if err := validatePipeline(inputs); err != nil {
+		return nil, err
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := findJob(tx, jobID); err != nil {
+			return err
+		}
+		if _, err := ensurePipeline(tx, jobID); err != nil {
+			return err
+		}
+
+		// 包含已删除的阶段，重新加入同一 code 时恢复原记录以保留历史关联
+		var existing []models.PipelineStage
+		if err := tx.Unscoped().Where("job_id = ?", jobID).Find(&existing).Error; err != nil {
+			return fmt.Errorf("查询招聘阶段失败: %w", err)
+		}
+		byCode := make(map[string]models.PipelineStage, len(existing))
+		for _, stage := range existing {
+			byCode[stage.Code] = stage
+		}
+
+		kept := make(map[string]bool, len(inputs))
+		for i, input := range inputs {
+			code := strings.TrimSpace(input.Code)
+			kept[code] = true
+			stage, ok := byCode[code]
+			if !ok {
+				stage = models.PipelineStage{JobID: jobID, Code: code}
+			}
+			stage.Name = strings.TrimSpace(input.Name)
+			stage.Kind = stageKind(input.Kind)
+			stage.Position = i
+			stage.DeletedAt = gorm.DeletedAt{}
+			if err := tx.Unscoped().Save(&stage).Error; err != nil {
+				return fmt.Errorf("保存阶段 %s 失败: %w", code, err)
+			}
+		}
+
+		for _, stage := range existing {
+			if kept[stage.Code] || stage.DeletedAt.Valid {
+				continue
+			}
+			var count int64
+			if err := tx.Model(&models.Application{}).Where("stage_id = ?", stage.ID).Count(&count).Error; err != nil {
+				return fmt.Errorf("查询阶段申请失败: %w", err)
+			}
+			if count > 0 {
+				return utils.NewValidationError(fmt.Sprintf("阶段「%s」中仍有%d个申请，无法移除", stage.Name, count), "stages")
+			}
+			if err := tx.Delete(&stage).Error; err != nil {
+				return fmt.Errorf("删除阶段 %s 失败: %w", stage.Code, err)
+			}
+		}
+
+		// 阶段类型或顺序变化后同步申请状态，候选人撤回的申请保持原状态；
+		// 改为淘汰阶段时其中申请尚未答复的录用通知作废
+		stages, err := ensurePipeline(tx, jobID)
+		if err != nil {
+			return err
+		}
+		for _, stage := range stages {
+			status := stageStatus(stages, stage)
+			if err := tx.Model(&models.Application{}).Where("stage_id = ? AND status <> ?", stage.ID, "withdrawn").
+				Update("status", status).Error; err != nil {
+				return fmt.Errorf("同步申请状态失败: %w", err)
+			}
+			if status == "rejected" {
+				if err := voidOpenOffers(tx, tx.Model(&models.Application{}).Select("id").Where("stage_id = ?", stage.ID)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ensurePipeline(s.db.WithContext(ctx), jobID)
+}
+
+// ListApplicationsByStage 按招聘阶段分组列出职位的全部申请，并附带面试评分汇总
+func (s *PipelineService) ListApplicationsByStage(ctx context.Context, jobID uint) ([]StageApplications, error) {
+	stages, err := s.GetPipeline(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	var applications []models.Application
+	if err := s.db.WithContext(ctx).Preload("User").
+		Where("job_id = ?", jobID).
+		Order("updated_at DESC").
+		Find(&applications).Error; err != nil {
+		return nil, fmt.Errorf("查询申请失败: %w", err)
+	}
+
+	ids := make([]uint, len(applications))
+	for i, application := range applications {
+		ids[i] = application.ID
+	}
+	recommendations, err := interviewRecommendations(s.db.WithContext(ctx), ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range applications {
+		applications[i].Recommendation = recommendations[applications[i].ID]
+	}
+
+	groups := make([]StageApplications, len(stages))
+	index := make(map[uint]int, len(stages))
+	for i, stage := range stages {
+		groups[i] = StageApplications{Stage: stage, Applications: []models.Application{}}
+		index[stage.ID] = i
+	}
+	for _, application := range applications {
+		if application.StageID == nil {
+			continue
+		}
+		if i, ok := index[*application.StageID]; ok {
+			groups[i].Applications = append(groups[i].Applications, application)
+		}
+	}
+	return groups, nil
+}
+
+// validatePipeline 校验阶段配置：代码唯一，至少包含一个进行中阶段以及录用、淘汰阶段各一个
+func validatePipeline(inputs []PipelineStageInput) error {
+	seen := make(map[string]bool, len(inputs))
+	kinds := make(map[string]int)
+	for _, input := range inputs {
+		code := strings.TrimSpace(input.Code)
+		if code == "" || strings.TrimSpace(input.Name) == "" {
+			return utils.NewValidationError("阶段代码和名称不能为空", "stages")
+		}
+		if seen[code] {
+			return utils.NewValidationError("阶段代码重复: "+code, "stages")
+		}
+		seen[code] = true
+
+		kind := stageKind(input.Kind)
+		if kind != models.StageKindActive && kind != models.StageKindHired && kind != models.StageKindRejected {
+			return utils.NewValidationError("无效的阶段类型: "+input.Kind, "stages")
+		}
+		kinds[kind]++
+	}
+	if kinds[models.StageKindActive] == 0 {
+		return utils.NewValidationError("至少需要一个进行中的阶段", "stages")
+	}
+	if kinds[models.StageKindHired] != 1 || kinds[models.StageKindRejected] != 1 {
+		return utils.NewValidationError("录用阶段和淘汰阶段必须各有且仅有一个", "stages")
+	}
+	return nil
+}
+
+func stageKind(kind string) string {
+	if kind == "" {
+		return models.StageKindActive
+	}
+	return kind
+}
+
+// ensurePipeline 返回职位按顺序排列的招聘阶段；尚未配置时写入默认阶段，
+// 并将此前的申请按原状态归入对应阶段
+func ensurePipeline(tx *gorm.DB, jobID uint) ([]models.PipelineStage, error) {
+	var stages []models.PipelineStage
+	if err := tx.Where("job_id = ?", jobID).Order("position ASC, id ASC").Find(&stages).Error; err != nil {
+		return nil, fmt.Errorf("查询招聘阶段失败: %w", err)
+	}
+	if len(stages) > 0 {
+		return stages, nil
+	}
+
+	defaults := make([]models.PipelineStage, len(models.DefaultPipelineStages))
+	for i, stage := range models.DefaultPipelineStages {
+		stage.JobID = jobID
+		stage.Position = i
+		defaults[i] = stage
+	}
+	// 并发初始化时以先写入者为准
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&defaults).Error; err != nil {
+		return nil, fmt.Errorf("初始化招聘阶段失败: %w", err)
+	}
+	if err := tx.Where("job_id = ?", jobID).Order("position ASC, id ASC").Find(&stages).Error; err != nil {
+		return nil, fmt.Errorf("查询招聘阶段失败: %w", err)
+	}
+
+	for _, stage := range stages {
+		var statuses []string
+		switch {
+		case stage.Kind == models.StageKindHired:
+			statuses = []string{"hired"}
+		case stage.Kind == models.StageKindRejected:
+			statuses = []string{"rejected", "withdrawn"}
+		case stage.ID == firstActiveStage(stages).ID:
+			statuses = []string{"pending", "interviewed"}
+		default:
+			continue
+		}
+		if err := tx.Model(&models.Application{}).
+			Where("job_id = ? AND stage_id IS NULL AND status IN ?", jobID, statuses).
+			Update("stage_id", stage.ID).Error; err != nil {
+			return nil, fmt.Errorf("归入招聘阶段失败: %w", err)
+		}
+	}
+	return stages, nil
+}
+
+// activeStages 按顺序返回进行中的阶段
+func activeStages(stages []models.PipelineStage) []models.PipelineStage {
+	var active []models.PipelineStage
+	for _, stage := range stages {
+		if stage.Kind == models.StageKindActive {
+			active = append(active, stage)
+		}
+	}
+	return active
+}
+
+func firstActiveStage(stages []models.PipelineStage) models.PipelineStage {
+	return activeStages(stages)[0]
+}
+
+// canTransition 校验阶段流转：进行中的阶段只能前进或退回一步，
+// 任一进行中的阶段都可淘汰，只有最后一个进行中的阶段可以录用，终止阶段不可再流转
+func canTransition(stages []models.PipelineStage, from, to models.PipelineStage) error {
+	if from.ID == to.ID {
+		return utils.NewValidationError("申请已处于该阶段", "stage_id")
+	}
+	if from.Kind != models.StageKindActive {
+		return utils.NewValidationError("申请已处于终止阶段「"+from.Name+"」，不能再流转", "stage_id")
+	}
+
+	active := activeStages(stages)
+	fromIndex := -1
+	for i, stage := range active {
+		if stage.ID == from.ID {
+			fromIndex = i
+		}
+	}
+
+	switch to.Kind {
+	case models.StageKindRejected:
+		return nil
+	case models.StageKindHired:
+		if fromIndex != len(active)-1 {
+			return utils.NewValidationError("只有处于「"+active[len(active)-1].Name+"」阶段的申请才能录用", "stage_id")
+		}
+		return nil
+	}
+
+	for i, stage := range active {
+		if stage.ID == to.ID && (i == fromIndex+1 || i == fromIndex-1) {
+			return nil
+		}
+	}
+	return utils.NewValidationError(fmt.Sprintf("不能从「%s」直接流转到「%s」", from.Name, to.Name), "stage_id")
+}
+
+// stageStatus 由阶段推导兼容旧版的申请状态
+func stageStatus(stages []models.PipelineStage, stage models.PipelineStage) string {
+	switch stage.Kind {
+	case models.StageKindHired:
+		return "hired"
+	case models.StageKindRejected:
+		return "rejected"
+	}
+	if stage.ID == firstActiveStage(stages).ID {
+		return "pending"
+	}
+	return "interviewed"
+}
+
+func findJob(tx *gorm.DB, jobID uint) error {
+	var count int64
+	if err := tx.Model(&models.Job{}).Where("id = ?", jobID).Count(&count).Error; err != nil {
+		return fmt.Errorf("查询职位失败: %w", err)
+	}
+	if count == 0 {
+		return utils.NewNotFoundError("职位不存在", "job")
+	}
+	return nil
+}
+
+// errStageNotInPipeline 目标阶段不属于申请所在职位
+var errStageNotInPipeline = utils.NewValidationError("目标阶段不属于该职位的招聘流程", "stage_id")
+
+// findStage 在阶段列表中按ID查找
+func findStage(stages []models.PipelineStage, stageID uint) (models.PipelineStage, error) {
+	for _, stage := range stages {
+		if stage.ID == stageID {
+			return stage, nil
+		}
+	}
+	return models.PipelineStage{}, errStageNotInPipeline
+}
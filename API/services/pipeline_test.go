@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+
+	"API/models"
+)
+
+// testStages 按默认模板生成带 ID 的阶段
+func testStages() []models.PipelineStage {
+	stages := make([]models.PipelineStage, len(models.DefaultPipelineStages))
+	for i, stage := range models.DefaultPipelineStages {
+		stage.ID = uint(i + 1)
+		stage.Position = i
+		stages[i] = stage
+	}
+	return stages
+}
+
+func TestCanTransition(t *testing.T) {
+	stages := testStages()
+	byCode := make(map[string]models.PipelineStage)
+	for _, stage := range stages {
+		byCode[stage.Code] = stage
+	}
+
+	tests := []struct {
+		from, to string
+		wantOK   bool
+	}{
+		{"screening", "phone_screen", true},
+		{"phone_screen", "screening", true},
+		{"screening", "technical", false},
+		{"technical", "screening", false},
+		{"screening", "screening", false},
+		{"screening", "rejected", true},
+		{"technical", "rejected", true},
+		{"technical", "hired", false},
+		{"offer", "hired", true},
+		{"offer", "technical", true},
+		{"hired", "offer", false},
+		{"rejected", "screening", false},
+		{"rejected", "hired", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			err := canTransition(stages, byCode[tt.from], byCode[tt.to])
+			if (err == nil) != tt.wantOK {
+				t.Errorf("canTransition(%s, %s) = %v, 期望通过: %v", tt.from, tt.to, err, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestStageStatus(t *testing.T) {
+	stages := testStages()
+	want := []string{"pending", "interviewed", "interviewed", "interviewed", "hired", "rejected"}
+	for i, stage := range stages {
+		if got := stageStatus(stages, stage); got != want[i] {
+			t.Errorf("stageStatus(%s) = %s, 期望 %s", stage.Code, got, want[i])
+		}
+	}
+}
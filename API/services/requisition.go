@@ -0,0 +1,376 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"API/mailer"
+	"API/models"
+	"API/utils"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RequisitionConfig 招聘需求审批配置
+type RequisitionConfig struct {
+	ApprovalChain []string // 依次审批的角色，department_head 仅限需求所属部门的负责人
+}
+
+// LoadRequisitionConfig 从配置文件加载招聘需求审批配置
+func LoadRequisitionConfig() RequisitionConfig {
+	config := RequisitionConfig{ApprovalChain: viper.GetStringSlice("requisitions.approval_chain")}
+	if len(config.ApprovalChain) == 0 {
+		config.ApprovalChain = []string{models.RoleDepartmentHead, models.RoleHR}
+	}
+	return config
+}
+
+// RequisitionService 招聘需求及其逐级审批
+type RequisitionService struct {
+	db     *gorm.DB
+	mailer mailer.Mailer
+	config RequisitionConfig
+}
+
+func NewRequisitionService(db *gorm.DB, m mailer.Mailer, config RequisitionConfig) *RequisitionService {
+	return &RequisitionService{db: db, mailer: m, config: config}
+}
+
+// RequisitionInput 发起招聘需求的参数
+type RequisitionInput struct {
+	Headcount     uint    `json:"headcount" binding:"required,min=1,max=1000"`
+	Budget        float64 `json:"budget" binding:"gte=0"`
+	Justification string  `json:"justification" binding:"required,max=2000"`
+	Department    string  `json:"department" binding:"max=50"` // 为空时使用发起人所在部门
+}
+
+// Submit 为草稿职位发起招聘需求，职位转为待审批并通知第一步审批人
+func (s *RequisitionService) Submit(ctx context.Context, jobID, requesterID uint, input RequisitionInput) (*models.Requisition, error) {
+	var requisition models.Requisition
+	var notices []mailer.Message
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var job models.Job
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&job, jobID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewNotFoundError("职位不存在", "job")
+			}
+			return fmt.Errorf("查询职位失败: %w", err)
+		}
+		switch job.Status {
+		case models.JobDraft:
+		case models.JobPendingApproval:
+			return utils.NewValidationError("该职位已有待审批的招聘需求", "job")
+		default:
+			return utils.NewValidationError("职位已发布，无需再发起招聘需求", "job")
+		}
+		var approved int64
+		if err := tx.Model(&models.Requisition{}).
+			Where("job_id = ? AND status = ?", jobID, models.RequisitionApproved).
+			Count(&approved).Error; err != nil {
+			return fmt.Errorf("查询招聘需求失败: %w", err)
+		}
+		if approved > 0 {
+			return utils.NewValidationError("该职位的招聘需求已审批通过，可直接发布", "job")
+		}
+
+		var requester models.User
+		if err := tx.First(&requester, requesterID).Error; err != nil {
+			return fmt.Errorf("查询发起人失败: %w", err)
+		}
+		department := strings.TrimSpace(input.Department)
+		if department == "" {
+			department = requester.Department
+		}
+		if department == "" {
+			return utils.NewValidationError("请填写用人部门", "department")
+		}
+
+		requisition = models.Requisition{
+			JobID:         jobID,
+			RequestedBy:   requesterID,
+			Department:    department,
+			Headcount:     input.Headcount,
+			Budget:        input.Budget,
+			Justification: input.Justification,
+			Status:        models.RequisitionPending,
+			CurrentStep:   1,
+		}
+		for i, role := range s.config.ApprovalChain {
+			requisition.Approvals = append(requisition.Approvals, models.RequisitionApproval{
+				Step:         i + 1,
+				ApproverRole: role,
+				Status:       models.ApprovalPending,
+			})
+		}
+		if err := tx.Create(&requisition).Error; err != nil {
+			return fmt.Errorf("创建招聘需求失败: %w", err)
+		}
+		if err := tx.Model(&job).Update("status", models.JobPendingApproval).Error; err != nil {
+			return fmt.Errorf("更新职位状态失败: %w", err)
+		}
+		requisition.Job = job
+
+		var err error
+		notices, err = s.approvalNotices(tx, &requisition, requisition.Approvals[0])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notify(notices)
+	return &requisition, nil
+}
+
+// Approve 审批通过当前步骤，全部步骤通过后招聘需求生效，职位退回草稿等待发布
+func (s *RequisitionService) Approve(ctx context.Context, requisitionID, userID uint, comment string) (*models.Requisition, error) {
+	return s.decide(ctx, requisitionID, userID, models.ApprovalApproved, comment, func(tx *gorm.DB, requisition *models.Requisition) ([]mailer.Message, error) {
+		if requisition.CurrentStep < len(requisition.Approvals) {
+			requisition.CurrentStep++
+			if err := tx.Model(requisition).Update("current_step", requisition.CurrentStep).Error; err != nil {
+				return nil, fmt.Errorf("更新招聘需求失败: %w", err)
+			}
+			return s.approvalNotices(tx, requisition, requisition.Approvals[requisition.CurrentStep-1])
+		}
+
+		requisition.Status = models.RequisitionApproved
+		if err := tx.Model(requisition).Update("status", requisition.Status).Error; err != nil {
+			return nil, fmt.Errorf("更新招聘需求失败: %w", err)
+		}
+		requisition.Job.Status = models.JobDraft
+		if err := tx.Model(&requisition.Job).Update("status", models.JobDraft).Error; err != nil {
+			return nil, fmt.Errorf("更新职位状态失败: %w", err)
+		}
+		return s.requesterNotice(tx, requisition, "招聘需求已审批通过",
+			fmt.Sprintf("您为职位「%s」发起的招聘需求已全部审批通过，现在可以发布该职位。", requisition.Job.Title))
+	})
+}
+
+// Reject 驳回招聘需求，职位退回草稿，可修改后重新发起
+func (s *RequisitionService) Reject(ctx context.Context, requisitionID, userID uint, comment string) (*models.Requisition, error) {
+	if strings.TrimSpace(comment) == "" {
+		return nil, utils.NewValidationError("驳回时请填写审批意见", "comment")
+	}
+	return s.decide(ctx, requisitionID, userID, models.ApprovalRejected, comment, func(tx *gorm.DB, requisition *models.Requisition) ([]mailer.Message, error) {
+		if err := tx.Model(&models.RequisitionApproval{}).
+			Where("requisition_id = ? AND step > ?", requisition.ID, requisition.CurrentStep).
+			Update("status", models.ApprovalSkipped).Error; err != nil {
+			return nil, fmt.Errorf("更新审批步骤失败: %w", err)
+		}
+		for i := requisition.CurrentStep; i < len(requisition.Approvals); i++ {
+			requisition.Approvals[i].Status = models.ApprovalSkipped
+		}
+		requisition.Status = models.RequisitionRejected
+		if err := tx.Model(requisition).Update("status", requisition.Status).Error; err != nil {
+			return nil, fmt.Errorf("更新招聘需求失败: %w", err)
+		}
+		requisition.Job.Status = models.JobDraft
+		if err := tx.Model(&requisition.Job).Update("status", models.JobDraft).Error; err != nil {
+			return nil, fmt.Errorf("更新职位状态失败: %w", err)
+		}
+		return s.requesterNotice(tx, requisition, "招聘需求被驳回",
+			fmt.Sprintf("您为职位「%s」发起的招聘需求已被驳回。\n\n审批意见：%s\n\n职位已退回草稿，可修改后重新发起。", requisition.Job.Title, comment))
+	})
+}
+
+// decide 校验审批人后记录当前步骤的审批结果，再由 next 推进或终止流程
+func (s *RequisitionService) decide(ctx context.Context, requisitionID, userID uint, decision, comment string,
+	next func(tx *gorm.DB, requisition *models.Requisition) ([]mailer.Message, error)) (*models.Requisition, error) {
+	var requisition models.Requisition
+	var notices []mailer.Message
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Job").
+			Preload("Approvals", func(db *gorm.DB) *gorm.DB { return db.Order("step ASC") }).
+			First(&requisition, requisitionID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.NewNotFoundError("招聘需求不存在", "requisition")
+			}
+			return fmt.Errorf("查询招聘需求失败: %w", err)
+		}
+		if requisition.Status != models.RequisitionPending {
+			return utils.NewValidationError("招聘需求已完成审批", "requisition")
+		}
+		if requisition.RequestedBy == userID {
+			return utils.NewForbiddenError("不能审批自己发起的招聘需求")
+		}
+		approval := &requisition.Approvals[requisition.CurrentStep-1]
+		allowed, err := canApprove(tx, userID, approval.ApproverRole, requisition.Department)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return utils.NewForbiddenError("您不是当前审批步骤的审批人")
+		}
+
+		now := time.Now()
+		approval.Status = decision
+		approval.ApproverID = &userID
+		approval.Comment = comment
+		approval.DecidedAt = &now
+		if err := tx.Model(approval).Updates(map[string]interface{}{
+			"status":      decision,
+			"approver_id": userID,
+			"comment":     comment,
+			"decided_at":  now,
+		}).Error; err != nil {
+			return fmt.Errorf("记录审批结果失败: %w", err)
+		}
+
+		notices, err = next(tx, &requisition)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notify(notices)
+	return &requisition, nil
+}
+
+// ListRequisitions 分页获取招聘需求，status 为空时不筛选
+func (s *RequisitionService) ListRequisitions(ctx context.Context, status string, page, size int) ([]models.Requisition, int64, error) {
+	query := s.db.WithContext(ctx).Model(&models.Requisition{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计招聘需求失败: %w", err)
+	}
+	var requisitions []models.Requisition
+	if err := query.Preload("Job").
+		Order("id DESC").
+		Offset((page - 1) * size).
+		Limit(size).
+		Find(&requisitions).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询招聘需求失败: %w", err)
+	}
+	return requisitions, total, nil
+}
+
+// ListPendingForApprover 获取当前步骤由该用户审批的招聘需求
+func (s *RequisitionService) ListPendingForApprover(ctx context.Context, userID uint) ([]models.Requisition, error) {
+	var user models.User
+	if err := s.db.WithContext(ctx).Preload("Roles").First(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+	roles := make([]string, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		roles = append(roles, role.Name)
+	}
+	if len(roles) == 0 {
+		return []models.Requisition{}, nil
+	}
+
+	// 部门负责人仅审批本部门的招聘需求
+	requisitions := []models.Requisition{}
+	if err := s.db.WithContext(ctx).Preload("Job").
+		Joins("JOIN requisition_approvals ON requisition_approvals.requisition_id = requisitions.id AND requisition_approvals.step = requisitions.current_step").
+		Where("requisitions.status = ? AND requisitions.requested_by <> ? AND requisition_approvals.approver_role IN ?",
+			models.RequisitionPending, userID, roles).
+		Where("(requisition_approvals.approver_role <> ? OR requisitions.department = ?)", models.RoleDepartmentHead, user.Department).
+		Select("requisitions.*").
+		Order("requisitions.id ASC").
+		Find(&requisitions).Error; err != nil {
+		return nil, fmt.Errorf("查询待审批招聘需求失败: %w", err)
+	}
+	return requisitions, nil
+}
+
+// GetRequisition 获取招聘需求详情及审批记录
+func (s *RequisitionService) GetRequisition(ctx context.Context, requisitionID uint) (*models.Requisition, error) {
+	var requisition models.Requisition
+	if err := s.db.WithContext(ctx).
+		Preload("Job").
+		Preload("Requester").
+		Preload("Approvals", func(db *gorm.DB) *gorm.DB { return db.Order("step ASC") }).
+		First(&requisition, requisitionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("招聘需求不存在", "requisition")
+		}
+		return nil, fmt.Errorf("查询招聘需求失败: %w", err)
+	}
+	return &requisition, nil
+}
+
+// approvalNotices 生成通知某一步骤审批人的邮件
+func (s *RequisitionService) approvalNotices(tx *gorm.DB, requisition *models.Requisition, approval models.RequisitionApproval) ([]mailer.Message, error) {
+	approvers, err := approversFor(tx, approval.ApproverRole, requisition.Department)
+	if err != nil {
+		return nil, err
+	}
+	if len(approvers) == 0 {
+		log.Printf("招聘需求 %d 第%d步没有可通知的审批人（角色 %s，部门 %s）",
+			requisition.ID, approval.Step, approval.ApproverRole, requisition.Department)
+		return nil, nil
+	}
+	messages := make([]mailer.Message, 0, len(approvers))
+	for _, approver := range approvers {
+		if approver.ID == requisition.RequestedBy {
+			continue
+		}
+		messages = append(messages, mailer.Message{
+			To:      []string{approver.Email},
+			Subject: "待审批招聘需求：" + requisition.Job.Title,
+			Body: fmt.Sprintf("%s，您好：\n\n有一条招聘需求等待您审批（第%d步）：\n职位：%s\n部门：%s\n招聘人数：%d\n预算：%.2f\n招聘理由：%s",
+				approver.Username, approval.Step, requisition.Job.Title, requisition.Department,
+				requisition.Headcount, requisition.Budget, requisition.Justification),
+		})
+	}
+	return messages, nil
+}
+
+// requesterNotice 生成通知发起人审批结果的邮件
+func (s *RequisitionService) requesterNotice(tx *gorm.DB, requisition *models.Requisition, subject, body string) ([]mailer.Message, error) {
+	var requester models.User
+	if err := tx.First(&requester, requisition.RequestedBy).Error; err != nil {
+		return nil, fmt.Errorf("查询发起人失败: %w", err)
+	}
+	return []mailer.Message{{
+		To:      []string{requester.Email},
+		Subject: subject,
+		Body:    fmt.Sprintf("%s，您好：\n\n%s", requester.Username, body),
+	}}, nil
+}
+
+// notify 事务提交后发送通知邮件
+func (s *RequisitionService) notify(messages []mailer.Message) {
+	for _, msg := range messages {
+		sendMailAsync(s.mailer, msg)
+	}
+}
+
+// approversScope 持有指定角色的在职用户，部门负责人限定为需求所属部门
+func approversScope(tx *gorm.DB, role, department string) *gorm.DB {
+	scope := tx.Model(&models.User{}).
+		Joins("JOIN user_roles ON user_roles.user_id = users.id").
+		Joins("JOIN roles ON roles.id = user_roles.role_id").
+		Where("roles.name = ? AND users.active = ?", role, true)
+	if role == models.RoleDepartmentHead {
+		scope = scope.Where("users.department = ?", department)
+	}
+	return scope
+}
+
+// approversFor 查询某一审批步骤的全部审批人
+func approversFor(tx *gorm.DB, role, department string) ([]models.User, error) {
+	var users []models.User
+	if err := approversScope(tx, role, department).Select("users.*").Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("查询审批人失败: %w", err)
+	}
+	return users, nil
+}
+
+// canApprove 判断用户能否审批该步骤
+func canApprove(tx *gorm.DB, userID uint, role, department string) (bool, error) {
+	var count int64
+	if err := approversScope(tx, role, department).Where("users.id = ?", userID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("查询审批人失败: %w", err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"API/models"
+	"API/utils"
+)
+
+// requisitionFixture 招聘需求测试数据：研发部 requester 发起，研发部和销售部各有一名部门负责人，另有一名 HR
+type requisitionFixture struct {
+	svc       *RequisitionService
+	jobs      *JobService
+	mailer    *recordingMailer
+	job       models.Job
+	requester models.User
+	head      models.User
+	otherHead models.User
+	hr        models.User
+}
+
+func newRequisitionFixture(t *testing.T) *requisitionFixture {
+	t.Helper()
+	db := newTestDB(t, &models.User{}, &models.Role{}, &models.Job{}, &models.Requisition{}, &models.RequisitionApproval{})
+	roles := map[string]*models.Role{models.RoleDepartmentHead: {Name: models.RoleDepartmentHead}, models.RoleHR: {Name: models.RoleHR}}
+	for _, role := range roles {
+		if err := db.Create(role).Error; err != nil {
+			t.Fatalf("创建角色失败: %v", err)
+		}
+	}
+	f := &requisitionFixture{mailer: &recordingMailer{}}
+	users := []struct {
+		user       *models.User
+		name, dept string
+		role       string
+	}{
+		{&f.requester, "requester", "研发部", ""},
+		{&f.head, "head", "研发部", models.RoleDepartmentHead},
+		{&f.otherHead, "other_head", "销售部", models.RoleDepartmentHead},
+		{&f.hr, "hr", "人事部", models.RoleHR},
+	}
+	for _, u := range users {
+		*u.user = models.User{Username: u.name, Email: u.name + "@example.com", Phone: u.name, Department: u.dept, Usertype: "employee", Active: true}
+		if u.role != "" {
+			u.user.Roles = []models.Role{*roles[u.role]}
+		}
+		if err := db.Omit("Roles.*").Create(u.user).Error; err != nil {
+			t.Fatalf("创建用户失败: %v", err)
+		}
+	}
+
+	f.jobs = NewJobService(db)
+	f.job = models.Job{Title: "后端工程师", Description: "负责后端服务", Requirements: "熟悉 Go"}
+	if err := f.jobs.CreateJob(context.Background(), &f.job); err != nil {
+		t.Fatalf("CreateJob: %v", err)
+	}
+	f.svc = NewRequisitionService(db, f.mailer, RequisitionConfig{ApprovalChain: []string{models.RoleDepartmentHead, models.RoleHR}})
+	return f
+}
+
+func (f *requisitionFixture) submit(t *testing.T) *models.Requisition {
+	t.Helper()
+	requisition, err := f.svc.Submit(context.Background(), f.job.ID, f.requester.ID, RequisitionInput{Headcount: 2, Budget: 600000, Justification: "业务扩张"})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	return requisition
+}
+
+func (f *requisitionFixture) jobStatus(t *testing.T) string {
+	t.Helper()
+	var job models.Job
+	if err := f.svc.db.First(&job, f.job.ID).Error; err != nil {
+		t.Fatalf("查询职位失败: %v", err)
+	}
+	return job.Status
+}
+
+// approvalStatuses 按步骤返回审批状态
+func (f *requisitionFixture) approvalStatuses(t *testing.T, requisitionID uint) []string {
+	t.Helper()
+	requisition, err := f.svc.GetRequisition(context.Background(), requisitionID)
+	if err != nil {
+		t.Fatalf("GetRequisition: %v", err)
+	}
+	statuses := make([]string, len(requisition.Approvals))
+	for i, approval := range requisition.Approvals {
+		statuses[i] = approval.Status
+	}
+	return statuses
+}
+
+func TestJobRequisitionLifecycle(t *testing.T) {
+	ctx := context.Background()
+	f := newRequisitionFixture(t)
+	if got := f.jobStatus(t); got != models.JobDraft {
+		t.Fatalf("新建职位状态为 %s，期望草稿", got)
+	}
+	if _, err := f.jobs.PublishJob(ctx, f.job.ID); validationMessage(err) != "职位的招聘需求尚未审批通过" {
+		t.Fatalf("未审批的职位不应能发布，得到 %v", err)
+	}
+
+	requisition := f.submit(t)
+	if got := f.jobStatus(t); got != models.JobPendingApproval {
+		t.Fatalf("发起招聘需求后职位状态为 %s，期望待审批", got)
+	}
+	if requisition.Department != "研发部" || requisition.CurrentStep != 1 {
+		t.Errorf("招聘需求部门为 %s，当前步骤为 %d", requisition.Department, requisition.CurrentStep)
+	}
+	if _, err := f.svc.Submit(ctx, f.job.ID, f.requester.ID, RequisitionInput{Headcount: 1, Justification: "重复"}); validationMessage(err) != "该职位已有待审批的招聘需求" {
+		t.Errorf("重复发起应失败，得到 %v", err)
+	}
+	if _, err := f.jobs.PublishJob(ctx, f.job.ID); validationMessage(err) != "招聘需求正在审批中" {
+		t.Errorf("审批中的职位不应能发布，得到 %v", err)
+	}
+
+	for _, approver := range []models.User{f.head, f.hr} {
+		if _, err := f.svc.Approve(ctx, requisition.ID, approver.ID, "同意"); err != nil {
+			t.Fatalf("%s 审批失败: %v", approver.Username, err)
+		}
+	}
+	if got := f.approvalStatuses(t, requisition.ID); strings.Join(got, ",") != "approved,approved" {
+		t.Errorf("审批步骤状态为 %v", got)
+	}
+
+	// 审批通过后职位仍为草稿，由发布操作开放
+	if got := f.jobStatus(t); got != models.JobDraft {
+		t.Errorf("审批通过后职位状态为 %s，期望草稿", got)
+	}
+	if _, err := f.svc.Submit(ctx, f.job.ID, f.requester.ID, RequisitionInput{Headcount: 1, Justification: "重复"}); validationMessage(err) != "该职位的招聘需求已审批通过，可直接发布" {
+		t.Errorf("审批通过后再次发起应失败，得到 %v", err)
+	}
+	job, err := f.jobs.PublishJob(ctx, f.job.ID)
+	if err != nil {
+		t.Fatalf("PublishJob: %v", err)
+	}
+	if job.Status != models.JobOpen {
+		t.Errorf("发布后职位状态为 %s，期望开放", job.Status)
+	}
+	if _, err := f.jobs.PublishJob(ctx, f.job.ID); validationMessage(err) != "职位已发布" {
+		t.Errorf("重复发布应失败，得到 %v", err)
+	}
+}
+
+func TestRequisitionApprovalChain(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		decisions []struct{ approver, decision string } // decision 为 approve 或 reject
+		wantErr   string
+		status    string
+		steps     string
+		jobStatus string
+	}{
+		{"部门负责人驳回，后续步骤跳过", []struct{ approver, decision string }{{"head", "reject"}},
+			"", models.RequisitionRejected, "rejected,skipped", models.JobDraft},
+		{"HR驳回", []struct{ approver, decision string }{{"head", "approve"}, {"hr", "reject"}},
+			"", models.RequisitionRejected, "approved,rejected", models.JobDraft},
+		{"逐级审批通过", []struct{ approver, decision string }{{"head", "approve"}, {"hr", "approve"}},
+			"", models.RequisitionApproved, "approved,approved", models.JobDraft},
+		{"发起人不能审批", []struct{ approver, decision string }{{"requester", "approve"}},
+			"不能审批自己发起的招聘需求", models.RequisitionPending, "pending,pending", models.JobPendingApproval},
+		{"其他部门负责人不能审批", []struct{ approver, decision string }{{"other_head", "approve"}},
+			"您不是当前审批步骤的审批人", models.RequisitionPending, "pending,pending", models.JobPendingApproval},
+		{"不能越级审批", []struct{ approver, decision string }{{"hr", "approve"}},
+			"您不是当前审批步骤的审批人", models.RequisitionPending, "pending,pending", models.JobPendingApproval},
+		{"驳回后不能继续审批", []struct{ approver, decision string }{{"head", "reject"}, {"hr", "approve"}},
+			"招聘需求已完成审批", models.RequisitionRejected, "rejected,skipped", models.JobDraft},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRequisitionFixture(t)
+			approvers := map[string]uint{"requester": f.requester.ID, "head": f.head.ID, "other_head": f.otherHead.ID, "hr": f.hr.ID}
+			requisition := f.submit(t)
+			var err error
+			for _, d := range tt.decisions {
+				if d.decision == "reject" {
+					_, err = f.svc.Reject(ctx, requisition.ID, approvers[d.approver], "预算不足")
+				} else {
+					_, err = f.svc.Approve(ctx, requisition.ID, approvers[d.approver], "")
+				}
+				if err != nil {
+					break
+				}
+			}
+			var forbidden *utils.ForbiddenError
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("审批失败: %v", err)
+			case tt.wantErr != "" && validationMessage(err) != tt.wantErr && !(errors.As(err, &forbidden) && forbidden.Message == tt.wantErr):
+				t.Fatalf("期望错误 %q，得到 %v", tt.wantErr, err)
+			}
+
+			got, err := f.svc.GetRequisition(ctx, requisition.ID)
+			if err != nil {
+				t.Fatalf("GetRequisition: %v", err)
+			}
+			if got.Status != tt.status {
+				t.Errorf("招聘需求状态为 %s，期望 %s", got.Status, tt.status)
+			}
+			if steps := strings.Join(f.approvalStatuses(t, requisition.ID), ","); steps != tt.steps {
+				t.Errorf("审批步骤状态为 %s，期望 %s", steps, tt.steps)
+			}
+			if status := f.jobStatus(t); status != tt.jobStatus {
+				t.Errorf("职位状态为 %s，期望 %s", status, tt.jobStatus)
+			}
+		})
+	}
+
+	// 驳回必须填写意见，驳回后可重新发起
+	f := newRequisitionFixture(t)
+	requisition := f.submit(t)
+	if _, err := f.svc.Reject(ctx, requisition.ID, f.head.ID, " "); validationMessage(err) != "驳回时请填写审批意见" {
+		t.Errorf("未填写意见应失败，得到 %v", err)
+	}
+	if _, err := f.svc.Reject(ctx, requisition.ID, f.head.ID, "预算不足"); err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+	f.submit(t)
+}
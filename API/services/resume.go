@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"API/models"
+	"API/resumeparser"
 	"API/storage/cache"
+	"API/utils"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ResumeService struct {
@@ -37,57 +42,136 @@ func (s *ResumeService) CreateResume(ctx context.Context, resume *models.Resume)
 	return s.db.WithContext(ctx).Create(resume).Error
 }
 
-func (s *ResumeService) SubmitResume(ctx context.Context, userID uint, resume *models.Resume) error {
+// ResumeInput 提交简历的结构化内容，提交后整体替换原有的教育、工作经历和技能
+type ResumeInput struct {
+	Headline       string            `json:"headline" binding:"max=100"`
+	Summary        string            `json:"summary" binding:"max=5000"`
+	ExpectedSalary float64           `json:"expected_salary" binding:"gte=0"` // 期望月薪（人民币）
+	Location       string            `json:"location" binding:"max=100"`      // 期望工作地点
+	FilePath       string            `json:"file_path" binding:"max=255"`
+	Education      []EducationInput  `json:"education" binding:"max=20,dive"`
+	Employment     []EmploymentInput `json:"employment" binding:"max=50,dive"`
+	Skills         []string          `json:"skills" binding:"max=100,dive,max=50"`
+}
+
+// EducationInput 教育经历，日期格式为 YYYY-MM-DD、YYYY-MM 或 YYYY
+type EducationInput struct {
+	Institution string `json:"institution" binding:"required,max=100"`
+	Degree      string `json:"degree" binding:"max=50"`
+	Major       string `json:"major" binding:"max=100"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"` // 为空表示至今
+}
+
+// EmploymentInput 工作经历，日期格式同 EducationInput
+type EmploymentInput struct {
+	Company     string `json:"company" binding:"required,max=100"`
+	Title       string `json:"title" binding:"max=100"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"` // 为空表示至今
+	Description string `json:"description" binding:"max=5000"`
+}
+
+// SubmitResume 创建或更新当前用户的简历
+func (s *ResumeService) SubmitResume(ctx context.Context, userID uint, input ResumeInput) (*models.Resume, error) {
 	// 数据验证
-	if resume.Education == "" || resume.WorkExperience == "" || resume.Skills == "" {
-		return errors.New("教育背景、工作经历和技能信息不能为空")
+	if len(input.Education) == 0 && len(input.Employment) == 0 && len(input.Skills) == 0 {
+		return nil, utils.NewValidationError("教育背景、工作经历和技能至少填写一项", "education")
+	}
+	education, err := buildEducation(input.Education)
+	if err != nil {
+		return nil, err
+	}
+	employment, err := buildEmployment(input.Employment)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// 检查简历是否存在
-		var existingResume models.Resume
-		err := tx.Where("user_id = ?", userID).First(&existingResume).Error
-		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				// 如果简历不存在，创建新简历
-				resume.UserID = userID
-				if err := tx.Create(resume).Error; err != nil {
-					return fmt.Errorf("创建简历失败: %w", err)
-				}
-			} else {
-				return fmt.Errorf("查询简历失败: %w", err)
-			}
-		} else {
-			// 更新现有简历
-			if err := tx.Model(&existingResume).Updates(resume).Error; err != nil {
-				return fmt.Errorf("更新简历失败: %w", err)
-			}
+	var resume models.Resume
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 检查简历是否存在，锁定以免并发提交生成重复的版本号
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&resume).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("查询简历失败: %w", err)
 		}
-
-		// 更新用户信息
-		updates := map[string]interface{}{
-			"education":       resume.Education,
-			"work_experience": resume.WorkExperience,
-			"skills":          resume.Skills,
-			"salary_base":     resume.ExpectedSalary,
+		resume.UserID = userID
+		resume.Headline = input.Headline
+		resume.Summary = input.Summary
+		resume.ExpectedSalary = input.ExpectedSalary
+		resume.Location = input.Location
+		resume.FilePath = input.FilePath
+		if err := tx.Omit(clause.Associations).Save(&resume).Error; err != nil {
+			return fmt.Errorf("保存简历失败: %w", err)
 		}
 
-		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(updates).Error; err != nil {
-			return fmt.Errorf("更新用户信息失败: %w", err)
+		// 各部分整体替换
+		if err := tx.Unscoped().Where("resume_id = ?", resume.ID).Delete(&models.ResumeEducation{}).Error; err != nil {
+			return fmt.Errorf("更新教育经历失败: %w", err)
 		}
-
-		// 保存简历文件路径（如果有上传文件）
-		if resume.FilePath != "" {
-			if err := tx.Model(&models.User{}).Where("id = ?", userID).Update("resume_path", resume.FilePath).Error; err != nil {
-				return fmt.Errorf("保存简历路径失败: %w", err)
+		if err := tx.Unscoped().Where("resume_id = ?", resume.ID).Delete(&models.ResumeEmployment{}).Error; err != nil {
+			return fmt.Errorf("更新工作经历失败: %w", err)
+		}
+		for i := range education {
+			education[i].ResumeID = resume.ID
+		}
+		for i := range employment {
+			employment[i].ResumeID = resume.ID
+		}
+		if len(education) > 0 {
+			if err := tx.Create(&education).Error; err != nil {
+				return fmt.Errorf("保存教育经历失败: %w", err)
 			}
 		}
+		if len(employment) > 0 {
+			if err := tx.Create(&employment).Error; err != nil {
+				return fmt.Errorf("保存工作经历失败: %w", err)
+			}
+		}
+		skills, err := ensureSkills(tx, input.Skills)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&resume).Association("Skills").Replace(skills); err != nil {
+			return fmt.Errorf("保存技能失败: %w", err)
+		}
+		resume.Education, resume.Employment, resume.Skills = education, employment, skills
 
-		// 清除缓存
-		s.cache.Del(ctx, fmt.Sprintf("resume:%d", userID))
-
-		return nil
+		// 内容有变化时生成新版本，已投递的申请仍指向投递时的版本
+		_, err = recordResumeVersion(tx, &resume)
+		return err
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 清除缓存
+	s.cache.Del(ctx, fmt.Sprintf("resume:%d", userID))
+	return &resume, nil
+}
+
+// ParseResumeFile 从上传的简历文件中提取结构化内容，仅返回解析结果供用户确认，不保存
+func (s *ResumeService) ParseResumeFile(filename string, data []byte) (*ResumeInput, error) {
+	text, err := resumeparser.ExtractText(filename, data)
+	if err != nil {
+		return nil, utils.NewValidationError(err.Error(), "file")
+	}
+	parsed := resumeparser.Parse(text)
+	input := ResumeInput{
+		Summary:    parsed.Summary,
+		Education:  make([]EducationInput, 0, len(parsed.Education)),
+		Employment: make([]EmploymentInput, 0, len(parsed.Employment)),
+		Skills:     parsed.Skills,
+	}
+	for _, e := range parsed.Education {
+		input.Education = append(input.Education, EducationInput(e))
+	}
+	for _, e := range parsed.Employment {
+		input.Employment = append(input.Employment, EmploymentInput(e))
+	}
+	if input.Skills == nil {
+		input.Skills = []string{}
+	}
+	return &input, nil
 }
 
 func (s *ResumeService) GetResumeByUserID(ctx context.Context, userID uint) (*models.Resume, error) {
@@ -100,15 +184,15 @@ func (s *ResumeService) GetResumeByUserID(ctx context.Context, userID uint) (*mo
 	}
 
 	// 从数据库获取
-	if err := s.db.WithContext(ctx).Where("user_id = ?", userID).First(&resume).Error; err != nil {
+	if err := s.db.WithContext(ctx).Scopes(withResumeSections).Where("user_id = ?", userID).First(&resume).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("简历不存在")
+			return nil, utils.NewNotFoundError("简历不存在", "resume")
 		}
 		return nil, err
 	}
 
 	// 设置缓存
-	if err := s.cache.SetObject(ctx, cacheKey, resume, 3600); err != nil {
+	if err := s.cache.SetObject(ctx, cacheKey, resume, time.Hour); err != nil {
 		// 记录日志但不返回错误
 		fmt.Printf("设置简历缓存失败: %v\n", err)
 	}
@@ -129,6 +213,7 @@ func (s *ResumeService) GetResumeList(ctx context.Context, page, pageSize int) (
 	// 获取分页数据
 	if err := s.db.WithContext(ctx).
 		Preload("User"). // 预加载用户信息
+		Scopes(withResumeSections).
 		Offset((page - 1) * pageSize).
 		Limit(pageSize).
 		Find(&resumes).Error; err != nil {
@@ -142,20 +227,15 @@ func (s *ResumeService) GetResumeList(ctx context.Context, page, pageSize int) (
 func (s *ResumeService) DeleteResume(ctx context.Context, userID uint) error {
 	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 删除简历记录
-		if err := tx.Where("user_id = ?", userID).Delete(&models.Resume{}).Error; err != nil {
-			return fmt.Errorf("删除简历失败: %w", err)
-		}
-
-		// 清除用户相关信息
-		updates := map[string]interface{}{
-			"education":       "",
-			"work_experience": "",
-			"skills":          "",
-			"resume_path":     "",
+		var resume models.Resume
+		if err := tx.Where("user_id = ?", userID).First(&resume).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return fmt.Errorf("查询简历失败: %w", err)
 		}
-
-		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(updates).Error; err != nil {
-			return fmt.Errorf("清除用户简历信息失败: %w", err)
+		if err := tx.Select(clause.Associations).Delete(&resume).Error; err != nil {
+			return fmt.Errorf("删除简历失败: %w", err)
 		}
 
 		// 清除缓存
@@ -164,3 +244,86 @@ func (s *ResumeService) DeleteResume(ctx context.Context, userID uint) error {
 		return nil
 	})
 }
+
+// withResumeSections 预加载简历的教育、工作经历和技能
+func withResumeSections(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Education", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order ASC") }).
+		Preload("Employment", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order ASC") }).
+		Preload("Skills", func(db *gorm.DB) *gorm.DB { return db.Order("skills.name ASC") })
+}
+
+// buildEducation 校验并转换教育经历
+func buildEducation(inputs []EducationInput) ([]models.ResumeEducation, error) {
+	entries := make([]models.ResumeEducation, 0, len(inputs))
+	for i, input := range inputs {
+		start, end, err := parsePeriod(input.StartDate, input.EndDate, fmt.Sprintf("education[%d]", i))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, models.ResumeEducation{
+			Institution: strings.TrimSpace(input.Institution),
+			Degree:      strings.TrimSpace(input.Degree),
+			Major:       strings.TrimSpace(input.Major),
+			StartDate:   start,
+			EndDate:     end,
+			SortOrder:   i,
+		})
+	}
+	return entries, nil
+}
+
+// buildEmployment 校验并转换工作经历
+func buildEmployment(inputs []EmploymentInput) ([]models.ResumeEmployment, error) {
+	entries := make([]models.ResumeEmployment, 0, len(inputs))
+	for i, input := range inputs {
+		start, end, err := parsePeriod(input.StartDate, input.EndDate, fmt.Sprintf("employment[%d]", i))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, models.ResumeEmployment{
+			Company:     strings.TrimSpace(input.Company),
+			Title:       strings.TrimSpace(input.Title),
+			StartDate:   start,
+			EndDate:     end,
+			Description: strings.TrimSpace(input.Description),
+			SortOrder:   i,
+		})
+	}
+	return entries, nil
+}
+
+// parsePeriod 解析起止日期，结束日期不能早于开始日期
+func parsePeriod(startDate, endDate, field string) (*time.Time, *time.Time, error) {
+	start, err := resumeparser.ParseDate(startDate)
+	if err != nil {
+		return nil, nil, utils.NewValidationError(err.Error(), field+".start_date")
+	}
+	end, err := resumeparser.ParseDate(endDate)
+	if err != nil {
+		return nil, nil, utils.NewValidationError(err.Error(), field+".end_date")
+	}
+	if start != nil && end != nil && end.Before(*start) {
+		return nil, nil, utils.NewValidationError("结束日期不能早于开始日期", field+".end_date")
+	}
+	return start, end, nil
+}
+
+// ensureSkills 按规范化标识查找或创建技能标签，重复的技能只保留一个
+func ensureSkills(tx *gorm.DB, names []string) ([]models.Skill, error) {
+	skills := make([]models.Skill, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		key, display := resumeparser.NormalizeSkill(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		skill := models.Skill{Key: key}
+		if err := tx.Where(models.Skill{Key: key}).Attrs(models.Skill{Name: display}).FirstOrCreate(&skill).Error; err != nil {
+			return nil, fmt.Errorf("保存技能 %s 失败: %w", display, err)
+		}
+		skills = append(skills, skill)
+	}
+	return skills, nil
+}
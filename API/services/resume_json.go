@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"API/models"
+	"API/resumeparser"
+
+	"gorm.io/gorm"
+)
+
+// JSONResume JSON Resume 格式（https://jsonresume.org/schema）中本系统支持的部分
+type JSONResume struct {
+	Basics    JSONResumeBasics      `json:"basics"`
+	Work      []JSONResumeWork      `json:"work"`
+	Education []JSONResumeEducation `json:"education"`
+	Skills    []JSONResumeSkill     `json:"skills"`
+}
+
+type JSONResumeBasics struct {
+	Name     string              `json:"name,omitempty"`
+	Label    string              `json:"label,omitempty"`
+	Email    string              `json:"email,omitempty"`
+	Phone    string              `json:"phone,omitempty"`
+	Summary  string              `json:"summary,omitempty"`
+	Location *JSONResumeLocation `json:"location,omitempty"`
+}
+
+type JSONResumeLocation struct {
+	City   string `json:"city,omitempty"`
+	Region string `json:"region,omitempty"`
+}
+
+type JSONResumeWork struct {
+	Name       string   `json:"name"`
+	Position   string   `json:"position,omitempty"`
+	StartDate  string   `json:"startDate,omitempty"`
+	EndDate    string   `json:"endDate,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	Highlights []string `json:"highlights,omitempty"`
+}
+
+type JSONResumeEducation struct {
+	Institution string `json:"institution"`
+	Area        string `json:"area,omitempty"`
+	StudyType   string `json:"studyType,omitempty"`
+	StartDate   string `json:"startDate,omitempty"`
+	EndDate     string `json:"endDate,omitempty"`
+}
+
+type JSONResumeSkill struct {
+	Name     string   `json:"name"`
+	Level    string   `json:"level,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// ExportJSONResume 以 JSON Resume 格式导出当前用户的简历
+func (s *ResumeService) ExportJSONResume(ctx context.Context, userID uint) (*JSONResume, error) {
+	resume, err := s.GetResumeByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).First(&resume.User, userID).Error; err != nil {
+		return nil, err
+	}
+
+	export := JSONResume{
+		Basics: JSONResumeBasics{
+			Name:    resume.User.Username,
+			Label:   resume.Headline,
+			Email:   resume.User.Email,
+			Phone:   resume.User.Phone,
+			Summary: resume.Summary,
+		},
+		Work:      make([]JSONResumeWork, 0, len(resume.Employment)),
+		Education: make([]JSONResumeEducation, 0, len(resume.Education)),
+		Skills:    make([]JSONResumeSkill, 0, len(resume.Skills)),
+	}
+	if resume.Location != "" {
+		export.Basics.Location = &JSONResumeLocation{City: resume.Location}
+	}
+	for _, e := range resume.Employment {
+		export.Work = append(export.Work, JSONResumeWork{
+			Name:      e.Company,
+			Position:  e.Title,
+			StartDate: resumeparser.FormatDate(e.StartDate),
+			EndDate:   resumeparser.FormatDate(e.EndDate),
+			Summary:   e.Description,
+		})
+	}
+	for _, e := range resume.Education {
+		export.Education = append(export.Education, JSONResumeEducation{
+			Institution: e.Institution,
+			Area:        e.Major,
+			StudyType:   e.Degree,
+			StartDate:   resumeparser.FormatDate(e.StartDate),
+			EndDate:     resumeparser.FormatDate(e.EndDate),
+		})
+	}
+	for _, skill := range resume.Skills {
+		export.Skills = append(export.Skills, JSONResumeSkill{Name: skill.Name})
+	}
+	return &export, nil
+}
+
+// String 城市为空时使用地区
+func (l *JSONResumeLocation) String() string {
+	if l == nil {
+		return ""
+	}
+	if l.City != "" {
+		return l.City
+	}
+	return l.Region
+}
+
+// ResumeInput 转换为提交简历的参数；基本信息中仅导入职位头衔、简介和所在城市，
+// 姓名、邮箱、电话以账户信息为准。技能的 keywords 同样作为技能标签导入
+func (data JSONResume) ResumeInput() ResumeInput {
+	input := ResumeInput{
+		Headline:   data.Basics.Label,
+		Summary:    data.Basics.Summary,
+		Location:   data.Basics.Location.String(),
+		Education:  make([]EducationInput, 0, len(data.Education)),
+		Employment: make([]EmploymentInput, 0, len(data.Work)),
+	}
+	for _, e := range data.Education {
+		input.Education = append(input.Education, EducationInput{
+			Institution: e.Institution,
+			Degree:      e.StudyType,
+			Major:       e.Area,
+			StartDate:   e.StartDate,
+			EndDate:     e.EndDate,
+		})
+	}
+	for _, w := range data.Work {
+		description := w.Summary
+		if len(w.Highlights) > 0 {
+			description = strings.TrimSpace(description + "\n- " + strings.Join(w.Highlights, "\n- "))
+		}
+		input.Employment = append(input.Employment, EmploymentInput{
+			Company:     w.Name,
+			Title:       w.Position,
+			StartDate:   w.StartDate,
+			EndDate:     w.EndDate,
+			Description: description,
+		})
+	}
+	for _, skill := range data.Skills {
+		if skill.Name != "" {
+			input.Skills = append(input.Skills, skill.Name)
+		}
+		input.Skills = append(input.Skills, skill.Keywords...)
+	}
+	return input
+}
+
+// ImportResume 用导入的内容替换当前简历，期望薪资和简历文件沿用原简历
+func (s *ResumeService) ImportResume(ctx context.Context, userID uint, input ResumeInput) (*models.Resume, error) {
+	var existing models.Resume
+	err := s.db.WithContext(ctx).Where("user_id = ?", userID).First(&existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询简历失败: %w", err)
+	}
+	input.ExpectedSalary = existing.ExpectedSalary
+	input.FilePath = existing.FilePath
+	return s.SubmitResume(ctx, userID, input)
+}
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"API/models"
+	"API/resumeparser"
+	"API/utils"
+
+	"gorm.io/gorm"
+)
+
+// ResumeVersionDetail 简历版本及其内容快照
+type ResumeVersionDetail struct {
+	ID        uint        `json:"id"`
+	Version   int         `json:"version"`
+	CreatedAt time.Time   `json:"created_at"`
+	Content   ResumeInput `json:"content"`
+}
+
+// FieldChange 简历基本信息字段的变化
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// EntryChange 同一段经历修改前后的内容
+type EntryChange[T any] struct {
+	From T `json:"from"`
+	To   T `json:"to"`
+}
+
+// EntryDiff 教育或工作经历的增删改
+type EntryDiff[T any] struct {
+	Added   []T              `json:"added"`
+	Removed []T              `json:"removed"`
+	Changed []EntryChange[T] `json:"changed"`
+}
+
+// SkillDiff 技能的增删
+type SkillDiff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// ResumeDiff 两个简历版本之间的差异
+type ResumeDiff struct {
+	From       int                        `json:"from"`
+	To         int                        `json:"to"`
+	Fields     []FieldChange              `json:"fields"`
+	Education  EntryDiff[EducationInput]  `json:"education"`
+	Employment EntryDiff[EmploymentInput] `json:"employment"`
+	Skills     SkillDiff                  `json:"skills"`
+}
+
+// ListVersions 获取当前用户简历的全部版本，新版本在前
+func (s *ResumeService) ListVersions(ctx context.Context, userID uint) ([]models.ResumeVersion, error) {
+	var versions []models.ResumeVersion
+	if err := s.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("version DESC").
+		Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("查询简历版本失败: %w", err)
+	}
+	return versions, nil
+}
+
+// GetVersion 获取当前用户简历的指定版本
+func (s *ResumeService) GetVersion(ctx context.Context, userID uint, version int) (*ResumeVersionDetail, error) {
+	var record models.ResumeVersion
+	if err := s.db.WithContext(ctx).
+		Where("user_id = ? AND version = ?", userID, version).
+		First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("简历版本不存在", "resume_version")
+		}
+		return nil, fmt.Errorf("查询简历版本失败: %w", err)
+	}
+	return versionDetail(record)
+}
+
+// DiffVersions 比较当前用户简历的两个版本。经历按学校或公司及开始日期对应，技能按规范化标识比较
+func (s *ResumeService) DiffVersions(ctx context.Context, userID uint, from, to int) (*ResumeDiff, error) {
+	old, err := s.GetVersion(ctx, userID, from)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := s.GetVersion(ctx, userID, to)
+	if err != nil {
+		return nil, err
+	}
+	a, b := old.Content, cur.Content
+
+	diff := ResumeDiff{From: from, To: to, Fields: []FieldChange{}}
+	for _, field := range []struct {
+		name     string
+		from, to interface{}
+	}{
+		{"headline", a.Headline, b.Headline},
+		{"summary", a.Summary, b.Summary},
+		{"expected_salary", a.ExpectedSalary, b.ExpectedSalary},
+		{"location", a.Location, b.Location},
+		{"file_path", a.FilePath, b.FilePath},
+	} {
+		if field.from != field.to {
+			diff.Fields = append(diff.Fields, FieldChange{Field: field.name, From: field.from, To: field.to})
+		}
+	}
+	diff.Education = diffEntries(a.Education, b.Education, func(e EducationInput) string {
+		return e.Institution + "|" + e.StartDate
+	})
+	diff.Employment = diffEntries(a.Employment, b.Employment, func(e EmploymentInput) string {
+		return e.Company + "|" + e.StartDate
+	})
+	diff.Skills = diffSkills(a.Skills, b.Skills)
+	return &diff, nil
+}
+
+// PurgeRejectedVersions 清理淘汰候选人的历史简历版本：候选人的申请均已淘汰或撤回、
+// 且最近一次变动早于保留期限时，仅保留简历的最新版本，被删除版本的申请不再关联版本
+func (s *ResumeService) PurgeRejectedVersions(ctx context.Context, retention time.Duration) (int64, error) {
+	var userIDs []uint
+	if err := s.db.WithContext(ctx).Model(&models.Application{}).
+		Group("user_id").
+		Having("SUM(status IN ?) = 0 AND MAX(updated_at) < ?", []string{"pending", "interviewed", "hired"}, time.Now().Add(-retention)).
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return 0, fmt.Errorf("查询淘汰候选人失败: %w", err)
+	}
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+
+	var purged int64
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 先查出待删除的版本，MySQL 不允许在删除语句的子查询中引用同一张表
+		var versionIDs []uint
+		if err := tx.Table("resume_versions").
+			Joins("JOIN resumes ON resumes.id = resume_versions.resume_id").
+			Where("resume_versions.user_id IN ? AND resume_versions.version < resumes.version", userIDs).
+			Pluck("resume_versions.id", &versionIDs).Error; err != nil {
+			return fmt.Errorf("查询历史简历版本失败: %w", err)
+		}
+		if len(versionIDs) == 0 {
+			return nil
+		}
+		if err := tx.Model(&models.Application{}).
+			Where("resume_version_id IN ?", versionIDs).
+			Update("resume_version_id", nil).Error; err != nil {
+			return fmt.Errorf("解除申请关联的简历版本失败: %w", err)
+		}
+		result := tx.Delete(&models.ResumeVersion{}, versionIDs)
+		if result.Error != nil {
+			return fmt.Errorf("删除历史简历版本失败: %w", result.Error)
+		}
+		purged = result.RowsAffected
+		return nil
+	})
+	return purged, err
+}
+
+// recordResumeVersion 内容与最新版本不同时为简历生成新版本，返回最新版本
+func recordResumeVersion(tx *gorm.DB, resume *models.Resume) (*models.ResumeVersion, error) {
+	content, err := json.Marshal(resumeContent(*resume))
+	if err != nil {
+		return nil, fmt.Errorf("序列化简历内容失败: %w", err)
+	}
+
+	if resume.Version > 0 {
+		var latest models.ResumeVersion
+		err := tx.Where("resume_id = ? AND version = ?", resume.ID, resume.Version).First(&latest).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("查询简历版本失败: %w", err)
+		}
+		if err == nil {
+			// 数据库会重新排列 JSON 字段，按解析后的内容比较
+			var stored ResumeInput
+			if err := json.Unmarshal([]byte(latest.Content), &stored); err == nil {
+				if normalized, err := json.Marshal(stored); err == nil && bytes.Equal(normalized, content) {
+					return &latest, nil
+				}
+			}
+		}
+	}
+
+	version := models.ResumeVersion{
+		ResumeID: resume.ID,
+		UserID:   resume.UserID,
+		Version:  resume.Version + 1,
+		Content:  string(content),
+	}
+	if err := tx.Create(&version).Error; err != nil {
+		return nil, fmt.Errorf("保存简历版本失败: %w", err)
+	}
+	if err := tx.Model(resume).Update("version", version.Version).Error; err != nil {
+		return nil, fmt.Errorf("更新简历版本号失败: %w", err)
+	}
+	return &version, nil
+}
+
+// resumeContent 将简历转换为版本快照的内容，需已加载教育、工作经历和技能
+func resumeContent(resume models.Resume) ResumeInput {
+	content := ResumeInput{
+		Headline:       resume.Headline,
+		Summary:        resume.Summary,
+		ExpectedSalary: resume.ExpectedSalary,
+		Location:       resume.Location,
+		FilePath:       resume.FilePath,
+		Education:      make([]EducationInput, 0, len(resume.Education)),
+		Employment:     make([]EmploymentInput, 0, len(resume.Employment)),
+		Skills:         make([]string, 0, len(resume.Skills)),
+	}
+	for _, e := range resume.Education {
+		content.Education = append(content.Education, EducationInput{
+			Institution: e.Institution,
+			Degree:      e.Degree,
+			Major:       e.Major,
+			StartDate:   resumeparser.FormatDate(e.StartDate),
+			EndDate:     resumeparser.FormatDate(e.EndDate),
+		})
+	}
+	for _, e := range resume.Employment {
+		content.Employment = append(content.Employment, EmploymentInput{
+			Company:     e.Company,
+			Title:       e.Title,
+			StartDate:   resumeparser.FormatDate(e.StartDate),
+			EndDate:     resumeparser.FormatDate(e.EndDate),
+			Description: e.Description,
+		})
+	}
+	for _, skill := range resume.Skills {
+		content.Skills = append(content.Skills, skill.Name)
+	}
+	return content
+}
+
+func versionDetail(record models.ResumeVersion) (*ResumeVersionDetail, error) {
+	detail := ResumeVersionDetail{ID: record.ID, Version: record.Version, CreatedAt: record.CreatedAt}
+	if err := json.Unmarshal([]byte(record.Content), &detail.Content); err != nil {
+		return nil, fmt.Errorf("解析简历版本内容失败: %w", err)
+	}
+	return &detail, nil
+}
+
+// snapshotResume 将简历版本快照还原为匹配度计算使用的简历，技能仅含规范化标识和展示名称
+func snapshotResume(record models.ResumeVersion) (models.Resume, error) {
+	detail, err := versionDetail(record)
+	if err != nil {
+		return models.Resume{}, err
+	}
+	content := detail.Content
+	employment, err := buildEmployment(content.Employment)
+	if err != nil {
+		return models.Resume{}, fmt.Errorf("简历版本 %d 的工作经历无效: %w", record.ID, err)
+	}
+	resume := models.Resume{
+		UserID:         record.UserID,
+		Headline:       content.Headline,
+		Summary:        content.Summary,
+		ExpectedSalary: content.ExpectedSalary,
+		Location:       content.Location,
+		Version:        record.Version,
+		Employment:     employment,
+	}
+	resume.ID = record.ResumeID
+	for _, name := range content.Skills {
+		if key, display := resumeparser.NormalizeSkill(name); key != "" {
+			resume.Skills = append(resume.Skills, models.Skill{Key: key, Name: display})
+		}
+	}
+	return resume, nil
+}
+
+// diffEntries 按 key 对应两组经历，key 重复时按出现顺序依次对应
+func diffEntries[T comparable](from, to []T, key func(T) string) EntryDiff[T] {
+	diff := EntryDiff[T]{Added: []T{}, Removed: []T{}, Changed: []EntryChange[T]{}}
+	remaining := make(map[string][]T, len(from))
+	for _, entry := range from {
+		k := key(entry)
+		remaining[k] = append(remaining[k], entry)
+	}
+	for _, entry := range to {
+		k := key(entry)
+		candidates := remaining[k]
+		if len(candidates) == 0 {
+			diff.Added = append(diff.Added, entry)
+			continue
+		}
+		remaining[k] = candidates[1:]
+		if candidates[0] != entry {
+			diff.Changed = append(diff.Changed, EntryChange[T]{From: candidates[0], To: entry})
+		}
+	}
+	for _, entry := range from {
+		k := key(entry)
+		if len(remaining[k]) > 0 {
+			diff.Removed = append(diff.Removed, remaining[k][0])
+			remaining[k] = remaining[k][1:]
+		}
+	}
+	return diff
+}
+
+func diffSkills(from, to []string) SkillDiff {
+	diff := SkillDiff{Added: []string{}, Removed: []string{}}
+	keys := func(names []string) map[string]bool {
+		set := make(map[string]bool, len(names))
+		for _, name := range names {
+			key, _ := resumeparser.NormalizeSkill(name)
+			set[key] = true
+		}
+		return set
+	}
+	old, cur := keys(from), keys(to)
+	for _, name := range to {
+		if key, _ := resumeparser.NormalizeSkill(name); !old[key] {
+			diff.Added = append(diff.Added, name)
+		}
+	}
+	for _, name := range from {
+		if key, _ := resumeparser.NormalizeSkill(name); !cur[key] {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"API/models"
+)
+
+func newTestResumeService(t *testing.T) *ResumeService {
+	t.Helper()
+	db := newTestDB(t, &models.Resume{}, &models.ResumeEducation{}, &models.ResumeEmployment{}, &models.Skill{},
+		&models.ResumeVersion{}, &models.Job{}, &models.PipelineStage{}, &models.Application{}, &models.ApplicationStageHistory{})
+	return NewResumeService(db, newMemoryCache())
+}
+
+func resumeInput(headline string, skills ...string) ResumeInput {
+	return ResumeInput{
+		Headline:   headline,
+		Employment: []EmploymentInput{{Company: "字节跳动", Title: "后端工程师", StartDate: "2020-07"}},
+		Skills:     skills,
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+// pinnedVersion 返回申请固定的简历版本号
+func pinnedVersion(t *testing.T, apps *ApplicationService, applicationID uint) int {
+	t.Helper()
+	detail, err := apps.GetApplicationResume(context.Background(), applicationID)
+	if err != nil {
+		t.Fatalf("GetApplicationResume: %v", err)
+	}
+	return detail.Version
+}
+
+func TestResumeVersionPinning(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestResumeService(t)
+	jobs, apps := NewJobService(svc.db), NewApplicationService(svc.db)
+	var open []models.Job
+	for _, title := range []string{"后端工程师", "架构师", "技术经理"} {
+		job := models.Job{Title: title, Status: models.JobOpen}
+		if err := svc.db.Create(&job).Error; err != nil {
+			t.Fatalf("创建职位失败: %v", err)
+		}
+		open = append(open, job)
+	}
+	applicationID := func(jobID uint) uint {
+		var application models.Application
+		if err := svc.db.Where("user_id = ? AND job_id = ?", 7, jobID).First(&application).Error; err != nil {
+			t.Fatalf("查询申请失败: %v", err)
+		}
+		return application.ID
+	}
+
+	if _, err := svc.SubmitResume(ctx, 7, resumeInput("Go 工程师", "Go")); err != nil {
+		t.Fatalf("SubmitResume: %v", err)
+	}
+	// 内容未变化时不生成新版本
+	resume, err := svc.SubmitResume(ctx, 7, resumeInput("Go 工程师", "Go"))
+	if err != nil {
+		t.Fatalf("SubmitResume: %v", err)
+	}
+	if resume.Version != 1 {
+		t.Fatalf("重复提交相同内容后版本号为 %d，期望 1", resume.Version)
+	}
+	if err := jobs.ApplyForJob(ctx, 7, open[0].ID, ApplyInput{}); err != nil {
+		t.Fatalf("ApplyForJob: %v", err)
+	}
+
+	// 投递后修改简历，已投递的申请仍指向投递时的版本
+	if resume, err = svc.SubmitResume(ctx, 7, resumeInput("资深 Go 工程师", "Go", "Kubernetes")); err != nil {
+		t.Fatalf("SubmitResume: %v", err)
+	}
+	if resume.Version != 2 {
+		t.Fatalf("修改内容后版本号为 %d，期望 2", resume.Version)
+	}
+	first := applicationID(open[0].ID)
+	detail, err := apps.GetApplicationResume(ctx, first)
+	if err != nil {
+		t.Fatalf("GetApplicationResume: %v", err)
+	}
+	if detail.Version != 1 || detail.Content.Headline != "Go 工程师" || !reflect.DeepEqual(detail.Content.Skills, []string{"Go"}) {
+		t.Errorf("申请固定的简历为版本 %d，内容 %+v", detail.Version, detail.Content)
+	}
+
+	// 未指定版本时使用最新版本，也可指定历史版本
+	if err := jobs.ApplyForJob(ctx, 7, open[1].ID, ApplyInput{}); err != nil {
+		t.Fatalf("ApplyForJob: %v", err)
+	}
+	if got := pinnedVersion(t, apps, applicationID(open[1].ID)); got != 2 {
+		t.Errorf("未指定版本时固定为版本 %d，期望 2", got)
+	}
+	if err := jobs.ApplyForJob(ctx, 7, open[2].ID, ApplyInput{ResumeVersion: intPtr(3)}); validationMessage(err) != "简历版本不存在" {
+		t.Errorf("指定不存在的版本应失败，得到 %v", err)
+	}
+	if err := jobs.ApplyForJob(ctx, 7, open[2].ID, ApplyInput{ResumeVersion: intPtr(1)}); err != nil {
+		t.Fatalf("ApplyForJob: %v", err)
+	}
+	if got := pinnedVersion(t, apps, applicationID(open[2].ID)); got != 1 {
+		t.Errorf("指定历史版本时固定为版本 %d，期望 1", got)
+	}
+	if err := jobs.ApplyForJob(ctx, 8, open[0].ID, ApplyInput{ResumeID: &resume.ID}); validationMessage(err) != "简历不存在或不属于本人" {
+		t.Errorf("使用他人简历应失败，得到 %v", err)
+	}
+
+	// 申请进行中可更换为最新版本
+	if _, err := apps.UpdateMyApplication(ctx, 7, first, ApplyInput{}); err != nil {
+		t.Fatalf("UpdateMyApplication: %v", err)
+	}
+	if got := pinnedVersion(t, apps, first); got != 2 {
+		t.Errorf("更换后固定为版本 %d，期望 2", got)
+	}
+
+	versions, err := svc.ListVersions(ctx, 7)
+	if err != nil {
+		t.Fatalf("ListVersions: %v", err)
+	}
+	if len(versions) != 2 || versions[0].Version != 2 {
+		t.Errorf("版本列表为 %+v", versions)
+	}
+}
+
+func TestDiffResumeVersions(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestResumeService(t)
+	before := resumeInput("Go 工程师", "Go", "MySQL")
+	before.Education = []EducationInput{{Institution: "浙江大学", Degree: "本科", StartDate: "2016-09", EndDate: "2020-06"}}
+	after := resumeInput("资深 Go 工程师", "Golang", "Redis")
+	after.Education = []EducationInput{{Institution: "浙江大学", Degree: "学士", StartDate: "2016-09", EndDate: "2020-06"}}
+	after.Employment = append(after.Employment, EmploymentInput{Company: "阿里巴巴", Title: "技术专家", StartDate: "2023-03"})
+	for _, input := range []ResumeInput{before, after} {
+		if _, err := svc.SubmitResume(ctx, 7, input); err != nil {
+			t.Fatalf("SubmitResume: %v", err)
+		}
+	}
+
+	diff, err := svc.DiffVersions(ctx, 7, 1, 2)
+	if err != nil {
+		t.Fatalf("DiffVersions: %v", err)
+	}
+	if want := []FieldChange{{Field: "headline", From: "Go 工程师", To: "资深 Go 工程师"}}; !reflect.DeepEqual(diff.Fields, want) {
+		t.Errorf("字段变化为 %+v", diff.Fields)
+	}
+	if len(diff.Education.Changed) != 1 || diff.Education.Changed[0].To.Degree != "学士" || len(diff.Education.Added)+len(diff.Education.Removed) != 0 {
+		t.Errorf("教育经历变化为 %+v", diff.Education)
+	}
+	if len(diff.Employment.Added) != 1 || diff.Employment.Added[0].Company != "阿里巴巴" || len(diff.Employment.Changed) != 0 {
+		t.Errorf("工作经历变化为 %+v", diff.Employment)
+	}
+	// Golang 与 Go 规范化后为同一技能
+	if !reflect.DeepEqual(diff.Skills, SkillDiff{Added: []string{"Redis"}, Removed: []string{"MySQL"}}) {
+		t.Errorf("技能变化为 %+v", diff.Skills)
+	}
+	if _, err := svc.DiffVersions(ctx, 8, 1, 2); err == nil {
+		t.Error("不能比较他人的简历版本")
+	}
+}
+
+func TestPurgeRejectedVersions(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestResumeService(t)
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	candidates := []struct {
+		userID uint
+		status string
+		at     time.Time
+		kept   int // 清理后保留的版本数
+	}{
+		{7, "rejected", old, 1},
+		{8, "pending", old, 2},
+		{9, "rejected", time.Now(), 2},
+	}
+	pinned := make(map[uint]uint)
+	for _, c := range candidates {
+		for _, headline := range []string{"初版", "修订版"} {
+			if _, err := svc.SubmitResume(ctx, c.userID, resumeInput(headline, "Go")); err != nil {
+				t.Fatalf("SubmitResume: %v", err)
+			}
+		}
+		var first models.ResumeVersion
+		svc.db.Where("user_id = ? AND version = 1", c.userID).First(&first)
+		application := models.Application{UserID: c.userID, JobID: 1, Status: c.status, ResumeVersionID: &first.ID}
+		if err := svc.db.Create(&application).Error; err != nil {
+			t.Fatalf("创建申请失败: %v", err)
+		}
+		svc.db.Model(&application).UpdateColumn("updated_at", c.at)
+		pinned[c.userID] = application.ID
+	}
+
+	purged, err := svc.PurgeRejectedVersions(ctx, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("PurgeRejectedVersions: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("清理了 %d 个版本，期望 1", purged)
+	}
+	for _, c := range candidates {
+		versions, err := svc.ListVersions(ctx, c.userID)
+		if err != nil {
+			t.Fatalf("ListVersions: %v", err)
+		}
+		if len(versions) != c.kept || versions[0].Version != 2 {
+			t.Errorf("用户 %d 保留了 %d 个版本，期望 %d 个且包含最新版本", c.userID, len(versions), c.kept)
+		}
+		var application models.Application
+		svc.db.First(&application, pinned[c.userID])
+		if unlinked := application.ResumeVersionID == nil; unlinked != (c.kept == 1) {
+			t.Errorf("用户 %d 的申请关联的版本为 %v", c.userID, application.ResumeVersionID)
+		}
+	}
+}
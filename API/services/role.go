@@ -2,24 +2,36 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"API/models"
+	"API/storage/cache"
+	"API/utils"
+
 	"gorm.io/gorm"
 )
 
 // RoleService 角色服务
 type RoleService struct {
-	db *gorm.DB
+	db     *gorm.DB
+	cache  cache.Provider
+	tokens *TokenService
 }
 
 // NewRoleService 初始化角色服务
-func NewRoleService(db *gorm.DB) *RoleService {
-	return &RoleService{db: db}
+func NewRoleService(db *gorm.DB, cache cache.Provider, tokens *TokenService) *RoleService {
+	return &RoleService{db: db, cache: cache, tokens: tokens}
 }
 
 // CreateRole 创建角色
 func (s *RoleService) CreateRole(ctx context.Context, role *models.Role) error {
-	return s.db.WithContext(ctx).Create(role).Error
+	if err := s.db.WithContext(ctx).Create(role).Error; err != nil {
+		return err
+	}
+	// 同名角色可能已缓存了空权限
+	invalidateRolePermissions(ctx, s.cache, role.Name)
+	return nil
 }
 
 // GetRoles 获取角色列表
@@ -30,3 +42,242 @@ func (s *RoleService) GetRoles(ctx context.Context) ([]models.Role, error) {
 	}
 	return roles, nil
 }
+
+// GetRoleByID 获取角色详情（含权限）
+func (s *RoleService) GetRoleByID(ctx context.Context, roleID uint) (*models.Role, error) {
+	var role models.Role
+	if err := s.db.WithContext(ctx).Preload("Permissions").First(&role, roleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("角色不存在", "role")
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+// RoleUpdate 修改角色的参数，未传的字段保持不变
+type RoleUpdate struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	RequireMFA  *bool   `json:"require_mfa"`
+}
+
+// UpdateRole 更新角色名称、描述和两步验证策略，只修改请求中传入的字段，系统内置角色不可改名。
+// 令牌中携带角色名称，改名后持有该角色的用户需重新登录；开启强制两步验证后，
+// 该角色下尚未启用的用户需重新登录完成绑定。
+func (s *RoleService) UpdateRole(ctx context.Context, roleID uint, input RoleUpdate) error {
+	role, err := s.GetRoleByID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+
+	// Updates 会把新值写回 role，先记下修改前的名称和策略
+	oldName, mfaRequired := role.Name, role.RequireMFA
+	renamed := false
+	updates := map[string]interface{}{}
+	if input.Name != nil && *input.Name != "" && *input.Name != role.Name {
+		if models.IsSystemRole(role.Name) {
+			return utils.NewValidationError("系统内置角色不可改名", "name")
+		}
+		var count int64
+		if err := s.db.WithContext(ctx).Model(&models.Role{}).Where("name = ?", *input.Name).Count(&count).Error; err != nil {
+			return fmt.Errorf("查询角色失败: %w", err)
+		}
+		if count > 0 {
+			return utils.NewValidationError("角色名称已存在", "name")
+		}
+		updates["name"] = *input.Name
+		renamed = true
+	}
+	if input.Description != nil {
+		updates["description"] = *input.Description
+	}
+	if input.RequireMFA != nil {
+		updates["require_mfa"] = *input.RequireMFA
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	if err := s.db.WithContext(ctx).Model(role).Updates(updates).Error; err != nil {
+		return fmt.Errorf("更新角色失败: %w", err)
+	}
+	if renamed {
+		invalidateRolePermissions(ctx, s.cache, oldName, *input.Name)
+	}
+
+	var userIDs []uint
+	switch {
+	case renamed:
+		if err := s.db.WithContext(ctx).Table("user_roles").Where("role_id = ?", role.ID).
+			Pluck("user_id", &userIDs).Error; err != nil {
+			return fmt.Errorf("查询角色用户失败: %w", err)
+		}
+	case input.RequireMFA != nil && *input.RequireMFA && !mfaRequired:
+		if err := s.db.WithContext(ctx).Table("user_roles").
+			Joins("JOIN users ON users.id = user_roles.user_id").
+			Where("user_roles.role_id = ? AND users.mfa_enabled = ?", role.ID, false).
+			Pluck("user_roles.user_id", &userIDs).Error; err != nil {
+			return fmt.Errorf("查询角色用户失败: %w", err)
+		}
+	}
+	for _, userID := range userIDs {
+		if err := s.tokens.RevokeUserTokens(ctx, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteRole 删除角色并解除其与权限、用户的关联，系统内置角色不可删除
+func (s *RoleService) DeleteRole(ctx context.Context, roleID uint) error {
+	role, err := s.GetRoleByID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	if models.IsSystemRole(role.Name) {
+		return utils.NewValidationError("系统内置角色不可删除", "id")
+	}
+
+	// 记录受影响的用户，删除后需吊销其令牌
+	var userIDs []uint
+	if err := s.db.WithContext(ctx).Table("user_roles").Where("role_id = ?", role.ID).Pluck("user_id", &userIDs).Error; err != nil {
+		return fmt.Errorf("查询角色用户失败: %w", err)
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(role).Association("Permissions").Clear(); err != nil {
+			return fmt.Errorf("解除角色权限失败: %w", err)
+		}
+		if err := tx.Model(role).Association("Users").Clear(); err != nil {
+			return fmt.Errorf("解除用户角色失败: %w", err)
+		}
+		return tx.Delete(role).Error
+	})
+	if err != nil {
+		return err
+	}
+	invalidateRolePermissions(ctx, s.cache, role.Name)
+	for _, userID := range userIDs {
+		if err := s.tokens.RevokeUserTokens(ctx, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GrantPermissions 为角色授予权限
+func (s *RoleService) GrantPermissions(ctx context.Context, roleID uint, permissionIDs []uint) error {
+	role, permissions, err := s.loadRoleAndPermissions(ctx, roleID, permissionIDs)
+	if err != nil {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Model(role).Association("Permissions").Append(permissions); err != nil {
+		return fmt.Errorf("授予权限失败: %w", err)
+	}
+	invalidateRolePermissions(ctx, s.cache, role.Name)
+	return nil
+}
+
+// RevokePermissions 撤销角色的权限
+func (s *RoleService) RevokePermissions(ctx context.Context, roleID uint, permissionIDs []uint) error {
+	role, permissions, err := s.loadRoleAndPermissions(ctx, roleID, permissionIDs)
+	if err != nil {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Model(role).Association("Permissions").Delete(permissions); err != nil {
+		return fmt.Errorf("撤销权限失败: %w", err)
+	}
+	invalidateRolePermissions(ctx, s.cache, role.Name)
+	return nil
+}
+
+// AssignRoles 为用户分配角色
+func (s *RoleService) AssignRoles(ctx context.Context, userID uint, roleIDs []uint) error {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	roles, err := s.findRoles(ctx, roleIDs)
+	if err != nil {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Model(user).Association("Roles").Append(roles); err != nil {
+		return fmt.Errorf("分配角色失败: %w", err)
+	}
+	// 令牌中携带角色，变更后需重新登录
+	return s.tokens.RevokeUserTokens(ctx, userID)
+}
+
+// UnassignRole 移除用户的角色
+func (s *RoleService) UnassignRole(ctx context.Context, userID, roleID uint) error {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	roles, err := s.findRoles(ctx, []uint{roleID})
+	if err != nil {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Model(user).Association("Roles").Delete(roles); err != nil {
+		return fmt.Errorf("移除角色失败: %w", err)
+	}
+	return s.tokens.RevokeUserTokens(ctx, userID)
+}
+
+// loadRoleAndPermissions 加载角色及待授予/撤销的权限
+func (s *RoleService) loadRoleAndPermissions(ctx context.Context, roleID uint, permissionIDs []uint) (*models.Role, []models.Permission, error) {
+	var role models.Role
+	if err := s.db.WithContext(ctx).First(&role, roleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, utils.NewNotFoundError("角色不存在", "role")
+		}
+		return nil, nil, err
+	}
+
+	var permissions []models.Permission
+	if err := s.db.WithContext(ctx).Where("id IN ?", permissionIDs).Find(&permissions).Error; err != nil {
+		return nil, nil, fmt.Errorf("查询权限失败: %w", err)
+	}
+	if len(permissions) != len(uniqueIDs(permissionIDs)) {
+		return nil, nil, utils.NewNotFoundError("部分权限不存在", "permission")
+	}
+	return &role, permissions, nil
+}
+
+// findUser 查询用户
+func (s *RoleService) findUser(ctx context.Context, userID uint) (*models.User, error) {
+	var user models.User
+	if err := s.db.WithContext(ctx).First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("用户不存在", "user")
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// findRoles 按ID批量查询角色，任一不存在即报错
+func (s *RoleService) findRoles(ctx context.Context, roleIDs []uint) ([]models.Role, error) {
+	var roles []models.Role
+	if err := s.db.WithContext(ctx).Where("id IN ?", roleIDs).Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("查询角色失败: %w", err)
+	}
+	if len(roles) != len(uniqueIDs(roleIDs)) {
+		return nil, utils.NewNotFoundError("部分角色不存在", "role")
+	}
+	return roles, nil
+}
+
+// uniqueIDs 对ID去重
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]struct{}, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		result = append(result, id)
+	}
+	return result
+}
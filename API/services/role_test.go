@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"API/models"
+	"API/utils"
+)
+
+// newTestTokenService 创建使用内存缓存和 HS256 测试密钥的令牌服务
+func newTestTokenService(t *testing.T, store *memoryCache) *TokenService {
+	t.Helper()
+	issuer, err := utils.NewTokenIssuer(utils.JWTConfig{
+		Secret:            "test-secret-at-least-32-characters!!",
+		Issuer:            "hrms-test",
+		AccessExpiration:  15 * time.Minute,
+		RefreshExpiration: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewTokenIssuer: %v", err)
+	}
+	return NewTokenService(store, issuer)
+}
+
+func strPtr(v string) *string { return &v }
+
+func boolPtr(v bool) *bool { return &v }
+
+func TestUpdateRole(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T) (*RoleService, *memoryCache, models.Role, []models.User) {
+		store := newMemoryCache()
+		db := newTestDB(t, &models.Role{}, &models.User{})
+		users := []models.User{
+			{Username: "alice", Email: "alice@example.com", Phone: "13800000001", MFAEnabled: true},
+			{Username: "bob", Email: "bob@example.com", Phone: "13800000002"},
+			{Username: "carol", Email: "carol@example.com", Phone: "13800000003"},
+		}
+		if err := db.Create(&users).Error; err != nil {
+			t.Fatalf("创建用户失败: %v", err)
+		}
+		role := models.Role{Name: "recruiter", Description: "招聘专员", Users: users[:2]}
+		if err := db.Create(&role).Error; err != nil {
+			t.Fatalf("创建角色失败: %v", err)
+		}
+		if err := db.Create(&models.Role{Name: "auditor"}).Error; err != nil {
+			t.Fatalf("创建角色失败: %v", err)
+		}
+		return NewRoleService(db, store, newTestTokenService(t, store)), store, role, users
+	}
+	revoked := func(store *memoryCache, users []models.User) []string {
+		var names []string
+		for _, user := range users {
+			if store.has(revokedBeforeKey(user.ID)) {
+				names = append(names, user.Username)
+			}
+		}
+		return names
+	}
+
+	tests := []struct {
+		name        string
+		input       RoleUpdate
+		wantErr     string
+		wantName    string
+		wantDesc    string
+		wantRevoked []string
+	}{
+		{
+			name:     "只改描述",
+			input:    RoleUpdate{Description: strPtr("负责社招")},
+			wantName: "recruiter", wantDesc: "负责社招",
+		},
+		{
+			name:     "改名时未传描述则保留原描述，并吊销持有该角色用户的令牌",
+			input:    RoleUpdate{Name: strPtr("talent")},
+			wantName: "talent", wantDesc: "招聘专员",
+			wantRevoked: []string{"alice", "bob"},
+		},
+		{
+			name:     "名称不变不吊销令牌",
+			input:    RoleUpdate{Name: strPtr("recruiter"), Description: strPtr("")},
+			wantName: "recruiter", wantDesc: "",
+		},
+		{
+			name:     "开启强制两步验证只吊销未启用的用户",
+			input:    RoleUpdate{RequireMFA: boolPtr(true)},
+			wantName: "recruiter", wantDesc: "招聘专员",
+			wantRevoked: []string{"bob"},
+		},
+		{
+			name:    "名称与其他角色重复",
+			input:   RoleUpdate{Name: strPtr("auditor")},
+			wantErr: "角色名称已存在",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, store, role, users := setup(t)
+			err := svc.UpdateRole(ctx, role.ID, tt.input)
+			if tt.wantErr != "" {
+				var validation *utils.ValidationError
+				if !errors.As(err, &validation) || validation.Message != tt.wantErr {
+					t.Fatalf("期望错误 %q，得到 %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateRole: %v", err)
+			}
+			var got models.Role
+			svc.db.First(&got, role.ID)
+			if got.Name != tt.wantName || got.Description != tt.wantDesc {
+				t.Errorf("角色为 %q / %q, 期望 %q / %q", got.Name, got.Description, tt.wantName, tt.wantDesc)
+			}
+			if names := revoked(store, users); !reflect.DeepEqual(names, tt.wantRevoked) {
+				t.Errorf("吊销了 %v 的令牌, 期望 %v", names, tt.wantRevoked)
+			}
+		})
+	}
+
+	svc, _, _, _ := setup(t)
+	admin := models.Role{Name: models.RoleAdmin}
+	if err := svc.db.Create(&admin).Error; err != nil {
+		t.Fatalf("创建角色失败: %v", err)
+	}
+	var validation *utils.ValidationError
+	if err := svc.UpdateRole(ctx, admin.ID, RoleUpdate{Name: strPtr("root")}); !errors.As(err, &validation) {
+		t.Errorf("系统内置角色改名应返回验证错误，得到 %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"API/models"
 
@@ -11,17 +12,21 @@ import (
 )
 
 type SalaryService struct {
-	db *gorm.DB
+	db       *gorm.DB
+	workdays *WorkdayCalculator
+	overtime *OvertimeService
 }
 
 func (s *SalaryService) GetSalaryDetailByMonth(ctx context.Context, userID uint, month string) (*models.Salary, error) {
 	return s.GetSalaryDetails(ctx, userID, month, false)
 }
 
-func NewSalaryService(db *gorm.DB) *SalaryService {
-	return &SalaryService{db: db}
+func NewSalaryService(db *gorm.DB, workdays *WorkdayCalculator, overtime *OvertimeService) *SalaryService {
+	return &SalaryService{db: db, workdays: workdays, overtime: overtime}
 }
 
+// GenerateSalary 生成月度薪资，基本工资按计薪天数占当月工作日的比例折算：
+// 当月入职的从入职日起计薪，已批准的无薪假不计薪。加班工资按月度加班统计的加权时长计算
 func (s *SalaryService) GenerateSalary(ctx context.Context, userID uint, month string) error {
 	var existing models.Salary
 	if err := s.db.WithContext(ctx).Where("user_id = ? AND month = ?", userID, month).First(&existing).Error; err == nil {
@@ -31,12 +36,41 @@ func (s *SalaryService) GenerateSalary(ctx context.Context, userID uint, month s
 	if err := s.db.WithContext(ctx).First(&user, userID).Error; err != nil {
 		return fmt.Errorf("用户不存在: %w", err)
 	}
+	start, err := time.ParseInLocation("2006-01", month, time.Local)
+	if err != nil {
+		return fmt.Errorf("月份格式无效: %w", err)
+	}
+	end := start.AddDate(0, 1, -1)
+
+	db := s.db.WithContext(ctx)
+	workDays, err := s.workdays.CountWorkingDays(db, userID, start, end)
+	if err != nil {
+		return err
+	}
+	paidDays, err := s.paidDays(db, user, start, end)
+	if err != nil {
+		return err
+	}
+	base := user.SalaryBase
+	if workDays > 0 {
+		base = round2(user.SalaryBase * paidDays / float64(workDays))
+	}
+
+	overtime, err := s.overtime.MonthlySummary(ctx, userID, month)
+	if err != nil {
+		return err
+	}
+
 	salary := models.Salary{
-		UserID: userID,
-		Month:  month,
-		Base:   user.SalaryBase,
+		UserID:      userID,
+		Month:       month,
+		Base:        base,
+		WorkDays:    workDays,
+		PaidDays:    paidDays,
+		Overtime:    overtime.TotalHours,
+		OvertimePay: s.overtime.overtimePay(overtime, user.SalaryBase, workDays),
 	}
-	return s.db.WithContext(ctx).Create(&salary).Error
+	return db.Create(&salary).Error
 }
 
 func (s *SalaryService) GetSalaryDetails(ctx context.Context, userID uint, month string, isAdmin bool) (*models.Salary, error) {
@@ -52,6 +86,46 @@ func (s *SalaryService) GetSalaryDetails(ctx context.Context, userID uint, month
 	return &salary, err
 }
 
+// paidDays 统计 [start, end] 期间的计薪天数：入职日起的工作日，扣除已批准无薪假占用的工作日
+func (s *SalaryService) paidDays(db *gorm.DB, user models.User, start, end time.Time) (float64, error) {
+	if user.HireDate != nil && dateOf(*user.HireDate).After(start) {
+		start = dateOf(*user.HireDate)
+	}
+	if start.After(end) {
+		return 0, nil
+	}
+	days, err := s.workdays.CountWorkingDays(db, user.ID, start, end)
+	if err != nil {
+		return 0, err
+	}
+
+	var leaves []models.LeaveRequest
+	if err := db.Joins("JOIN leave_types ON leave_types.id = leave_requests.leave_type_id").
+		Where("leave_requests.user_id = ? AND leave_requests.status = ? AND leave_types.paid = ?", user.ID, models.LeaveApproved, false).
+		Where("leave_requests.start_date <= ? AND leave_requests.end_date >= ?", end, start).
+		Find(&leaves).Error; err != nil {
+		return 0, fmt.Errorf("查询无薪假失败: %w", err)
+	}
+	for _, leave := range leaves {
+		from, to := dateOf(leave.StartDate), dateOf(leave.EndDate)
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		unpaid, err := s.workdays.CountWorkingDays(db, user.ID, from, to)
+		if err != nil {
+			return 0, err
+		}
+		days -= unpaid
+	}
+	if days < 0 {
+		days = 0
+	}
+	return float64(days), nil
+}
+
 // GetSalaryHistory 获取薪资发放记录
 func (s *SalaryService) GetSalaryHistory(ctx context.Context, userID uint) ([]models.Salary, error) {
 	var salaries []models.Salary
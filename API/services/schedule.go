@@ -0,0 +1,535 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"API/models"
+	"API/utils"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ScheduleConfig 未分配班次时使用的默认班次，以及班次结束后仍可下班打卡的时长
+type ScheduleConfig struct {
+	Default        models.WorkSchedule
+	ClockOutWindow time.Duration
+}
+
+// LoadScheduleConfig 从配置文件加载默认班次
+func LoadScheduleConfig() ScheduleConfig {
+	return ScheduleConfig{Default: models.WorkSchedule{
+		Name:            "默认班次",
+		Kind:            models.ScheduleFixed,
+		StartTime:       viper.GetString("attendance.default_schedule.start_time"),
+		EndTime:         viper.GetString("attendance.default_schedule.end_time"),
+		LateGrace:       viper.GetInt("attendance.default_schedule.late_grace"),
+		EarlyLeaveGrace: viper.GetInt("attendance.default_schedule.early_leave_grace"),
+		WorkDays:        viper.GetString("attendance.default_schedule.work_days"),
+	}, ClockOutWindow: viper.GetDuration("attendance.clock_out_window")}
+}
+
+// ScheduleService 班次定义、班次分配及按班次判断迟到早退
+type ScheduleService struct {
+	db     *gorm.DB
+	config ScheduleConfig
+}
+
+func NewScheduleService(db *gorm.DB, config ScheduleConfig) *ScheduleService {
+	return &ScheduleService{db: db, config: config}
+}
+
+// ScheduleInput 创建或修改班次的参数，时间格式为 HH:MM
+type ScheduleInput struct {
+	Name            string  `json:"name" binding:"required,max=50"`
+	Kind            string  `json:"kind" binding:"required,oneof=fixed flexible night"`
+	StartTime       string  `json:"start_time" binding:"required"`                             // 固定班次和夜班为上班时间，弹性班次为核心时间开始
+	EndTime         string  `json:"end_time" binding:"required"`                               // 固定班次和夜班为下班时间（夜班为次日），弹性班次为核心时间结束
+	RequiredHours   float64 `json:"required_hours" binding:"gte=0,lte=24"`                     // 弹性班次每日最低工时
+	LateGrace       int     `json:"late_grace" binding:"gte=0,lte=240"`                        // 迟到宽限（分钟）
+	EarlyLeaveGrace int     `json:"early_leave_grace" binding:"gte=0,lte=240"`                 // 早退宽限（分钟）
+	WorkDays        []int   `json:"work_days" binding:"required,min=1,max=7,dive,gte=0,lte=6"` // 工作日，0为周日
+	Description     string  `json:"description" binding:"max=200"`
+}
+
+// AssignmentInput 分配班次的参数，员工和部门二选一，日期格式为 YYYY-MM-DD
+type AssignmentInput struct {
+	ScheduleID    uint   `json:"schedule_id" binding:"required"`
+	UserID        *uint  `json:"user_id"`
+	Department    string `json:"department" binding:"max=50"`
+	EffectiveFrom string `json:"effective_from" binding:"required"`
+	EffectiveTo   string `json:"effective_to"` // 为空表示长期有效
+}
+
+// ShiftInfo 某日适用的班次及该日的班次时间
+type ShiftInfo struct {
+	Date     time.Time           `json:"date"`
+	Schedule models.WorkSchedule `json:"schedule"`
+	WorkDay  bool                `json:"work_day"`
+	Start    time.Time           `json:"start"` // 上班时间或核心时间开始
+	End      time.Time           `json:"end"`   // 下班时间或核心时间结束，夜班为次日
+}
+
+// ListSchedules 获取全部班次
+func (s *ScheduleService) ListSchedules(ctx context.Context) ([]models.WorkSchedule, error) {
+	var schedules []models.WorkSchedule
+	if err := s.db.WithContext(ctx).Order("name ASC").Find(&schedules).Error; err != nil {
+		return nil, fmt.Errorf("查询班次失败: %w", err)
+	}
+	return schedules, nil
+}
+
+// CreateSchedule 创建班次
+func (s *ScheduleService) CreateSchedule(ctx context.Context, input ScheduleInput) (*models.WorkSchedule, error) {
+	schedule, err := buildSchedule(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkScheduleName(ctx, schedule.Name, 0); err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Create(schedule).Error; err != nil {
+		return nil, fmt.Errorf("创建班次失败: %w", err)
+	}
+	return schedule, nil
+}
+
+// UpdateSchedule 修改班次，已打卡记录的判定结果不随之改变
+func (s *ScheduleService) UpdateSchedule(ctx context.Context, scheduleID uint, input ScheduleInput) (*models.WorkSchedule, error) {
+	existing, err := s.findSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := buildSchedule(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkScheduleName(ctx, schedule.Name, scheduleID); err != nil {
+		return nil, err
+	}
+	schedule.Model = existing.Model
+	if err := s.db.WithContext(ctx).Save(schedule).Error; err != nil {
+		return nil, fmt.Errorf("更新班次失败: %w", err)
+	}
+	return schedule, nil
+}
+
+// DeleteSchedule 删除班次，仍有生效中或未来生效的分配时不能删除
+func (s *ScheduleService) DeleteSchedule(ctx context.Context, scheduleID uint) error {
+	if _, err := s.findSchedule(ctx, scheduleID); err != nil {
+		return err
+	}
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.ScheduleAssignment{}).
+		Where("schedule_id = ? AND (effective_to IS NULL OR effective_to >= ?)", scheduleID, dateOf(time.Now())).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("查询班次分配失败: %w", err)
+	}
+	if count > 0 {
+		return utils.NewValidationError("班次仍有生效中的分配，不能删除", "schedule")
+	}
+	if err := s.db.WithContext(ctx).Delete(&models.WorkSchedule{}, scheduleID).Error; err != nil {
+		return fmt.Errorf("删除班次失败: %w", err)
+	}
+	return nil
+}
+
+// ListAssignments 获取班次分配，可按员工或部门筛选
+func (s *ScheduleService) ListAssignments(ctx context.Context, userID uint, department string) ([]models.ScheduleAssignment, error) {
+	query := s.db.WithContext(ctx).Preload("Schedule")
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if department != "" {
+		query = query.Where("department = ?", department)
+	}
+	var assignments []models.ScheduleAssignment
+	if err := query.Order("effective_from DESC, id DESC").Find(&assignments).Error; err != nil {
+		return nil, fmt.Errorf("查询班次分配失败: %w", err)
+	}
+	return assignments, nil
+}
+
+// Assign 为员工或部门分配班次。同一对象的分配期间不能重叠，
+// 但早于新分配开始的长期分配会自动在新分配生效前一天结束
+func (s *ScheduleService) Assign(ctx context.Context, operatorID uint, input AssignmentInput) (*models.ScheduleAssignment, error) {
+	department := strings.TrimSpace(input.Department)
+	if (input.UserID == nil) == (department == "") {
+		return nil, utils.NewValidationError("员工和部门需指定且只能指定一个", "user_id")
+	}
+	from, err := time.ParseInLocation("2006-01-02", input.EffectiveFrom, time.Local)
+	if err != nil {
+		return nil, utils.NewValidationError("生效日期格式应为 YYYY-MM-DD", "effective_from")
+	}
+	var to *time.Time
+	if input.EffectiveTo != "" {
+		t, err := time.ParseInLocation("2006-01-02", input.EffectiveTo, time.Local)
+		if err != nil {
+			return nil, utils.NewValidationError("失效日期格式应为 YYYY-MM-DD", "effective_to")
+		}
+		if t.Before(from) {
+			return nil, utils.NewValidationError("失效日期不能早于生效日期", "effective_to")
+		}
+		to = &t
+	}
+	if _, err := s.findSchedule(ctx, input.ScheduleID); err != nil {
+		return nil, err
+	}
+
+	assignment := models.ScheduleAssignment{
+		ScheduleID:    input.ScheduleID,
+		UserID:        input.UserID,
+		Department:    department,
+		EffectiveFrom: from,
+		EffectiveTo:   to,
+		CreatedBy:     operatorID,
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if input.UserID != nil {
+			var count int64
+			if err := tx.Model(&models.User{}).Where("id = ?", *input.UserID).Count(&count).Error; err != nil {
+				return fmt.Errorf("查询员工失败: %w", err)
+			}
+			if count == 0 {
+				return utils.NewNotFoundError("员工不存在", "user")
+			}
+		}
+
+		var existing []models.ScheduleAssignment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Scopes(assignmentTarget(input.UserID, department)).
+			Where("effective_to IS NULL OR effective_to >= ?", from).
+			Find(&existing).Error; err != nil {
+			return fmt.Errorf("查询班次分配失败: %w", err)
+		}
+		for _, other := range existing {
+			if to != nil && other.EffectiveFrom.After(*to) {
+				continue
+			}
+			if other.EffectiveTo == nil && other.EffectiveFrom.Before(from) {
+				end := from.AddDate(0, 0, -1)
+				if err := tx.Model(&other).Update("effective_to", end).Error; err != nil {
+					return fmt.Errorf("结束原班次分配失败: %w", err)
+				}
+				continue
+			}
+			return utils.NewValidationError(fmt.Sprintf("与 %s 起生效的班次分配重叠", other.EffectiveFrom.Format("2006-01-02")), "effective_from")
+		}
+		return tx.Create(&assignment).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// DeleteAssignment 删除班次分配
+func (s *ScheduleService) DeleteAssignment(ctx context.Context, assignmentID uint) error {
+	result := s.db.WithContext(ctx).Delete(&models.ScheduleAssignment{}, assignmentID)
+	if result.Error != nil {
+		return fmt.Errorf("删除班次分配失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return utils.NewNotFoundError("班次分配不存在", "schedule_assignment")
+	}
+	return nil
+}
+
+// ShiftFor 获取员工某日适用的班次：个人分配优先，其次为所在部门的分配，均没有时使用默认班次
+func (s *ScheduleService) ShiftFor(ctx context.Context, userID uint, date time.Time) (*ShiftInfo, error) {
+	schedule, err := s.scheduleFor(s.db.WithContext(ctx), userID, date)
+	if err != nil {
+		return nil, err
+	}
+	return newShiftInfo(*schedule, date), nil
+}
+
+// scheduleFor 查找员工某日适用的班次
+func (s *ScheduleService) scheduleFor(tx *gorm.DB, userID uint, date time.Time) (*models.WorkSchedule, error) {
+	resolver, err := s.resolverFor(tx, userID, date, date)
+	if err != nil {
+		return nil, err
+	}
+	return resolver.scheduleOn(date), nil
+}
+
+// scheduleResolver 员工某段期间内生效的班次分配，按日期在内存中确定适用班次
+type scheduleResolver struct {
+	personal   []models.ScheduleAssignment // 个人分配，按生效日期倒序
+	department []models.ScheduleAssignment // 部门分配，按生效日期倒序
+	fallback   models.WorkSchedule         // 未分配班次时使用的默认班次
+}
+
+// resolverFor 一次性加载员工在 [start, end] 期间生效的个人和部门班次分配
+func (s *ScheduleService) resolverFor(tx *gorm.DB, userID uint, start, end time.Time) (*scheduleResolver, error) {
+	var user models.User
+	if err := tx.Select("id", "department").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("用户不存在", "user")
+		}
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+
+	query := tx.Preload("Schedule").
+		Where("effective_from <= ? AND (effective_to IS NULL OR effective_to >= ?)", dateOf(end), dateOf(start)).
+		Order("effective_from DESC")
+	if user.Department != "" {
+		query = query.Where("(user_id = ? OR (user_id IS NULL AND department = ?))", user.ID, user.Department)
+	} else {
+		query = query.Where("user_id = ?", user.ID)
+	}
+	var assignments []models.ScheduleAssignment
+	if err := query.Find(&assignments).Error; err != nil {
+		return nil, fmt.Errorf("查询班次分配失败: %w", err)
+	}
+
+	resolver := &scheduleResolver{fallback: s.config.Default}
+	for _, assignment := range assignments {
+		if assignment.UserID != nil {
+			resolver.personal = append(resolver.personal, assignment)
+		} else {
+			resolver.department = append(resolver.department, assignment)
+		}
+	}
+	return resolver, nil
+}
+
+// scheduleOn 返回某日适用的班次：个人分配优先于部门分配，同类分配取生效日期最晚的一条，
+// 该分配的班次已删除时改用下一类分配
+func (r *scheduleResolver) scheduleOn(date time.Time) *models.WorkSchedule {
+	day := dateOf(date).Format("2006-01-02")
+	for _, assignments := range [][]models.ScheduleAssignment{r.personal, r.department} {
+		for i := range assignments {
+			assignment := &assignments[i]
+			if assignment.EffectiveFrom.Format("2006-01-02") > day ||
+				assignment.EffectiveTo != nil && assignment.EffectiveTo.Format("2006-01-02") < day {
+				continue
+			}
+			if assignment.Schedule.ID != 0 {
+				return &assignment.Schedule
+			}
+			break
+		}
+	}
+	schedule := r.fallback
+	return &schedule
+}
+
+// scheduleByID 获取打卡记录对应的班次，班次已删除时仍可读取，未记录班次时使用默认班次
+func (s *ScheduleService) scheduleByID(tx *gorm.DB, scheduleID *uint) (*models.WorkSchedule, error) {
+	if scheduleID == nil {
+		schedule := s.config.Default
+		return &schedule, nil
+	}
+	var schedule models.WorkSchedule
+	if err := tx.Unscoped().First(&schedule, *scheduleID).Error; err != nil {
+		return nil, fmt.Errorf("查询班次失败: %w", err)
+	}
+	return &schedule, nil
+}
+
+// newShiftInfo 计算班次在某日的起止时间
+func newShiftInfo(schedule models.WorkSchedule, date time.Time) *ShiftInfo {
+	day := dateOf(date)
+	start, _ := parseClock(schedule.StartTime)
+	end, _ := parseClock(schedule.EndTime)
+	info := ShiftInfo{
+		Date:     day,
+		Schedule: schedule,
+		WorkDay:  isWorkDay(schedule, day),
+		Start:    day.Add(start),
+		End:      day.Add(end),
+	}
+	if schedule.Kind == models.ScheduleNight {
+		info.End = info.End.AddDate(0, 0, 1)
+	}
+	return &info
+}
+
+// LateMinutes 按班次计算上班打卡的迟到分钟数，未超过宽限时为0
+func (info *ShiftInfo) LateMinutes(clockIn time.Time) int {
+	if !info.WorkDay {
+		return 0
+	}
+	minutes := int(clockIn.Sub(info.Start).Minutes())
+	if minutes <= info.Schedule.LateGrace {
+		return 0
+	}
+	return minutes
+}
+
+// EarlyLeaveMinutes 按班次计算下班打卡的早退分钟数，未超过宽限时为0。
+// 弹性班次同时要求覆盖核心时间和满足每日最低工时，取两者中较大的差额
+func (info *ShiftInfo) EarlyLeaveMinutes(clockIn, clockOut time.Time) int {
+	if !info.WorkDay {
+		return 0
+	}
+	minutes := int(info.End.Sub(clockOut).Minutes())
+	if info.Schedule.Kind == models.ScheduleFlexible && info.Schedule.RequiredHours > 0 {
+		shortfall := int(info.Schedule.RequiredHours*60 - clockOut.Sub(clockIn).Minutes())
+		if shortfall > minutes {
+			minutes = shortfall
+		}
+	}
+	if minutes <= info.Schedule.EarlyLeaveGrace {
+		return 0
+	}
+	return minutes
+}
+
+// clockOutDeadline 班次的最晚下班打卡时间，超过后只能通过补卡申请补录
+func (s *ScheduleService) clockOutDeadline(shift *ShiftInfo) time.Time {
+	return shift.End.Add(s.config.ClockOutWindow)
+}
+
+// FlagMissingClockOuts 标记已过最晚下班打卡时间仍未下班打卡的记录，返回新标记的数量
+func (s *ScheduleService) FlagMissingClockOuts(ctx context.Context) (int, error) {
+	db := s.db.WithContext(ctx)
+	now := time.Now()
+	today := dateOf(now)
+	// 已标记的记录不再检查，任务停运多日后恢复时仍能补标之前遗漏的记录
+	var open []models.Attendance
+	if err := db.Where("clock_out IS NULL AND clock_out_missing = ? AND date <= ?", false, today).
+		Find(&open).Error; err != nil {
+		return 0, fmt.Errorf("查询未下班打卡记录失败: %w", err)
+	}
+
+	var ids []uint
+	schedules := make(map[uint]*models.WorkSchedule) // 按班次ID缓存，0 表示默认班次
+	for _, attendance := range open {
+		var key uint
+		if attendance.ScheduleID != nil {
+			key = *attendance.ScheduleID
+		}
+		schedule, ok := schedules[key]
+		if !ok {
+			var err error
+			if schedule, err = s.scheduleByID(db, attendance.ScheduleID); err != nil {
+				return 0, err
+			}
+			schedules[key] = schedule
+		}
+		if now.After(s.clockOutDeadline(newShiftInfo(*schedule, attendance.Date))) {
+			ids = append(ids, attendance.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if err := db.Model(&models.Attendance{}).Where("id IN ?", ids).Update("clock_out_missing", true).Error; err != nil {
+		return 0, fmt.Errorf("标记未下班打卡记录失败: %w", err)
+	}
+	return len(ids), nil
+}
+
+// buildSchedule 校验班次参数
+func buildSchedule(input ScheduleInput) (*models.WorkSchedule, error) {
+	start, err := parseClock(input.StartTime)
+	if err != nil {
+		return nil, utils.NewValidationError(err.Error(), "start_time")
+	}
+	end, err := parseClock(input.EndTime)
+	if err != nil {
+		return nil, utils.NewValidationError(err.Error(), "end_time")
+	}
+	switch input.Kind {
+	case models.ScheduleNight:
+		if end >= start {
+			return nil, utils.NewValidationError("夜班的下班时间应在次日，需早于上班时间", "end_time")
+		}
+	default:
+		if end <= start {
+			return nil, utils.NewValidationError("结束时间必须晚于开始时间", "end_time")
+		}
+	}
+	if input.Kind == models.ScheduleFlexible && input.RequiredHours == 0 {
+		return nil, utils.NewValidationError("弹性班次需设置每日最低工时", "required_hours")
+	}
+
+	seen := map[int]bool{}
+	days := make([]string, 0, len(input.WorkDays))
+	for _, day := range input.WorkDays {
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, strconv.Itoa(day))
+		}
+	}
+	return &models.WorkSchedule{
+		Name:            strings.TrimSpace(input.Name),
+		Kind:            input.Kind,
+		StartTime:       input.StartTime,
+		EndTime:         input.EndTime,
+		RequiredHours:   input.RequiredHours,
+		LateGrace:       input.LateGrace,
+		EarlyLeaveGrace: input.EarlyLeaveGrace,
+		WorkDays:        strings.Join(days, ","),
+		Description:     input.Description,
+	}, nil
+}
+
+func (s *ScheduleService) findSchedule(ctx context.Context, scheduleID uint) (*models.WorkSchedule, error) {
+	var schedule models.WorkSchedule
+	if err := s.db.WithContext(ctx).First(&schedule, scheduleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("班次不存在", "schedule")
+		}
+		return nil, fmt.Errorf("查询班次失败: %w", err)
+	}
+	return &schedule, nil
+}
+
+// checkScheduleName 校验班次名称未被其他班次使用
+func (s *ScheduleService) checkScheduleName(ctx context.Context, name string, excludeID uint) error {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.WorkSchedule{}).
+		Where("name = ? AND id <> ?", name, excludeID).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("查询班次失败: %w", err)
+	}
+	if count > 0 {
+		return utils.NewValidationError("班次名称已存在", "name")
+	}
+	return nil
+}
+
+// assignmentTarget 按分配对象筛选班次分配
+func assignmentTarget(userID *uint, department string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if userID != nil {
+			return db.Where("user_id = ?", *userID)
+		}
+		return db.Where("user_id IS NULL AND department = ?", department)
+	}
+}
+
+// parseClock 解析 HH:MM 格式的时刻，返回距零点的时长
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("时间格式应为 HH:MM: %s", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// isWorkDay 判断某日是否为班次的工作日
+func isWorkDay(schedule models.WorkSchedule, date time.Time) bool {
+	weekday := strconv.Itoa(int(date.Weekday()))
+	for _, day := range strings.Split(schedule.WorkDays, ",") {
+		if strings.TrimSpace(day) == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// dateOf 返回当地时间所在日期的零点
+func dateOf(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"API/models"
+)
+
+// at 返回当地时间，2025-03-03 为周一
+func at(day, hour, minute int) time.Time {
+	return time.Date(2025, 3, day, hour, minute, 0, 0, time.Local)
+}
+
+var (
+	fixedSchedule = models.WorkSchedule{
+		Kind: models.ScheduleFixed, StartTime: "09:00", EndTime: "18:00",
+		LateGrace: 5, WorkDays: "1,2,3,4,5",
+	}
+	nightSchedule = models.WorkSchedule{
+		Kind: models.ScheduleNight, StartTime: "22:00", EndTime: "06:00",
+		WorkDays: "1,2,3,4,5",
+	}
+	flexibleSchedule = models.WorkSchedule{
+		Kind: models.ScheduleFlexible, StartTime: "10:00", EndTime: "16:00",
+		RequiredHours: 8, EarlyLeaveGrace: 10, WorkDays: "1,2,3,4,5",
+	}
+)
+
+func TestNewShiftInfo(t *testing.T) {
+	tests := []struct {
+		name       string
+		schedule   models.WorkSchedule
+		date       time.Time
+		start, end time.Time
+		workDay    bool
+	}{
+		{"固定班次", fixedSchedule, at(3, 14, 30), at(3, 9, 0), at(3, 18, 0), true},
+		{"休息日", fixedSchedule, at(8, 0, 0), at(8, 9, 0), at(8, 18, 0), false},
+		{"夜班在次日下班", nightSchedule, at(3, 0, 0), at(3, 22, 0), at(4, 6, 0), true},
+		{"周五夜班跨到周六", nightSchedule, at(7, 23, 0), at(7, 22, 0), at(8, 6, 0), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := newShiftInfo(tt.schedule, tt.date)
+			if !info.Date.Equal(dateOf(tt.date)) {
+				t.Errorf("Date = %s, 期望 %s", info.Date, dateOf(tt.date))
+			}
+			if !info.Start.Equal(tt.start) || !info.End.Equal(tt.end) {
+				t.Errorf("班次时间为 %s - %s, 期望 %s - %s", info.Start, info.End, tt.start, tt.end)
+			}
+			if info.WorkDay != tt.workDay {
+				t.Errorf("WorkDay = %v, 期望 %v", info.WorkDay, tt.workDay)
+			}
+		})
+	}
+}
+
+func TestLateMinutes(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule models.WorkSchedule
+		date     time.Time
+		clockIn  time.Time
+		want     int
+	}{
+		{"提前到达", fixedSchedule, at(3, 0, 0), at(3, 8, 50), 0},
+		{"宽限内", fixedSchedule, at(3, 0, 0), at(3, 9, 5), 0},
+		{"超过宽限按实际分钟数计", fixedSchedule, at(3, 0, 0), at(3, 9, 6), 6},
+		{"休息日不计迟到", fixedSchedule, at(8, 0, 0), at(8, 11, 0), 0},
+		{"夜班当晚迟到", nightSchedule, at(3, 0, 0), at(3, 22, 30), 30},
+		{"夜班过零点才到", nightSchedule, at(3, 0, 0), at(4, 0, 10), 130},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newShiftInfo(tt.schedule, tt.date).LateMinutes(tt.clockIn); got != tt.want {
+				t.Errorf("LateMinutes = %d, 期望 %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEarlyLeaveMinutes(t *testing.T) {
+	tests := []struct {
+		name              string
+		schedule          models.WorkSchedule
+		date              time.Time
+		clockIn, clockOut time.Time
+		want              int
+	}{
+		{"固定班次早退", fixedSchedule, at(3, 0, 0), at(3, 9, 0), at(3, 17, 30), 30},
+		{"固定班次按时下班", fixedSchedule, at(3, 0, 0), at(3, 9, 0), at(3, 18, 10), 0},
+		{"休息日不计早退", fixedSchedule, at(8, 0, 0), at(8, 9, 0), at(8, 12, 0), 0},
+		{"夜班次日早退", nightSchedule, at(3, 0, 0), at(3, 22, 0), at(4, 5, 0), 60},
+		{"夜班次日按时下班", nightSchedule, at(3, 0, 0), at(3, 22, 0), at(4, 6, 30), 0},
+		{"夜班零点前离开", nightSchedule, at(3, 0, 0), at(3, 22, 0), at(3, 23, 59), 361},
+		{"弹性班次覆盖核心时间但工时不足", flexibleSchedule, at(3, 0, 0), at(3, 9, 0), at(3, 16, 30), 30},
+		{"弹性班次取核心时间和工时差额中较大者", flexibleSchedule, at(3, 0, 0), at(3, 10, 0), at(3, 15, 0), 180},
+		{"弹性班次差额在宽限内", flexibleSchedule, at(3, 0, 0), at(3, 8, 0), at(3, 15, 50), 0},
+		{"弹性班次工时满足", flexibleSchedule, at(3, 0, 0), at(3, 9, 0), at(3, 17, 0), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newShiftInfo(tt.schedule, tt.date).EarlyLeaveMinutes(tt.clockIn, tt.clockOut); got != tt.want {
+				t.Errorf("EarlyLeaveMinutes = %d, 期望 %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlagMissingClockOuts(t *testing.T) {
+	db := newTestDB(t, &models.Attendance{}, &models.WorkSchedule{})
+	// 下班后 48 小时内仍可打卡，今天和昨天的记录尚未过最晚下班打卡时间
+	svc := NewScheduleService(db, ScheduleConfig{Default: fixedSchedule, ClockOutWindow: 48 * time.Hour})
+	today := dateOf(time.Now())
+	daysAgo := func(n int) time.Time { return today.AddDate(0, 0, -n) }
+	records := map[string]*models.Attendance{
+		"任务停运期间遗漏": {UserID: 1, Date: daysAgo(10), ClockIn: daysAgo(10).Add(9 * time.Hour)},
+		"已过期限":     {UserID: 2, Date: daysAgo(3), ClockIn: daysAgo(3).Add(9 * time.Hour)},
+		"已标记":      {UserID: 3, Date: daysAgo(5), ClockIn: daysAgo(5).Add(9 * time.Hour), ClockOutMissing: true},
+		"已下班打卡":    {UserID: 4, Date: daysAgo(4), ClockIn: daysAgo(4).Add(9 * time.Hour), ClockOut: timePtr(daysAgo(4).Add(18 * time.Hour))},
+		"昨天未到期限":   {UserID: 5, Date: daysAgo(1), ClockIn: daysAgo(1).Add(9 * time.Hour)},
+		"今天未到期限":   {UserID: 6, Date: today, ClockIn: today.Add(9 * time.Hour)},
+	}
+	for _, record := range records {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("创建打卡记录失败: %v", err)
+		}
+	}
+
+	flagged, err := svc.FlagMissingClockOuts(context.Background())
+	if err != nil {
+		t.Fatalf("FlagMissingClockOuts: %v", err)
+	}
+	if flagged != 2 {
+		t.Errorf("标记了 %d 条记录，期望 2 条", flagged)
+	}
+	want := map[string]bool{"任务停运期间遗漏": true, "已过期限": true, "已标记": true}
+	for name, record := range records {
+		var got models.Attendance
+		if err := db.First(&got, record.ID).Error; err != nil {
+			t.Fatalf("查询打卡记录失败: %v", err)
+		}
+		if got.ClockOutMissing != want[name] {
+			t.Errorf("%s: 未下班打卡标记为 %v", name, got.ClockOutMissing)
+		}
+	}
+}
@@ -0,0 +1,575 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"API/models"
+	"API/resumeparser"
+	"API/utils"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TalentConfig 人才库配置
+type TalentConfig struct {
+	AllowFreeFormTags bool // 是否允许打标签时自动创建受控标签之外的自由标签
+}
+
+// LoadTalentConfig 从配置文件加载人才库配置
+func LoadTalentConfig() TalentConfig {
+	return TalentConfig{AllowFreeFormTags: viper.GetBool("talent.allow_free_form_tags")}
+}
+
+// TalentService 人才库、候选人标签与备注
+type TalentService struct {
+	db     *gorm.DB
+	config TalentConfig
+}
+
+func NewTalentService(db *gorm.DB, config TalentConfig) *TalentService {
+	return &TalentService{db: db, config: config}
+}
+
+// TalentPoolInput 创建或修改人才库的参数
+type TalentPoolInput struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
+}
+
+// TalentTagInput 定义受控标签的参数
+type TalentTagInput struct {
+	Name        string `json:"name" binding:"required,max=50"`
+	Description string `json:"description" binding:"max=200"`
+}
+
+// CandidateFilter 人才搜索条件，标签和技能需全部匹配
+type CandidateFilter struct {
+	PoolID       uint       `form:"pool_id"`
+	Tags         []string   `form:"tag" binding:"max=20"`
+	Skills       []string   `form:"skill" binding:"max=20"`
+	Status       string     `form:"status" binding:"omitempty,oneof=pending interviewed hired rejected withdrawn"` // 最近一次申请的状态
+	ActiveAfter  *time.Time `form:"active_after" time_format:"2006-01-02"`                                         // 最近活动不早于该日期
+	ActiveBefore *time.Time `form:"active_before" time_format:"2006-01-02"`                                        // 最近活动早于该日期，用于筛选久未联系的候选人
+	ExcludeJobID uint       `form:"exclude_job_id"`                                                                // 排除已申请该职位的候选人，便于为新职位重新联系
+	Keyword      string     `form:"q" binding:"max=100"`                                                           // 匹配用户名、邮箱或求职意向
+}
+
+// CandidateSummary 人才搜索结果中的候选人概要
+type CandidateSummary struct {
+	UserID           uint               `json:"user_id"`
+	Username         string             `json:"username"`
+	Email            string             `json:"email"`
+	Phone            string             `json:"phone"`
+	Headline         string             `json:"headline"`
+	Location         string             `json:"location"`
+	Skills           []string           `json:"skills"`
+	Tags             []models.TalentTag `json:"tags"`
+	Pools            []string           `json:"pools"`
+	ApplicationCount int64              `json:"application_count"`
+	LastStatus       string             `json:"last_status"` // 最近一次申请的状态
+	LastActivity     time.Time          `json:"last_activity"`
+}
+
+// CreatePool 创建人才库
+func (s *TalentService) CreatePool(ctx context.Context, operatorID uint, input TalentPoolInput) (*models.TalentPool, error) {
+	name := strings.TrimSpace(input.Name)
+	if err := s.checkPoolName(ctx, name, 0); err != nil {
+		return nil, err
+	}
+	pool := models.TalentPool{Name: name, Description: input.Description, CreatedBy: operatorID}
+	if err := s.db.WithContext(ctx).Create(&pool).Error; err != nil {
+		return nil, fmt.Errorf("创建人才库失败: %w", err)
+	}
+	return &pool, nil
+}
+
+// UpdatePool 修改人才库名称和说明
+func (s *TalentService) UpdatePool(ctx context.Context, poolID uint, input TalentPoolInput) (*models.TalentPool, error) {
+	pool, err := s.findPool(ctx, poolID)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(input.Name)
+	if err := s.checkPoolName(ctx, name, poolID); err != nil {
+		return nil, err
+	}
+	pool.Name, pool.Description = name, input.Description
+	if err := s.db.WithContext(ctx).Model(pool).Updates(map[string]interface{}{
+		"name":        pool.Name,
+		"description": pool.Description,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("更新人才库失败: %w", err)
+	}
+	return pool, nil
+}
+
+// DeletePool 删除人才库及其候选人名单，候选人本身的标签和备注不受影响
+func (s *TalentService) DeletePool(ctx context.Context, poolID uint) error {
+	if _, err := s.findPool(ctx, poolID); err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("pool_id = ?", poolID).Delete(&models.TalentPoolMember{}).Error; err != nil {
+			return fmt.Errorf("删除人才库成员失败: %w", err)
+		}
+		// 物理删除以便重新使用名称
+		if err := tx.Unscoped().Delete(&models.TalentPool{}, poolID).Error; err != nil {
+			return fmt.Errorf("删除人才库失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListPools 获取全部人才库及各库的候选人数量
+func (s *TalentService) ListPools(ctx context.Context) ([]models.TalentPool, error) {
+	var pools []models.TalentPool
+	if err := s.db.WithContext(ctx).Order("name ASC").Find(&pools).Error; err != nil {
+		return nil, fmt.Errorf("查询人才库失败: %w", err)
+	}
+	var counts []struct {
+		PoolID uint
+		Total  int64
+	}
+	if err := s.db.WithContext(ctx).Model(&models.TalentPoolMember{}).
+		Select("pool_id, COUNT(*) AS total").
+		Group("pool_id").
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("统计人才库成员失败: %w", err)
+	}
+	totals := make(map[uint]int64, len(counts))
+	for _, c := range counts {
+		totals[c.PoolID] = c.Total
+	}
+	for i := range pools {
+		pools[i].MemberCount = totals[pools[i].ID]
+	}
+	return pools, nil
+}
+
+// AddPoolMembers 将候选人加入人才库，已在库中的候选人忽略
+func (s *TalentService) AddPoolMembers(ctx context.Context, poolID, operatorID uint, userIDs []uint) error {
+	if _, err := s.findPool(ctx, poolID); err != nil {
+		return err
+	}
+	if err := s.checkCandidates(ctx, userIDs...); err != nil {
+		return err
+	}
+	members := make([]models.TalentPoolMember, 0, len(userIDs))
+	for _, userID := range userIDs {
+		members = append(members, models.TalentPoolMember{PoolID: poolID, UserID: userID, AddedBy: operatorID})
+	}
+	if err := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&members).Error; err != nil {
+		return fmt.Errorf("加入人才库失败: %w", err)
+	}
+	return nil
+}
+
+// RemovePoolMember 将候选人移出人才库
+func (s *TalentService) RemovePoolMember(ctx context.Context, poolID, userID uint) error {
+	result := s.db.WithContext(ctx).Where("pool_id = ? AND user_id = ?", poolID, userID).Delete(&models.TalentPoolMember{})
+	if result.Error != nil {
+		return fmt.Errorf("移出人才库失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return utils.NewNotFoundError("候选人不在该人才库中", "talent_pool_member")
+	}
+	return nil
+}
+
+// ListTags 获取全部标签，受控标签在前
+func (s *TalentService) ListTags(ctx context.Context) ([]models.TalentTag, error) {
+	var tags []models.TalentTag
+	if err := s.db.WithContext(ctx).Order("controlled DESC, name ASC").Find(&tags).Error; err != nil {
+		return nil, fmt.Errorf("查询标签失败: %w", err)
+	}
+	return tags, nil
+}
+
+// CreateTag 定义受控标签，同名的自由标签转为受控标签
+func (s *TalentService) CreateTag(ctx context.Context, operatorID uint, input TalentTagInput) (*models.TalentTag, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, utils.NewValidationError("标签名称不能为空", "name")
+	}
+	var tag models.TalentTag
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("name = ?", name).First(&tag).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("查询标签失败: %w", err)
+		}
+		if tag.Controlled {
+			return utils.NewValidationError("标签已存在", "name")
+		}
+		if tag.ID == 0 {
+			tag = models.TalentTag{Name: name, CreatedBy: operatorID}
+		}
+		tag.Description = input.Description
+		tag.Controlled = true
+		return tx.Save(&tag).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// DeleteTag 删除标签及其在候选人上的使用
+func (s *TalentService) DeleteTag(ctx context.Context, tagID uint) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", tagID).Delete(&models.CandidateTag{}).Error; err != nil {
+			return fmt.Errorf("删除候选人标签失败: %w", err)
+		}
+		result := tx.Delete(&models.TalentTag{}, tagID)
+		if result.Error != nil {
+			return fmt.Errorf("删除标签失败: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return utils.NewNotFoundError("标签不存在", "tag")
+		}
+		return nil
+	})
+}
+
+// TagCandidate 为候选人打标签，返回候选人的全部标签。标签按名称匹配，
+// 不存在时在允许自由标签的情况下自动创建，否则只能使用受控标签
+func (s *TalentService) TagCandidate(ctx context.Context, userID, operatorID uint, names []string) ([]models.TalentTag, error) {
+	if err := s.checkCandidates(ctx, userID); err != nil {
+		return nil, err
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, name := range names {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			var tag models.TalentTag
+			err := tx.Where("name = ?", name).First(&tag).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("查询标签失败: %w", err)
+			}
+			if tag.ID == 0 {
+				if !s.config.AllowFreeFormTags {
+					return utils.NewValidationError(fmt.Sprintf("标签 %s 不在受控标签中", name), "tags")
+				}
+				tag = models.TalentTag{Name: name, CreatedBy: operatorID}
+				if err := tx.Where(models.TalentTag{Name: name}).FirstOrCreate(&tag).Error; err != nil {
+					return fmt.Errorf("创建标签失败: %w", err)
+				}
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.CandidateTag{
+				UserID:   userID,
+				TagID:    tag.ID,
+				TaggedBy: operatorID,
+			}).Error; err != nil {
+				return fmt.Errorf("保存候选人标签失败: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.candidateTags(ctx, userID)
+}
+
+// UntagCandidate 移除候选人的标签
+func (s *TalentService) UntagCandidate(ctx context.Context, userID, tagID uint) error {
+	result := s.db.WithContext(ctx).Where("user_id = ? AND tag_id = ?", userID, tagID).Delete(&models.CandidateTag{})
+	if result.Error != nil {
+		return fmt.Errorf("移除候选人标签失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return utils.NewNotFoundError("候选人没有该标签", "tag")
+	}
+	return nil
+}
+
+// AddNote 为候选人添加备注
+func (s *TalentService) AddNote(ctx context.Context, userID, authorID uint, content string) (*models.CandidateNote, error) {
+	if err := s.checkCandidates(ctx, userID); err != nil {
+		return nil, err
+	}
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, utils.NewValidationError("备注内容不能为空", "content")
+	}
+	note := models.CandidateNote{UserID: userID, AuthorID: authorID, Content: content}
+	if err := s.db.WithContext(ctx).Create(&note).Error; err != nil {
+		return nil, fmt.Errorf("保存备注失败: %w", err)
+	}
+	if err := s.db.WithContext(ctx).First(&note.Author, authorID).Error; err != nil {
+		return nil, fmt.Errorf("查询备注作者失败: %w", err)
+	}
+	return &note, nil
+}
+
+// ListNotes 获取候选人的备注，新备注在前
+func (s *TalentService) ListNotes(ctx context.Context, userID uint) ([]models.CandidateNote, error) {
+	var notes []models.CandidateNote
+	if err := s.db.WithContext(ctx).
+		Preload("Author").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&notes).Error; err != nil {
+		return nil, fmt.Errorf("查询备注失败: %w", err)
+	}
+	return notes, nil
+}
+
+// DeleteNote 删除备注，仅作者本人可删除
+func (s *TalentService) DeleteNote(ctx context.Context, noteID, operatorID uint) error {
+	var note models.CandidateNote
+	if err := s.db.WithContext(ctx).First(&note, noteID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.NewNotFoundError("备注不存在", "note")
+		}
+		return fmt.Errorf("查询备注失败: %w", err)
+	}
+	if note.AuthorID != operatorID {
+		return utils.NewForbiddenError("只能删除自己的备注")
+	}
+	if err := s.db.WithContext(ctx).Delete(&note).Error; err != nil {
+		return fmt.Errorf("删除备注失败: %w", err)
+	}
+	return nil
+}
+
+// candidateActivityExpr 候选人最近活动时间：最近一次申请变动和简历更新中的较晚者，均没有时为注册时间。
+// SQLite 没有 GREATEST，多参数的 MAX 即取较大值
+func candidateActivityExpr(db *gorm.DB) string {
+	greatest := "GREATEST"
+	if db.Dialector.Name() == "sqlite" {
+		greatest = "MAX"
+	}
+	return greatest + `(
+	COALESCE((SELECT MAX(a.updated_at) FROM applications a WHERE a.user_id = users.id AND a.deleted_at IS NULL), users.created_at),
+	COALESCE((SELECT r.updated_at FROM resumes r WHERE r.user_id = users.id AND r.deleted_at IS NULL), users.created_at))`
+}
+
+// SearchCandidates 在投递过职位、提交过简历或加入人才库的候选人中搜索，按最近活动倒序
+func (s *TalentService) SearchCandidates(ctx context.Context, filter CandidateFilter, page, size int) ([]CandidateSummary, int64, error) {
+	db := s.db.WithContext(ctx)
+	inner := db.Table("users").
+		Select("users.id, users.username, users.email, users.phone, users.created_at, " + candidateActivityExpr(db) + " AS last_activity").
+		Where("users.deleted_at IS NULL").
+		Where("(users.id IN (SELECT user_id FROM applications WHERE deleted_at IS NULL)" +
+			" OR users.id IN (SELECT user_id FROM resumes WHERE deleted_at IS NULL)" +
+			" OR users.id IN (SELECT user_id FROM talent_pool_members))")
+
+	if filter.PoolID != 0 {
+		inner = inner.Where("users.id IN (SELECT user_id FROM talent_pool_members WHERE pool_id = ?)", filter.PoolID)
+	}
+	if tags := uniqueStrings(filter.Tags, strings.TrimSpace); len(tags) > 0 {
+		inner = inner.Where(`users.id IN (SELECT ct.user_id FROM candidate_tags ct JOIN talent_tags t ON t.id = ct.tag_id
+			WHERE t.name IN ? GROUP BY ct.user_id HAVING COUNT(DISTINCT t.id) = ?)`, tags, len(tags))
+	}
+	if skills := uniqueStrings(filter.Skills, func(name string) string {
+		key, _ := resumeparser.NormalizeSkill(name)
+		return key
+	}); len(skills) > 0 {
+		inner = inner.Where("users.id IN (SELECT r.user_id FROM resumes r JOIN resume_skills rs ON rs.resume_id = r.id JOIN skills s ON s.id = rs.skill_id"+
+			" WHERE r.deleted_at IS NULL AND s.`key` IN ? GROUP BY r.user_id HAVING COUNT(DISTINCT s.id) = ?)", skills, len(skills))
+	}
+	if filter.Status != "" {
+		inner = inner.Where(`users.id IN (SELECT a.user_id FROM applications a WHERE a.deleted_at IS NULL AND a.status = ?
+			AND a.updated_at = (SELECT MAX(b.updated_at) FROM applications b WHERE b.user_id = a.user_id AND b.deleted_at IS NULL))`, filter.Status)
+	}
+	if filter.ExcludeJobID != 0 {
+		inner = inner.Where("users.id NOT IN (SELECT user_id FROM applications WHERE job_id = ? AND deleted_at IS NULL)", filter.ExcludeJobID)
+	}
+	if keyword := strings.TrimSpace(filter.Keyword); keyword != "" {
+		like := "%" + escapeLike(keyword) + "%"
+		inner = inner.Where(fmt.Sprintf("(users.username LIKE ? %[1]s OR users.email LIKE ? %[1]s"+
+			" OR users.id IN (SELECT user_id FROM resumes WHERE deleted_at IS NULL AND headline LIKE ? %[1]s))", likeEscape(db)), like, like, like)
+	}
+
+	query := db.Table("(?) AS candidates", inner)
+	if filter.ActiveAfter != nil {
+		query = query.Where("last_activity >= ?", *filter.ActiveAfter)
+	}
+	if filter.ActiveBefore != nil {
+		query = query.Where("last_activity < ?", *filter.ActiveBefore)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计候选人失败: %w", err)
+	}
+	// 最近活动时间由 fillCandidateDetails 根据简历和申请计算，不读取表达式结果
+	var rows []struct {
+		ID        uint
+		Username  string
+		Email     string
+		Phone     string
+		CreatedAt time.Time
+	}
+	if err := query.
+		Select("id, username, email, phone, created_at").
+		Order("last_activity DESC, id DESC").
+		Offset((page - 1) * size).
+		Limit(size).
+		Scan(&rows).Error; err != nil {
+		return nil, 0, fmt.Errorf("搜索候选人失败: %w", err)
+	}
+
+	candidates := make([]CandidateSummary, 0, len(rows))
+	index := make(map[uint]*CandidateSummary, len(rows))
+	userIDs := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		candidates = append(candidates, CandidateSummary{
+			UserID:       row.ID,
+			Username:     row.Username,
+			Email:        row.Email,
+			Phone:        row.Phone,
+			Skills:       []string{},
+			Tags:         []models.TalentTag{},
+			Pools:        []string{},
+			LastActivity: row.CreatedAt,
+		})
+		userIDs = append(userIDs, row.ID)
+	}
+	for i := range candidates {
+		index[candidates[i].UserID] = &candidates[i]
+	}
+	if len(userIDs) == 0 {
+		return candidates, total, nil
+	}
+	if err := s.fillCandidateDetails(ctx, userIDs, index); err != nil {
+		return nil, 0, err
+	}
+	return candidates, total, nil
+}
+
+// fillCandidateDetails 补充候选人的简历概要、标签、所在人才库和申请情况，
+// 最近活动时间取简历更新和最近一次申请变动中晚于注册时间者，与 candidateActivityExpr 一致
+func (s *TalentService) fillCandidateDetails(ctx context.Context, userIDs []uint, index map[uint]*CandidateSummary) error {
+	db := s.db.WithContext(ctx)
+
+	var resumes []models.Resume
+	if err := db.Preload("Skills", func(db *gorm.DB) *gorm.DB { return db.Order("skills.name ASC") }).
+		Where("user_id IN ?", userIDs).
+		Find(&resumes).Error; err != nil {
+		return fmt.Errorf("查询简历失败: %w", err)
+	}
+	for _, resume := range resumes {
+		c := index[resume.UserID]
+		c.Headline, c.Location = resume.Headline, resume.Location
+		if resume.UpdatedAt.After(c.LastActivity) {
+			c.LastActivity = resume.UpdatedAt
+		}
+		for _, skill := range resume.Skills {
+			c.Skills = append(c.Skills, skill.Name)
+		}
+	}
+
+	var tags []models.CandidateTag
+	if err := db.Preload("Tag").Where("user_id IN ?", userIDs).Order("id ASC").Find(&tags).Error; err != nil {
+		return fmt.Errorf("查询候选人标签失败: %w", err)
+	}
+	for _, tag := range tags {
+		index[tag.UserID].Tags = append(index[tag.UserID].Tags, tag.Tag)
+	}
+
+	var members []models.TalentPoolMember
+	if err := db.Preload("Pool").Where("user_id IN ?", userIDs).Order("id ASC").Find(&members).Error; err != nil {
+		return fmt.Errorf("查询人才库成员失败: %w", err)
+	}
+	for _, member := range members {
+		index[member.UserID].Pools = append(index[member.UserID].Pools, member.Pool.Name)
+	}
+
+	var applications []models.Application
+	if err := db.Select("user_id", "status", "updated_at").
+		Where("user_id IN ?", userIDs).
+		Order("updated_at ASC").
+		Find(&applications).Error; err != nil {
+		return fmt.Errorf("查询申请失败: %w", err)
+	}
+	for _, application := range applications {
+		c := index[application.UserID]
+		c.ApplicationCount++
+		c.LastStatus = application.Status
+		if application.UpdatedAt.After(c.LastActivity) {
+			c.LastActivity = application.UpdatedAt
+		}
+	}
+	return nil
+}
+
+// candidateTags 获取候选人的全部标签
+func (s *TalentService) candidateTags(ctx context.Context, userID uint) ([]models.TalentTag, error) {
+	tags := []models.TalentTag{}
+	if err := s.db.WithContext(ctx).
+		Joins("JOIN candidate_tags ON candidate_tags.tag_id = talent_tags.id").
+		Where("candidate_tags.user_id = ?", userID).
+		Order("talent_tags.name ASC").
+		Find(&tags).Error; err != nil {
+		return nil, fmt.Errorf("查询候选人标签失败: %w", err)
+	}
+	return tags, nil
+}
+
+func (s *TalentService) findPool(ctx context.Context, poolID uint) (*models.TalentPool, error) {
+	var pool models.TalentPool
+	if err := s.db.WithContext(ctx).First(&pool, poolID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewNotFoundError("人才库不存在", "talent_pool")
+		}
+		return nil, fmt.Errorf("查询人才库失败: %w", err)
+	}
+	return &pool, nil
+}
+
+// checkPoolName 校验人才库名称未被其他人才库使用
+func (s *TalentService) checkPoolName(ctx context.Context, name string, excludeID uint) error {
+	if name == "" {
+		return utils.NewValidationError("人才库名称不能为空", "name")
+	}
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.TalentPool{}).
+		Where("name = ? AND id <> ?", name, excludeID).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("查询人才库失败: %w", err)
+	}
+	if count > 0 {
+		return utils.NewValidationError("人才库名称已存在", "name")
+	}
+	return nil
+}
+
+// checkCandidates 校验候选人均存在
+func (s *TalentService) checkCandidates(ctx context.Context, userIDs ...uint) error {
+	ids := make(map[uint]bool, len(userIDs))
+	for _, id := range userIDs {
+		ids[id] = true
+	}
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.User{}).Where("id IN ?", userIDs).Count(&count).Error; err != nil {
+		return fmt.Errorf("查询候选人失败: %w", err)
+	}
+	if int(count) != len(ids) {
+		return utils.NewNotFoundError("候选人不存在", "user")
+	}
+	return nil
+}
+
+// uniqueStrings 规范化并去重，忽略规范化后为空的值
+func uniqueStrings(values []string, normalize func(string) string) []string {
+	var result []string
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		value = normalize(value)
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"API/models"
+)
+
+func TestSearchCandidatesFilters(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &models.User{}, &models.Role{}, &models.Resume{}, &models.ResumeEducation{}, &models.ResumeEmployment{},
+		&models.Skill{}, &models.ResumeVersion{}, &models.Application{}, &models.TalentPool{}, &models.TalentPoolMember{},
+		&models.TalentTag{}, &models.CandidateTag{})
+	svc := NewTalentService(db, TalentConfig{AllowFreeFormTags: true})
+	resumes := NewResumeService(db, newMemoryCache())
+	now := time.Now()
+	longAgo := now.AddDate(0, 0, -90)
+
+	// alice 久未联系且已被淘汰，bob 正在流程中，carol 只在人才库中，dave 与招聘无关
+	users := map[string]*models.User{}
+	for _, name := range []string{"alice", "bob", "carol", "dave"} {
+		user := &models.User{Username: name, Email: name + "@example.com", Phone: name, Usertype: "candidate", Active: true}
+		if name == "alice" {
+			user.CreatedAt = longAgo
+		}
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("创建用户失败: %v", err)
+		}
+		users[name] = user
+	}
+	for name, input := range map[string]ResumeInput{
+		"alice": {Headline: "Go_后端", Skills: []string{"Go", "MySQL"}},
+		"bob":   {Headline: "前端工程师", Skills: []string{"Golang", "React"}},
+	} {
+		if _, err := resumes.SubmitResume(ctx, users[name].ID, input); err != nil {
+			t.Fatalf("SubmitResume: %v", err)
+		}
+	}
+	db.Model(&models.Resume{}).Where("user_id = ?", users["alice"].ID).UpdateColumn("updated_at", longAgo)
+	for _, application := range []models.Application{
+		{UserID: users["alice"].ID, JobID: 1, Status: "rejected"},
+		{UserID: users["bob"].ID, JobID: 2, Status: "pending"},
+	} {
+		if err := db.Create(&application).Error; err != nil {
+			t.Fatalf("创建申请失败: %v", err)
+		}
+		if application.Status == "rejected" {
+			db.Model(&application).UpdateColumn("updated_at", longAgo)
+		}
+	}
+	pool, err := svc.CreatePool(ctx, 1, TalentPoolInput{Name: "前端储备"})
+	if err != nil {
+		t.Fatalf("CreatePool: %v", err)
+	}
+	if err := svc.AddPoolMembers(ctx, pool.ID, 1, []uint{users["carol"].ID}); err != nil {
+		t.Fatalf("AddPoolMembers: %v", err)
+	}
+	for name, tags := range map[string][]string{"alice": {"高潜"}, "bob": {"高潜", "可复联"}} {
+		if _, err := svc.TagCandidate(ctx, users[name].ID, 1, tags); err != nil {
+			t.Fatalf("TagCandidate: %v", err)
+		}
+	}
+
+	monthAgo := now.AddDate(0, 0, -30)
+	tests := []struct {
+		name   string
+		filter CandidateFilter
+		want   []string
+	}{
+		{"不筛选", CandidateFilter{}, []string{"alice", "bob", "carol"}},
+		{"人才库", CandidateFilter{PoolID: pool.ID}, []string{"carol"}},
+		{"单个标签", CandidateFilter{Tags: []string{"高潜"}}, []string{"alice", "bob"}},
+		{"多个标签需全部匹配", CandidateFilter{Tags: []string{"高潜", "可复联"}}, []string{"bob"}},
+		{"技能按规范化标识匹配", CandidateFilter{Skills: []string{"golang"}}, []string{"alice", "bob"}},
+		{"多个技能需全部匹配", CandidateFilter{Skills: []string{"Go", "react"}}, []string{"bob"}},
+		{"最近申请状态", CandidateFilter{Status: "rejected"}, []string{"alice"}},
+		{"关键词匹配用户名", CandidateFilter{Keyword: "carol"}, []string{"carol"}},
+		{"关键词匹配求职意向", CandidateFilter{Keyword: "前端"}, []string{"bob"}},
+		{"关键词中的下划线按字面匹配", CandidateFilter{Keyword: "o_"}, []string{"alice"}},
+		{"关键词中的百分号按字面匹配", CandidateFilter{Keyword: "%"}, nil},
+		{"久未联系", CandidateFilter{ActiveBefore: &monthAgo}, []string{"alice"}},
+		{"近期活跃", CandidateFilter{ActiveAfter: &monthAgo}, []string{"bob", "carol"}},
+		{"排除已申请职位的候选人", CandidateFilter{ExcludeJobID: 2}, []string{"alice", "carol"}},
+		{"组合条件", CandidateFilter{Tags: []string{"高潜"}, ActiveBefore: &monthAgo, Skills: []string{"mysql"}}, []string{"alice"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates, total, err := svc.SearchCandidates(ctx, tt.filter, 1, 10)
+			if err != nil {
+				t.Fatalf("SearchCandidates: %v", err)
+			}
+			var got []string
+			for _, c := range candidates {
+				got = append(got, c.Username)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) || total != int64(len(tt.want)) {
+				t.Errorf("结果为 %v（共 %d 个），期望 %v", got, total, tt.want)
+			}
+		})
+	}
+
+	// 按最近活动倒序，并补充简历、标签、人才库和申请情况
+	candidates, _, err := svc.SearchCandidates(ctx, CandidateFilter{}, 1, 10)
+	if err != nil {
+		t.Fatalf("SearchCandidates: %v", err)
+	}
+	last := candidates[len(candidates)-1]
+	if last.Username != "alice" || !last.LastActivity.Before(monthAgo) {
+		t.Errorf("最后一位为 %s，最近活动 %s，期望久未联系的 alice", last.Username, last.LastActivity)
+	}
+	if last.Headline != "Go_后端" || last.LastStatus != "rejected" || last.ApplicationCount != 1 ||
+		len(last.Tags) != 1 || !reflect.DeepEqual(last.Skills, []string{"Go", "MySQL"}) {
+		t.Errorf("候选人概要为 %+v", last)
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"API/storage/cache"
+	"API/utils"
+)
+
+// TokenPair 登录或刷新后下发的令牌对
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// TokenService 负责令牌签发、刷新令牌轮换与服务端吊销
+type TokenService struct {
+	cache  cache.Provider
+	issuer *utils.TokenIssuer
+}
+
+func NewTokenService(cache cache.Provider, issuer *utils.TokenIssuer) *TokenService {
+	return &TokenService{cache: cache, issuer: issuer}
+}
+
+// 吊销相关缓存键
+func revokedTokenKey(jti string) string {
+	return "token_revoked:" + jti
+}
+
+func revokedBeforeKey(userID uint) string {
+	return fmt.Sprintf("token_revoked_before:%d", userID)
+}
+
+func refreshTokenKey(jti string) string {
+	return "refresh_token:" + jti
+}
+
+// IssueTokenPair 签发访问令牌和刷新令牌，刷新令牌登记为可用
+func (s *TokenService) IssueTokenPair(ctx context.Context, userID uint, roles []string) (*TokenPair, error) {
+	accessToken, accessClaims, err := s.issuer.GenerateToken(userID, roles)
+	if err != nil {
+		return nil, fmt.Errorf("生成访问令牌失败: %w", err)
+	}
+	refreshToken, refreshClaims, err := s.issuer.GenerateRefreshToken(userID)
+	if err != nil {
+		return nil, fmt.Errorf("生成刷新令牌失败: %w", err)
+	}
+
+	if err := s.cache.SetObject(ctx, refreshTokenKey(refreshClaims.ID), userID, time.Until(refreshClaims.ExpiresAt.Time)); err != nil {
+		return nil, fmt.Errorf("保存刷新令牌失败: %w", err)
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		ExpiresAt:        accessClaims.ExpiresAt.Time,
+		RefreshExpiresAt: refreshClaims.ExpiresAt.Time,
+	}, nil
+}
+
+// ParseAccessToken 解析访问令牌
+func (s *TokenService) ParseAccessToken(tokenString string) (*utils.Claims, error) {
+	return s.issuer.ParseToken(tokenString)
+}
+
+// ParseRefreshToken 解析刷新令牌
+func (s *TokenService) ParseRefreshToken(tokenString string) (*utils.Claims, error) {
+	return s.issuer.ParseRefreshToken(tokenString)
+}
+
+// ConsumeRefreshToken 校验并作废刷新令牌（一次性使用）。
+// 已被使用过的刷新令牌再次出现时视为泄露，吊销该用户的全部令牌。
+func (s *TokenService) ConsumeRefreshToken(ctx context.Context, refreshToken string) (*utils.Claims, error) {
+	claims, err := s.issuer.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return nil, utils.NewAuthError("无效的刷新令牌")
+	}
+
+	revoked, err := s.IsRevoked(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, utils.NewAuthError("刷新令牌已失效")
+	}
+
+	// 读取与作废在同一条 GETDEL 中完成，并发刷新时只有一个请求能取到令牌
+	var owner uint
+	if err := s.cache.GetDelObject(ctx, refreshTokenKey(claims.ID), &owner); err != nil {
+		if !errors.Is(err, cache.ErrNotFound) {
+			return nil, fmt.Errorf("查询刷新令牌失败: %w", err)
+		}
+		log.Printf("[Token] 检测到刷新令牌重复使用，吊销用户 %d 的全部令牌", claims.UserID)
+		if err := s.RevokeUserTokens(ctx, claims.UserID); err != nil {
+			return nil, err
+		}
+		return nil, utils.NewAuthError("刷新令牌已失效")
+	}
+	return claims, nil
+}
+
+// RevokeToken 按 jti 吊销单个令牌，吊销记录保留到令牌过期
+func (s *TokenService) RevokeToken(ctx context.Context, claims *utils.Claims) error {
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+	if err := s.cache.SetObject(ctx, revokedTokenKey(claims.ID), true, ttl); err != nil {
+		return fmt.Errorf("吊销令牌失败: %w", err)
+	}
+	if claims.TokenType == utils.TokenTypeRefresh {
+		if err := s.cache.Del(ctx, refreshTokenKey(claims.ID)); err != nil {
+			return fmt.Errorf("作废刷新令牌失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// RevokeUserTokens 吊销用户在此刻之前签发的全部令牌，吊销时间精确到毫秒
+func (s *TokenService) RevokeUserTokens(ctx context.Context, userID uint) error {
+	if err := s.cache.SetObject(ctx, revokedBeforeKey(userID), time.Now().UnixMilli(), s.issuer.RefreshTTL()); err != nil {
+		return fmt.Errorf("吊销用户令牌失败: %w", err)
+	}
+	return nil
+}
+
+// IsRevoked 判断令牌是否已被吊销
+func (s *TokenService) IsRevoked(ctx context.Context, claims *utils.Claims) (bool, error) {
+	var revoked bool
+	if err := s.cache.GetObject(ctx, revokedTokenKey(claims.ID), &revoked); err == nil {
+		return true, nil
+	} else if !errors.Is(err, cache.ErrNotFound) {
+		return false, fmt.Errorf("查询令牌吊销状态失败: %w", err)
+	}
+
+	var revokedBefore int64
+	if err := s.cache.GetObject(ctx, revokedBeforeKey(claims.UserID), &revokedBefore); err != nil {
+		if errors.Is(err, cache.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("查询令牌吊销状态失败: %w", err)
+	}
+	// 吊销后立即签发的令牌（如修改密码后返回的新令牌）与吊销时间可能在同一毫秒内，不视为失效
+	return claims.IssuedAtMs < revokedBefore, nil
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"API/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// authMessage 返回认证错误的提示信息，不是认证错误时返回空字符串
+func authMessage(err error) string {
+	var authErr *utils.AuthError
+	if errors.As(err, &authErr) {
+		return authErr.Message
+	}
+	return ""
+}
+
+func TestConsumeRefreshTokenRotation(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryCache()
+	tokens := newTestTokenService(t, store)
+
+	pair, err := tokens.IssueTokenPair(ctx, 7, []string{"employee"})
+	if err != nil {
+		t.Fatalf("IssueTokenPair: %v", err)
+	}
+	claims, err := tokens.ConsumeRefreshToken(ctx, pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("首次使用刷新令牌失败: %v", err)
+	}
+	if claims.UserID != 7 || store.has(refreshTokenKey(claims.ID)) {
+		t.Fatalf("刷新令牌使用后应作废，用户为 %d", claims.UserID)
+	}
+	next, err := tokens.IssueTokenPair(ctx, 7, []string{"employee"})
+	if err != nil {
+		t.Fatalf("IssueTokenPair: %v", err)
+	}
+
+	// 已使用的刷新令牌再次出现视为泄露，吊销用户此前签发的全部令牌
+	time.Sleep(2 * time.Millisecond)
+	if _, err := tokens.ConsumeRefreshToken(ctx, pair.RefreshToken); authMessage(err) != "刷新令牌已失效" {
+		t.Fatalf("重复使用刷新令牌应失败，得到 %v", err)
+	}
+	for name, token := range map[string]string{"原访问令牌": pair.AccessToken, "轮换后的访问令牌": next.AccessToken} {
+		access, err := tokens.ParseAccessToken(token)
+		if err != nil {
+			t.Fatalf("ParseAccessToken: %v", err)
+		}
+		if revoked, err := tokens.IsRevoked(ctx, access); err != nil || !revoked {
+			t.Errorf("%s应已被吊销，得到 %v, %v", name, revoked, err)
+		}
+	}
+	if _, err := tokens.ConsumeRefreshToken(ctx, next.RefreshToken); authMessage(err) != "刷新令牌已失效" {
+		t.Errorf("轮换后的刷新令牌应随之失效，得到 %v", err)
+	}
+
+	// 吊销之后重新登录签发的令牌不受影响
+	fresh, err := tokens.IssueTokenPair(ctx, 7, []string{"employee"})
+	if err != nil {
+		t.Fatalf("IssueTokenPair: %v", err)
+	}
+	if _, err := tokens.ConsumeRefreshToken(ctx, fresh.RefreshToken); err != nil {
+		t.Errorf("重新登录后的刷新令牌应可用，得到 %v", err)
+	}
+}
+
+func TestConsumeRefreshTokenRejects(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryCache()
+	tokens := newTestTokenService(t, store)
+	pair, err := tokens.IssueTokenPair(ctx, 7, nil)
+	if err != nil {
+		t.Fatalf("IssueTokenPair: %v", err)
+	}
+	refresh, err := tokens.ParseRefreshToken(pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("ParseRefreshToken: %v", err)
+	}
+	if err := tokens.RevokeToken(ctx, refresh); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{"格式错误", "not-a-token", "无效的刷新令牌"},
+		{"访问令牌不能用于刷新", pair.AccessToken, "无效的刷新令牌"},
+		{"已登出的刷新令牌", pair.RefreshToken, "刷新令牌已失效"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tokens.ConsumeRefreshToken(ctx, tt.token); authMessage(err) != tt.want {
+				t.Errorf("期望错误 %q，得到 %v", tt.want, err)
+			}
+		})
+	}
+	if store.has(revokedBeforeKey(7)) {
+		t.Error("登出后的刷新令牌被拒绝时不应视为泄露")
+	}
+}
+
+func TestIsRevokedByUser(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryCache()
+	tokens := newTestTokenService(t, store)
+	revokedBefore := time.Date(2025, 3, 3, 9, 0, 0, 500_000_000, time.Local).UnixMilli()
+	if err := store.SetObject(ctx, revokedBeforeKey(7), revokedBefore, time.Hour); err != nil {
+		t.Fatalf("SetObject: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		userID     uint
+		issuedAtMs int64
+		want       bool
+	}{
+		{"吊销前签发", 7, revokedBefore - 1, true},
+		{"与吊销时间同一秒但更早", 7, revokedBefore - 400, true},
+		{"同一毫秒签发", 7, revokedBefore, false},
+		{"吊销后签发", 7, revokedBefore + 1, false},
+		{"其他用户", 8, revokedBefore - 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := &utils.Claims{
+				UserID:           tt.userID,
+				IssuedAtMs:       tt.issuedAtMs,
+				RegisteredClaims: jwt.RegisteredClaims{ID: "jti"},
+			}
+			got, err := tokens.IsRevoked(ctx, claims)
+			if err != nil {
+				t.Fatalf("IsRevoked: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsRevoked = %v, 期望 %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -69,9 +69,16 @@ func (s *TrainingService) UpdateTrainingRecord(ctx context.Context, recordID uin
 	return s.db.WithContext(ctx).Save(&record).Error
 }
 
-// CancelTrainingRegistration 取消培训注册
-func (s *TrainingService) CancelTrainingRegistration(ctx context.Context, recordID uint) error {
-	return s.db.WithContext(ctx).Delete(&models.TrainingRecord{}, recordID).Error
+// CancelTrainingRegistration 取消本人的培训注册
+func (s *TrainingService) CancelTrainingRegistration(ctx context.Context, userID, recordID uint) error {
+	result := s.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.TrainingRecord{}, recordID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("培训记录不存在")
+	}
+	return nil
 }
 
 // GetTrainingByID 获取培训详情
@@ -5,6 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
 
 	"API/models"
 	"API/storage/cache"
@@ -13,15 +17,40 @@ import (
 	"gorm.io/gorm"
 )
 
+// errInvalidCredentials 登录失败统一返回的错误，不区分用户名不存在与密码错误
+var errInvalidCredentials = utils.NewAuthError("用户名或密码错误")
+
+// dummyPasswordHash 用户不存在时参与比对，使响应耗时与密码错误一致
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+// LoginResult 登录结果。未启用两步验证时直接返回令牌对；
+// 否则返回 mfa_token，客户端需携带验证码调用第二步接口换取令牌
+type LoginResult struct {
+	*TokenPair
+	MFARequired       bool       `json:"mfa_required,omitempty"`
+	MFAEnrollRequired bool       `json:"mfa_enroll_required,omitempty"`
+	MFAToken          string     `json:"mfa_token,omitempty"`
+	MFATokenExpiresAt *time.Time `json:"mfa_token_expires_at,omitempty"`
+	RecoveryCodes     []string   `json:"recovery_codes,omitempty"`
+}
+
 type UserService struct {
-	db    *gorm.DB
-	cache cache.Provider
+	db     *gorm.DB
+	cache  cache.Provider
+	tokens *TokenService
+	guard  *LoginGuard
+	mfa    *MFAService
+	policy *PasswordPolicy
 }
 
-func NewUserService(db *gorm.DB, cache cache.Provider) *UserService {
+func NewUserService(db *gorm.DB, cache cache.Provider, tokens *TokenService, guard *LoginGuard, mfa *MFAService, policy *PasswordPolicy) *UserService {
 	return &UserService{
-		db:    db,
-		cache: cache,
+		db:     db,
+		cache:  cache,
+		tokens: tokens,
+		guard:  guard,
+		mfa:    mfa,
+		policy: policy,
 	}
 }
 
@@ -36,6 +65,13 @@ func (s *UserService) RegisterUser(ctx context.Context, user *models.User) error
 	if usernameCount > 0 {
 		return errors.New("用户名已存在")
 	}
+	// 邮箱和手机号同样可用于登录，用户名不能与之混淆
+	if strings.Contains(user.Username, "@") {
+		return utils.NewValidationError("用户名不能包含@", "username")
+	}
+	if err := s.policy.Validate(user.PasswordHash, user.Username); err != nil {
+		return err
+	}
 
 	// 检查联系方式唯一性
 	if user.Phone != "" {
@@ -71,7 +107,14 @@ func (s *UserService) RegisterUser(ctx context.Context, user *models.User) error
 		if err := tx.Create(user).Error; err != nil {
 			return err
 		}
-		return tx.Model(user).Association("Roles").Append([]models.Role{{Name: "candidate"}})
+		if err := s.policy.RecordHistory(tx, user.ID, user.PasswordHash); err != nil {
+			return err
+		}
+		var candidate models.Role
+		if err := tx.Where("name = ?", models.RoleCandidate).First(&candidate).Error; err != nil {
+			return fmt.Errorf("查询候选人角色失败: %w", err)
+		}
+		return tx.Model(user).Association("Roles").Append(&candidate)
 	})
 }
 
@@ -84,48 +127,337 @@ func (s *UserService) GetUserByID(ctx context.Context, userID uint) (*models.Use
 	return &user, nil
 }
 
-// UpdateProfile 更新用户资料
-func (s *UserService) UpdateProfile(ctx context.Context, userID uint, updates map[string]interface{}) error {
+// profileFields 用户可自行修改的资料字段，密码、两步验证、账户状态等须通过专用接口修改
+var profileFields = map[string]bool{"email": true, "phone": true}
+
+// contactNames 联系方式字段的中文名称
+var contactNames = map[string]string{"email": "邮箱", "phone": "手机号"}
+
+// phonePattern 手机号格式，可带国际区号前缀 +
+var phonePattern = regexp.MustCompile(`^\+?[0-9]{6,19}$`)
+
+// UpdateProfile 更新用户资料，返回邮箱是否变更。邮箱变更后清除验证状态，需重新验证
+func (s *UserService) UpdateProfile(ctx context.Context, userID uint, updates map[string]interface{}) (bool, error) {
+	var user models.User
+	if err := s.db.WithContext(ctx).First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, utils.NewNotFoundError("用户不存在", "user")
+		}
+		return false, fmt.Errorf("查询用户失败: %w", err)
+	}
+
+	changes := make(map[string]interface{}, len(updates))
+	for field, raw := range updates {
+		if !profileFields[field] {
+			return false, utils.NewValidationError("不允许修改的字段: "+field, field)
+		}
+		value, ok := raw.(string)
+		if !ok {
+			return false, utils.NewValidationError(field+"必须为字符串", field)
+		}
+		value = strings.TrimSpace(value)
+
+		current := user.Phone
+		if field == "email" {
+			current = user.Email
+		}
+		if value == current {
+			continue
+		}
+		if err := validateContact(field, value); err != nil {
+			return false, err
+		}
+		if err := s.checkContactAvailable(ctx, field, value, userID); err != nil {
+			return false, err
+		}
+		changes[field] = value
+	}
+	if len(changes) == 0 {
+		return false, nil
+	}
+
+	_, emailChanged := changes["email"]
+	if emailChanged {
+		changes["email_verified_at"] = nil
+	}
+	if err := s.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ?", userID).
+		Updates(changes).Error; err != nil {
+		return false, fmt.Errorf("更新用户信息失败: %w", err)
+	}
+
 	// 清除缓存
 	if err := s.cache.Del(ctx, fmt.Sprintf("user:%d", userID)); err != nil {
 		log.Printf("缓存清除失败: %v", err)
 	}
+	return emailChanged, nil
+}
 
-	return s.db.WithContext(ctx).Model(&models.User{}).
-		Where("id = ?", userID).
-		Updates(updates).Error
+// validateContact 校验邮箱或手机号格式
+func validateContact(field, value string) error {
+	switch field {
+	case "email":
+		address, err := mail.ParseAddress(value)
+		if err != nil || address.Address != value || len(value) > 50 {
+			return utils.NewValidationError("邮箱格式不正确", field)
+		}
+	case "phone":
+		if !phonePattern.MatchString(value) {
+			return utils.NewValidationError("手机号格式不正确", field)
+		}
+	}
+	return nil
+}
+
+// checkContactAvailable 检查邮箱或手机号是否已被其他用户使用，已删除的用户仍占用唯一索引
+func (s *UserService) checkContactAvailable(ctx context.Context, field, value string, userID uint) error {
+	var count int64
+	if err := s.db.WithContext(ctx).Unscoped().Model(&models.User{}).
+		Where(field+" = ? AND id <> ?", value, userID).Count(&count).Error; err != nil {
+		return fmt.Errorf("检查%s失败: %w", contactNames[field], err)
+	}
+	if count > 0 {
+		return utils.NewValidationError(contactNames[field]+"已被注册", field)
+	}
+	return nil
+}
+
+// Authenticate 用户认证，失败次数过多时按账户和IP退避或临时锁定。
+// 已启用或按策略必须启用两步验证的用户，密码验证通过后返回第二步令牌而非访问令牌。
+func (s *UserService) Authenticate(ctx context.Context, identifier, password, clientIP string) (*LoginResult, error) {
+	user, err := s.findByLoginIdentifier(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+	// 失败计数以用户名为准，避免轮换用户名、邮箱、手机号绕过锁定
+	account := identifier
+	if user != nil {
+		account = user.Username
+	}
+	if err := s.guard.Check(ctx, account, clientIP); err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, s.loginFailed(ctx, account, clientIP)
+	}
+
+	// 验证密码
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, s.loginFailed(ctx, account, clientIP)
+	}
+	if !user.Active {
+		return nil, utils.NewAuthError("账户已停用")
+	}
+
+	if err := s.guard.Reset(ctx, account); err != nil {
+		log.Printf("清除登录失败记录失败: %v", err)
+	}
+
+	enroll := false
+	if !user.MFAEnabled {
+		required, err := s.mfa.Required(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		enroll = required
+	}
+	if user.MFAEnabled || enroll {
+		token, expiresAt, err := s.mfa.CreateChallenge(ctx, user.ID, enroll)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{
+			MFARequired:       true,
+			MFAEnrollRequired: enroll,
+			MFAToken:          token,
+			MFATokenExpiresAt: &expiresAt,
+		}, nil
+	}
+
+	tokens, err := s.issueTokens(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{TokenPair: tokens}, nil
+}
+
+// findByLoginIdentifier 按用户名、邮箱或手机号查找用户，用户名优先；用户不存在时返回 nil
+func (s *UserService) findByLoginIdentifier(ctx context.Context, identifier string) (*models.User, error) {
+	identifier = strings.TrimSpace(identifier)
+	if identifier == "" {
+		return nil, nil
+	}
 
+	columns := []string{"username"}
+	if strings.Contains(identifier, "@") {
+		columns = []string{"email"}
+	} else if isPhoneNumber(identifier) {
+		columns = append(columns, "phone")
+	}
+
+	for _, column := range columns {
+		var user models.User
+		err := s.db.WithContext(ctx).Where(column+" = ?", identifier).First(&user).Error
+		if err == nil {
+			return &user, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("查询用户失败: %w", err)
+		}
+	}
+	return nil, nil
+}
+
+// isPhoneNumber 粗略判断是否为手机号（可带 + 前缀的纯数字）
+func isPhoneNumber(s string) bool {
+	s = strings.TrimPrefix(s, "+")
+	if len(s) < 5 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
 }
 
-// Authenticate 用户认证
-func (s *UserService) Authenticate(ctx context.Context, username, password string) (string, error) {
+// ChangePassword 校验当前密码后修改密码，成功后吊销该用户全部令牌
+func (s *UserService) ChangePassword(ctx context.Context, userID uint, currentPassword, newPassword string) error {
 	var user models.User
-	if err := s.db.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&user, userID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", errors.New("用户不存在")
+			return utils.NewNotFoundError("用户不存在", "user")
 		}
-		return "", fmt.Errorf("查询用户失败: %w", err)
+		return fmt.Errorf("查询用户失败: %w", err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword)); err != nil {
+		return utils.NewValidationError("当前密码错误", "current_password")
 	}
 
-	// 验证密码
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return "", errors.New("密码错误")
+	if err := setPassword(ctx, s.db, s.policy, &user, newPassword, nil); err != nil {
+		return err
+	}
+	return s.tokens.RevokeUserTokens(ctx, user.ID)
+}
+
+// CompleteMFALogin 校验两步验证码（或恢复码）并签发令牌，完成登录
+func (s *UserService) CompleteMFALogin(ctx context.Context, mfaToken, code string) (*LoginResult, error) {
+	user, recoveryCodes, err := s.mfa.CompleteChallenge(ctx, mfaToken, code)
+	if err != nil {
+		return nil, err
+	}
+	if !user.Active {
+		return nil, utils.NewAuthError("账户已停用")
+	}
+
+	tokens, err := s.issueTokens(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{TokenPair: tokens, RecoveryCodes: recoveryCodes}, nil
+}
+
+// loginFailed 记录失败次数并返回统一的认证错误
+func (s *UserService) loginFailed(ctx context.Context, username, clientIP string) error {
+	if err := s.guard.RecordFailure(ctx, username, clientIP); err != nil {
+		return err
+	}
+	return errInvalidCredentials
+}
+
+// UnlockAccount 解除账户的登录锁定
+func (s *UserService) UnlockAccount(ctx context.Context, userID uint) error {
+	var user models.User
+	if err := s.db.WithContext(ctx).First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.NewNotFoundError("用户不存在", "user")
+		}
+		return fmt.Errorf("查询用户失败: %w", err)
+	}
+	if err := s.guard.Unlock(ctx, user.Username); err != nil {
+		return fmt.Errorf("解除锁定失败: %w", err)
+	}
+	return nil
+}
+
+// RefreshToken 使用刷新令牌换取新的令牌对，旧刷新令牌随即作废
+func (s *UserService) RefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	claims, err := s.tokens.ConsumeRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var user models.User
+	if err := s.db.WithContext(ctx).First(&user, claims.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.NewAuthError("用户不存在")
+		}
+		return nil, fmt.Errorf("查询用户失败: %w", err)
 	}
+	if !user.Active {
+		return nil, utils.NewAuthError("账户已停用")
+	}
+	// 策略变更后要求启用两步验证的用户需重新登录完成绑定
+	if !user.MFAEnabled {
+		required, err := s.mfa.Required(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if required {
+			return nil, utils.NewAuthError("账户需启用两步验证，请重新登录")
+		}
+	}
+
+	return s.issueTokens(ctx, &user)
+}
+
+// Logout 吊销当前访问令牌，并可同时吊销对应的刷新令牌
+func (s *UserService) Logout(ctx context.Context, accessClaims *utils.Claims, refreshToken string) error {
+	if err := s.tokens.RevokeToken(ctx, accessClaims); err != nil {
+		return err
+	}
+	if refreshToken == "" {
+		return nil
+	}
+
+	refreshClaims, err := s.tokens.ParseRefreshToken(refreshToken)
+	if err != nil || refreshClaims.UserID != accessClaims.UserID {
+		return utils.NewValidationError("无效的刷新令牌", "refresh_token")
+	}
+	return s.tokens.RevokeToken(ctx, refreshClaims)
+}
+
+// RevokeUserTokens 吊销指定用户已签发的全部令牌，用于停用账户或强制下线
+func (s *UserService) RevokeUserTokens(ctx context.Context, userID uint) error {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Count(&count).Error; err != nil {
+		return fmt.Errorf("查询用户失败: %w", err)
+	}
+	if count == 0 {
+		return utils.NewNotFoundError("用户不存在", "user")
+	}
+	return s.tokens.RevokeUserTokens(ctx, userID)
+}
 
+// issueTokens 读取用户当前角色并签发令牌对
+func (s *UserService) issueTokens(ctx context.Context, user *models.User) (*TokenPair, error) {
 	// 获取用户角色
 	var roles []string
-	if err := s.db.WithContext(ctx).Model(&user).Association("Roles").Find(&user.Roles); err != nil {
-		return "", fmt.Errorf("获取角色失败: %w", err)
+	if err := s.db.WithContext(ctx).Model(user).Association("Roles").Find(&user.Roles); err != nil {
+		return nil, fmt.Errorf("获取角色失败: %w", err)
 	}
 	for _, role := range user.Roles {
 		roles = append(roles, role.Name)
 	}
 
 	// 生成JWT令牌
-	token, err := utils.GenerateToken(user.ID, roles)
+	tokens, err := s.tokens.IssueTokenPair(ctx, user.ID, roles)
 	if err != nil {
-		return "", fmt.Errorf("生成令牌失败: %w", err)
+		return nil, fmt.Errorf("生成令牌失败: %w", err)
 	}
 
-	return token, nil
+	return tokens, nil
 }
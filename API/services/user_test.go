@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"API/models"
+)
+
+func TestUpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T) (*UserService, *memoryCache, models.User) {
+		store := newMemoryCache()
+		svc := NewUserService(newTestDB(t, &models.User{}), store, nil, nil, nil, nil)
+		verifiedAt := time.Now()
+		users := []models.User{
+			{Username: "alice", Email: "alice@example.com", EmailVerifiedAt: &verifiedAt, Phone: "13800000001", PasswordHash: "x"},
+			{Username: "bob", Email: "bob@example.com", Phone: "13800000002", PasswordHash: "x"},
+			{Username: "carol", Email: "carol@example.com", Phone: "13800000003", PasswordHash: "x"},
+		}
+		if err := svc.db.Create(&users).Error; err != nil {
+			t.Fatalf("创建用户失败: %v", err)
+		}
+		svc.db.Delete(&users[2])
+		store.SetObject(ctx, "user:1", users[0], time.Hour)
+		return svc, store, users[0]
+	}
+
+	tests := []struct {
+		name         string
+		updates      map[string]interface{}
+		wantErr      string
+		emailChanged bool
+		want         models.User
+	}{
+		{"修改手机号", map[string]interface{}{"phone": " +8613900000001 "}, "", false, models.User{Email: "alice@example.com", Phone: "+8613900000001"}},
+		{"修改邮箱", map[string]interface{}{"email": "alice@new.example.com"}, "", true, models.User{Email: "alice@new.example.com", Phone: "13800000001"}},
+		{"与原值相同不视为变更", map[string]interface{}{"email": "alice@example.com", "phone": "13800000001"}, "", false, models.User{Email: "alice@example.com", Phone: "13800000001"}},
+		{"不允许修改的字段", map[string]interface{}{"usertype": "admin"}, "不允许修改的字段: usertype", false, models.User{}},
+		{"值不是字符串", map[string]interface{}{"phone": 13900000001}, "phone必须为字符串", false, models.User{}},
+		{"邮箱格式错误", map[string]interface{}{"email": "alice"}, "邮箱格式不正确", false, models.User{}},
+		{"邮箱带显示名", map[string]interface{}{"email": "Alice <alice@new.example.com>"}, "邮箱格式不正确", false, models.User{}},
+		{"邮箱为空", map[string]interface{}{"email": ""}, "邮箱格式不正确", false, models.User{}},
+		{"手机号格式错误", map[string]interface{}{"phone": "138-0000"}, "手机号格式不正确", false, models.User{}},
+		{"邮箱已被其他用户使用", map[string]interface{}{"email": "bob@example.com"}, "邮箱已被注册", false, models.User{}},
+		{"手机号已被其他用户使用", map[string]interface{}{"phone": "13800000002"}, "手机号已被注册", false, models.User{}},
+		{"已删除用户的手机号仍被占用", map[string]interface{}{"phone": "13800000003"}, "手机号已被注册", false, models.User{}},
+		{"部分字段无效时不更新其他字段", map[string]interface{}{"phone": "13900000001", "email": "bob@example.com"}, "邮箱已被注册", false, models.User{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, store, before := setup(t)
+			emailChanged, err := svc.UpdateProfile(ctx, before.ID, tt.updates)
+
+			var after models.User
+			if err := svc.db.First(&after, before.ID).Error; err != nil {
+				t.Fatalf("查询用户失败: %v", err)
+			}
+			if tt.wantErr != "" {
+				if validationMessage(err) != tt.wantErr {
+					t.Fatalf("期望错误 %q，得到 %v", tt.wantErr, err)
+				}
+				if after.Email != before.Email || after.Phone != before.Phone || after.EmailVerifiedAt == nil {
+					t.Errorf("校验失败时不应修改资料，得到 %s %s", after.Email, after.Phone)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateProfile: %v", err)
+			}
+			if emailChanged != tt.emailChanged {
+				t.Errorf("邮箱变更为 %v, 期望 %v", emailChanged, tt.emailChanged)
+			}
+			if after.Email != tt.want.Email || after.Phone != tt.want.Phone {
+				t.Errorf("资料为 %s %s, 期望 %s %s", after.Email, after.Phone, tt.want.Email, tt.want.Phone)
+			}
+			// 邮箱变更后需重新验证
+			if verified := after.EmailVerifiedAt != nil; verified == tt.emailChanged {
+				t.Errorf("邮箱验证状态为 %v, 邮箱变更为 %v", verified, tt.emailChanged)
+			}
+			if changed := after.Email != before.Email || after.Phone != before.Phone; changed == store.has("user:1") {
+				t.Errorf("资料变更为 %v 时用户缓存存在为 %v", changed, store.has("user:1"))
+			}
+		})
+	}
+}
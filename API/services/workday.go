@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"API/models"
+	"API/utils"
+
+	"gorm.io/gorm"
+)
+
+// maxWorkdayRange 单次计算工作日的最大天数
+const maxWorkdayRange = 366
+
+// WorkdayCalculator 按员工的班次和所在地区的节假日日历计算工作日，
+// 供缺勤统计、请假天数和薪资折算共用
+type WorkdayCalculator struct {
+	db        *gorm.DB
+	schedules *ScheduleService
+	holidays  *HolidayService
+}
+
+func NewWorkdayCalculator(db *gorm.DB, schedules *ScheduleService, holidays *HolidayService) *WorkdayCalculator {
+	return &WorkdayCalculator{db: db, schedules: schedules, holidays: holidays}
+}
+
+// WorkdaySummary 某段期间的工作日
+type WorkdaySummary struct {
+	Start    time.Time   `json:"start"`
+	End      time.Time   `json:"end"`
+	Count    int         `json:"count"`
+	Workdays []time.Time `json:"workdays"`
+}
+
+// Summarize 获取员工 [start, end] 期间的工作日
+func (w *WorkdayCalculator) Summarize(ctx context.Context, userID uint, start, end time.Time) (*WorkdaySummary, error) {
+	start, end = dateOf(start), dateOf(end)
+	if end.Before(start) {
+		return nil, utils.NewValidationError("结束日期不能早于开始日期", "end")
+	}
+	if end.Sub(start) >= maxWorkdayRange*24*time.Hour {
+		return nil, utils.NewValidationError("查询期间不能超过一年", "end")
+	}
+	days, err := w.WorkingDays(w.db.WithContext(ctx), userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &WorkdaySummary{Start: start, End: end, Count: len(days), Workdays: days}, nil
+}
+
+// WorkingDays 返回员工 [start, end] 期间的工作日。节假日不上班；
+// 调休上班日即使是班次的休息日也需上班；其余日期按当日适用班次的工作日判断
+func (w *WorkdayCalculator) WorkingDays(tx *gorm.DB, userID uint, start, end time.Time) ([]time.Time, error) {
+	start, end = dateOf(start), dateOf(end)
+	if end.Before(start) {
+		return []time.Time{}, nil
+	}
+
+	kinds := map[string]string{}
+	calendar, err := w.holidays.calendarFor(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if calendar != nil {
+		if kinds, err = w.holidays.holidaysBetween(tx, calendar.ID, start, end); err != nil {
+			return nil, err
+		}
+	}
+
+	resolver, err := w.schedules.resolverFor(tx, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return workingDaysIn(start, end, kinds, resolver), nil
+}
+
+// workingDaysIn 按节假日日历和班次分配在内存中逐日判断工作日，kinds 的键为 YYYY-MM-DD
+func workingDaysIn(start, end time.Time, kinds map[string]string, resolver *scheduleResolver) []time.Time {
+	days := []time.Time{}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		switch kinds[day.Format("2006-01-02")] {
+		case models.HolidayOff:
+			continue
+		case models.HolidayWorkday:
+			days = append(days, day)
+			continue
+		}
+		if isWorkDay(*resolver.scheduleOn(day), day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// DayKind 判断员工某日为法定节假日、休息日还是工作日，调休上班日视为工作日
+func (w *WorkdayCalculator) DayKind(tx *gorm.DB, userID uint, date time.Time) (string, error) {
+	day := dateOf(date)
+	calendar, err := w.holidays.calendarFor(tx, userID)
+	if err != nil {
+		return "", err
+	}
+	if calendar != nil {
+		kinds, err := w.holidays.holidaysBetween(tx, calendar.ID, day, day)
+		if err != nil {
+			return "", err
+		}
+		switch kinds[day.Format("2006-01-02")] {
+		case models.HolidayOff:
+			return models.OvertimeHoliday, nil
+		case models.HolidayWorkday:
+			return models.OvertimeWeekday, nil
+		}
+	}
+	schedule, err := w.schedules.scheduleFor(tx, userID, day)
+	if err != nil {
+		return "", err
+	}
+	if isWorkDay(*schedule, day) {
+		return models.OvertimeWeekday, nil
+	}
+	return models.OvertimeWeekend, nil
+}
+
+// CountWorkingDays 统计员工 [start, end] 期间的工作日天数
+func (w *WorkdayCalculator) CountWorkingDays(tx *gorm.DB, userID uint, start, end time.Time) (int, error) {
+	days, err := w.WorkingDays(tx, userID, start, end)
+	if err != nil {
+		return 0, err
+	}
+	return len(days), nil
+}
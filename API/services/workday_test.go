@@ -0,0 +1,90 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"API/models"
+)
+
+func assignment(userID *uint, from time.Time, to *time.Time, schedule models.WorkSchedule) models.ScheduleAssignment {
+	return models.ScheduleAssignment{UserID: userID, EffectiveFrom: from, EffectiveTo: to, Schedule: schedule}
+}
+
+func TestScheduleResolver(t *testing.T) {
+	userID := uint(1)
+	weekend := models.WorkSchedule{Kind: models.ScheduleFixed, StartTime: "09:00", EndTime: "18:00", WorkDays: "0,6"}
+	weekend.ID = 2
+	personal := fixedSchedule
+	personal.ID = 1
+	department := nightSchedule
+	department.ID = 3
+	deleted := models.WorkSchedule{}
+	end := at(9, 0, 0)
+
+	resolver := &scheduleResolver{
+		// 同类分配按生效日期倒序
+		personal: []models.ScheduleAssignment{
+			assignment(&userID, at(10, 0, 0), nil, deleted),
+			assignment(&userID, at(5, 0, 0), &end, weekend),
+			assignment(&userID, at(1, 0, 0), nil, personal),
+		},
+		department: []models.ScheduleAssignment{
+			assignment(nil, at(1, 0, 0), nil, department),
+		},
+		fallback: models.WorkSchedule{Name: "默认班次"},
+	}
+	tests := []struct {
+		day  time.Time
+		want uint
+	}{
+		{at(1, 10, 0), 1}, // 个人分配优先
+		{at(5, 0, 0), 2},  // 取生效日期最晚的个人分配
+		{at(9, 23, 0), 2}, // 失效日期当天仍有效
+		{at(10, 0, 0), 3}, // 个人分配的班次已删除时改用部门分配
+		{time.Date(2025, 2, 28, 0, 0, 0, 0, time.Local), 0}, // 均未生效时使用默认班次
+	}
+	for _, tt := range tests {
+		if got := resolver.scheduleOn(tt.day); got.ID != tt.want {
+			t.Errorf("scheduleOn(%s) = 班次%d, 期望 班次%d", tt.day.Format("2006-01-02"), got.ID, tt.want)
+		}
+	}
+	if got := resolver.scheduleOn(time.Date(2025, 2, 28, 0, 0, 0, 0, time.Local)); got.Name != "默认班次" {
+		t.Errorf("未分配班次时应使用默认班次，得到 %+v", got)
+	}
+}
+
+func TestWorkingDaysIn(t *testing.T) {
+	userID := uint(1)
+	weekend := models.WorkSchedule{Kind: models.ScheduleFixed, StartTime: "09:00", EndTime: "18:00", WorkDays: "0,6"}
+	weekend.ID = 2
+	end := at(9, 0, 0)
+	resolver := &scheduleResolver{
+		personal: []models.ScheduleAssignment{assignment(&userID, at(8, 0, 0), &end, weekend)},
+		fallback: fixedSchedule,
+	}
+	kinds := map[string]string{
+		"2025-03-04": models.HolidayOff,     // 周二放假
+		"2025-03-02": models.HolidayWorkday, // 周日调休上班
+	}
+
+	// 2025-03-01（周六）至 03-09（周日）：默认班次周一至周五上班，08、09 两日改为周末班次
+	days := workingDaysIn(at(1, 0, 0), at(9, 0, 0), kinds, resolver)
+	var got []string
+	for _, day := range days {
+		got = append(got, day.Format("01-02"))
+	}
+	want := []string{"03-02", "03-03", "03-05", "03-06", "03-07", "03-08", "03-09"}
+	if len(got) != len(want) {
+		t.Fatalf("工作日为 %v, 期望 %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("工作日为 %v, 期望 %v", got, want)
+		}
+	}
+
+	if days := workingDaysIn(at(8, 0, 0), at(7, 0, 0), nil, resolver); len(days) != 0 {
+		t.Errorf("结束早于开始时应没有工作日，得到 %v", days)
+	}
+}
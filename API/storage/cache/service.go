@@ -10,10 +10,16 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// ErrNotFound 缓存键不存在
+var ErrNotFound = errors.New("缓存不存在")
+
 type Provider interface {
 	GetObject(ctx context.Context, key string, value interface{}) error
+	// GetDelObject 读取并删除键，读取与删除为一次原子操作
+	GetDelObject(ctx context.Context, key string, value interface{}) error
 	SetObject(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 	Del(ctx context.Context, keys ...string) error
+	Incr(ctx context.Context, key string, expiration time.Duration) (int64, error)
 }
 
 type RedisCacheService struct {
@@ -29,11 +35,40 @@ func (r *RedisCacheService) Del(ctx context.Context, keys ...string) error {
 	return r.client.Del(ctx, keys...).Err()
 }
 
+// Incr 计数器自增，首次创建时设置过期时间。
+// 在同一事务中先以 SET NX EX 创建带过期时间的键再自增，保证计数器始终有过期时间
+func (r *RedisCacheService) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	if expiration <= 0 {
+		return r.client.Incr(ctx, key).Result()
+	}
+	var incr *redis.IntCmd
+	if _, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetNX(ctx, key, 0, expiration)
+		incr = pipe.Incr(ctx, key)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
 func (r *RedisCacheService) GetObject(ctx context.Context, key string, dest interface{}) error {
 	val, err := r.client.Get(ctx, key).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return fmt.Errorf("key值 %s 找不到", key)
+			return fmt.Errorf("key值 %s 找不到: %w", key, ErrNotFound)
+		}
+		return err
+	}
+	return json.Unmarshal([]byte(val), dest)
+}
+
+// GetDelObject 使用 GETDEL 读取并删除键（需 Redis 6.2 及以上）
+func (r *RedisCacheService) GetDelObject(ctx context.Context, key string, dest interface{}) error {
+	val, err := r.client.GetDel(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return fmt.Errorf("key值 %s 找不到: %w", key, ErrNotFound)
 		}
 		return err
 	}
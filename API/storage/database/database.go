@@ -2,15 +2,20 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"regexp"
+	"strconv"
 	"time"
 
 	"API/models"
+	"API/resumeparser"
 
 	"github.com/spf13/viper"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -99,7 +104,22 @@ func InitMySQL() (*gorm.DB, error) {
 	sqlDB.SetConnMaxLifetime(config.MaxLifetime)
 
 	DB = db
-	return db, autoMigrate(db)
+	if err := autoMigrate(db); err != nil {
+		return db, err
+	}
+	if err := migrateJobSearchIndex(db); err != nil {
+		return db, err
+	}
+	if err := migrateJobSalaryRange(db); err != nil {
+		return db, err
+	}
+	if err := migrateResumeSections(db); err != nil {
+		return db, err
+	}
+	if err := seedRBAC(db); err != nil {
+		return db, err
+	}
+	return db, seedLeaveTypes(db)
 }
 
 func CheckMySQLHealth(ctx context.Context) error {
@@ -123,17 +143,357 @@ func autoMigrate(db *gorm.DB) error {
 		&models.Job{},
 		&models.Application{},
 		&models.Attendance{},
+		&models.WorkSchedule{},
+		&models.ScheduleAssignment{},
+		&models.LeaveType{},
+		&models.LeaveBalance{},
+		&models.LeaveRequest{},
+		&models.HolidayCalendar{},
+		&models.Holiday{},
+		&models.OvertimeRequest{},
+		&models.AttendanceCorrection{},
 		&models.Notice{},
 		&models.Permission{},
 		&models.Role{},
+		&models.RoleDefaultGrant{},
 		&models.Salary{},
 		&models.Training{},
 		&models.TrainingRecord{},
 		&models.User{},
 		&models.Resume{},
+		&models.ResumeEducation{},
+		&models.ResumeEmployment{},
+		&models.Skill{},
+		&models.ResumeVersion{},
+		&models.TalentPool{},
+		&models.TalentPoolMember{},
+		&models.TalentTag{},
+		&models.CandidateTag{},
+		&models.CandidateNote{},
+		&models.MFARecoveryCode{},
+		&models.PasswordHistory{},
+		&models.PipelineStage{},
+		&models.ApplicationStageHistory{},
+		&models.Interview{},
+		&models.Scorecard{},
+		&models.ScorecardRating{},
+		&models.OfferTemplate{},
+		&models.Offer{},
+		&models.Requisition{},
+		&models.RequisitionApproval{},
 	)
 }
 
+// ReseededRoles 本次启动补授了默认权限的系统角色，缓存初始化后需清除这些角色的权限缓存
+var ReseededRoles []string
+
+// seedRBAC 写入系统内置角色与权限，已存在的记录不会被覆盖
+func seedRBAC(db *gorm.DB) error {
+	ReseededRoles = nil
+	return db.Transaction(func(tx *gorm.DB) error {
+		var existing []string
+		if err := tx.Model(&models.Permission{}).Pluck("code", &existing).Error; err != nil {
+			return fmt.Errorf("查询权限失败: %w", err)
+		}
+		preexisting := make(map[string]bool, len(existing))
+		for _, code := range existing {
+			preexisting[code] = true
+		}
+
+		permissions := make(map[string]models.Permission, len(models.DefaultPermissions))
+		for _, p := range models.DefaultPermissions {
+			permission := models.Permission{Code: p.Code}
+			if err := tx.Where(models.Permission{Code: p.Code}).
+				Attrs(models.Permission{Description: p.Description}).
+				FirstOrCreate(&permission).Error; err != nil {
+				return fmt.Errorf("初始化权限 %s 失败: %w", p.Code, err)
+			}
+			permissions[p.Code] = permission
+		}
+
+		for _, name := range models.SystemRoles {
+			var role models.Role
+			created := false
+			if err := tx.Where(models.Role{Name: name}).First(&role).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+				role = models.Role{Name: name}
+				if err := tx.Create(&role).Error; err != nil {
+					return fmt.Errorf("初始化角色 %s 失败: %w", name, err)
+				}
+				created = true
+			} else if err != nil {
+				return fmt.Errorf("查询角色 %s 失败: %w", name, err)
+			}
+
+			// admin 始终拥有全部内置权限；其余角色只补授尚未记录过的默认权限，避免覆盖管理员的调整
+			var grants []models.Permission
+			if name == models.RoleAdmin {
+				current, err := rolePermissions(tx, role)
+				if err != nil {
+					return err
+				}
+				held := make(map[uint]bool, len(current))
+				for _, p := range current {
+					held[p.ID] = true
+				}
+				for _, p := range models.DefaultPermissions {
+					if !held[permissions[p.Code].ID] {
+						grants = append(grants, permissions[p.Code])
+					}
+				}
+			} else {
+				if !created {
+					if err := backfillDefaultGrants(tx, role, permissions, preexisting); err != nil {
+						return err
+					}
+				}
+				missing, err := missingDefaultGrants(tx, role, permissions)
+				if err != nil {
+					return err
+				}
+				grants = missing
+			}
+			if len(grants) == 0 {
+				continue
+			}
+			if err := tx.Model(&role).Association("Permissions").Append(grants); err != nil {
+				return fmt.Errorf("初始化角色 %s 权限失败: %w", name, err)
+			}
+			ReseededRoles = append(ReseededRoles, name)
+			if name == models.RoleAdmin {
+				continue
+			}
+			if err := recordDefaultGrants(tx, role, grants); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// backfillDefaultGrants 为启用默认权限记录之前已存在的角色补写记录：角色当前拥有的权限，
+// 以及此前已存在、但角色未拥有（已被管理员撤销）的默认权限，均视为已授予过。
+// 已有记录的角色不做处理
+func backfillDefaultGrants(tx *gorm.DB, role models.Role, permissions map[string]models.Permission, preexisting map[string]bool) error {
+	var count int64
+	if err := tx.Model(&models.RoleDefaultGrant{}).Where("role_id = ?", role.ID).Count(&count).Error; err != nil {
+		return fmt.Errorf("查询角色 %s 的默认权限失败: %w", role.Name, err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	current, err := rolePermissions(tx, role)
+	if err != nil {
+		return err
+	}
+	seen := make(map[uint]bool)
+	var grants []models.Permission
+	for _, p := range current {
+		seen[p.ID] = true
+		grants = append(grants, p)
+	}
+	for _, code := range models.DefaultRolePermissions[role.Name] {
+		if p := permissions[code]; preexisting[code] && !seen[p.ID] {
+			seen[p.ID] = true
+			grants = append(grants, p)
+		}
+	}
+	return recordDefaultGrants(tx, role, grants)
+}
+
+// rolePermissions 查询角色当前拥有的权限
+func rolePermissions(tx *gorm.DB, role models.Role) ([]models.Permission, error) {
+	var current []models.Permission
+	if err := tx.Model(&role).Association("Permissions").Find(&current); err != nil {
+		return nil, fmt.Errorf("查询角色 %s 的权限失败: %w", role.Name, err)
+	}
+	return current, nil
+}
+
+// recordDefaultGrants 记录已授予角色的默认权限
+func recordDefaultGrants(tx *gorm.DB, role models.Role, grants []models.Permission) error {
+	if len(grants) == 0 {
+		return nil
+	}
+	records := make([]models.RoleDefaultGrant, 0, len(grants))
+	for _, p := range grants {
+		records = append(records, models.RoleDefaultGrant{RoleID: role.ID, PermissionID: p.ID})
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&records).Error; err != nil {
+		return fmt.Errorf("记录角色 %s 默认权限失败: %w", role.Name, err)
+	}
+	return nil
+}
+
+// missingDefaultGrants 返回角色的默认权限中尚未授予过的部分
+func missingDefaultGrants(tx *gorm.DB, role models.Role, permissions map[string]models.Permission) ([]models.Permission, error) {
+	var granted []uint
+	if err := tx.Model(&models.RoleDefaultGrant{}).Where("role_id = ?", role.ID).
+		Pluck("permission_id", &granted).Error; err != nil {
+		return nil, fmt.Errorf("查询角色 %s 的默认权限失败: %w", role.Name, err)
+	}
+	seen := make(map[uint]bool, len(granted))
+	for _, id := range granted {
+		seen[id] = true
+	}
+	var missing []models.Permission
+	for _, code := range models.DefaultRolePermissions[role.Name] {
+		if p := permissions[code]; !seen[p.ID] {
+			missing = append(missing, p)
+		}
+	}
+	return missing, nil
+}
+
+// seedLeaveTypes 写入内置假期类型，已存在的不会被覆盖，以保留管理员调整过的额度规则
+func seedLeaveTypes(db *gorm.DB) error {
+	for _, t := range models.DefaultLeaveTypes {
+		var count int64
+		if err := db.Model(&models.LeaveType{}).Where("code = ?", t.Code).Count(&count).Error; err != nil {
+			return fmt.Errorf("查询假期类型 %s 失败: %w", t.Code, err)
+		}
+		if count > 0 {
+			continue
+		}
+		leaveType := t
+		if err := db.Create(&leaveType).Error; err != nil {
+			return fmt.Errorf("初始化假期类型 %s 失败: %w", t.Code, err)
+		}
+	}
+	return nil
+}
+
+// jobSearchIndex 职位搜索使用的全文索引，仅 MySQL 创建
+const jobSearchIndex = "idx_job_search"
+
+// migrateJobSearchIndex 在 MySQL 上为职位标题、描述和要求创建 ngram 全文索引，
+// 其他数据库不创建，职位搜索退化为 LIKE 匹配
+func migrateJobSearchIndex(db *gorm.DB) error {
+	if db.Dialector.Name() != "mysql" || db.Migrator().HasIndex(&models.Job{}, jobSearchIndex) {
+		return nil
+	}
+	if err := db.Exec("CREATE FULLTEXT INDEX " + jobSearchIndex + " ON jobs (title, description, requirements) WITH PARSER ngram").Error; err != nil {
+		return fmt.Errorf("创建职位全文索引失败: %w", err)
+	}
+	return nil
+}
+
+// salaryRangePattern 匹配旧版自由文本薪资范围，如 "8000-12000"、"10k-20k"、"1.5万~2万"
+var salaryRangePattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*([kK千wW万]?)\s*[-~～至到]\s*(\d+(?:\.\d+)?)\s*([kK千wW万]?)`)
+
+// migrateJobSalaryRange 将旧版 salary_range 文本解析为薪资上下限，无法解析的保留原文本不做处理
+func migrateJobSalaryRange(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("jobs", "salary_range") {
+		return nil
+	}
+	var rows []struct {
+		ID          uint
+		SalaryRange string
+	}
+	if err := db.Table("jobs").Select("id, salary_range").
+		Where("salary_min IS NULL AND salary_max IS NULL AND salary_range <> ''").
+		Scan(&rows).Error; err != nil {
+		return fmt.Errorf("读取旧版薪资范围失败: %w", err)
+	}
+
+	unit := func(u string) float64 {
+		switch u {
+		case "k", "K", "千":
+			return 1000
+		case "w", "W", "万":
+			return 10000
+		}
+		return 1
+	}
+	for _, row := range rows {
+		m := salaryRangePattern.FindStringSubmatch(row.SalaryRange)
+		if m == nil {
+			continue
+		}
+		// "10-20k" 中单位只写在上限时，下限沿用同一单位
+		lowUnit, highUnit := m[2], m[4]
+		if lowUnit == "" {
+			lowUnit = highUnit
+		}
+		low, _ := strconv.ParseFloat(m[1], 64)
+		high, _ := strconv.ParseFloat(m[3], 64)
+		low, high = low*unit(lowUnit), high*unit(highUnit)
+		if low > high {
+			continue
+		}
+		if err := db.Table("jobs").Where("id = ?", row.ID).
+			Updates(map[string]interface{}{"salary_min": low, "salary_max": high}).Error; err != nil {
+			return fmt.Errorf("迁移职位 %d 薪资范围失败: %w", row.ID, err)
+		}
+	}
+	return nil
+}
+
+// migrateResumeSections 将旧版简历中的教育背景、工作经历、技能文本解析为结构化记录，
+// 仅处理尚无结构化内容的简历，无法解析的文本保留在原列中
+func migrateResumeSections(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasColumn("resumes", "education") || !migrator.HasColumn("resumes", "work_experience") || !migrator.HasColumn("resumes", "skills") {
+		return nil
+	}
+	var rows []struct {
+		ID             uint
+		Education      string
+		WorkExperience string
+		Skills         string
+	}
+	if err := db.Table("resumes").Select("id, education, work_experience, skills").
+		Where("deleted_at IS NULL AND (education <> '' OR work_experience <> '' OR skills <> '')").
+		Where("NOT EXISTS (SELECT 1 FROM resume_educations WHERE resume_educations.resume_id = resumes.id)").
+		Where("NOT EXISTS (SELECT 1 FROM resume_employments WHERE resume_employments.resume_id = resumes.id)").
+		Where("NOT EXISTS (SELECT 1 FROM resume_skills WHERE resume_skills.resume_id = resumes.id)").
+		Scan(&rows).Error; err != nil {
+		return fmt.Errorf("读取旧版简历失败: %w", err)
+	}
+
+	for _, row := range rows {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for i, e := range resumeparser.ParseEducation(row.Education) {
+				start, _ := resumeparser.ParseDate(e.StartDate)
+				end, _ := resumeparser.ParseDate(e.EndDate)
+				if err := tx.Create(&models.ResumeEducation{
+					ResumeID: row.ID, Institution: e.Institution, Degree: e.Degree, Major: e.Major,
+					StartDate: start, EndDate: end, SortOrder: i,
+				}).Error; err != nil {
+					return err
+				}
+			}
+			for i, e := range resumeparser.ParseEmployment(row.WorkExperience) {
+				start, _ := resumeparser.ParseDate(e.StartDate)
+				end, _ := resumeparser.ParseDate(e.EndDate)
+				if err := tx.Create(&models.ResumeEmployment{
+					ResumeID: row.ID, Company: e.Company, Title: e.Title, Description: e.Description,
+					StartDate: start, EndDate: end, SortOrder: i,
+				}).Error; err != nil {
+					return err
+				}
+			}
+			var skills []models.Skill
+			for _, name := range resumeparser.SplitSkills(row.Skills) {
+				key, display := resumeparser.NormalizeSkill(name)
+				skill := models.Skill{Key: key}
+				if err := tx.Where(models.Skill{Key: key}).Attrs(models.Skill{Name: display}).FirstOrCreate(&skill).Error; err != nil {
+					return err
+				}
+				skills = append(skills, skill)
+			}
+			if len(skills) == 0 {
+				return nil
+			}
+			return tx.Model(&models.Resume{Model: gorm.Model{ID: row.ID}}).Association("Skills").Append(skills)
+		})
+		if err != nil {
+			return fmt.Errorf("迁移简历 %d 失败: %w", row.ID, err)
+		}
+	}
+	return nil
+}
+
 func Close() error {
 	if DB == nil {
 		return nil
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"API/models"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newSeedDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("获取测试数据库连接失败: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	// 角色关联用户表，AutoMigrate 会一并迁移含 ENUM 列的 users 表，这里只建所需的表
+	if err := db.Migrator().CreateTable(&models.Permission{}, &models.Role{}, &models.RoleDefaultGrant{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	if err := db.Exec("CREATE TABLE role_permissions (role_id integer, permission_id integer, PRIMARY KEY (role_id, permission_id))").Error; err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	return db
+}
+
+// roleCodes 返回角色当前拥有的权限代码
+func roleCodes(t *testing.T, db *gorm.DB, name string) []string {
+	t.Helper()
+	var codes []string
+	if err := db.Model(&models.Permission{}).
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("roles.name = ?", name).Order("permissions.code").
+		Pluck("permissions.code", &codes).Error; err != nil {
+		t.Fatalf("查询角色权限失败: %v", err)
+	}
+	return codes
+}
+
+func TestSeedRBAC(t *testing.T) {
+	db := newSeedDB(t)
+	if err := seedRBAC(db); err != nil {
+		t.Fatalf("seedRBAC: %v", err)
+	}
+	if len(ReseededRoles) != len(models.SystemRoles) {
+		t.Errorf("首次初始化补授的角色为 %v, 期望全部系统角色", ReseededRoles)
+	}
+	if got := roleCodes(t, db, models.RoleCandidate); !reflect.DeepEqual(got, []string{models.PermJobApply, models.PermJobView}) {
+		t.Errorf("candidate 权限为 %v", got)
+	}
+
+	if err := seedRBAC(db); err != nil {
+		t.Fatalf("seedRBAC: %v", err)
+	}
+	if len(ReseededRoles) != 0 {
+		t.Errorf("重复初始化不应补授权限，补授了 %v", ReseededRoles)
+	}
+}
+
+func TestSeedRBACUpgrade(t *testing.T) {
+	db := newSeedDB(t)
+	if err := seedRBAC(db); err != nil {
+		t.Fatalf("seedRBAC: %v", err)
+	}
+
+	// 模拟升级前的部署：没有默认权限记录，管理员撤销了 candidate 的 job:view，
+	// job:apply 为本次升级新增的默认权限
+	var candidate models.Role
+	db.Where("name = ?", models.RoleCandidate).First(&candidate)
+	var view, apply models.Permission
+	db.Where("code = ?", models.PermJobView).First(&view)
+	db.Where("code = ?", models.PermJobApply).First(&apply)
+	db.Exec("DELETE FROM role_default_grants")
+	db.Exec("DELETE FROM role_permissions WHERE role_id = ? AND permission_id = ?", candidate.ID, view.ID)
+	db.Exec("DELETE FROM role_permissions WHERE permission_id = ?", apply.ID)
+	db.Unscoped().Delete(&apply)
+
+	if err := seedRBAC(db); err != nil {
+		t.Fatalf("seedRBAC: %v", err)
+	}
+	if got := roleCodes(t, db, models.RoleCandidate); !reflect.DeepEqual(got, []string{models.PermJobApply}) {
+		t.Errorf("candidate 权限为 %v, 期望仅补授新增的 job:apply，不恢复已撤销的 job:view", got)
+	}
+	if got := roleCodes(t, db, models.RoleEmployee); !reflect.DeepEqual(got, []string{models.PermJobView}) {
+		t.Errorf("employee 权限为 %v", got)
+	}
+	reseeded := append([]string(nil), ReseededRoles...)
+	sort.Strings(reseeded)
+	if want := []string{models.RoleAdmin, models.RoleCandidate}; !reflect.DeepEqual(reseeded, want) {
+		t.Errorf("补授了权限的角色为 %v, 期望 %v", reseeded, want)
+	}
+
+	// 记录补写后，之后的启动不再恢复被撤销的权限
+	if err := seedRBAC(db); err != nil {
+		t.Fatalf("seedRBAC: %v", err)
+	}
+	if len(ReseededRoles) != 0 || len(roleCodes(t, db, models.RoleCandidate)) != 1 {
+		t.Errorf("再次启动补授了 %v，candidate 权限为 %v", ReseededRoles, roleCodes(t, db, models.RoleCandidate))
+	}
+}
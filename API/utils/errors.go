@@ -1,5 +1,7 @@
 package utils
 
+import "time"
+
 // AuthError 表示认证相关的错误
 type AuthError struct {
 	Message string
@@ -50,6 +52,20 @@ func NewNotFoundError(msg string, resource string) error {
 	return &NotFoundError{Message: msg, Resource: resource}
 }
 
+// ForbiddenError 表示已认证但无权操作该资源
+type ForbiddenError struct {
+	Message string
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
+
+// NewForbiddenError 创建一个新的无权操作错误
+func NewForbiddenError(msg string) error {
+	return &ForbiddenError{Message: msg}
+}
+
 // DatabaseError 表示数据库操作相关的错误
 type DatabaseError struct {
 	Message string
@@ -67,3 +83,18 @@ func (e *DatabaseError) Error() string {
 func NewDatabaseError(msg string, op string) error {
 	return &DatabaseError{Message: msg, Op: op}
 }
+
+// TooManyAttemptsError 表示尝试次数过多被暂时限制
+type TooManyAttemptsError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return e.Message
+}
+
+// NewTooManyAttemptsError 创建一个新的尝试次数过多错误
+func NewTooManyAttemptsError(msg string, retryAfter time.Duration) error {
+	return &TooManyAttemptsError{Message: msg, RetryAfter: retryAfter}
+}
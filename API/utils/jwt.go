@@ -1,40 +1,324 @@
 package utils
 
 import (
+	"crypto"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
 	"time"
 
+	"API/config"
+
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/spf13/viper"
 )
 
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+
+	// minSecretLength HMAC 密钥最小长度
+	minSecretLength = 16
+)
+
 type Claims struct {
-	UserID uint
-	Roles  []string
+	UserID    uint
+	Roles     []string
+	TokenType string `json:"token_type"`
+	// IssuedAtMs 毫秒精度的签发时间，iat 仅精确到秒
+	IssuedAtMs int64 `json:"iat_ms"`
 	jwt.RegisteredClaims
 }
 
-var jwtSecret = []byte(viper.GetString("jwt.secret"))
+// JWTKeyConfig 单个密钥配置，HS256 使用 secret，RS256/EdDSA 使用 PEM 密钥文件
+type JWTKeyConfig struct {
+	KID            string `mapstructure:"kid"`
+	Algorithm      string `mapstructure:"algorithm"`
+	Secret         string `mapstructure:"secret"`
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+	PublicKeyFile  string `mapstructure:"public_key_file"`
+}
+
+// JWTConfig 令牌签发配置
+type JWTConfig struct {
+	Algorithm         string
+	Secret            string
+	Issuer            string
+	Audience          string
+	ActiveKID         string
+	Keys              []JWTKeyConfig
+	AccessExpiration  time.Duration
+	RefreshExpiration time.Duration
+}
+
+// LoadJWTConfig 从配置文件加载令牌配置，需在 config.InitConfig() 之后调用
+func LoadJWTConfig() (JWTConfig, error) {
+	cfg := JWTConfig{
+		Algorithm:         viper.GetString("jwt.algorithm"),
+		Secret:            viper.GetString("jwt.secret"),
+		Issuer:            viper.GetString("jwt.issuer"),
+		Audience:          viper.GetString("jwt.audience"),
+		ActiveKID:         viper.GetString("jwt.active_kid"),
+		AccessExpiration:  viper.GetDuration("jwt.expiration"),
+		RefreshExpiration: viper.GetDuration("jwt.refresh_expiration"),
+	}
+	if err := viper.UnmarshalKey("jwt.keys", &cfg.Keys); err != nil {
+		return cfg, fmt.Errorf("解析 jwt.keys 失败: %w", err)
+	}
+	return cfg, nil
+}
+
+// verificationKey 验签密钥
+type verificationKey struct {
+	method jwt.SigningMethod
+	key    interface{}
+}
+
+// TokenIssuer 根据配置签发和校验令牌，支持多个验签密钥以便轮换
+type TokenIssuer struct {
+	method     jwt.SigningMethod
+	signingKey interface{}
+	keyID      string
+	verifyKeys map[string]verificationKey
+	validAlgs  []string
+	issuer     string
+	audience   string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewTokenIssuer 构建令牌签发器。
+// 未配置 jwt.keys 时使用 jwt.secret 作为唯一的 HS256 密钥；
+// jwt.active_kid 指定签名密钥，其余密钥仅用于校验轮换前签发的令牌。
+func NewTokenIssuer(cfg JWTConfig) (*TokenIssuer, error) {
+	if cfg.AccessExpiration <= 0 || cfg.RefreshExpiration <= 0 {
+		return nil, errors.New("令牌有效期必须大于0")
+	}
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = jwt.SigningMethodHS256.Alg()
+	}
+
+	keys := cfg.Keys
+	if len(keys) == 0 {
+		keys = []JWTKeyConfig{{KID: "default", Algorithm: cfg.Algorithm, Secret: cfg.Secret}}
+	}
+	activeKID := cfg.ActiveKID
+	if activeKID == "" {
+		activeKID = keys[0].KID
+	}
+
+	issuer := &TokenIssuer{
+		verifyKeys: make(map[string]verificationKey, len(keys)),
+		issuer:     cfg.Issuer,
+		audience:   cfg.Audience,
+		accessTTL:  cfg.AccessExpiration,
+		refreshTTL: cfg.RefreshExpiration,
+	}
+	seenAlgs := make(map[string]bool)
+
+	for _, k := range keys {
+		if k.KID == "" {
+			return nil, errors.New("jwt.keys 中存在未设置 kid 的密钥")
+		}
+		if _, ok := issuer.verifyKeys[k.KID]; ok {
+			return nil, fmt.Errorf("重复的密钥ID: %s", k.KID)
+		}
+		if k.Algorithm == "" {
+			k.Algorithm = cfg.Algorithm
+		}
+
+		method, signKey, verifyKey, err := loadKey(k, k.KID == activeKID)
+		if err != nil {
+			return nil, fmt.Errorf("加载密钥 %s 失败: %w", k.KID, err)
+		}
+		issuer.verifyKeys[k.KID] = verificationKey{method: method, key: verifyKey}
+		if !seenAlgs[method.Alg()] {
+			seenAlgs[method.Alg()] = true
+			issuer.validAlgs = append(issuer.validAlgs, method.Alg())
+		}
+		if k.KID == activeKID {
+			issuer.method = method
+			issuer.signingKey = signKey
+			issuer.keyID = k.KID
+		}
+	}
+
+	if issuer.signingKey == nil {
+		return nil, fmt.Errorf("未找到签名密钥: %s", activeKID)
+	}
+	return issuer, nil
+}
+
+// loadKey 按算法加载密钥，签名密钥必须包含私钥（或 HMAC 密钥）
+func loadKey(k JWTKeyConfig, signing bool) (jwt.SigningMethod, interface{}, interface{}, error) {
+	switch strings.ToUpper(k.Algorithm) {
+	case "HS256":
+		if k.Secret == "" {
+			return nil, nil, nil, errors.New("HS256 密钥不能为空")
+		}
+		if k.Secret == config.DefaultInsecureSecret {
+			return nil, nil, nil, errors.New("禁止使用默认的不安全密钥，请配置 jwt.secret")
+		}
+		if len(k.Secret) < minSecretLength {
+			return nil, nil, nil, fmt.Errorf("HS256 密钥长度不能少于%d个字符", minSecretLength)
+		}
+		secret := []byte(k.Secret)
+		return jwt.SigningMethodHS256, secret, secret, nil
+
+	case "RS256":
+		var signKey, verifyKey interface{}
+		if k.PrivateKeyFile != "" {
+			pem, err := os.ReadFile(k.PrivateKeyFile)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			signKey, verifyKey = privateKey, privateKey.Public()
+		}
+		if k.PublicKeyFile != "" {
+			pem, err := os.ReadFile(k.PublicKeyFile)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if verifyKey, err = jwt.ParseRSAPublicKeyFromPEM(pem); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		return checkKeyPair(jwt.SigningMethodRS256, signKey, verifyKey, signing)
 
-func GenerateToken(userID uint, roles []string) (string, error) {
-	claims := Claims{
-		UserID: userID,
-		Roles:  roles,
+	case "EDDSA":
+		var signKey, verifyKey interface{}
+		if k.PrivateKeyFile != "" {
+			pem, err := os.ReadFile(k.PrivateKeyFile)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			privateKey, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			signKey, verifyKey = privateKey, privateKey.(crypto.Signer).Public()
+		}
+		if k.PublicKeyFile != "" {
+			pem, err := os.ReadFile(k.PublicKeyFile)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if verifyKey, err = jwt.ParseEdPublicKeyFromPEM(pem); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		return checkKeyPair(jwt.SigningMethodEdDSA, signKey, verifyKey, signing)
+
+	default:
+		return nil, nil, nil, fmt.Errorf("不支持的签名算法: %s", k.Algorithm)
+	}
+}
+
+func checkKeyPair(method jwt.SigningMethod, signKey, verifyKey interface{}, signing bool) (jwt.SigningMethod, interface{}, interface{}, error) {
+	if verifyKey == nil {
+		return nil, nil, nil, errors.New("缺少公钥或私钥文件")
+	}
+	if signing && signKey == nil {
+		return nil, nil, nil, errors.New("签名密钥缺少私钥文件")
+	}
+	return method, signKey, verifyKey, nil
+}
+
+// RefreshTTL 刷新令牌有效期
+func (i *TokenIssuer) RefreshTTL() time.Duration {
+	return i.refreshTTL
+}
+
+// GenerateToken 生成访问令牌
+func (i *TokenIssuer) GenerateToken(userID uint, roles []string) (string, *Claims, error) {
+	return i.generate(userID, roles, TokenTypeAccess, i.accessTTL)
+}
+
+// GenerateRefreshToken 生成刷新令牌，刷新令牌不携带角色，刷新时重新读取
+func (i *TokenIssuer) GenerateRefreshToken(userID uint) (string, *Claims, error) {
+	return i.generate(userID, nil, TokenTypeRefresh, i.refreshTTL)
+}
+
+func (i *TokenIssuer) generate(userID uint, roles []string, tokenType string, ttl time.Duration) (string, *Claims, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID:     userID,
+		Roles:      roles,
+		TokenType:  tokenType,
+		IssuedAtMs: now.UnixMilli(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ID:        uuid.New().String(),
+			Issuer:    i.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 		},
 	}
+	if i.audience != "" {
+		claims.Audience = jwt.ClaimStrings{i.audience}
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	token := jwt.NewWithClaims(i.method, claims)
+	token.Header["kid"] = i.keyID
+	signed, err := token.SignedString(i.signingKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
+}
+
+// ParseToken 解析访问令牌
+func (i *TokenIssuer) ParseToken(tokenString string) (*Claims, error) {
+	return i.parse(tokenString, TokenTypeAccess)
+}
+
+// ParseRefreshToken 解析刷新令牌
+func (i *TokenIssuer) ParseRefreshToken(tokenString string) (*Claims, error) {
+	return i.parse(tokenString, TokenTypeRefresh)
 }
 
-func ParseToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+func (i *TokenIssuer) parse(tokenString, tokenType string) (*Claims, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(i.validAlgs),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if i.issuer != "" {
+		options = append(options, jwt.WithIssuer(i.issuer))
+	}
+	if i.audience != "" {
+		options = append(options, jwt.WithAudience(i.audience))
+	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, i.keyFunc, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, errors.New("无效的令牌")
+	}
+	if claims.TokenType != tokenType || claims.ID == "" || claims.IssuedAt == nil {
+		return nil, errors.New("令牌类型不匹配")
+	}
+	return claims, nil
+}
+
+// keyFunc 按令牌头中的 kid 选择验签密钥，并校验算法与密钥匹配
+func (i *TokenIssuer) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := i.verifyKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("未知的密钥ID: %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("密钥 %s 不支持算法 %s", kid, token.Method.Alg())
 	}
-	return nil, err
+	return key.key, nil
 }
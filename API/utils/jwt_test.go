@@ -0,0 +1,192 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"API/config"
+)
+
+const (
+	oldSecret = "old-secret-kept-for-verification"
+	newSecret = "new-secret-used-for-signing-now!"
+)
+
+func hsConfig(keys ...JWTKeyConfig) JWTConfig {
+	return JWTConfig{
+		Issuer:            "hrms",
+		Keys:              keys,
+		AccessExpiration:  15 * time.Minute,
+		RefreshExpiration: time.Hour,
+	}
+}
+
+// writeEdDSAKey 生成 Ed25519 密钥对并写入临时 PEM 文件
+func writeEdDSAKey(t *testing.T) (privateFile, publicFile string) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("编码私钥失败: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatalf("编码公钥失败: %v", err)
+	}
+	dir := t.TempDir()
+	privateFile, publicFile = filepath.Join(dir, "ed25519.pem"), filepath.Join(dir, "ed25519.pub.pem")
+	if err := os.WriteFile(privateFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600); err != nil {
+		t.Fatalf("写入私钥失败: %v", err)
+	}
+	if err := os.WriteFile(publicFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600); err != nil {
+		t.Fatalf("写入公钥失败: %v", err)
+	}
+	return privateFile, publicFile
+}
+
+func TestNewTokenIssuerValidatesConfig(t *testing.T) {
+	_, publicFile := writeEdDSAKey(t)
+	tests := []struct {
+		name    string
+		cfg     JWTConfig
+		wantErr string
+	}{
+		{"默认的不安全密钥", JWTConfig{Secret: config.DefaultInsecureSecret, AccessExpiration: time.Minute, RefreshExpiration: time.Hour}, "禁止使用默认的不安全密钥"},
+		{"密钥过短", JWTConfig{Secret: "short", AccessExpiration: time.Minute, RefreshExpiration: time.Hour}, "密钥长度不能少于16个字符"},
+		{"有效期为0", JWTConfig{Secret: newSecret, RefreshExpiration: time.Hour}, "令牌有效期必须大于0"},
+		{"不支持的算法", hsConfig(JWTKeyConfig{KID: "k1", Algorithm: "HS512", Secret: newSecret}), "不支持的签名算法"},
+		{"重复的密钥ID", hsConfig(JWTKeyConfig{KID: "k1", Secret: newSecret}, JWTKeyConfig{KID: "k1", Secret: oldSecret}), "重复的密钥ID"},
+		{"签名密钥不存在", func() JWTConfig {
+			cfg := hsConfig(JWTKeyConfig{KID: "k1", Secret: newSecret})
+			cfg.ActiveKID = "k2"
+			return cfg
+		}(), "未找到签名密钥"},
+		{"签名密钥缺少私钥", hsConfig(JWTKeyConfig{KID: "ed", Algorithm: "EdDSA", PublicKeyFile: publicFile}), "签名密钥缺少私钥文件"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.cfg.Algorithm == "" {
+				tt.cfg.Algorithm = "HS256"
+			}
+			_, err := NewTokenIssuer(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("期望错误包含 %q，得到 %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTokenIssuerKeyRotation(t *testing.T) {
+	privateFile, publicFile := writeEdDSAKey(t)
+	before, err := NewTokenIssuer(hsConfig(JWTKeyConfig{KID: "2024", Secret: oldSecret}))
+	if err != nil {
+		t.Fatalf("NewTokenIssuer: %v", err)
+	}
+	oldToken, _, err := before.GenerateToken(7, []string{"employee"})
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	// 轮换为 EdDSA 签名，旧的 HS256 密钥保留用于校验
+	rotated := hsConfig(
+		JWTKeyConfig{KID: "2025", Algorithm: "EdDSA", PrivateKeyFile: privateFile},
+		JWTKeyConfig{KID: "2024", Algorithm: "HS256", Secret: oldSecret},
+	)
+	rotated.ActiveKID = "2025"
+	after, err := NewTokenIssuer(rotated)
+	if err != nil {
+		t.Fatalf("NewTokenIssuer: %v", err)
+	}
+	newToken, _, err := after.GenerateToken(7, []string{"employee"})
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	// 只保留新公钥的实例（如其他服务）不再接受旧密钥签发的令牌
+	verifyOnly := hsConfig(JWTKeyConfig{KID: "2025", Algorithm: "EdDSA", PublicKeyFile: publicFile}, JWTKeyConfig{KID: "sign", Secret: newSecret})
+	verifyOnly.ActiveKID = "sign"
+	retired, err := NewTokenIssuer(verifyOnly)
+	if err != nil {
+		t.Fatalf("NewTokenIssuer: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		issuer *TokenIssuer
+		token  string
+		valid  bool
+	}{
+		{"轮换后仍可校验旧令牌", after, oldToken, true},
+		{"新密钥签发的令牌", after, newToken, true},
+		{"公钥校验新令牌", retired, newToken, true},
+		{"移除旧密钥后拒绝旧令牌", retired, oldToken, false},
+		{"轮换前的实例不认识新密钥", before, newToken, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := tt.issuer.ParseToken(tt.token)
+			if (err == nil) != tt.valid {
+				t.Fatalf("ParseToken 错误为 %v, 期望有效为 %v", err, tt.valid)
+			}
+			if tt.valid && (claims.UserID != 7 || claims.IssuedAtMs == 0) {
+				t.Errorf("令牌内容为 %+v", claims)
+			}
+		})
+	}
+}
+
+func TestTokenIssuerParse(t *testing.T) {
+	cfg := hsConfig(JWTKeyConfig{KID: "k1", Secret: newSecret})
+	cfg.Audience = "hrms-web"
+	issuer, err := NewTokenIssuer(cfg)
+	if err != nil {
+		t.Fatalf("NewTokenIssuer: %v", err)
+	}
+	access, _, err := issuer.GenerateToken(7, nil)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	refresh, _, err := issuer.GenerateRefreshToken(7)
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken: %v", err)
+	}
+
+	other := cfg
+	other.Issuer = "other"
+	foreign, err := NewTokenIssuer(other)
+	if err != nil {
+		t.Fatalf("NewTokenIssuer: %v", err)
+	}
+	foreignToken, _, _ := foreign.GenerateToken(7, nil)
+
+	expired := *issuer
+	expired.accessTTL = -time.Minute
+	expiredToken, _, _ := expired.GenerateToken(7, nil)
+
+	if _, err := issuer.ParseToken(access); err != nil {
+		t.Errorf("访问令牌应有效，得到 %v", err)
+	}
+	if _, err := issuer.ParseRefreshToken(refresh); err != nil {
+		t.Errorf("刷新令牌应有效，得到 %v", err)
+	}
+	for name, parse := range map[string]func() error{
+		"刷新令牌不能作为访问令牌": func() error { _, err := issuer.ParseToken(refresh); return err },
+		"访问令牌不能作为刷新令牌": func() error { _, err := issuer.ParseRefreshToken(access); return err },
+		"签发者不匹配":       func() error { _, err := issuer.ParseToken(foreignToken); return err },
+		"已过期":          func() error { _, err := issuer.ParseToken(expiredToken); return err },
+	} {
+		if parse() == nil {
+			t.Errorf("%s: 期望解析失败", name)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// sealedPrefix 加密值的版本前缀，便于日后更换加密算法
+const sealedPrefix = "v1:"
+
+// SecretBox 使用 AES-256-GCM 加密需要落库的敏感字段，如 TOTP 密钥
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+// NewSecretBox 使用 Base64 编码的 32 字节密钥创建加密器
+func NewSecretBox(encodedKey string) (*SecretBox, error) {
+	if encodedKey == "" {
+		return nil, errors.New("未配置加密密钥")
+	}
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("加密密钥不是有效的Base64: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("加密密钥长度应为32字节，实际为%d字节", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretBox{aead: aead}, nil
+}
+
+// Seal 加密明文，返回 "v1:" 加 Base64(nonce|密文)
+func (b *SecretBox) Seal(plaintext string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("生成随机数失败: %w", err)
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open 解密 Seal 生成的值
+func (b *SecretBox) Open(value string) (string, error) {
+	if !IsSealed(value) {
+		return "", errors.New("值未加密")
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, sealedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("密文格式错误: %w", err)
+	}
+	size := b.aead.NonceSize()
+	if len(data) < size {
+		return "", errors.New("密文长度错误")
+	}
+	plaintext, err := b.aead.Open(nil, data[:size], data[size:], nil)
+	if err != nil {
+		return "", fmt.Errorf("解密失败: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// IsSealed 判断值是否为 Seal 生成的密文
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix)
+}
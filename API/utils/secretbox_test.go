@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func testSecretBox(t *testing.T, seed byte) *SecretBox {
+	t.Helper()
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = seed
+	}
+	box, err := NewSecretBox(base64.StdEncoding.EncodeToString(key))
+	if err != nil {
+		t.Fatalf("NewSecretBox: %v", err)
+	}
+	return box
+}
+
+func TestSecretBoxRoundTrip(t *testing.T) {
+	box := testSecretBox(t, 1)
+	sealed, err := box.Seal(rfc6238Secret)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if !IsSealed(sealed) || strings.Contains(sealed, rfc6238Secret) {
+		t.Fatalf("密文格式错误: %s", sealed)
+	}
+	if len(sealed) > 128 {
+		t.Errorf("密文长度 %d 超过字段长度", len(sealed))
+	}
+	again, _ := box.Seal(rfc6238Secret)
+	if again == sealed {
+		t.Error("每次加密应使用不同的随机数")
+	}
+	plain, err := box.Open(sealed)
+	if err != nil || plain != rfc6238Secret {
+		t.Fatalf("Open = %q, %v", plain, err)
+	}
+}
+
+func TestSecretBoxRejectsTampering(t *testing.T) {
+	box := testSecretBox(t, 1)
+	sealed, _ := box.Seal(rfc6238Secret)
+
+	if _, err := testSecretBox(t, 2).Open(sealed); err == nil {
+		t.Error("使用其他密钥不应解密成功")
+	}
+	data, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(sealed, sealedPrefix))
+	data[len(data)-1] ^= 1
+	if _, err := box.Open(sealedPrefix + base64.StdEncoding.EncodeToString(data)); err == nil {
+		t.Error("篡改后的密文不应解密成功")
+	}
+	if _, err := box.Open(rfc6238Secret); err == nil {
+		t.Error("明文不应当作密文解密")
+	}
+}
+
+func TestNewSecretBoxValidatesKey(t *testing.T) {
+	for _, key := range []string{"", "not-base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := NewSecretBox(key); err == nil {
+			t.Errorf("密钥 %q 应被拒绝", key)
+		}
+	}
+}
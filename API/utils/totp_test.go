@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret RFC 6238 附录 B 中 SHA1 测试密钥 "12345678901234567890" 的 Base32 编码
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	// 附录 B 的 8 位验证码取后 6 位
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		got, err := TOTPCode(rfc6238Secret, TOTPStep(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("TOTPCode(%d): %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("TOTPCode(%d) = %s, 期望 %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := TOTPStep(now)
+	codeAt := func(step int64) string {
+		code, err := TOTPCode(rfc6238Secret, step)
+		if err != nil {
+			t.Fatalf("TOTPCode: %v", err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name   string
+		step   int64
+		skew   int
+		wantOK bool
+	}{
+		{"当前时间步", current, 0, true},
+		{"前一时间步且无偏差", current - 1, 0, false},
+		{"前一时间步", current - 1, 1, true},
+		{"后一时间步", current + 1, 1, true},
+		{"超出偏差", current - 2, 1, false},
+		{"两步偏差", current + 2, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(rfc6238Secret, codeAt(tt.step), now, tt.skew)
+			if ok != tt.wantOK {
+				t.Fatalf("ValidateTOTP ok = %v, 期望 %v", ok, tt.wantOK)
+			}
+			if ok && step != tt.step {
+				t.Errorf("匹配的时间步为 %d，期望 %d", step, tt.step)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPRejectsMalformed(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870820", "abcdef"} {
+		if _, ok := ValidateTOTP(rfc6238Secret, code, now, 1); ok {
+			t.Errorf("验证码 %q 不应通过", code)
+		}
+	}
+	if _, ok := ValidateTOTP("not base32!", "287082", now, 1); ok {
+		t.Error("无效密钥不应通过")
+	}
+	if code, _ := TOTPCode(rfc6238Secret, 1); code != "287082" {
+		t.Fatalf("前置条件失败: %s", code)
+	}
+	if _, ok := ValidateTOTP(" "+rfc6238Secret+" ", " 287082 ", now, 0); !ok {
+		t.Error("应忽略首尾空白")
+	}
+}